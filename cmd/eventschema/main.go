@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+)
+
+// eventschema writes the JSON Schema bundle for all realtime events.
+//
+//	go run ./cmd/eventschema -out events.schema.json
+func main() {
+	out := flag.String("out", "", "output file (defaults to stdout)")
+	flag.Parse()
+
+	data, err := json.MarshalIndent(events.SchemaBundle(), "", "  ")
+	if err != nil {
+		log.Fatalf("✗ Failed to build event schema: %v", err)
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		log.Fatalf("✗ Failed to write %s: %v", *out, err)
+	}
+	log.Printf("✓ Wrote %d event definitions to %s", len(events.All()), *out)
+}
@@ -8,11 +8,20 @@ import (
 	"os"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jwtkeys"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/mailer"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/middleware"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/oidc"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/revocation"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	auditHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/http"
+	auditRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/repository"
+	auditService "github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/service"
 	authHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/http"
+	authRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
 	authService "github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/service"
 	courseHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/course/http"
 	courseRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/course/repository"
@@ -42,9 +51,15 @@ import (
 	peopleHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/people/http"
 	peopleRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/people/repository"
 	peopleService "github.com/M1ralai/go-modular-monolith-template/internal/modules/people/service"
+	systemlogHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/http"
+	systemlogRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/repository"
+	systemlogService "github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/service"
 	taskHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/task/http"
 	taskRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/task/repository"
 	taskService "github.com/M1ralai/go-modular-monolith-template/internal/modules/task/service"
+	trashHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/http"
+	trashRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/repository"
+	trashService "github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/service"
 	userHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/http"
 	userRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
 	userService "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/service"
@@ -57,11 +72,31 @@ import (
 	scheduleRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/schedule/repository"
 	scheduleService "github.com/M1ralai/go-modular-monolith-template/internal/modules/schedule/service"
 
-	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/websocket"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/websocket"
 
+	jobHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/job/http"
+	jobimpl "github.com/M1ralai/go-modular-monolith-template/internal/modules/job/jobs"
+	jobRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/job/repository"
+	jobService "github.com/M1ralai/go-modular-monolith-template/internal/modules/job/service"
+	notifHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/http"
+	notifRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/repository"
 	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 
+	webhookHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/http"
+	webhookRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/repository"
+	webhookService "github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/service"
+
+	digestHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/http"
+	digestRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/repository"
+	digestService "github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/service"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace"
+	workspaceHttp "github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/http"
+	workspaceRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/repository"
+	workspaceService "github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/service"
+
 	"github.com/gorilla/mux"
 	"github.com/jmoiron/sqlx"
 )
@@ -70,16 +105,74 @@ type Server struct {
 	httpServer *http.Server
 	db         *sqlx.DB
 	logger     *logger.ZapLogger
+	scheduler  *jobs.Scheduler
+	outbox     *eventbus.Outbox
+	revocation *revocation.List
+	fakeSMTP   *mailer.FakeServer
 }
 
 func NewServer(db *sqlx.DB, zapLogger *logger.ZapLogger) *Server {
+	// Access token keys, shared by the issuer (auth), the auth middleware and the WebSocket handler
+	jwtKeys, err := jwtkeys.NewManager(jwtkeys.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("✗ Failed to load JWT keys: %v", err)
+	}
+	if jwtKeys.Ephemeral() {
+		log.Println("⚠ APP_ENV=development without JWT_KEYS_DIR, signing access tokens with a temporary key; they stop working on restart and are not accepted by other instances")
+	}
+
+	// Revoked access tokens, honored by the auth middleware and the WebSocket handler
+	revocationList := revocation.NewList(db, zapLogger)
+	if err := revocationList.Load(context.Background()); err != nil {
+		log.Fatalf("✗ Failed to load token revocations: %v", err)
+	}
+	revocationList.Start()
+
 	// WebSocket Hub
-	wsHub := websocket.NewHub(zapLogger)
+	wsHub := websocket.NewHub(zapLogger, websocket.ConfigFromEnv())
 	go wsHub.Run()
-	wsHandler := websocket.NewHandler(wsHub, zapLogger)
+	wsHandler := websocket.NewHandler(wsHub, zapLogger, jwtKeys, revocationList)
+	sseHandler := websocket.NewSSEHandler(wsHub, zapLogger)
+
+	// Broadcaster for real-time notifications, backed by the persistent inbox
+	// and filtered by each user's delivery preferences and quiet hours
+	notificationRepository := notifRepo.NewPostgresRepository(db)
+	preferenceRepository := notifRepo.NewPreferenceRepository(db)
+	deferredRepository := notifRepo.NewDeferredRepository(db)
+	preferenceSvc := notifService.NewPreferenceService(preferenceRepository, zapLogger)
+	broadcaster := notifService.NewBroadcaster(wsHub, notificationRepository, deferredRepository, preferenceSvc, zapLogger)
+	wsHub.SetDeliveryFilter(broadcaster.AllowLive)
+	notificationSvc := notifService.NewNotificationService(notificationRepository, zapLogger, broadcaster)
+	notificationHandler := notifHttp.NewHandler(notificationSvc, preferenceSvc)
+
+	// Email channel: real SMTP when SMTP_HOST is set; in development an in-process stand-in that keeps mail in memory
+	mailConfig := mailer.ConfigFromEnv()
+	var fakeSMTP *mailer.FakeServer
+	if mailConfig.Host == "" {
+		if !mailConfig.Development {
+			log.Fatalf("✗ SMTP_HOST not set; configure SMTP, or set APP_ENV=development to keep emails in an in-process fake server")
+		}
+		fake, err := mailer.NewFakeServer(zapLogger)
+		if err != nil {
+			log.Fatalf("✗ Failed to start fake SMTP server: %v", err)
+		}
+		fakeSMTP = fake
+		mailConfig = fakeSMTP.Config(mailConfig.From)
+		log.Printf("⚠ APP_ENV=development without SMTP_HOST, emails go to the in-process fake SMTP server (%s:%d) and are not delivered\n", mailConfig.Host, mailConfig.Port)
+	}
+	mailRenderer, err := mailer.NewRenderer()
+	if err != nil {
+		log.Fatalf("✗ Failed to load email templates: %v", err)
+	}
+	emailOutbox := mailer.NewOutbox(db, mailer.NewSMTPMailer(mailConfig), nil, zapLogger)
+	broadcaster.SetEmailChannel(notifService.NewEmailChannel(emailOutbox, mailRenderer, preferenceRepository, zapLogger))
 
-	// Broadcaster for real-time notifications
-	broadcaster := notifService.NewBroadcaster(wsHub, zapLogger)
+	// Webhook module: user endpoints receive signed copies of published events
+	webhookEndpointRepository := webhookRepo.NewEndpointRepository(db)
+	webhookDeliveryRepository := webhookRepo.NewDeliveryRepository(db)
+	webhookConfig := webhookService.ConfigFromEnv()
+	webhookDispatcher := webhookService.NewDispatcher(webhookEndpointRepository, webhookDeliveryRepository, webhookConfig, zapLogger)
+	broadcaster.SetWebhookDispatcher(webhookDispatcher)
 
 	// Distributed lock for jobs
 	jobLock := jobs.NewDistributedLock(db)
@@ -88,66 +181,145 @@ func NewServer(db *sqlx.DB, zapLogger *logger.ZapLogger) *Server {
 	jobPool := jobs.NewWorkerPool(5, 100, zapLogger, nil, jobLock)
 	jobPool.Start()
 
+	// Event bus: modules publish domain events, the broadcaster delivers catalog events to users.
+	// Events go through the transactional outbox, so subscribers only see committed changes.
+	unitOfWork := database.NewUnitOfWork(db)
+	eventBus := eventbus.New(jobPool, zapLogger)
+	eventBus.SubscribeAll("notification.broadcast", broadcaster.HandleEvent)
+	eventOutbox := eventbus.NewOutbox(db, nil, zapLogger)
+	eventBus.UseOutbox(eventOutbox)
+	eventOutbox.Start()
+
+	webhookSvc := webhookService.NewWebhookService(webhookEndpointRepository, webhookDeliveryRepository, unitOfWork, webhookConfig, zapLogger, eventBus)
+	webhookHandler := webhookHttp.NewHandler(webhookSvc)
+
+	// Audit module: records every entity change published on the bus
+	auditRepository := auditRepo.NewPostgresRepository(db)
+	auditService.NewRecorder(auditRepository, zapLogger).Subscribe(eventBus)
+	auditSvc := auditService.NewAuditService(auditRepository, zapLogger)
+	auditHandler := auditHttp.NewHandler(auditSvc)
+
+	// Scheduler for periodic jobs
+	scheduler := jobs.NewScheduler(jobPool, zapLogger)
+	if err := scheduler.Register(jobimpl.NewDeferredNotificationJob(zapLogger, broadcaster)); err != nil {
+		log.Fatalf("✗ Failed to register deferred notification job: %v", err)
+	}
+	if err := scheduler.Register(jobimpl.NewEmailOutboxJob(zapLogger, emailOutbox)); err != nil {
+		log.Fatalf("✗ Failed to register email outbox job: %v", err)
+	}
+	if err := scheduler.Register(jobimpl.NewWebhookDeliveryJob(zapLogger, webhookDispatcher)); err != nil {
+		log.Fatalf("✗ Failed to register webhook delivery job: %v", err)
+	}
+	if err := scheduler.Register(jobimpl.NewEventOutboxPurgeJob(zapLogger, eventOutbox)); err != nil {
+		log.Fatalf("✗ Failed to register event outbox purge job: %v", err)
+	}
+
 	// Health module
 	healthHandler := healthHttp.NewHandler()
 
-	// User module
+	// Auth module: short-lived access tokens carrying the user's roles, plus rotating refresh tokens
 	userRepository := userRepo.NewPostgresRepository(db)
-	userSvc := userService.NewUserService(userRepository, zapLogger)
+	roleRepository := userRepo.NewRoleRepository(db)
+	refreshTokenRepository := authRepo.NewPostgresRepository(db)
+	sessionRepository := authRepo.NewSessionRepository(db)
+	mfaRepository := authRepo.NewMFARepository(db)
+	mfaSvc, err := authService.NewMFAService(userRepository, mfaRepository, unitOfWork, authService.MFAConfigFromEnv(), zapLogger, eventBus)
+	if err != nil {
+		log.Fatalf("✗ Failed to create MFA service: %v", err)
+	}
+	verificationSvc := authService.NewEmailVerificationService(userRepository, unitOfWork, emailOutbox, mailRenderer, authService.EmailVerificationConfigFromEnv(), zapLogger, eventBus)
+	tokenConfig := authService.TokenConfigFromEnv()
+	authSvc := authService.NewAuthService(userRepository, roleRepository, refreshTokenRepository, sessionRepository, mfaRepository, mfaSvc, verificationSvc, unitOfWork, revocationList, wsHub, jwtKeys, tokenConfig, zapLogger, eventBus)
+	sessionSvc := authService.NewSessionService(sessionRepository, refreshTokenRepository, revocationList, wsHub, tokenConfig, zapLogger)
+	passwordResetRepository := authRepo.NewPasswordResetRepository(db)
+	passwordSvc := authService.NewPasswordService(userRepository, passwordResetRepository, unitOfWork, authSvc, emailOutbox, mailRenderer, authService.PasswordConfigFromEnv(), zapLogger, eventBus)
+	accessTokenSvc := authService.NewAccessTokenService(authRepo.NewAccessTokenRepository(db), unitOfWork, zapLogger, eventBus)
+
+	// OIDC login: disabled without OIDC_ISSUER
+	oidcConfig := oidc.ConfigFromEnv()
+	var oidcClient *oidc.Client
+	if oidcConfig.Enabled() {
+		oidcClient = oidc.NewClient(oidcConfig)
+	}
+	oidcSvc := authService.NewOIDCService(oidcClient, authRepo.NewOIDCRepository(db), userRepository, unitOfWork, authSvc, authService.OIDCAutoRegisterFromEnv(), zapLogger, eventBus)
+
+	authHandler := authHttp.NewHandler(authSvc, passwordSvc, mfaSvc, accessTokenSvc, oidcSvc, sessionSvc, verificationSvc)
+	if err := scheduler.Register(jobimpl.NewAuthTokenCleanupJob(zapLogger, authSvc, passwordSvc, accessTokenSvc, oidcSvc)); err != nil {
+		log.Fatalf("✗ Failed to register auth token cleanup job: %v", err)
+	}
+
+	// User module: self-service profile plus admin-only user and role management
+	userSvc := userService.NewUserService(userRepository, roleRepository, unitOfWork, authSvc, zapLogger, eventBus)
+	if _, err := userSvc.BootstrapAdmins(context.Background(), userService.AdminEmailsFromEnv()); err != nil {
+		log.Fatalf("✗ Failed to grant admin role from ADMIN_EMAILS: %v", err)
+	}
 	userHandler := userHttp.NewHandler(userSvc)
 
-	// Auth module
-	authSvc := authService.NewAuthService(userRepository, zapLogger)
-	authHandler := authHttp.NewHandler(authSvc)
+	// Job module (admin): registered jobs, manual triggers and execution history
+	jobSvc := jobService.NewJobService(jobRepo.NewPostgresRepository(db), scheduler, zapLogger)
+	jobHandler := jobHttp.NewHandler(jobSvc)
+
+	// System log module (admin)
+	systemLogSvc := systemlogService.NewLogService(systemlogRepo.NewPostgresRepository(db), zapLogger)
+	systemLogHandler := systemlogHttp.NewHandler(systemLogSvc)
 
 	// LifeArea module
 	lifeareaRepository := lifeareaRepo.NewPostgresRepository(db)
-	lifeareaSvc := lifeareaService.NewLifeAreaService(lifeareaRepository, zapLogger, broadcaster)
+	lifeareaSvc := lifeareaService.NewLifeAreaService(lifeareaRepository, unitOfWork, zapLogger, eventBus)
+	lifeareaService.Subscribe(eventBus, lifeareaSvc)
 	lifeareaHandler := lifeareaHttp.NewHandler(lifeareaSvc)
 
+	// Workspace module: shares tasks, courses and goals; access checks grants and fans events out to members
+	workspaceMemberRepository := workspaceRepo.NewMemberRepository(db)
+	workspaceAccess := workspace.NewAccess(workspaceMemberRepository, eventBus)
+	workspaceSvc := workspaceService.NewWorkspaceService(workspaceRepo.NewWorkspaceRepository(db), workspaceMemberRepository, workspaceRepo.NewInvitationRepository(db), userRepository, unitOfWork, emailOutbox, mailRenderer, workspaceService.InvitationConfigFromEnv(), zapLogger, eventBus)
+	workspaceHandler := workspaceHttp.NewHandler(workspaceSvc)
+
 	// Course module
 	courseRepository := courseRepo.NewPostgresRepository(db)
-	courseSvc := courseService.NewCourseService(courseRepository, zapLogger, broadcaster)
+	courseSvc := courseService.NewCourseService(courseRepository, unitOfWork, zapLogger, eventBus, workspaceAccess)
+	courseService.Subscribe(eventBus, courseSvc)
 	courseHandler := courseHttp.NewHandler(courseSvc)
 
 	// Task module
 	taskRepository := taskRepo.NewPostgresRepository(db)
-	taskSvc := taskService.NewTaskService(taskRepository, zapLogger, broadcaster)
-	taskHandler := taskHttp.NewHandler(taskSvc, jobPool, taskRepository, broadcaster, zapLogger)
+	taskSvc := taskService.NewTaskService(taskRepository, unitOfWork, zapLogger, eventBus, workspaceAccess)
+	taskHandler := taskHttp.NewHandler(taskSvc, jobPool, taskRepository, unitOfWork, workspaceAccess, zapLogger)
 
 	// Note module
 	noteRepository := noteRepo.NewPostgresRepository(db)
-	noteSvc := noteService.NewNoteService(noteRepository, zapLogger, broadcaster)
+	noteSvc := noteService.NewNoteService(noteRepository, unitOfWork, zapLogger, eventBus)
 	noteHandler := noteHttp.NewHandler(noteSvc)
 
 	// Habit module
 	habitRepository := habitRepo.NewPostgresRepository(db)
-	habitSvc := habitService.NewHabitService(habitRepository, zapLogger, broadcaster)
-	habitHandler := habitHttp.NewHandler(habitSvc, habitRepository, broadcaster, zapLogger)
+	habitSvc := habitService.NewHabitService(habitRepository, unitOfWork, zapLogger, eventBus)
+	habitHandler := habitHttp.NewHandler(habitSvc, habitRepository, eventBus, zapLogger)
 
 	// Goal module
 	goalRepository := goalRepo.NewPostgresRepository(db)
-	goalSvc := goalService.NewGoalService(goalRepository, zapLogger, broadcaster)
+	goalSvc := goalService.NewGoalService(goalRepository, unitOfWork, zapLogger, eventBus, workspaceAccess)
+	goalService.Subscribe(eventBus, goalSvc)
 	goalHandler := goalHttp.NewHandler(goalSvc)
 
 	// Event module
 	eventRepository := eventRepo.NewPostgresRepository(db)
-	eventSvc := eventService.NewEventService(eventRepository, zapLogger, broadcaster)
+	eventSvc := eventService.NewEventService(eventRepository, unitOfWork, zapLogger, eventBus)
 	eventHandler := eventHttp.NewHandler(eventSvc)
 
 	// People module
 	peopleRepository := peopleRepo.NewPostgresRepository(db)
-	peopleSvc := peopleService.NewPersonService(peopleRepository, zapLogger, broadcaster)
+	peopleSvc := peopleService.NewPersonService(peopleRepository, unitOfWork, zapLogger, eventBus)
 	peopleHandler := peopleHttp.NewHandler(peopleSvc)
 
 	// Journal module
 	journalRepository := journalRepo.NewPostgresRepository(db)
-	journalSvc := journalService.NewJournalService(journalRepository, zapLogger, broadcaster)
+	journalSvc := journalService.NewJournalService(journalRepository, unitOfWork, zapLogger, eventBus)
 	journalHandler := journalHttp.NewHandler(journalSvc)
 
 	// Finance module
 	financeRepository := financeRepo.NewPostgresRepository(db)
-	financeSvc := financeService.NewFinanceService(financeRepository, zapLogger, broadcaster)
+	financeSvc := financeService.NewFinanceService(financeRepository, unitOfWork, zapLogger, eventBus)
 	financeHandler := financeHttp.NewHandler(financeSvc)
 
 	// Calendar module
@@ -158,9 +330,28 @@ func NewServer(db *sqlx.DB, zapLogger *logger.ZapLogger) *Server {
 
 	// Schedule module
 	blockedSlotRepository := scheduleRepo.NewBlockedTimeSlotRepository(db)
-	scheduleSvc := scheduleService.NewScheduleService(blockedSlotRepository, zapLogger, broadcaster)
+	scheduleSvc := scheduleService.NewScheduleService(blockedSlotRepository, unitOfWork, zapLogger, eventBus)
 	scheduleHandler := scheduleHttp.NewHandler(scheduleSvc)
 
+	// Digest module: opt-in daily/weekly summaries built from the modules above
+	digestRepository := digestRepo.NewPostgresRepository(db)
+	digestBuilder := digestService.NewBuilder(digestRepository, taskRepository, financeRepository)
+	digestSender := digestService.NewSender(digestRepository, unitOfWork, digestBuilder, eventBus, emailOutbox, mailRenderer, zapLogger)
+	digestSvc := digestService.NewDigestService(digestRepository, digestBuilder, zapLogger)
+	digestHandler := digestHttp.NewHandler(digestSvc)
+	if err := scheduler.Register(jobimpl.NewDigestJob(zapLogger, digestSender)); err != nil {
+		log.Fatalf("✗ Failed to register digest job: %v", err)
+	}
+
+	// Trash module: deleted entities wait here until restored or purged after retention
+	trashRepository := trashRepo.NewPostgresRepository(db)
+	trashSvc := trashService.NewTrashService(trashRepository, unitOfWork, zapLogger, workspaceAccess, trashService.RetentionFromEnv())
+	trashHandler := trashHttp.NewHandler(trashSvc)
+	if err := scheduler.Register(jobimpl.NewTrashPurgeJob(zapLogger, trashSvc)); err != nil {
+		log.Fatalf("✗ Failed to register trash purge job: %v", err)
+	}
+	scheduler.Start()
+
 	router := mux.NewRouter()
 
 	// WebSocket route - MUST be registered BEFORE middleware to avoid ResponseWriter wrapping
@@ -168,6 +359,7 @@ func NewServer(db *sqlx.DB, zapLogger *logger.ZapLogger) *Server {
 	router.HandleFunc("/ws", wsHandler.HandleConnection).Methods("GET")
 
 	// Apply middleware to all routes EXCEPT WebSocket
+	router.Use(middleware.RequestIDMiddleware(middleware.TrustedProxiesFromEnv()))
 	router.Use(middleware.RecoveryMiddleware)
 	router.Use(zapLogger.Middleware)
 	router.Use(middleware.MetricsMiddleware)
@@ -184,17 +376,34 @@ func NewServer(db *sqlx.DB, zapLogger *logger.ZapLogger) *Server {
 
 	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
 	router.HandleFunc("/health", healthHandler.HealthCheck).Methods("GET")
+	router.HandleFunc("/.well-known/jwks.json", jwtKeys.ServeJWKS).Methods("GET")
 
-	// Public routes (no auth required)
-	authHandler.RegisterRoutes(router)
+	// Public routes (no auth required), rate limited per client IP
+	authRoutes := router.NewRoute().Subrouter()
+	authRoutes.Use(middleware.RateLimit(middleware.RateLimitPolicyFromEnv("auth", 60, 20)))
+	authHandler.RegisterRoutes(authRoutes)
 
-	// API routes (protected)
+	// API routes (protected), rate limited per user
 	api := router.PathPrefix("/api").Subrouter()
-	api.Use(middleware.AuthMiddleware)
+	api.Use(middleware.AuthMiddleware(jwtKeys, revocationList, accessTokenSvc))
+	api.Use(middleware.RateLimit(middleware.RateLimitPolicyFromEnv("api", 600, 100)))
+	// Unverified accounts are kept out of the areas in EMAIL_VERIFICATION_REQUIRED_FOR
+	api.Use(middleware.RequireVerifiedEmail(middleware.EmailVerificationPolicyFromEnv(), verificationSvc))
 
 	// Register all module routes
+	authHandler.RegisterProtectedRoutes(api)
+	wsHandler.RegisterRoutes(api)
+	sseHandler.RegisterRoutes(api)
+	notificationHandler.RegisterRoutes(api)
+	webhookHandler.RegisterRoutes(api)
+	digestHandler.RegisterRoutes(api)
+	auditHandler.RegisterRoutes(api)
+	trashHandler.RegisterRoutes(api)
 	userHandler.RegisterRoutes(api)
+	jobHandler.RegisterRoutes(api)
+	systemLogHandler.RegisterRoutes(api)
 	lifeareaHandler.RegisterRoutes(api)
+	workspaceHandler.RegisterRoutes(api)
 	courseHandler.RegisterRoutes(api)
 	taskHandler.RegisterRoutes(api)
 	noteHandler.RegisterRoutes(api)
@@ -227,6 +436,10 @@ func NewServer(db *sqlx.DB, zapLogger *logger.ZapLogger) *Server {
 		httpServer: httpServer,
 		db:         db,
 		logger:     zapLogger,
+		scheduler:  scheduler,
+		outbox:     eventOutbox,
+		revocation: revocationList,
+		fakeSMTP:   fakeSMTP,
 	}
 }
 
@@ -256,6 +469,14 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		return fmt.Errorf("server shutdown error: %w", err)
 	}
 
+	s.scheduler.Stop()
+	s.outbox.Stop()
+	s.revocation.Stop()
+
+	if s.fakeSMTP != nil {
+		s.fakeSMTP.Close()
+	}
+
 	if err := s.db.Close(); err != nil {
 		return fmt.Errorf("database close error: %w", err)
 	}
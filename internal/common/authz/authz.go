@@ -0,0 +1,45 @@
+package authz
+
+import (
+	"context"
+	"slices"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+// Roles persisted in the roles table
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Permissions granted to roles through the role_permissions table. Handlers declare
+// which one a route needs; access to a user's own data needs none.
+const (
+	UsersRead   = "users:read"
+	UsersWrite  = "users:write"
+	RolesManage = "roles:manage"
+	JobsRead    = "jobs:read"
+	JobsRun     = "jobs:run"
+	LogsRead    = "logs:read"
+)
+
+// Permissions lists every known permission
+var Permissions = []string{UsersRead, UsersWrite, RolesManage, JobsRead, JobsRun, LogsRead}
+
+// Roles returns the roles of the authenticated caller
+func Roles(ctx context.Context) []string {
+	roles, _ := ctx.Value(utils.RolesKey).([]string)
+	return roles
+}
+
+// Can reports whether the authenticated caller holds the permission
+func Can(ctx context.Context, permission string) bool {
+	permissions, _ := ctx.Value(utils.PermissionsKey).([]string)
+	return slices.Contains(permissions, permission)
+}
+
+// HasRole reports whether the authenticated caller has the role
+func HasRole(ctx context.Context, role string) bool {
+	return slices.Contains(Roles(ctx), role)
+}
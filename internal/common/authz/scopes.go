@@ -0,0 +1,83 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+// scopeAreas maps the first path segment under /api to the scope area a personal access
+// token needs for it. Areas not listed (account, sessions, tokens, admin routes ...) cannot
+// be reached with a personal access token at all.
+var scopeAreas = map[string]string{
+	"tasks":         "tasks",
+	"notes":         "notes",
+	"journal":       "journal",
+	"finance":       "finance",
+	"habits":        "habits",
+	"goals":         "goals",
+	"events":        "events",
+	"schedule":      "events",
+	"courses":       "courses",
+	"people":        "people",
+	"life-areas":    "life-areas",
+	"notifications": "notifications",
+}
+
+// tokenlessPaths are routes inside a scope area that personal access tokens still may not
+// use. The live event stream carries every area's changes, not just the calendar's.
+var tokenlessPaths = []string{"/api/events/stream"}
+
+// Scopes lists every scope a personal access token can carry: <area>:read and <area>:write.
+// A write scope includes reading the same area.
+var Scopes = func() []string {
+	var scopes []string
+	for _, area := range scopeAreas {
+		if !slices.Contains(scopes, area+":read") {
+			scopes = append(scopes, area+":read", area+":write")
+		}
+	}
+	slices.Sort(scopes)
+	return scopes
+}()
+
+// ScopeFor returns the scope a request needs: reads (GET, HEAD) need <area>:read, anything
+// else <area>:write. ok is false for routes personal access tokens may not use.
+func ScopeFor(method, path string) (scope string, ok bool) {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/api/"), "/")
+	area, ok := scopeAreas[segment]
+	if !ok || !strings.HasPrefix(path, "/api/") || slices.Contains(tokenlessPaths, strings.TrimSuffix(path, "/")) {
+		return "", false
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return area + ":read", true
+	}
+	return area + ":write", true
+}
+
+// Scoped reports whether the caller authenticated with a scoped credential (a personal
+// access token) rather than a login session
+func Scoped(ctx context.Context) bool {
+	_, ok := ctx.Value(utils.ScopesKey).([]string)
+	return ok
+}
+
+// HasScope reports whether the caller may use the scope. Login sessions are not scoped and
+// may use every scope.
+func HasScope(ctx context.Context, scope string) bool {
+	scopes, ok := ctx.Value(utils.ScopesKey).([]string)
+	if !ok {
+		return true
+	}
+	if slices.Contains(scopes, scope) {
+		return true
+	}
+	area, access, _ := strings.Cut(scope, ":")
+	return access == "read" && slices.Contains(scopes, area+":write")
+}
+
+// PersonalAccessTokenPrefix starts every personal access token, telling them apart from JWTs
+const PersonalAccessTokenPrefix = "pat_"
@@ -0,0 +1,69 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+func TestScopeFor(t *testing.T) {
+	tests := []struct {
+		method    string
+		path      string
+		wantScope string
+		wantOK    bool
+	}{
+		{http.MethodGet, "/api/tasks", "tasks:read", true},
+		{http.MethodHead, "/api/tasks/3", "tasks:read", true},
+		{http.MethodPost, "/api/tasks", "tasks:write", true},
+		{http.MethodDelete, "/api/notes/1", "notes:write", true},
+		{http.MethodGet, "/api/schedule/today", "events:read", true},
+		{http.MethodGet, "/api/events/4", "events:read", true},
+		{http.MethodGet, "/api/events/stream", "", false},
+		{http.MethodGet, "/api/events/stream/", "", false},
+		{http.MethodGet, "/api/auth/tokens", "", false},
+		{http.MethodGet, "/api/sessions", "", false},
+		{http.MethodGet, "/tasks", "", false},
+		{http.MethodGet, "/ws", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			scope, ok := ScopeFor(tt.method, tt.path)
+			if scope != tt.wantScope || ok != tt.wantOK {
+				t.Fatalf("ScopeFor = (%q, %v), want (%q, %v)", scope, ok, tt.wantScope, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{"login session", nil, "finance:write", true},
+		{"exact scope", []string{"tasks:read"}, "tasks:read", true},
+		{"write includes read", []string{"tasks:write"}, "tasks:read", true},
+		{"read does not include write", []string{"tasks:read"}, "tasks:write", false},
+		{"other area", []string{"tasks:write"}, "notes:read", false},
+		{"no scopes", []string{}, "tasks:read", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.scopes != nil {
+				ctx = context.WithValue(ctx, utils.ScopesKey, tt.scopes)
+			}
+			if got := HasScope(ctx, tt.scope); got != tt.want {
+				t.Fatalf("HasScope(%q) = %v, want %v", tt.scope, got, tt.want)
+			}
+			if got := Scoped(ctx); got != (tt.scopes != nil) {
+				t.Fatalf("Scoped = %v", got)
+			}
+		})
+	}
+}
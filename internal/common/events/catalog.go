@@ -0,0 +1,297 @@
+package events
+
+import "sort"
+
+// Event is a typed payload that can be published to clients.
+// Every implementation must be registered in the catalog below.
+type Event interface {
+	EventType() string
+}
+
+// Definition describes one event in the catalog
+type Definition struct {
+	Type        string
+	Version     int
+	Description string
+	Payload     Event
+}
+
+// Task events
+const (
+	TypeTaskCreated   = "task.created"
+	TypeTaskUpdated   = "task.updated"
+	TypeTaskCompleted = "task.completed"
+	TypeTaskDeleted   = "task.deleted"
+)
+
+// Habit events
+const (
+	TypeHabitCreated         = "habit.created"
+	TypeHabitUpdated         = "habit.updated"
+	TypeHabitDeleted         = "habit.deleted"
+	TypeHabitReminder        = "habit.reminder"
+	TypeHabitCompleted       = "habit.completed"
+	TypeHabitSkipped         = "habit.skipped"
+	TypeHabitStreakIncreased = "habit.streak_increased"
+	TypeHabitMilestone       = "habit.milestone"
+	TypeHabitStreakBroken    = "habit.streak_broken"
+)
+
+// Course events
+const (
+	TypeCourseCreated    = "course.created"
+	TypeCourseUpdated    = "course.updated"
+	TypeCourseDeleted    = "course.deleted"
+	TypeComponentCreated = "component.created"
+	TypeComponentUpdated = "component.updated"
+	TypeComponentDeleted = "component.deleted"
+	TypeComponentGraded  = "component.graded"
+	TypeScheduleCreated  = "schedule.created"
+	TypeScheduleUpdated  = "schedule.updated"
+	TypeScheduleDeleted  = "schedule.deleted"
+)
+
+// Calendar events
+const (
+	TypeCalendarSyncStarted     = "calendar.sync_started"
+	TypeCalendarSyncProgress    = "calendar.sync_progress"
+	TypeCalendarSyncCompleted   = "calendar.sync_completed"
+	TypeCalendarSyncFailed      = "calendar.sync_failed"
+	TypeCalendarConflict        = "calendar.conflict"
+	TypeCalendarEventsGenerated = "calendar.events_generated"
+)
+
+// Event module events
+const (
+	TypeEventCreated = "event.created"
+	TypeEventUpdated = "event.updated"
+	TypeEventDeleted = "event.deleted"
+)
+
+// Goal events
+const (
+	TypeGoalCreated          = "goal.created"
+	TypeGoalUpdated          = "goal.updated"
+	TypeGoalDeleted          = "goal.deleted"
+	TypeGoalCompleted        = "goal.completed"
+	TypeGoalMilestoneReached = "goal.milestone_reached"
+)
+
+// Note events
+const (
+	TypeNoteCreated = "note.created"
+	TypeNoteUpdated = "note.updated"
+	TypeNoteDeleted = "note.deleted"
+)
+
+// LifeArea events
+const (
+	TypeLifeAreaCreated = "lifearea.created"
+	TypeLifeAreaUpdated = "lifearea.updated"
+	TypeLifeAreaDeleted = "lifearea.deleted"
+)
+
+// People events
+const (
+	TypePersonCreated = "person.created"
+	TypePersonUpdated = "person.updated"
+	TypePersonDeleted = "person.deleted"
+)
+
+// Journal events
+const (
+	TypeJournalCreated = "journal.created"
+	TypeJournalUpdated = "journal.updated"
+	TypeJournalDeleted = "journal.deleted"
+)
+
+// Finance events
+const (
+	TypeTransactionCreated = "transaction.created"
+	TypeTransactionUpdated = "transaction.updated"
+	TypeTransactionDeleted = "transaction.deleted"
+)
+
+// Job events
+const (
+	TypeJobStarted   = "job.started"
+	TypeJobProgress  = "job.progress"
+	TypeJobCompleted = "job.completed"
+	TypeJobFailed    = "job.failed"
+)
+
+// Notification inbox events
+const (
+	TypeNotificationUnreadCount = "notification.unread_count"
+	TypeNotificationBatch       = "notification.batch"
+)
+
+// Digest events
+const (
+	TypeDigestDaily  = "digest.daily"
+	TypeDigestWeekly = "digest.weekly"
+)
+
+// System events
+const (
+	TypeConnected    = "connected"
+	TypeError        = "error"
+	TypeAuthExpiring = "auth.expiring"
+	TypeDeviceSync   = "device.sync"
+)
+
+// catalog is the single source of truth for event types, payloads and schema versions.
+// Bump Version whenever a payload changes in a way clients must notice.
+var catalog = []Definition{
+	{TypeTaskCreated, 1, "A task was created", TaskCreated{}},
+	{TypeTaskUpdated, 1, "A task was updated", TaskUpdated{}},
+	{TypeTaskCompleted, 1, "A task was completed, directly or because all subtasks were", TaskCompleted{}},
+	{TypeTaskDeleted, 1, "A task was deleted", TaskDeleted{}},
+
+	{TypeHabitCreated, 1, "A habit was created", HabitCreated{}},
+	{TypeHabitUpdated, 1, "A habit was updated", HabitUpdated{}},
+	{TypeHabitDeleted, 1, "A habit was deleted", HabitDeleted{}},
+	{TypeHabitReminder, 1, "A habit is due", HabitReminder{}},
+	{TypeHabitCompleted, 1, "A habit was logged as completed", HabitCompleted{}},
+	{TypeHabitSkipped, 1, "A habit was skipped", HabitSkipped{}},
+	{TypeHabitStreakIncreased, 1, "A habit streak grew", HabitStreakIncreased{}},
+	{TypeHabitMilestone, 1, "A habit streak reached a milestone", HabitMilestone{}},
+	{TypeHabitStreakBroken, 1, "A habit streak was reset", HabitStreakBroken{}},
+
+	{TypeCourseCreated, 1, "A course was created", CourseCreated{}},
+	{TypeCourseUpdated, 1, "A course was updated", CourseUpdated{}},
+	{TypeCourseDeleted, 1, "A course was deleted", CourseDeleted{}},
+	{TypeComponentCreated, 1, "A course component was created", ComponentCreated{}},
+	{TypeComponentUpdated, 1, "A course component was updated", ComponentUpdated{}},
+	{TypeComponentDeleted, 1, "A course component was deleted", ComponentDeleted{}},
+	{TypeComponentGraded, 2, "A course component's score, or the weight of a scored component, changed", ComponentGraded{}},
+	{TypeScheduleCreated, 1, "A course schedule slot was created", ScheduleCreated{}},
+	{TypeScheduleUpdated, 1, "A course schedule slot was updated", ScheduleUpdated{}},
+	{TypeScheduleDeleted, 1, "A course schedule slot was deleted", ScheduleDeleted{}},
+
+	{TypeCalendarSyncStarted, 1, "A calendar sync started", CalendarSyncStarted{}},
+	{TypeCalendarSyncProgress, 1, "A calendar sync made progress", CalendarSyncProgress{}},
+	{TypeCalendarSyncCompleted, 1, "A calendar sync finished", CalendarSyncCompleted{}},
+	{TypeCalendarSyncFailed, 1, "A calendar sync failed", CalendarSyncFailed{}},
+	{TypeCalendarConflict, 1, "A time range overlaps a blocked slot", CalendarConflict{}},
+	{TypeCalendarEventsGenerated, 1, "Recurring events were generated for a semester", CalendarEventsGenerated{}},
+
+	{TypeEventCreated, 1, "A calendar event was created", EventCreated{}},
+	{TypeEventUpdated, 1, "A calendar event was updated", EventUpdated{}},
+	{TypeEventDeleted, 1, "A calendar event was deleted", EventDeleted{}},
+
+	{TypeGoalCreated, 1, "A goal was created", GoalCreated{}},
+	{TypeGoalUpdated, 1, "A goal was updated", GoalUpdated{}},
+	{TypeGoalDeleted, 1, "A goal was deleted", GoalDeleted{}},
+	{TypeGoalCompleted, 1, "A goal was completed", GoalCompleted{}},
+	{TypeGoalMilestoneReached, 1, "A goal milestone was reached", GoalMilestoneReached{}},
+
+	{TypeNoteCreated, 1, "A note was created", NoteCreated{}},
+	{TypeNoteUpdated, 1, "A note was updated", NoteUpdated{}},
+	{TypeNoteDeleted, 1, "A note was deleted", NoteDeleted{}},
+
+	{TypeLifeAreaCreated, 1, "A life area was created", LifeAreaCreated{}},
+	{TypeLifeAreaUpdated, 1, "A life area was updated", LifeAreaUpdated{}},
+	{TypeLifeAreaDeleted, 1, "A life area was deleted", LifeAreaDeleted{}},
+
+	{TypePersonCreated, 1, "A person was created", PersonCreated{}},
+	{TypePersonUpdated, 1, "A person was updated", PersonUpdated{}},
+	{TypePersonDeleted, 1, "A person was deleted", PersonDeleted{}},
+
+	{TypeJournalCreated, 1, "A journal entry was created", JournalCreated{}},
+	{TypeJournalUpdated, 1, "A journal entry was updated", JournalUpdated{}},
+	{TypeJournalDeleted, 1, "A journal entry was deleted", JournalDeleted{}},
+
+	{TypeTransactionCreated, 1, "A transaction was created", TransactionCreated{}},
+	{TypeTransactionUpdated, 1, "A transaction was updated", TransactionUpdated{}},
+	{TypeTransactionDeleted, 1, "A transaction was deleted", TransactionDeleted{}},
+
+	{TypeJobStarted, 1, "A background job started", JobStarted{}},
+	{TypeJobProgress, 1, "A background job reported progress", JobProgress{}},
+	{TypeJobCompleted, 1, "A background job finished", JobCompleted{}},
+	{TypeJobFailed, 1, "A background job failed", JobFailed{}},
+
+	{TypeNotificationUnreadCount, 1, "The user's unread notification count changed", NotificationUnreadCount{}},
+	{TypeNotificationBatch, 1, "Notifications held back during quiet hours", NotificationBatch{}},
+
+	{TypeDigestDaily, 1, "The user's daily summary", DigestDaily{}},
+	{TypeDigestWeekly, 1, "The user's weekly summary", DigestWeekly{}},
+
+	{TypeConnected, 1, "The connection was established", Connected{}},
+	{TypeError, 1, "A protocol-level error occurred", Error{}},
+	{TypeAuthExpiring, 1, "The access token expires soon; send a fresh one", AuthExpiring{}},
+	{TypeDeviceSync, 1, "The client should refetch its state", DeviceSync{}},
+}
+
+// transient events are delivered live only and never stored in the notification inbox
+var transient = map[string]bool{
+	TypeNotificationUnreadCount: true,
+	TypeNotificationBatch:       true,
+	TypeConnected:               true,
+	TypeError:                   true,
+	TypeAuthExpiring:            true,
+	TypeDeviceSync:              true,
+}
+
+// urgent events are delivered immediately even during the user's quiet hours
+var urgent = map[string]bool{
+	TypeHabitReminder:      true,
+	TypeCalendarConflict:   true,
+	TypeCalendarSyncFailed: true,
+	TypeJobFailed:          true,
+}
+
+// selfMailed events send their own email (according to the digest settings), so the
+// notification email channel skips them
+var selfMailed = map[string]bool{
+	TypeDigestDaily:  true,
+	TypeDigestWeekly: true,
+}
+
+var byType = func() map[string]Definition {
+	m := make(map[string]Definition, len(catalog))
+	for _, def := range catalog {
+		if _, exists := m[def.Type]; exists {
+			panic("events: duplicate event type " + def.Type)
+		}
+		if def.Payload.EventType() != def.Type {
+			panic("events: payload for " + def.Type + " reports " + def.Payload.EventType())
+		}
+		m[def.Type] = def
+	}
+	return m
+}()
+
+// Lookup returns the definition registered for an event type
+func Lookup(eventType string) (Definition, bool) {
+	def, ok := byType[eventType]
+	return def, ok
+}
+
+// Version returns the schema version of an event type, or 0 if it is not registered
+func Version(eventType string) int {
+	return byType[eventType].Version
+}
+
+// IsTransient reports whether an event type is live-only and skipped by the inbox
+func IsTransient(eventType string) bool {
+	return transient[eventType]
+}
+
+// IsUrgent reports whether an event type bypasses quiet hours
+func IsUrgent(eventType string) bool {
+	return urgent[eventType]
+}
+
+// IsSelfMailed reports whether an event type sends its own email instead of the notification email
+func IsSelfMailed(eventType string) bool {
+	return selfMailed[eventType]
+}
+
+// All returns every registered definition sorted by type
+func All() []Definition {
+	defs := make([]Definition, len(catalog))
+	copy(defs, catalog)
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Type < defs[j].Type })
+	return defs
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// payloadTypes maps every known event type, catalog and domain-only, to its payload type
+var payloadTypes = func() map[string]reflect.Type {
+	m := make(map[string]reflect.Type, len(catalog)+len(domainEvents))
+	for _, def := range catalog {
+		m[def.Type] = reflect.TypeOf(def.Payload)
+	}
+	for _, event := range domainEvents {
+		m[event.EventType()] = reflect.TypeOf(event)
+	}
+	return m
+}()
+
+// Decode rebuilds a typed event from its type and JSON payload, e.g. when reading it back
+// from the outbox. Entity snapshots typed as interface{} come back as generic JSON objects.
+func Decode(eventType string, payload []byte) (Event, error) {
+	t, ok := payloadTypes[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type: %s", eventType)
+	}
+
+	ptr := reflect.New(t)
+	if err := json.Unmarshal(payload, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", eventType, err)
+	}
+	return ptr.Elem().Interface().(Event), nil
+}
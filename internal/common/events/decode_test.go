@@ -0,0 +1,67 @@
+package events
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		payload   string
+		want      Event
+		wantErr   bool
+	}{
+		{
+			name:      "numeric milestone with its name",
+			eventType: TypeHabitMilestone,
+			payload:   `{"habit_id":3,"streak":30,"milestone":30,"milestone_name":"30 gün"}`,
+			want:      HabitMilestone{HabitID: 3, Streak: 30, Milestone: 30, MilestoneName: "30 gün"},
+		},
+		{
+			name:      "task created with a title",
+			eventType: TypeTaskCreated,
+			payload:   `{"task_id":4,"title":"Rapor","task":null}`,
+			want:      TaskCreated{TaskID: 4, Title: "Rapor"},
+		},
+		{name: "textual milestone", eventType: TypeHabitMilestone, payload: `{"milestone":"30 gün"}`, wantErr: true},
+		{name: "unknown type", eventType: "habit.unknown", payload: `{}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.eventType, []byte(tt.payload))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Decode = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Decode = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHabitMilestoneWireFormat(t *testing.T) {
+	data, err := json.Marshal(HabitMilestone{HabitID: 1, Streak: 10, Milestone: 10, MilestoneName: "10 gün"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["milestone"].(float64); !ok {
+		t.Fatalf("milestone = %#v, want a number", fields["milestone"])
+	}
+	if fields["milestone_name"] != "10 gün" {
+		t.Fatalf("milestone_name = %#v", fields["milestone_name"])
+	}
+}
@@ -0,0 +1,263 @@
+package events
+
+import "time"
+
+// Domain events are published on the in-process event bus for other modules to react to.
+// They are not in the catalog, so they never reach clients, inboxes or webhooks.
+const (
+	TypeHabitLogged = "habit.logged"
+	TypeUserCreated = "user.created"
+	TypeUserUpdated = "user.updated"
+	TypeUserDeleted = "user.deleted"
+
+	TypeTrashRestored = "trash.restored"
+	TypeTrashPurged   = "trash.purged"
+
+	TypeLoginFailed     = "security.login_failed"
+	TypeAccountLocked   = "security.account_locked"
+	TypeMFAEnabled      = "security.mfa_enabled"
+	TypeMFADisabled     = "security.mfa_disabled"
+	TypePasswordChanged = "security.password_changed"
+	TypePasswordReset   = "security.password_reset"
+	TypeRolesChanged    = "security.roles_changed"
+
+	TypeNoteLinkCreated = "note_link.created"
+	TypeNoteLinkDeleted = "note_link.deleted"
+
+	TypeWorkspaceCreated       = "workspace.created"
+	TypeWorkspaceUpdated       = "workspace.updated"
+	TypeWorkspaceDeleted       = "workspace.deleted"
+	TypeWorkspaceMemberAdded   = "workspace.member_added"
+	TypeWorkspaceMemberUpdated = "workspace.member_updated"
+	TypeWorkspaceMemberRemoved = "workspace.member_removed"
+
+	TypeWebhookCreated       = "webhook.created"
+	TypeWebhookUpdated       = "webhook.updated"
+	TypeWebhookDeleted       = "webhook.deleted"
+	TypeWebhookSecretRotated = "webhook.secret_rotated"
+
+	TypeTokenCreated = "token.created"
+	TypeTokenRevoked = "token.revoked"
+)
+
+// HabitLogged is published whenever a habit is completed or skipped for a day
+type HabitLogged struct {
+	HabitID   int
+	Date      time.Time
+	Completed bool
+	Skipped   bool
+	Streak    int
+}
+
+func (HabitLogged) EventType() string { return TypeHabitLogged }
+
+// User account events, raised by the user module for the audit log
+type UserCreated struct {
+	UserID int         `json:"user_id"`
+	User   interface{} `json:"user"`
+}
+
+type UserUpdated struct {
+	UserID int         `json:"user_id"`
+	User   interface{} `json:"user"`
+}
+
+type UserDeleted struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (UserCreated) EventType() string { return TypeUserCreated }
+func (UserUpdated) EventType() string { return TypeUserUpdated }
+func (UserDeleted) EventType() string { return TypeUserDeleted }
+
+// Trash events, raised when a deleted entity is restored or removed for good
+type TrashRestored struct {
+	Entity   string `json:"entity"`
+	EntityID int    `json:"entity_id"`
+}
+
+type TrashPurged struct {
+	Entity   string `json:"entity"`
+	EntityID int    `json:"entity_id"`
+	Title    string `json:"title"`
+}
+
+func (TrashRestored) EventType() string { return TypeTrashRestored }
+func (TrashPurged) EventType() string   { return TypeTrashPurged }
+
+// Security events, raised by the auth module for the audit log of the targeted account
+type LoginFailed struct {
+	UserID   int `json:"user_id"`
+	Attempts int `json:"attempts"` // Consecutive failures, this one included
+}
+
+type AccountLocked struct {
+	UserID      int       `json:"user_id"`
+	Attempts    int       `json:"attempts"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// MFA and password events, raised by the auth module when the account's credentials change
+type MFAEnabled struct {
+	UserID int `json:"user_id"`
+}
+
+type MFADisabled struct {
+	UserID int `json:"user_id"`
+}
+
+type PasswordChanged struct {
+	UserID int `json:"user_id"`
+}
+
+// PasswordReset is a password set through an emailed reset link
+type PasswordReset struct {
+	UserID int `json:"user_id"`
+}
+
+// RolesChanged is raised by the user module when an admin grants or removes roles, admin included
+type RolesChanged struct {
+	UserID        int      `json:"user_id"`
+	ActorID       int      `json:"actor_id"` // The admin who made the change
+	PreviousRoles []string `json:"previous_roles"`
+	Roles         []string `json:"roles"`
+}
+
+func (LoginFailed) EventType() string     { return TypeLoginFailed }
+func (AccountLocked) EventType() string   { return TypeAccountLocked }
+func (MFAEnabled) EventType() string      { return TypeMFAEnabled }
+func (MFADisabled) EventType() string     { return TypeMFADisabled }
+func (PasswordChanged) EventType() string { return TypePasswordChanged }
+func (PasswordReset) EventType() string   { return TypePasswordReset }
+func (RolesChanged) EventType() string    { return TypeRolesChanged }
+
+// Note link events, raised by the note module for the audit log
+type NoteLinkCreated struct {
+	NoteLinkID int         `json:"note_link_id"`
+	NoteLink   interface{} `json:"note_link"`
+}
+
+type NoteLinkDeleted struct {
+	NoteLinkID   int `json:"note_link_id"`
+	SourceNoteID int `json:"source_note_id"`
+	TargetNoteID int `json:"target_note_id"`
+}
+
+func (NoteLinkCreated) EventType() string { return TypeNoteLinkCreated }
+func (NoteLinkDeleted) EventType() string { return TypeNoteLinkDeleted }
+
+// Workspace events, raised by the workspace module for the audit log. Member events name the
+// member by user ID; Role is empty for a member who was removed.
+type WorkspaceCreated struct {
+	WorkspaceID int         `json:"workspace_id"`
+	Workspace   interface{} `json:"workspace"`
+}
+
+type WorkspaceUpdated struct {
+	WorkspaceID int         `json:"workspace_id"`
+	Workspace   interface{} `json:"workspace"`
+}
+
+type WorkspaceDeleted struct {
+	WorkspaceID int    `json:"workspace_id"`
+	Name        string `json:"name"`
+}
+
+type WorkspaceMemberAdded struct {
+	WorkspaceID int    `json:"workspace_id"`
+	MemberID    int    `json:"member_id"`
+	Role        string `json:"role"`
+}
+
+type WorkspaceMemberUpdated struct {
+	WorkspaceID  int    `json:"workspace_id"`
+	MemberID     int    `json:"member_id"`
+	PreviousRole string `json:"previous_role"`
+	Role         string `json:"role"`
+}
+
+type WorkspaceMemberRemoved struct {
+	WorkspaceID  int    `json:"workspace_id"`
+	MemberID     int    `json:"member_id"`
+	PreviousRole string `json:"previous_role"`
+}
+
+func (WorkspaceCreated) EventType() string       { return TypeWorkspaceCreated }
+func (WorkspaceUpdated) EventType() string       { return TypeWorkspaceUpdated }
+func (WorkspaceDeleted) EventType() string       { return TypeWorkspaceDeleted }
+func (WorkspaceMemberAdded) EventType() string   { return TypeWorkspaceMemberAdded }
+func (WorkspaceMemberUpdated) EventType() string { return TypeWorkspaceMemberUpdated }
+func (WorkspaceMemberRemoved) EventType() string { return TypeWorkspaceMemberRemoved }
+
+// Webhook endpoint events, raised by the webhook module for the audit log. Snapshots never
+// include the signing secret.
+type WebhookCreated struct {
+	WebhookID int         `json:"webhook_id"`
+	Webhook   interface{} `json:"webhook"`
+}
+
+type WebhookUpdated struct {
+	WebhookID int         `json:"webhook_id"`
+	Webhook   interface{} `json:"webhook"`
+}
+
+type WebhookDeleted struct {
+	WebhookID int    `json:"webhook_id"`
+	URL       string `json:"url"`
+}
+
+type WebhookSecretRotated struct {
+	WebhookID int `json:"webhook_id"`
+}
+
+func (WebhookCreated) EventType() string       { return TypeWebhookCreated }
+func (WebhookUpdated) EventType() string       { return TypeWebhookUpdated }
+func (WebhookDeleted) EventType() string       { return TypeWebhookDeleted }
+func (WebhookSecretRotated) EventType() string { return TypeWebhookSecretRotated }
+
+// Personal access token events, raised by the auth module for the audit log. Snapshots hold
+// the token's prefix, never the token.
+type TokenCreated struct {
+	TokenID int         `json:"token_id"`
+	Token   interface{} `json:"token"`
+}
+
+type TokenRevoked struct {
+	TokenID int    `json:"token_id"`
+	Name    string `json:"name"`
+}
+
+func (TokenCreated) EventType() string { return TypeTokenCreated }
+func (TokenRevoked) EventType() string { return TypeTokenRevoked }
+
+// domainEvents lists the payload of every domain event so stored events can be decoded
+var domainEvents = []Event{
+	HabitLogged{},
+	UserCreated{},
+	UserUpdated{},
+	UserDeleted{},
+	TrashRestored{},
+	TrashPurged{},
+	LoginFailed{},
+	AccountLocked{},
+	MFAEnabled{},
+	MFADisabled{},
+	PasswordChanged{},
+	PasswordReset{},
+	RolesChanged{},
+	NoteLinkCreated{},
+	NoteLinkDeleted{},
+	WorkspaceCreated{},
+	WorkspaceUpdated{},
+	WorkspaceDeleted{},
+	WorkspaceMemberAdded{},
+	WorkspaceMemberUpdated{},
+	WorkspaceMemberRemoved{},
+	WebhookCreated{},
+	WebhookUpdated{},
+	WebhookDeleted{},
+	WebhookSecretRotated{},
+	TokenCreated{},
+	TokenRevoked{},
+}
@@ -0,0 +1,464 @@
+package events
+
+import "time"
+
+// Entity snapshots (the module's DTO response) are typed as interface{} so this
+// package does not import module DTOs; they appear as free-form objects in the schema.
+
+// Task payloads
+
+type TaskCreated struct {
+	TaskID int         `json:"task_id"`
+	Title  string      `json:"title,omitempty"`
+	Task   interface{} `json:"task"`
+}
+
+type TaskUpdated struct {
+	TaskID int         `json:"task_id"`
+	Task   interface{} `json:"task"`
+}
+
+type TaskCompleted struct {
+	TaskID        int         `json:"task_id"`
+	Title         string      `json:"title,omitempty"`
+	Task          interface{} `json:"task,omitempty"`
+	AutoCompleted bool        `json:"auto_completed,omitempty"`
+}
+
+type TaskDeleted struct {
+	TaskID int    `json:"task_id"`
+	Title  string `json:"title"`
+}
+
+func (TaskCreated) EventType() string   { return TypeTaskCreated }
+func (TaskUpdated) EventType() string   { return TypeTaskUpdated }
+func (TaskCompleted) EventType() string { return TypeTaskCompleted }
+func (TaskDeleted) EventType() string   { return TypeTaskDeleted }
+
+// Habit payloads
+
+type HabitCreated struct {
+	HabitID int         `json:"habit_id"`
+	Habit   interface{} `json:"habit"`
+}
+
+type HabitUpdated struct {
+	HabitID int         `json:"habit_id"`
+	Habit   interface{} `json:"habit"`
+}
+
+type HabitDeleted struct {
+	HabitID int    `json:"habit_id"`
+	Title   string `json:"title"`
+}
+
+type HabitReminder struct {
+	HabitID      int    `json:"habit_id"`
+	Title        string `json:"title"`
+	ReminderTime string `json:"reminder_time"`
+}
+
+type HabitCompleted struct {
+	HabitID int         `json:"habit_id"`
+	Title   string      `json:"title,omitempty"`
+	Habit   interface{} `json:"habit,omitempty"`
+	Streak  int         `json:"streak"`
+}
+
+type HabitSkipped struct {
+	HabitID int         `json:"habit_id"`
+	Title   string      `json:"title,omitempty"`
+	Habit   interface{} `json:"habit,omitempty"`
+}
+
+type HabitStreakIncreased struct {
+	HabitID int         `json:"habit_id"`
+	Habit   interface{} `json:"habit,omitempty"`
+	Streak  int         `json:"streak"`
+}
+
+type HabitMilestone struct {
+	HabitID int         `json:"habit_id"`
+	Title   string      `json:"title,omitempty"`
+	Habit   interface{} `json:"habit,omitempty"`
+	Streak  int         `json:"streak"`
+	// Milestone is the streak length reached; MilestoneName is its display name
+	Milestone     int    `json:"milestone"`
+	MilestoneName string `json:"milestone_name"`
+}
+
+type HabitStreakBroken struct {
+	HabitID        int    `json:"habit_id"`
+	Title          string `json:"title"`
+	PreviousStreak int    `json:"previous_streak"`
+}
+
+func (HabitCreated) EventType() string         { return TypeHabitCreated }
+func (HabitUpdated) EventType() string         { return TypeHabitUpdated }
+func (HabitDeleted) EventType() string         { return TypeHabitDeleted }
+func (HabitReminder) EventType() string        { return TypeHabitReminder }
+func (HabitCompleted) EventType() string       { return TypeHabitCompleted }
+func (HabitSkipped) EventType() string         { return TypeHabitSkipped }
+func (HabitStreakIncreased) EventType() string { return TypeHabitStreakIncreased }
+func (HabitMilestone) EventType() string       { return TypeHabitMilestone }
+func (HabitStreakBroken) EventType() string    { return TypeHabitStreakBroken }
+
+// Course payloads
+
+type CourseCreated struct {
+	CourseID int         `json:"course_id"`
+	Course   interface{} `json:"course"`
+}
+
+type CourseUpdated struct {
+	CourseID int         `json:"course_id"`
+	Course   interface{} `json:"course"`
+}
+
+type CourseDeleted struct {
+	CourseID int    `json:"course_id"`
+	Name     string `json:"name"`
+}
+
+type ComponentCreated struct {
+	ComponentID int         `json:"component_id"`
+	CourseID    int         `json:"course_id"`
+	Component   interface{} `json:"component"`
+}
+
+type ComponentUpdated struct {
+	ComponentID int         `json:"component_id"`
+	CourseID    int         `json:"course_id"`
+	Component   interface{} `json:"component"`
+}
+
+type ComponentDeleted struct {
+	ComponentID int `json:"component_id"`
+	CourseID    int `json:"course_id"`
+}
+
+type ComponentGraded struct {
+	ComponentID int         `json:"component_id"`
+	CourseID    int         `json:"course_id"`
+	Component   interface{} `json:"component"`
+}
+
+type ScheduleCreated struct {
+	ScheduleID int         `json:"schedule_id"`
+	CourseID   int         `json:"course_id"`
+	Schedule   interface{} `json:"schedule"`
+}
+
+type ScheduleUpdated struct {
+	ScheduleID int         `json:"schedule_id"`
+	CourseID   int         `json:"course_id"`
+	Schedule   interface{} `json:"schedule"`
+}
+
+type ScheduleDeleted struct {
+	ScheduleID int    `json:"schedule_id"`
+	CourseID   int    `json:"course_id"`
+	DayOfWeek  string `json:"day_of_week"`
+}
+
+func (CourseCreated) EventType() string    { return TypeCourseCreated }
+func (CourseUpdated) EventType() string    { return TypeCourseUpdated }
+func (CourseDeleted) EventType() string    { return TypeCourseDeleted }
+func (ComponentCreated) EventType() string { return TypeComponentCreated }
+func (ComponentUpdated) EventType() string { return TypeComponentUpdated }
+func (ComponentDeleted) EventType() string { return TypeComponentDeleted }
+func (ComponentGraded) EventType() string  { return TypeComponentGraded }
+func (ScheduleCreated) EventType() string  { return TypeScheduleCreated }
+func (ScheduleUpdated) EventType() string  { return TypeScheduleUpdated }
+func (ScheduleDeleted) EventType() string  { return TypeScheduleDeleted }
+
+// Calendar payloads
+
+type CalendarSyncStarted struct {
+	Provider string `json:"provider"`
+}
+
+type CalendarSyncProgress struct {
+	Provider string `json:"provider"`
+	Progress int    `json:"progress"`
+}
+
+type CalendarSyncCompleted struct {
+	Provider     string `json:"provider"`
+	EventsSynced int    `json:"events_synced"`
+}
+
+type CalendarSyncFailed struct {
+	Provider string `json:"provider"`
+	Error    string `json:"error"`
+}
+
+type CalendarConflict struct {
+	Reason string    `json:"reason"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+type CalendarEventsGenerated struct {
+	Title         string    `json:"title"`
+	EventsCreated int       `json:"events_created"`
+	SemesterStart time.Time `json:"semester_start"`
+	SemesterEnd   time.Time `json:"semester_end"`
+}
+
+func (CalendarSyncStarted) EventType() string     { return TypeCalendarSyncStarted }
+func (CalendarSyncProgress) EventType() string    { return TypeCalendarSyncProgress }
+func (CalendarSyncCompleted) EventType() string   { return TypeCalendarSyncCompleted }
+func (CalendarSyncFailed) EventType() string      { return TypeCalendarSyncFailed }
+func (CalendarConflict) EventType() string        { return TypeCalendarConflict }
+func (CalendarEventsGenerated) EventType() string { return TypeCalendarEventsGenerated }
+
+// Event module payloads
+
+type EventCreated struct {
+	EventID int         `json:"event_id"`
+	Event   interface{} `json:"event"`
+}
+
+type EventUpdated struct {
+	EventID int         `json:"event_id"`
+	Event   interface{} `json:"event"`
+}
+
+type EventDeleted struct {
+	EventID int    `json:"event_id"`
+	Title   string `json:"title"`
+}
+
+func (EventCreated) EventType() string { return TypeEventCreated }
+func (EventUpdated) EventType() string { return TypeEventUpdated }
+func (EventDeleted) EventType() string { return TypeEventDeleted }
+
+// Goal payloads
+
+type GoalCreated struct {
+	GoalID int         `json:"goal_id"`
+	Goal   interface{} `json:"goal"`
+}
+
+type GoalUpdated struct {
+	GoalID int         `json:"goal_id"`
+	Goal   interface{} `json:"goal"`
+}
+
+type GoalDeleted struct {
+	GoalID int    `json:"goal_id"`
+	Title  string `json:"title"`
+}
+
+type GoalCompleted struct {
+	GoalID int         `json:"goal_id"`
+	Goal   interface{} `json:"goal"`
+}
+
+type GoalMilestoneReached struct {
+	GoalID      int    `json:"goal_id"`
+	MilestoneID int    `json:"milestone_id"`
+	Title       string `json:"title"`
+}
+
+func (GoalCreated) EventType() string          { return TypeGoalCreated }
+func (GoalUpdated) EventType() string          { return TypeGoalUpdated }
+func (GoalDeleted) EventType() string          { return TypeGoalDeleted }
+func (GoalCompleted) EventType() string        { return TypeGoalCompleted }
+func (GoalMilestoneReached) EventType() string { return TypeGoalMilestoneReached }
+
+// Note payloads
+
+type NoteCreated struct {
+	NoteID int         `json:"note_id"`
+	Note   interface{} `json:"note"`
+}
+
+type NoteUpdated struct {
+	NoteID int         `json:"note_id"`
+	Note   interface{} `json:"note"`
+}
+
+type NoteDeleted struct {
+	NoteID int    `json:"note_id"`
+	Title  string `json:"title"`
+}
+
+func (NoteCreated) EventType() string { return TypeNoteCreated }
+func (NoteUpdated) EventType() string { return TypeNoteUpdated }
+func (NoteDeleted) EventType() string { return TypeNoteDeleted }
+
+// LifeArea payloads
+
+type LifeAreaCreated struct {
+	LifeAreaID int         `json:"lifearea_id"`
+	LifeArea   interface{} `json:"lifearea"`
+}
+
+type LifeAreaUpdated struct {
+	LifeAreaID int         `json:"lifearea_id"`
+	LifeArea   interface{} `json:"lifearea"`
+}
+
+type LifeAreaDeleted struct {
+	LifeAreaID int    `json:"lifearea_id"`
+	Name       string `json:"name"`
+}
+
+func (LifeAreaCreated) EventType() string { return TypeLifeAreaCreated }
+func (LifeAreaUpdated) EventType() string { return TypeLifeAreaUpdated }
+func (LifeAreaDeleted) EventType() string { return TypeLifeAreaDeleted }
+
+// People payloads
+
+type PersonCreated struct {
+	PersonID int         `json:"person_id"`
+	Person   interface{} `json:"person"`
+}
+
+type PersonUpdated struct {
+	PersonID int         `json:"person_id"`
+	Person   interface{} `json:"person"`
+}
+
+type PersonDeleted struct {
+	PersonID int    `json:"person_id"`
+	Name     string `json:"name"`
+}
+
+func (PersonCreated) EventType() string { return TypePersonCreated }
+func (PersonUpdated) EventType() string { return TypePersonUpdated }
+func (PersonDeleted) EventType() string { return TypePersonDeleted }
+
+// Journal payloads
+
+type JournalCreated struct {
+	JournalID int         `json:"journal_id"`
+	Journal   interface{} `json:"journal"`
+}
+
+type JournalUpdated struct {
+	JournalID int         `json:"journal_id"`
+	Journal   interface{} `json:"journal"`
+}
+
+type JournalDeleted struct {
+	JournalID int `json:"journal_id"`
+}
+
+func (JournalCreated) EventType() string { return TypeJournalCreated }
+func (JournalUpdated) EventType() string { return TypeJournalUpdated }
+func (JournalDeleted) EventType() string { return TypeJournalDeleted }
+
+// Finance payloads
+
+type TransactionCreated struct {
+	TransactionID int         `json:"transaction_id"`
+	Transaction   interface{} `json:"transaction"`
+}
+
+type TransactionUpdated struct {
+	TransactionID int         `json:"transaction_id"`
+	Transaction   interface{} `json:"transaction"`
+}
+
+type TransactionDeleted struct {
+	TransactionID int `json:"transaction_id"`
+}
+
+func (TransactionCreated) EventType() string { return TypeTransactionCreated }
+func (TransactionUpdated) EventType() string { return TypeTransactionUpdated }
+func (TransactionDeleted) EventType() string { return TypeTransactionDeleted }
+
+// Job payloads
+
+type JobStarted struct {
+	JobName string `json:"job_name"`
+	Status  string `json:"status"`
+}
+
+type JobProgress struct {
+	JobName  string  `json:"job_name"`
+	Progress float64 `json:"progress"`
+	Message  string  `json:"message,omitempty"`
+}
+
+type JobCompleted struct {
+	JobName string      `json:"job_name"`
+	Status  string      `json:"status"`
+	Result  interface{} `json:"result,omitempty"`
+}
+
+type JobFailed struct {
+	JobName string `json:"job_name"`
+	Status  string `json:"status"`
+	Error   string `json:"error"`
+}
+
+func (JobStarted) EventType() string   { return TypeJobStarted }
+func (JobProgress) EventType() string  { return TypeJobProgress }
+func (JobCompleted) EventType() string { return TypeJobCompleted }
+func (JobFailed) EventType() string    { return TypeJobFailed }
+
+// Notification inbox payloads
+
+type NotificationUnreadCount struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+// NotificationBatchItem is one deferred message inside a batch; Payload matches that type's own schema
+type NotificationBatchItem struct {
+	Type      string      `json:"type"`
+	Version   int         `json:"version"`
+	Payload   interface{} `json:"payload"`
+	Timestamp time.Time   `json:"timestamp"`
+	MessageID string      `json:"message_id,omitempty"`
+}
+
+type NotificationBatch struct {
+	Reason string                  `json:"reason"`
+	Items  []NotificationBatchItem `json:"items"`
+}
+
+func (NotificationUnreadCount) EventType() string { return TypeNotificationUnreadCount }
+func (NotificationBatch) EventType() string       { return TypeNotificationBatch }
+
+// Digest payloads
+
+type DigestDaily struct {
+	Date   string      `json:"date"`
+	Digest interface{} `json:"digest"`
+}
+
+type DigestWeekly struct {
+	Date   string      `json:"date"`
+	Digest interface{} `json:"digest"`
+}
+
+func (DigestDaily) EventType() string  { return TypeDigestDaily }
+func (DigestWeekly) EventType() string { return TypeDigestWeekly }
+
+// System payloads
+
+type Connected struct {
+	Message   string    `json:"message"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+type AuthExpiring struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type DeviceSync struct {
+	Reason string `json:"reason"`
+}
+
+func (Connected) EventType() string    { return TypeConnected }
+func (Error) EventType() string        { return TypeError }
+func (AuthExpiring) EventType() string { return TypeAuthExpiring }
+func (DeviceSync) EventType() string   { return TypeDeviceSync }
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaBundle builds a JSON Schema (draft 2020-12) describing every message
+// clients can receive. Each definition is the full envelope keyed as
+// "<type>.v<version>", and the root schema is a oneOf over all of them.
+func SchemaBundle() map[string]interface{} {
+	defs := make(map[string]interface{}, len(catalog))
+	oneOf := make([]interface{}, 0, len(catalog))
+
+	for _, def := range All() {
+		key := fmt.Sprintf("%s.v%d", def.Type, def.Version)
+		defs[key] = envelopeSchema(def)
+		oneOf = append(oneOf, map[string]interface{}{"$ref": "#/$defs/" + key})
+	}
+
+	return map[string]interface{}{
+		"$schema": schemaDialect,
+		"$id":     "urn:events:bundle",
+		"title":   "Realtime event messages",
+		"oneOf":   oneOf,
+		"$defs":   defs,
+	}
+}
+
+// envelopeSchema describes the message wrapper sent over WebSocket and SSE
+func envelopeSchema(def Definition) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": def.Description,
+		"properties": map[string]interface{}{
+			"type":       map[string]interface{}{"const": def.Type},
+			"version":    map[string]interface{}{"const": def.Version},
+			"payload":    typeSchema(reflect.TypeOf(def.Payload)),
+			"timestamp":  map[string]interface{}{"type": "string", "format": "date-time"},
+			"message_id": map[string]interface{}{"type": "string"},
+		},
+		"required": []string{"type", "version", "payload", "timestamp"},
+	}
+}
+
+// typeSchema maps a Go type to a JSON Schema fragment following encoding/json rules
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		// interface{} fields carry module DTO snapshots
+		return map[string]interface{}{}
+	}
+}
+
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = typeSchema(field.Type)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
@@ -0,0 +1,147 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// PageInfo is embedded in list responses next to the items
+type PageInfo struct {
+	Total int `json:"total"`
+	Limit int `json:"limit"`
+	// Page is omitted when the request continued from a cursor
+	Page       int    `json:"page,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// cursor is the position after the last item of a page, bound to the sort it was made for
+type cursor struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// Result drops the extra row Paginate fetched and describes the page. total is the row
+// count for the clause returned by Where
+func (q *Query[T]) Result(items []*T, total int) ([]*T, PageInfo) {
+	info := PageInfo{Total: total, Limit: q.Limit, Page: q.Page}
+	if len(items) <= q.Limit {
+		return items, info
+	}
+	items = items[:q.Limit]
+	info.HasMore = true
+
+	last := items[len(items)-1]
+	values := make([]any, len(q.orders))
+	for i, o := range q.orders {
+		if o.key == "" {
+			values[i] = q.spec.ID(last)
+			continue
+		}
+		values[i] = plain(q.spec.Sorts[o.key].Value(last))
+	}
+	encoded, err := json.Marshal(cursor{Sort: q.sort, Values: values})
+	if err == nil {
+		info.NextCursor = base64.RawURLEncoding.EncodeToString(encoded)
+	}
+	return items, info
+}
+
+// plain dereferences the pointers nullable fields are kept in
+func plain(value any) any {
+	switch v := value.(type) {
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format(time.RFC3339Nano)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *int:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *float64:
+		if v == nil {
+			return nil
+		}
+		return *v
+	default:
+		return v
+	}
+}
+
+func decodeCursor(raw, sort string, orders []order) ([]any, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	dec := json.NewDecoder(strings.NewReader(string(decoded)))
+	dec.UseNumber()
+	var c cursor
+	if err := dec.Decode(&c); err != nil || len(c.Values) != len(orders) {
+		return nil, errInvalidCursor
+	}
+	if c.Sort != sort {
+		return nil, errors.New("cursor was made for a different sort")
+	}
+
+	values := make([]any, len(orders))
+	for i, o := range orders {
+		if c.Values[i] == nil {
+			if !o.nullable {
+				return nil, errInvalidCursor
+			}
+			continue
+		}
+		value, ok := cursorValue(o.kind, c.Values[i])
+		if !ok {
+			return nil, errInvalidCursor
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func cursorValue(kind Kind, raw any) (any, bool) {
+	switch kind {
+	case Int:
+		n, ok := raw.(json.Number)
+		if !ok {
+			return nil, false
+		}
+		v, err := n.Int64()
+		return int(v), err == nil
+	case Float:
+		n, ok := raw.(json.Number)
+		if !ok {
+			return nil, false
+		}
+		v, err := n.Float64()
+		return v, err == nil
+	case Bool:
+		v, ok := raw.(bool)
+		return v, ok
+	case Time:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, false
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return t, err == nil
+	default:
+		v, ok := raw.(string)
+		return v, ok
+	}
+}
@@ -0,0 +1,268 @@
+// Package query turns the query string of a list endpoint (filters, sorting, offset or
+// cursor pagination) into SQL, accepting only what the endpoint's Spec whitelists
+package query
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// Kind is the type a query-string value is parsed into
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+// Op is how a filter compares its column with the value
+type Op int
+
+const (
+	Eq       Op = iota // column = value; comma separated strings match any of them
+	Lt                 // column < value
+	Lte                // column <= value
+	Gte                // column >= value
+	Contains           // column ILIKE %value%, with the value's wildcards matched literally
+	Has                // value = ANY(column), for array columns
+	Present            // true: column IS NOT NULL, false: column IS NULL
+)
+
+// Filter whitelists one query parameter
+type Filter struct {
+	Column string
+	Op     Op
+	Kind   Kind
+	// Values restricts what the parameter accepts; empty accepts anything of the kind
+	Values []string
+}
+
+// Sort whitelists one sort key. Value reads the key from an item so that the last item
+// of a page can become the cursor of the next one
+type Sort[T any] struct {
+	Column   string
+	Kind     Kind
+	Nullable bool
+	Value    func(*T) any
+}
+
+// Spec is the whitelist of one list endpoint. Rows are always ordered by id last so
+// that pages are stable when sort keys repeat
+type Spec[T any] struct {
+	Filters map[string]Filter
+	Sorts   map[string]Sort[T]
+	// DefaultSort is used without a sort parameter, e.g. "-created_at"
+	DefaultSort string
+	ID          func(*T) int
+}
+
+// reserved parameters control pagination and sorting and are never filters
+var reserved = []string{"page", "limit", "cursor", "sort"}
+
+// likeEscaper escapes the LIKE wildcards of a value, with \ as the ESCAPE character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type condition struct {
+	expr string // holds one %d for the argument index unless arg is nil
+	arg  any
+}
+
+type order struct {
+	key      string // empty for the id tiebreaker
+	column   string
+	kind     Kind
+	nullable bool
+	desc     bool
+}
+
+// Query is a parsed list request. Page is 0 when the request continues from a cursor
+type Query[T any] struct {
+	spec       *Spec[T]
+	conditions []condition
+	orders     []order
+	sort       string
+	after      []any
+	Limit      int
+	Page       int
+}
+
+// Parse validates the query string against the spec. Parameters the spec does not know
+// are ignored, e.g. cache busters or those the handler reads itself; unknown sort keys and
+// invalid filter values are errors
+func (s *Spec[T]) Parse(values url.Values) (*Query[T], error) {
+	q := &Query[T]{spec: s, Limit: DefaultLimit, Page: 1}
+
+	if raw := values.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		q.Limit = n
+	}
+	if raw := values.Get("page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return nil, errors.New("page must be a positive number")
+		}
+		q.Page = n
+	}
+
+	sort := values.Get("sort")
+	if sort == "" {
+		sort = s.DefaultSort
+	}
+	if err := q.parseSort(sort); err != nil {
+		return nil, err
+	}
+
+	if raw := values.Get("cursor"); raw != "" {
+		if values.Has("page") {
+			return nil, errors.New("page and cursor cannot be used together")
+		}
+		after, err := decodeCursor(raw, q.sort, q.orders)
+		if err != nil {
+			return nil, err
+		}
+		q.after = after
+		q.Page = 0
+	}
+
+	// Sorted so that the same query string always binds its arguments in the same order
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if slices.Contains(reserved, name) {
+			continue
+		}
+		filter, ok := s.Filters[name]
+		if !ok {
+			continue
+		}
+		cond, err := filter.parse(values.Get(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+		q.conditions = append(q.conditions, cond)
+	}
+	return q, nil
+}
+
+func (q *Query[T]) parseSort(raw string) error {
+	var keys []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, desc := strings.CutPrefix(part, "-")
+		sort, ok := q.spec.Sorts[key]
+		if !ok {
+			return fmt.Errorf("unknown sort key: %s", key)
+		}
+		if slices.ContainsFunc(q.orders, func(o order) bool { return o.key == key }) {
+			return fmt.Errorf("duplicate sort key: %s", key)
+		}
+		q.orders = append(q.orders, order{key: key, column: sort.Column, kind: sort.Kind, nullable: sort.Nullable, desc: desc})
+		keys = append(keys, part)
+	}
+
+	desc := len(q.orders) > 0 && q.orders[len(q.orders)-1].desc
+	q.orders = append(q.orders, order{column: "id", kind: Int, desc: desc})
+	q.sort = strings.Join(keys, ",")
+	return nil
+}
+
+func (f Filter) parse(raw string) (condition, error) {
+	switch f.Op {
+	case Present:
+		present, err := strconv.ParseBool(raw)
+		if err != nil {
+			return condition{}, errors.New("expected true or false")
+		}
+		if present {
+			return condition{expr: f.Column + " IS NOT NULL"}, nil
+		}
+		return condition{expr: f.Column + " IS NULL"}, nil
+	case Contains:
+		return condition{expr: f.Column + ` ILIKE '%%' || $%d || '%%' ESCAPE '\'`, arg: likeEscaper.Replace(raw)}, nil
+	}
+
+	if f.Op == Eq && f.Kind == String {
+		parts := strings.Split(raw, ",")
+		for i, part := range parts {
+			parts[i] = strings.TrimSpace(part)
+			if len(f.Values) > 0 && !slices.Contains(f.Values, parts[i]) {
+				return condition{}, fmt.Errorf("expected one of %s", strings.Join(f.Values, ", "))
+			}
+		}
+		if len(parts) > 1 {
+			return condition{expr: f.Column + " = ANY($%d)", arg: pq.Array(parts)}, nil
+		}
+		return condition{expr: f.Column + " = $%d", arg: parts[0]}, nil
+	}
+
+	value, err := parseValue(f.Kind, raw)
+	if err != nil {
+		return condition{}, err
+	}
+	switch f.Op {
+	case Lt:
+		return condition{expr: f.Column + " < $%d", arg: value}, nil
+	case Lte:
+		return condition{expr: f.Column + " <= $%d", arg: value}, nil
+	case Gte:
+		return condition{expr: f.Column + " >= $%d", arg: value}, nil
+	case Has:
+		return condition{expr: "$%d = ANY(" + f.Column + ")", arg: value}, nil
+	default:
+		return condition{expr: f.Column + " = $%d", arg: value}, nil
+	}
+}
+
+func parseValue(kind Kind, raw string) (any, error) {
+	switch kind {
+	case Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errors.New("expected a whole number")
+		}
+		return n, nil
+	case Float:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, errors.New("expected a number")
+		}
+		return n, nil
+	case Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("expected true or false")
+		}
+		return b, nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, errors.New("expected an RFC 3339 time or a YYYY-MM-DD date")
+		}
+		return t, nil
+	default:
+		return raw, nil
+	}
+}
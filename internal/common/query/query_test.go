@@ -0,0 +1,200 @@
+package query
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type item struct {
+	ID    int
+	Title string
+	Due   *time.Time
+}
+
+var testSpec = &Spec[item]{
+	Filters: map[string]Filter{
+		"status":     {Column: "status", Op: Eq, Values: []string{"open", "done"}},
+		"owner_id":   {Column: "owner_id", Op: Eq, Kind: Int},
+		"due_before": {Column: "due", Op: Lt, Kind: Time},
+		"has_due":    {Column: "due", Op: Present},
+		"q":          {Column: "title", Op: Contains},
+	},
+	Sorts: map[string]Sort[item]{
+		"title": {Column: "title", Value: func(i *item) any { return i.Title }},
+		"due":   {Column: "due", Kind: Time, Nullable: true, Value: func(i *item) any { return i.Due }},
+	},
+	DefaultSort: "-title",
+	ID:          func(i *item) int { return i.ID },
+}
+
+func parse(t *testing.T, raw string) (*Query[item], error) {
+	t.Helper()
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testSpec.Parse(values)
+}
+
+func TestParseWhere(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantWhere string
+		wantArgs  []any
+		wantErr   bool
+	}{
+		{name: "no filters", query: "", wantWhere: "user_id = $1", wantArgs: []any{1}},
+		{name: "single value", query: "status=open", wantWhere: "user_id = $1 AND status = $2", wantArgs: []any{1, "open"}},
+		{name: "value list", query: "status=open,done", wantWhere: "user_id = $1 AND status = ANY($2)", wantArgs: []any{1, pq.Array([]string{"open", "done"})}},
+		{name: "value outside the whitelist", query: "status=archived", wantErr: true},
+		{name: "typed value", query: "owner_id=4", wantWhere: "user_id = $1 AND owner_id = $2", wantArgs: []any{1, 4}},
+		{name: "invalid typed value", query: "owner_id=four", wantErr: true},
+		{name: "date", query: "due_before=2026-11-01", wantWhere: "user_id = $1 AND due < $2", wantArgs: []any{1, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}},
+		{name: "invalid date", query: "due_before=tomorrow", wantErr: true},
+		{name: "presence", query: "has_due=false", wantWhere: "user_id = $1 AND due IS NULL", wantArgs: []any{1}},
+		{name: "invalid presence", query: "has_due=maybe", wantErr: true},
+		{name: "contains", query: "q=plan", wantWhere: `user_id = $1 AND title ILIKE '%' || $2 || '%' ESCAPE '\'`, wantArgs: []any{1, "plan"}},
+		{name: "contains escapes wildcards", query: `q=50%25_off\`, wantWhere: `user_id = $1 AND title ILIKE '%' || $2 || '%' ESCAPE '\'`, wantArgs: []any{1, `50\%\_off\\`}},
+		{name: "filters bind in name order", query: "status=done&owner_id=2", wantWhere: "user_id = $1 AND owner_id = $2 AND status = $3", wantArgs: []any{1, 2, "done"}},
+		{name: "unknown parameters are ignored", query: "_=1700000000&start=2026-01-01", wantWhere: "user_id = $1", wantArgs: []any{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := parse(t, tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Parse accepted the query")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			where, args := q.Where("user_id = $1", 1)
+			if where != tt.wantWhere {
+				t.Errorf("where = %s, want %s", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantOrder string
+		wantLimit int
+		wantPage  int
+		wantErr   bool
+	}{
+		{name: "defaults", query: "", wantOrder: "title DESC, id DESC", wantLimit: DefaultLimit, wantPage: 1},
+		{name: "sort keys", query: "sort=due,-title", wantOrder: "due NULLS LAST, title DESC, id DESC", wantLimit: DefaultLimit, wantPage: 1},
+		{name: "unknown sort key", query: "sort=priority", wantErr: true},
+		{name: "duplicate sort key", query: "sort=title,-title", wantErr: true},
+		{name: "page and limit", query: "page=3&limit=10", wantOrder: "title DESC, id DESC", wantLimit: 10, wantPage: 3},
+		{name: "limit above the maximum", query: "limit=201", wantErr: true},
+		{name: "zero limit", query: "limit=0", wantErr: true},
+		{name: "zero page", query: "page=0", wantErr: true},
+		{name: "page and cursor", query: "page=2&cursor=abc", wantErr: true},
+		{name: "malformed cursor", query: "cursor=not-base64!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := parse(t, tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Parse accepted the query")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if q.Limit != tt.wantLimit || q.Page != tt.wantPage {
+				t.Errorf("limit, page = %d, %d, want %d, %d", q.Limit, q.Page, tt.wantLimit, tt.wantPage)
+			}
+			sql, _ := q.Paginate("", nil)
+			if want := " ORDER BY " + tt.wantOrder; !strings.HasPrefix(sql, want) {
+				t.Errorf("sql = %s, want it to start with %s", sql, want)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	due := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		sort      string
+		last      item
+		nextSort  string
+		wantWhere string
+		wantArgs  []any
+		wantErr   bool
+	}{
+		{
+			name: "continues after the last item", sort: "title", last: item{ID: 2, Title: "b"},
+			nextSort:  "title",
+			wantWhere: " AND ((title > $1) OR (title = $1 AND id > $2))",
+			wantArgs:  []any{"b", 2},
+		},
+		{
+			name: "null sort value continues among the nulls", sort: "due", last: item{ID: 2},
+			nextSort:  "due",
+			wantWhere: " AND ((due IS NULL AND id > $1))",
+			wantArgs:  []any{2},
+		},
+		{
+			name: "nullable value also matches the nulls after it", sort: "-due", last: item{ID: 2, Due: &due},
+			nextSort:  "-due",
+			wantWhere: " AND (((due < $1 OR due IS NULL)) OR (due = $1 AND id < $2))",
+			wantArgs:  []any{due, 2},
+		},
+		{name: "cursor of another sort", sort: "title", last: item{ID: 2, Title: "b"}, nextSort: "-title", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := testSpec.Parse(url.Values{"sort": {tt.sort}, "limit": {"1"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			items, info := q.Result([]*item{&tt.last, {ID: 3}}, 5)
+			if len(items) != 1 || !info.HasMore || info.NextCursor == "" {
+				t.Fatalf("Result = %d items, %+v, want one item and a cursor", len(items), info)
+			}
+
+			next, err := testSpec.Parse(url.Values{"sort": {tt.nextSort}, "limit": {"1"}, "cursor": {info.NextCursor}})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Parse accepted the cursor")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if next.Page != 0 {
+				t.Errorf("page = %d, want 0 after a cursor", next.Page)
+			}
+			sql, args := next.Paginate("", nil)
+			if !strings.HasPrefix(sql, tt.wantWhere) {
+				t.Errorf("sql = %s, want it to start with %s", sql, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args[:len(args)-1], tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v and the limit", args, tt.wantArgs)
+			}
+		})
+	}
+}
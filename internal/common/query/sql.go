@@ -0,0 +1,77 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Where appends the filters to base, a WHERE clause whose placeholders are bound by args.
+// Counting rows with the result gives the total for PageInfo; Paginate takes it from there
+func (q *Query[T]) Where(base string, args ...any) (string, []any) {
+	var b strings.Builder
+	b.WriteString(base)
+	for _, c := range q.conditions {
+		b.WriteString(" AND ")
+		if c.arg == nil {
+			b.WriteString(c.expr)
+			continue
+		}
+		args = append(args, c.arg)
+		fmt.Fprintf(&b, c.expr, len(args))
+	}
+	return b.String(), args
+}
+
+// Paginate extends the clause from Where with the cursor position, the order and the page
+// window. It fetches one row more than the limit so that Result can tell if a page follows
+func (q *Query[T]) Paginate(where string, args []any) (string, []any) {
+	var b strings.Builder
+	b.WriteString(where)
+
+	if q.after != nil {
+		var alternatives []string
+		var equal []string
+		for i, o := range q.orders {
+			value := q.after[i]
+			// Nothing sorts after NULL: NULLs are always last, whatever the direction
+			if value != nil {
+				args = append(args, value)
+				comparison := fmt.Sprintf("%s > $%d", o.column, len(args))
+				if o.desc {
+					comparison = fmt.Sprintf("%s < $%d", o.column, len(args))
+				}
+				if o.nullable {
+					comparison = fmt.Sprintf("(%s OR %s IS NULL)", comparison, o.column)
+				}
+				alternatives = append(alternatives, "("+strings.Join(append(equal, comparison), " AND ")+")")
+				equal = append(equal, fmt.Sprintf("%s = $%d", o.column, len(args)))
+			} else {
+				equal = append(equal, o.column+" IS NULL")
+			}
+		}
+		if len(alternatives) == 0 {
+			alternatives = []string{"FALSE"}
+		}
+		b.WriteString(" AND (" + strings.Join(alternatives, " OR ") + ")")
+	}
+
+	orders := make([]string, len(q.orders))
+	for i, o := range q.orders {
+		orders[i] = o.column
+		if o.desc {
+			orders[i] += " DESC"
+		}
+		if o.nullable {
+			orders[i] += " NULLS LAST"
+		}
+	}
+	b.WriteString(" ORDER BY " + strings.Join(orders, ", "))
+
+	args = append(args, q.Limit+1)
+	fmt.Fprintf(&b, " LIMIT $%d", len(args))
+	if q.Page > 1 {
+		args = append(args, (q.Page-1)*q.Limit)
+		fmt.Fprintf(&b, " OFFSET $%d", len(args))
+	}
+	return b.String(), args
+}
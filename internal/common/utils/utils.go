@@ -13,8 +13,17 @@ import (
 type ctxKey string
 
 const RoleKey ctxKey = "role"
+const RolesKey ctxKey = "roles"
+const PermissionsKey ctxKey = "permissions"
 const UsernameKey ctxKey = "username"
 const UserIDKey ctxKey = "user_id"
+const TokenExpiresAtKey ctxKey = "token_expires_at"
+const TokenIDKey ctxKey = "token_id"
+const SessionIDKey ctxKey = "session_id"
+const ScopesKey ctxKey = "scopes"
+const RequestIDKey ctxKey = "request_id"
+const ClientIPKey ctxKey = "client_ip"
+const UserAgentKey ctxKey = "user_agent"
 
 func ReadJson[T any](r *http.Request, validate *validator.Validate) (T, error) {
 	var res T
@@ -90,6 +99,51 @@ func GetUserIDFromContext(ctx interface{}) int {
 	return 0
 }
 
+func GetTokenIDFromContext(ctx interface{}) string {
+	if c, ok := ctx.(interface{ Value(any) any }); ok {
+		if id, ok := c.Value(TokenIDKey).(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+func GetSessionIDFromContext(ctx interface{}) string {
+	if c, ok := ctx.(interface{ Value(any) any }); ok {
+		if id, ok := c.Value(SessionIDKey).(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+func GetRequestIDFromContext(ctx interface{}) string {
+	if c, ok := ctx.(interface{ Value(any) any }); ok {
+		if id, ok := c.Value(RequestIDKey).(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+func GetClientIPFromContext(ctx interface{}) string {
+	if c, ok := ctx.(interface{ Value(any) any }); ok {
+		if ip, ok := c.Value(ClientIPKey).(string); ok {
+			return ip
+		}
+	}
+	return ""
+}
+
+func GetUserAgentFromContext(ctx interface{}) string {
+	if c, ok := ctx.(interface{ Value(any) any }); ok {
+		if ua, ok := c.Value(UserAgentKey).(string); ok {
+			return ua
+		}
+	}
+	return ""
+}
+
 func ReturnError(w http.ResponseWriter, code, message, details string) {
 	var status int
 	switch code {
@@ -101,6 +155,8 @@ func ReturnError(w http.ResponseWriter, code, message, details string) {
 		status = http.StatusForbidden
 	case "NOT_FOUND":
 		status = http.StatusNotFound
+	case "TOO_MANY_REQUESTS":
+		status = http.StatusTooManyRequests
 	default:
 		status = http.StatusInternalServerError
 	}
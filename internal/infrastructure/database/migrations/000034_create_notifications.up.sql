@@ -0,0 +1,15 @@
+CREATE TABLE notifications (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  type VARCHAR(100) NOT NULL,
+  version INTEGER NOT NULL DEFAULT 1,
+  payload JSONB NOT NULL DEFAULT '{}',
+  message_id VARCHAR(64),
+  is_read BOOLEAN NOT NULL DEFAULT FALSE,
+  read_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_user_type ON notifications(user_id, type);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE is_read = FALSE;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS deferred_notifications CASCADE;
+DROP TABLE IF EXISTS notification_quiet_hours CASCADE;
+DROP TABLE IF EXISTS notification_preferences CASCADE;
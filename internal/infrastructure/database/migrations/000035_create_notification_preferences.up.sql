@@ -0,0 +1,37 @@
+-- Per-user, per-event delivery preferences. event_type '*' is the user's default for all events.
+CREATE TABLE notification_preferences (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  event_type VARCHAR(100) NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  in_app BOOLEAN NOT NULL DEFAULT TRUE,
+  websocket BOOLEAN NOT NULL DEFAULT TRUE,
+  email BOOLEAN NOT NULL DEFAULT FALSE,
+  webhook BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  UNIQUE (user_id, event_type)
+);
+
+-- Quiet hours are interpreted in users.timezone
+CREATE TABLE notification_quiet_hours (
+  user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  start_time TIME NOT NULL DEFAULT '22:00',
+  end_time TIME NOT NULL DEFAULT '07:00',
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Live notifications held back during quiet hours, delivered as a batch afterwards
+CREATE TABLE deferred_notifications (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  type VARCHAR(100) NOT NULL,
+  version INTEGER NOT NULL DEFAULT 1,
+  payload JSONB NOT NULL DEFAULT '{}',
+  message_id VARCHAR(64),
+  deliver_after TIMESTAMP NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_deferred_notifications_due ON deferred_notifications(deliver_after);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS email_outbox;
+
+ALTER TABLE users DROP COLUMN IF EXISTS language;
@@ -0,0 +1,38 @@
+CREATE TABLE webhook_endpoints (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  url VARCHAR(2000) NOT NULL,
+  description VARCHAR(255),
+  secret VARCHAR(100) NOT NULL,
+  -- Catalog event types, or '*' for every event
+  event_types TEXT[] NOT NULL DEFAULT '{}',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhook_endpoints_user ON webhook_endpoints(user_id);
+
+-- One row per delivery; attempts and the last response are updated in place
+CREATE TABLE webhook_deliveries (
+  id SERIAL PRIMARY KEY,
+  endpoint_id INTEGER NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  event_type VARCHAR(100) NOT NULL,
+  message_id VARCHAR(64),
+  payload JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL DEFAULT 8,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  response_status INTEGER,
+  response_body TEXT,
+  last_error TEXT,
+  duration_ms INTEGER,
+  delivered_at TIMESTAMP,
+  redelivery_of INTEGER REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
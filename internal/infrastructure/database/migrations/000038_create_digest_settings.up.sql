@@ -0,0 +1,16 @@
+-- Opt-in daily and weekly digests. send_time and weekly_day are interpreted in users.timezone;
+-- weekly_day follows Go's time.Weekday (0 = Sunday). last_*_on hold the user's local date of the
+-- last digest so each one is sent once per period.
+CREATE TABLE digest_settings (
+  user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  daily_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  weekly_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  send_time TIME NOT NULL DEFAULT '07:00',
+  weekly_day SMALLINT NOT NULL DEFAULT 1 CHECK (weekly_day BETWEEN 0 AND 6),
+  email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  last_daily_on DATE,
+  last_weekly_on DATE,
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_digest_settings_enabled ON digest_settings(user_id) WHERE daily_enabled OR weekly_enabled;
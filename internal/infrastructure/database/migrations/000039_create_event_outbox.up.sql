@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS event_outbox (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  type VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL,
+  occurred_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL DEFAULT 5,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  published_at TIMESTAMP,
+  last_error TEXT
+);
+
+CREATE INDEX idx_event_outbox_due ON event_outbox(next_attempt_at, id) WHERE status IN ('pending', 'dispatching');
+CREATE INDEX idx_event_outbox_published ON event_outbox(published_at) WHERE status = 'published';
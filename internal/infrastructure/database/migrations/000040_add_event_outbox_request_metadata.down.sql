@@ -0,0 +1,4 @@
+ALTER TABLE event_outbox
+  DROP COLUMN IF EXISTS ip,
+  DROP COLUMN IF EXISTS request_id,
+  DROP COLUMN IF EXISTS actor_id;
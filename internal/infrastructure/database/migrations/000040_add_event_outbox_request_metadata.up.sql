@@ -0,0 +1,4 @@
+ALTER TABLE event_outbox
+  ADD COLUMN IF NOT EXISTS actor_id INTEGER,
+  ADD COLUMN IF NOT EXISTS request_id VARCHAR(64),
+  ADD COLUMN IF NOT EXISTS ip VARCHAR(64);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  actor_id INTEGER,
+  entity_type VARCHAR(50) NOT NULL,
+  entity_id INTEGER NOT NULL,
+  operation VARCHAR(20) NOT NULL,
+  changes JSONB NOT NULL DEFAULT '[]',
+  before_state JSONB,
+  after_state JSONB,
+  event_type VARCHAR(100) NOT NULL,
+  event_id BIGINT,
+  request_id VARCHAR(64),
+  ip VARCHAR(64),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, id);
+CREATE INDEX idx_audit_log_user ON audit_log(user_id, id DESC);
+CREATE UNIQUE INDEX idx_audit_log_event ON audit_log(event_id) WHERE event_id IS NOT NULL;
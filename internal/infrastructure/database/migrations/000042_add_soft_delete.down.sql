@@ -0,0 +1,56 @@
+-- Rows still in the trash count as deleted once soft delete is gone
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DELETE FROM habits WHERE deleted_at IS NOT NULL;
+DELETE FROM courses WHERE deleted_at IS NOT NULL;
+DELETE FROM goals WHERE deleted_at IS NOT NULL;
+DELETE FROM notes WHERE deleted_at IS NOT NULL;
+DELETE FROM life_areas WHERE deleted_at IS NOT NULL;
+DELETE FROM people WHERE deleted_at IS NOT NULL;
+DELETE FROM journal_entries WHERE deleted_at IS NOT NULL;
+DELETE FROM finance_transactions WHERE deleted_at IS NOT NULL;
+DELETE FROM events WHERE deleted_at IS NOT NULL;
+
+CREATE OR REPLACE VIEW course_grades_view AS
+SELECT
+  c.id as course_id,
+  c.name as course_name,
+  c.user_id,
+  COUNT(cc.id) FILTER (WHERE cc.is_completed = TRUE) as completed_components,
+  COUNT(cc.id) as total_components,
+  SUM(cc.achieved_score * cc.weight / NULLIF(cc.max_score, 0)) /
+    NULLIF(SUM(cc.weight) FILTER (WHERE cc.is_completed = TRUE), 0) as current_grade,
+  SUM(cc.weight) as total_weight,
+  SUM(cc.weight) FILTER (WHERE cc.is_completed = TRUE) as completed_weight
+FROM courses c
+LEFT JOIN course_components cc ON cc.course_id = c.id
+GROUP BY c.id, c.name, c.user_id;
+
+CREATE OR REPLACE VIEW upcoming_course_deadlines AS
+SELECT
+  c.id as course_id,
+  c.user_id,
+  c.name as course_name,
+  cc.id as component_id,
+  cc.type as component_type,
+  cc.name as component_name,
+  cc.due_date,
+  cc.is_completed,
+  cc.weight,
+  EXTRACT(DAY FROM (cc.due_date - NOW())) as days_remaining
+FROM course_components cc
+JOIN courses c ON c.id = cc.course_id
+WHERE cc.due_date > NOW()
+  AND cc.is_completed = FALSE
+ORDER BY cc.due_date ASC;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_by_parent;
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE habits DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE courses DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE goals DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE notes DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE life_areas DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE people DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE journal_entries DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE finance_transactions DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_at;
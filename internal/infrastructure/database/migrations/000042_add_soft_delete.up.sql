@@ -0,0 +1,60 @@
+-- Deleted rows stay in their table with deleted_at set until they are restored or purged.
+-- Subtasks moved to the trash with their parent are marked deleted_by_parent: they are
+-- restored and purged together with it and are not listed in the trash on their own.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_by_parent BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE habits ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE goals ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE life_areas ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE people ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE finance_transactions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX idx_tasks_trash ON tasks(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_habits_trash ON habits(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_courses_trash ON courses(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_goals_trash ON goals(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_notes_trash ON notes(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_life_areas_trash ON life_areas(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_people_trash ON people(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_journal_entries_trash ON journal_entries(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_finance_transactions_trash ON finance_transactions(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_events_trash ON events(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE OR REPLACE VIEW course_grades_view AS
+SELECT
+  c.id as course_id,
+  c.name as course_name,
+  c.user_id,
+  COUNT(cc.id) FILTER (WHERE cc.is_completed = TRUE) as completed_components,
+  COUNT(cc.id) as total_components,
+  SUM(cc.achieved_score * cc.weight / NULLIF(cc.max_score, 0)) /
+    NULLIF(SUM(cc.weight) FILTER (WHERE cc.is_completed = TRUE), 0) as current_grade,
+  SUM(cc.weight) as total_weight,
+  SUM(cc.weight) FILTER (WHERE cc.is_completed = TRUE) as completed_weight
+FROM courses c
+LEFT JOIN course_components cc ON cc.course_id = c.id
+WHERE c.deleted_at IS NULL
+GROUP BY c.id, c.name, c.user_id;
+
+CREATE OR REPLACE VIEW upcoming_course_deadlines AS
+SELECT
+  c.id as course_id,
+  c.user_id,
+  c.name as course_name,
+  cc.id as component_id,
+  cc.type as component_type,
+  cc.name as component_name,
+  cc.due_date,
+  cc.is_completed,
+  cc.weight,
+  EXTRACT(DAY FROM (cc.due_date - NOW())) as days_remaining
+FROM course_components cc
+JOIN courses c ON c.id = cc.course_id
+WHERE cc.due_date > NOW()
+  AND cc.is_completed = FALSE
+  AND c.deleted_at IS NULL
+ORDER BY cc.due_date ASC;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS token_revocations;
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS roles (
+  name VARCHAR(50) PRIMARY KEY,
+  description TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+  permission VARCHAR(100) NOT NULL,
+  PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX idx_user_roles_role ON user_roles(role);
+
+INSERT INTO roles (name, description) VALUES
+  ('admin', 'Manages users, roles, jobs and system logs'),
+  ('user', 'Regular account; works with its own data only');
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('admin', 'users:read'),
+  ('admin', 'users:write'),
+  ('admin', 'roles:manage'),
+  ('admin', 'jobs:read'),
+  ('admin', 'jobs:run'),
+  ('admin', 'logs:read');
+
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'user' FROM users;
@@ -0,0 +1,25 @@
+-- Pending OIDC logins between the redirect to the provider and the callback
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+  id BIGSERIAL PRIMARY KEY,
+  state_hash CHAR(64) NOT NULL UNIQUE,
+  nonce VARCHAR(128) NOT NULL,
+  code_verifier VARCHAR(128) NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_oidc_login_states_expires ON oidc_login_states(expires_at);
+
+-- Accounts at an OIDC provider linked to users
+CREATE TABLE IF NOT EXISTS oidc_identities (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  issuer VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  email VARCHAR(255),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  last_login_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (issuer, subject)
+);
+
+CREATE INDEX idx_oidc_identities_user ON oidc_identities(user_id);
@@ -0,0 +1,13 @@
+-- One row per login; the session lives as long as its refresh token family has a live token
+CREATE TABLE IF NOT EXISTS sessions (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  family_id VARCHAR(64) NOT NULL UNIQUE,
+  device_name VARCHAR(100) NOT NULL,
+  user_agent VARCHAR(512),
+  ip VARCHAR(64),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sessions_user ON sessions(user_id);
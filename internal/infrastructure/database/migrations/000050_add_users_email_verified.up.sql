@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+
+-- Accounts created before verification existed keep everything they could do
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE goals DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE courses DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS workspace_id;
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,8 @@
+-- Handlers that already received an outbox event, so that retrying the event after
+-- another handler failed does not deliver it to them again
+CREATE TABLE IF NOT EXISTS event_outbox_deliveries (
+  outbox_id BIGINT NOT NULL REFERENCES event_outbox(id) ON DELETE CASCADE,
+  handler VARCHAR(100) NOT NULL,
+  delivered_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (outbox_id, handler)
+);
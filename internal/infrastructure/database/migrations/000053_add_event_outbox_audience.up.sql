@@ -0,0 +1,3 @@
+-- Everyone notified of the event when that is more than user_id, e.g. the members of a
+-- shared workspace; NULL when it is only user_id
+ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS audience INTEGER[];
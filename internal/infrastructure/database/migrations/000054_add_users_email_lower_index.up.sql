@@ -0,0 +1,2 @@
+-- Logins look users up by their email whatever its case
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email));
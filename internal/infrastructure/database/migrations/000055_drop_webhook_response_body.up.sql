@@ -0,0 +1,2 @@
+-- Receivers' responses are no longer kept: showing them let users read whatever the server could reach
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS response_body;
@@ -0,0 +1,3 @@
+-- The changed entity's snapshot before the change, read inside the unit of work that
+-- changed it; NULL for creates and events that do not change an entity
+ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS before_state JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE courses DROP COLUMN IF EXISTS graded_weight;
+ALTER TABLE courses DROP COLUMN IF EXISTS current_grade;
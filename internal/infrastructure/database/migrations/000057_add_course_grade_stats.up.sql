@@ -0,0 +1,15 @@
+-- Grade stats the course module keeps up to date when a component is graded: the weighted
+-- grade over the graded, completed components and the weight they cover
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS current_grade DECIMAL(5,2);
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS graded_weight DECIMAL(6,2) NOT NULL DEFAULT 0;
+
+UPDATE courses c SET current_grade = s.grade, graded_weight = s.weight
+FROM (
+  SELECT course_id,
+    SUM(achieved_score / max_score * weight) / NULLIF(SUM(weight), 0) * 100 AS grade,
+    SUM(weight) AS weight
+  FROM course_components
+  WHERE achieved_score IS NOT NULL AND is_completed AND max_score > 0
+  GROUP BY course_id
+) s
+WHERE s.course_id = c.id;
@@ -0,0 +1 @@
+ALTER TABLE life_areas DROP COLUMN IF EXISTS last_activity_at;
@@ -0,0 +1,11 @@
+-- When a habit in the life area was last completed, kept up to date by the life area module
+ALTER TABLE life_areas ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMP;
+
+UPDATE life_areas la SET last_activity_at = s.last_log
+FROM (
+  SELECT h.life_area_id, MAX(l.log_date)::timestamp AS last_log
+  FROM habit_logs l JOIN habits h ON h.id = l.habit_id
+  WHERE h.life_area_id IS NOT NULL AND l.is_completed
+  GROUP BY h.life_area_id
+) s
+WHERE s.life_area_id = la.id;
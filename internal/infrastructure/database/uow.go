@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Executor is the query surface shared by *sqlx.DB and *sqlx.Tx
+type Executor interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type txKey struct{}
+
+// txState is the transaction carried by a unit of work's context
+type txState struct {
+	tx          *sqlx.Tx
+	afterCommit []func()
+}
+
+// UnitOfWork runs a group of repository calls in one transaction. Repositories take part
+// by running their queries on Conn(ctx, db), which picks up the transaction from ctx.
+type UnitOfWork struct {
+	db *sqlx.DB
+}
+
+func NewUnitOfWork(db *sqlx.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do runs fn in a transaction that is committed if fn returns nil and rolled back otherwise.
+// A Do nested in another joins the outer transaction. A nil UnitOfWork runs fn without one.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if u == nil || ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	state := &txState{tx: tx}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	for _, hook := range state.afterCommit {
+		hook()
+	}
+	return nil
+}
+
+// Conn returns the transaction of the unit of work running in ctx, or db outside one
+func Conn(ctx context.Context, db *sqlx.DB) Executor {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return db
+}
+
+// AfterCommit runs fn once the unit of work in ctx commits; it is dropped on rollback.
+// Outside a unit of work fn runs immediately.
+func AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
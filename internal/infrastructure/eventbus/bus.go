@@ -0,0 +1,260 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/metrics"
+)
+
+// AllEvents subscribes a handler to every published event
+const AllEvents = "*"
+
+// asyncHandlerTimeout bounds one asynchronous handler run on the job pool
+const asyncHandlerTimeout = 30 * time.Second
+
+// Envelope is one published event together with the user it concerns and the request
+// that raised it. ActorID is the authenticated user behind the change (0 for jobs and
+// other system work); ID is the outbox row, 0 for events not relayed through the outbox.
+// Audience lists everyone notified of the event when that is more than UserID, e.g. the
+// members of a shared workspace. Before is the changed entity's snapshot before the change,
+// attached with WithBefore; it is not part of the event, so subscribers that forward events
+// to clients never see it.
+type Envelope struct {
+	ID         int64
+	UserID     int
+	Audience   []int
+	Event      events.Event
+	Before     interface{}
+	OccurredAt time.Time
+	ActorID    int
+	RequestID  string
+	IP         string
+}
+
+type beforeKey struct{}
+
+// WithBefore attaches the snapshot of an entity as it was before a change to the events
+// published with the returned context. Read it from the repository in the same unit of
+// work as the change, so it is the row the change replaced.
+func WithBefore(ctx context.Context, snapshot interface{}) context.Context {
+	return context.WithValue(ctx, beforeKey{}, snapshot)
+}
+
+// Recipients returns the users the event is delivered to: its audience, or just UserID
+func (e Envelope) Recipients() []int {
+	if len(e.Audience) == 0 {
+		return []int{e.UserID}
+	}
+	return e.Audience
+}
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, env Envelope) error
+
+type subscription struct {
+	name    string
+	handler Handler
+	async   bool
+	retry   *jobs.RetryPolicy
+}
+
+// Bus is an in-process publish/subscribe bus for domain events. Modules publish events
+// without knowing who reacts to them; other modules subscribe with handlers that run
+// either on the publisher's goroutine or as jobs on the worker pool.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[string][]subscription
+	pool   *jobs.WorkerPool
+	outbox *Outbox
+	logger *logger.ZapLogger
+}
+
+// New creates a bus. Asynchronous handlers run on pool; without a pool they run on their own goroutine.
+func New(pool *jobs.WorkerPool, logger *logger.ZapLogger) *Bus {
+	return &Bus{subs: make(map[string][]subscription), pool: pool, logger: logger}
+}
+
+// UseOutbox routes published events through the transactional outbox: Publish stores them
+// with the caller's unit of work and the outbox relay dispatches them after commit.
+// Transient events (protocol and live-only messages) are still dispatched right away.
+func (b *Bus) UseOutbox(outbox *Outbox) {
+	outbox.bus = b
+	b.outbox = outbox
+}
+
+// SubscribeAll registers a synchronous handler for every event type
+func (b *Bus) SubscribeAll(name string, handler Handler) {
+	b.add(AllEvents, subscription{name: name, handler: handler})
+}
+
+// Subscribe registers a synchronous handler for one event type. Synchronous handlers run
+// on the dispatching goroutine in registration order, so they must be quick.
+func Subscribe[E events.Event](b *Bus, name string, fn func(ctx context.Context, userID int, event E) error) {
+	var zero E
+	b.add(zero.EventType(), subscription{name: name, handler: typed(fn)})
+}
+
+// SubscribeAsync registers a handler for one event type that runs as a job on the worker
+// pool, retried according to retry (nil disables retries)
+func SubscribeAsync[E events.Event](b *Bus, name string, fn func(ctx context.Context, userID int, event E) error, retry *jobs.RetryPolicy) {
+	var zero E
+	b.add(zero.EventType(), subscription{name: name, handler: typed(fn), async: true, retry: retry})
+}
+
+func typed[E events.Event](fn func(ctx context.Context, userID int, event E) error) Handler {
+	return func(ctx context.Context, env Envelope) error {
+		event, ok := env.Event.(E)
+		if !ok {
+			return fmt.Errorf("eventbus: unexpected payload %T for %s", env.Event, env.Event.EventType())
+		}
+		return fn(ctx, env.UserID, event)
+	}
+}
+
+func (b *Bus) add(eventType string, sub subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[eventType] = append(b.subs[eventType], sub)
+
+	b.logger.Info("Event handler subscribed", map[string]interface{}{
+		"type":    eventType,
+		"handler": sub.name,
+		"async":   sub.async,
+		"action":  "EVENT_HANDLER_SUBSCRIBED",
+	})
+}
+
+// Publish emits an event. With an outbox it is stored in the unit of work running in ctx
+// and dispatched once that commits; otherwise it is dispatched immediately.
+func (b *Bus) Publish(ctx context.Context, userID int, event events.Event) error {
+	return b.PublishTo(ctx, userID, nil, event)
+}
+
+// PublishTo emits an event about userID's data that a wider audience is notified of.
+// Subscribers get the event once; delivering it to each recipient is up to them.
+func (b *Bus) PublishTo(ctx context.Context, userID int, audience []int, event events.Event) error {
+	env := Envelope{
+		UserID:     userID,
+		Audience:   audience,
+		Event:      event,
+		Before:     ctx.Value(beforeKey{}),
+		OccurredAt: time.Now(),
+		ActorID:    utils.GetUserIDFromContext(ctx),
+		RequestID:  utils.GetRequestIDFromContext(ctx),
+		IP:         utils.GetClientIPFromContext(ctx),
+	}
+	if b.outbox != nil && !events.IsTransient(event.EventType()) {
+		return b.outbox.Record(ctx, env)
+	}
+	return b.Dispatch(ctx, env)
+}
+
+// Dispatch hands an event to its subscribers: handlers for the event type first, then
+// handlers for every event. Synchronous handlers run on the caller's goroutine; a failing
+// handler is logged and does not stop the others. Asynchronous handlers are queued.
+// Returns the first error of a synchronous handler or of a job the pool dropped.
+func (b *Bus) Dispatch(ctx context.Context, env Envelope) error {
+	return b.dispatch(ctx, env, nil, nil)
+}
+
+// dispatch is Dispatch skipping the handlers named in delivered. delivering, if set, is
+// called with each handler that ran successfully or whose job was queued
+func (b *Bus) dispatch(ctx context.Context, env Envelope, delivered map[string]bool, delivering func(handler string)) error {
+	eventType := env.Event.EventType()
+
+	b.mu.RLock()
+	subs := make([]subscription, 0, len(b.subs[eventType])+len(b.subs[AllEvents]))
+	subs = append(subs, b.subs[eventType]...)
+	subs = append(subs, b.subs[AllEvents]...)
+	b.mu.RUnlock()
+
+	var firstErr error
+	for _, sub := range subs {
+		if delivered[sub.name] {
+			continue
+		}
+		var err error
+		if sub.async {
+			err = b.enqueue(sub, env)
+		} else {
+			err = b.run(ctx, sub, env)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if delivering != nil {
+			delivering(sub.name)
+		}
+	}
+	return firstErr
+}
+
+// run calls a handler, recovering from panics so one subscriber cannot break the publisher
+func (b *Bus) run(ctx context.Context, sub subscription, env Envelope) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in event handler %s: %v", sub.name, r)
+		}
+		if err != nil {
+			metrics.EventHandlersTotal.WithLabelValues(sub.name, "error").Inc()
+			b.logger.Error("Event handler failed", err, map[string]interface{}{
+				"user_id": env.UserID,
+				"type":    env.Event.EventType(),
+				"handler": sub.name,
+				"action":  "EVENT_HANDLER_FAILED",
+			})
+			return
+		}
+		metrics.EventHandlersTotal.WithLabelValues(sub.name, "ok").Inc()
+	}()
+	return sub.handler(ctx, env)
+}
+
+// enqueue queues an asynchronous handler; the job retries it on its own, so once queued
+// the handler counts as delivered. Returns an error if the pool dropped the job
+func (b *Bus) enqueue(sub subscription, env Envelope) error {
+	if b.pool == nil {
+		go b.run(context.Background(), sub, env)
+		return nil
+	}
+
+	job := &handlerJob{
+		BaseJob: jobs.NewBaseJob("event:"+sub.name, "", asyncHandlerTimeout, sub.retry),
+		bus:     b,
+		sub:     sub,
+		env:     env,
+	}
+	if err := b.pool.SubmitAsync(job); err != nil {
+		metrics.EventHandlersTotal.WithLabelValues(sub.name, "dropped").Inc()
+		b.logger.Error("Event handler dropped", err, map[string]interface{}{
+			"user_id": env.UserID,
+			"type":    env.Event.EventType(),
+			"handler": sub.name,
+			"action":  "EVENT_HANDLER_DROPPED",
+		})
+		return err
+	}
+	return nil
+}
+
+// handlerJob runs one asynchronous handler on the worker pool
+type handlerJob struct {
+	jobs.BaseJob
+	bus *Bus
+	sub subscription
+	env Envelope
+}
+
+func (j *handlerJob) Execute(ctx context.Context) error {
+	return j.bus.run(ctx, j.sub, j.env)
+}
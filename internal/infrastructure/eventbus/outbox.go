@@ -0,0 +1,367 @@
+package eventbus
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/metrics"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Outbox statuses
+const (
+	OutboxPending     = "pending"
+	OutboxDispatching = "dispatching"
+	OutboxPublished   = "published"
+	OutboxFailed      = "failed"
+)
+
+const (
+	// relayBatchSize caps how many events one Relay call dispatches
+	relayBatchSize = 100
+	// relayPollInterval is how often the relay looks for due events when nothing wakes it
+	relayPollInterval = time.Second
+	// relayRunTimeout bounds one relay pass
+	relayRunTimeout = 30 * time.Second
+	// dispatchLease is how long a claimed event stays hidden from other relays;
+	// if the process dies mid-dispatch the event becomes due again after it
+	dispatchLease = time.Minute
+)
+
+// DefaultOutboxRetryPolicy returns the relay retry schedule: 5s, 35s, 65s, 95s between attempts
+func DefaultOutboxRetryPolicy() *jobs.RetryPolicy {
+	return &jobs.RetryPolicy{
+		MaxRetries: 4,
+		Delay:      5 * time.Second,
+		Backoff:    30 * time.Second,
+	}
+}
+
+type outboxRow struct {
+	ID          int64         `db:"id"`
+	UserID      int           `db:"user_id"`
+	Audience    pq.Int64Array `db:"audience"`
+	Type        string        `db:"type"`
+	Payload     []byte        `db:"payload"`
+	BeforeState []byte        `db:"before_state"`
+	OccurredAt  time.Time     `db:"occurred_at"`
+	ActorID     *int          `db:"actor_id"`
+	RequestID   *string       `db:"request_id"`
+	IP          *string       `db:"ip"`
+	Attempts    int           `db:"attempts"`
+	MaxAttempts int           `db:"max_attempts"`
+}
+
+func (row outboxRow) envelope(event events.Event) Envelope {
+	env := Envelope{ID: row.ID, UserID: row.UserID, Event: event, OccurredAt: row.OccurredAt}
+	for _, userID := range row.Audience {
+		env.Audience = append(env.Audience, int(userID))
+	}
+	if row.ActorID != nil {
+		env.ActorID = *row.ActorID
+	}
+	if row.RequestID != nil {
+		env.RequestID = *row.RequestID
+	}
+	if row.IP != nil {
+		env.IP = *row.IP
+	}
+	if row.BeforeState != nil {
+		var before map[string]interface{}
+		if err := json.Unmarshal(row.BeforeState, &before); err == nil {
+			env.Before = before
+		}
+	}
+	return env
+}
+
+// Outbox stores published events in the event_outbox table, in the same transaction as
+// the change that raised them, and relays them to the bus's subscribers once committed.
+// A retry only reaches the handlers that have not had the event yet; a crash mid-dispatch
+// can still deliver it twice, so delivery is at least once.
+type Outbox struct {
+	db     *sqlx.DB
+	bus    *Bus
+	retry  *jobs.RetryPolicy
+	logger *logger.ZapLogger
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewOutbox creates an outbox; a nil retry policy uses DefaultOutboxRetryPolicy
+func NewOutbox(db *sqlx.DB, retry *jobs.RetryPolicy, logger *logger.ZapLogger) *Outbox {
+	if retry == nil {
+		retry = DefaultOutboxRetryPolicy()
+	}
+	return &Outbox{
+		db:     db,
+		retry:  retry,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Record stores an event in the unit of work running in ctx (or on its own outside one)
+// and wakes the relay once it is committed
+func (o *Outbox) Record(ctx context.Context, env Envelope) error {
+	payload, err := json.Marshal(env.Event)
+	if err != nil {
+		return err
+	}
+
+	var before []byte
+	if env.Before != nil {
+		if before, err = json.Marshal(env.Before); err != nil {
+			return err
+		}
+	}
+
+	var audience pq.Int64Array
+	for _, userID := range env.Audience {
+		audience = append(audience, int64(userID))
+	}
+
+	if _, err := database.Conn(ctx, o.db).ExecContext(ctx, `
+		INSERT INTO event_outbox (user_id, audience, type, payload, before_state, occurred_at, actor_id, request_id, ip, max_attempts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		env.UserID, audience, env.Event.EventType(), payload, nullString(string(before)), env.OccurredAt,
+		nullInt(env.ActorID), nullString(env.RequestID), nullString(env.IP), o.retry.MaxRetries+1,
+	); err != nil {
+		return err
+	}
+
+	database.AfterCommit(ctx, o.notify)
+	return nil
+}
+
+// Start runs the relay until Stop is called
+func (o *Outbox) Start() {
+	go o.run()
+}
+
+// Stop ends the relay and waits for the current pass to finish
+func (o *Outbox) Stop() {
+	close(o.stop)
+	<-o.done
+}
+
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) run() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(relayPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.stop:
+			return
+		case <-o.wake:
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), relayRunTimeout)
+		for {
+			n, err := o.Relay(ctx)
+			if err != nil {
+				o.logger.Error("Event outbox relay failed", err, map[string]interface{}{
+					"action": "EVENT_OUTBOX_RELAY_FAILED",
+				})
+			}
+			if err != nil || n < relayBatchSize {
+				break
+			}
+		}
+		cancel()
+	}
+}
+
+// Relay claims due events in commit order and hands them to the bus's subscribers.
+// An event whose synchronous handlers fail is retried per the retry policy and marked
+// failed once attempts run out. Returns the number of events claimed.
+func (o *Outbox) Relay(ctx context.Context) (int, error) {
+	var rows []outboxRow
+	err := o.db.SelectContext(ctx, &rows, `
+		UPDATE event_outbox
+		SET status = $1, attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM event_outbox
+			WHERE status IN ($3, $1) AND next_attempt_at <= NOW()
+			ORDER BY id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, audience, type, payload, before_state, occurred_at, actor_id, request_id, ip, attempts, max_attempts`,
+		OutboxDispatching, dispatchLease.Seconds(), OutboxPending, relayBatchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	// RETURNING does not keep the subquery's order
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			// Undelivered rows keep their lease and are picked up again once it expires
+			return len(rows), ctx.Err()
+		}
+		o.deliver(ctx, row)
+	}
+	return len(rows), nil
+}
+
+func (o *Outbox) deliver(ctx context.Context, row outboxRow) {
+	event, err := events.Decode(row.Type, row.Payload)
+	if err != nil {
+		// A payload that cannot be decoded will not decode on retry either
+		o.markFailed(ctx, row, err)
+		return
+	}
+
+	if o.bus != nil {
+		err = o.dispatch(ctx, row, event)
+	}
+	if err == nil {
+		o.markPublished(ctx, row)
+		return
+	}
+
+	if row.Attempts >= row.MaxAttempts {
+		o.markFailed(ctx, row, err)
+		return
+	}
+
+	delay := o.retry.Delay + time.Duration(row.Attempts-1)*o.retry.Backoff
+	if _, dbErr := o.db.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET status = $1, last_error = $2, next_attempt_at = NOW() + make_interval(secs => $3)
+		WHERE id = $4`,
+		OutboxPending, err.Error(), delay.Seconds(), row.ID,
+	); dbErr != nil {
+		o.logger.Error("Failed to reschedule outbox event", dbErr, map[string]interface{}{
+			"outbox_id": row.ID,
+			"action":    "EVENT_OUTBOX_RESCHEDULE_FAILED",
+		})
+	}
+
+	metrics.EventOutboxTotal.WithLabelValues("retry").Inc()
+	o.logger.Error("Outbox event dispatch failed, will retry", err, map[string]interface{}{
+		"outbox_id": row.ID,
+		"type":      row.Type,
+		"attempt":   row.Attempts,
+		"retry_in":  delay.String(),
+		"action":    "EVENT_OUTBOX_RETRY",
+	})
+}
+
+// dispatch hands the event to the handlers that did not get it on an earlier attempt. If a
+// handler fails, the ones that succeeded are recorded in event_outbox_deliveries so that
+// the retry skips them
+func (o *Outbox) dispatch(ctx context.Context, row outboxRow, event events.Event) error {
+	delivered := make(map[string]bool)
+	if row.Attempts > 1 {
+		var handlers []string
+		if err := o.db.SelectContext(ctx, &handlers, `
+			SELECT handler FROM event_outbox_deliveries WHERE outbox_id = $1`, row.ID,
+		); err != nil {
+			return err
+		}
+		for _, handler := range handlers {
+			delivered[handler] = true
+		}
+	}
+
+	var handled []string
+	err := o.bus.dispatch(ctx, row.envelope(event), delivered, func(handler string) {
+		handled = append(handled, handler)
+	})
+	if err == nil || len(handled) == 0 {
+		return err
+	}
+
+	if _, dbErr := o.db.ExecContext(ctx, `
+		INSERT INTO event_outbox_deliveries (outbox_id, handler)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING`,
+		row.ID, pq.Array(handled),
+	); dbErr != nil {
+		o.logger.Error("Failed to record outbox deliveries", dbErr, map[string]interface{}{
+			"outbox_id": row.ID,
+			"action":    "EVENT_OUTBOX_RECORD_DELIVERIES_FAILED",
+		})
+	}
+	return err
+}
+
+func (o *Outbox) markPublished(ctx context.Context, row outboxRow) {
+	if _, err := o.db.ExecContext(ctx, `
+		UPDATE event_outbox SET status = $1, published_at = NOW(), last_error = NULL WHERE id = $2`,
+		OutboxPublished, row.ID,
+	); err != nil {
+		o.logger.Error("Failed to mark outbox event as published", err, map[string]interface{}{
+			"outbox_id": row.ID,
+			"action":    "EVENT_OUTBOX_MARK_PUBLISHED_FAILED",
+		})
+	}
+	metrics.EventOutboxTotal.WithLabelValues("published").Inc()
+}
+
+func (o *Outbox) markFailed(ctx context.Context, row outboxRow, dispatchErr error) {
+	if _, err := o.db.ExecContext(ctx, `
+		UPDATE event_outbox SET status = $1, last_error = $2 WHERE id = $3`,
+		OutboxFailed, dispatchErr.Error(), row.ID,
+	); err != nil {
+		o.logger.Error("Failed to mark outbox event as failed", err, map[string]interface{}{
+			"outbox_id": row.ID,
+			"action":    "EVENT_OUTBOX_MARK_FAILED_FAILED",
+		})
+	}
+
+	metrics.EventOutboxTotal.WithLabelValues("failed").Inc()
+	o.logger.Error("Outbox event permanently failed", dispatchErr, map[string]interface{}{
+		"outbox_id": row.ID,
+		"user_id":   row.UserID,
+		"type":      row.Type,
+		"attempts":  row.Attempts,
+		"action":    "EVENT_OUTBOX_FAILED",
+	})
+}
+
+// Purge deletes events published before the cutoff. Failed events are kept for inspection.
+func (o *Outbox) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := o.db.ExecContext(ctx, `
+		DELETE FROM event_outbox WHERE status = $1 AND published_at < $2`,
+		OutboxPublished, before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func nullInt(v int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
+
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
@@ -0,0 +1,196 @@
+package eventbus
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// fakeOutboxStore stands in for the outbox tables: it answers the delivery lookup and
+// records the statuses and deliveries the relay writes
+type fakeOutboxStore struct {
+	mu        sync.Mutex
+	delivered []string
+	statuses  []string
+}
+
+func (s *fakeOutboxStore) Connect(context.Context) (driver.Conn, error) {
+	return &fakeOutboxConn{s}, nil
+}
+func (s *fakeOutboxStore) Driver() driver.Driver { return nil }
+
+type fakeOutboxConn struct{ store *fakeOutboxStore }
+
+func (c *fakeOutboxConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (c *fakeOutboxConn) Close() error { return nil }
+func (c *fakeOutboxConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *fakeOutboxConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	switch {
+	case strings.Contains(query, "INSERT INTO event_outbox_deliveries"):
+		var handlers pq.StringArray
+		if err := handlers.Scan(args[1].Value); err != nil {
+			return nil, err
+		}
+		for _, h := range handlers {
+			if !slices.Contains(c.store.delivered, h) {
+				c.store.delivered = append(c.store.delivered, h)
+			}
+		}
+	case strings.Contains(query, "UPDATE event_outbox"):
+		c.store.statuses = append(c.store.statuses, args[0].Value.(string))
+	default:
+		return nil, errors.New("unexpected statement: " + query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeOutboxConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "FROM event_outbox_deliveries") {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	return &fakeHandlerRows{handlers: slices.Clone(c.store.delivered)}, nil
+}
+
+type fakeHandlerRows struct{ handlers []string }
+
+func (r *fakeHandlerRows) Columns() []string { return []string{"handler"} }
+func (r *fakeHandlerRows) Close() error      { return nil }
+func (r *fakeHandlerRows) Next(dest []driver.Value) error {
+	if len(r.handlers) == 0 {
+		return io.EOF
+	}
+	dest[0], r.handlers = r.handlers[0], r.handlers[1:]
+	return nil
+}
+
+func TestOutboxRetriesOnlyUndeliveredHandlers(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int
+		delivered []string
+		failing   string
+		// wantRun lists the synchronous handlers called, in order
+		wantRun       []string
+		wantAsync     bool
+		wantDelivered []string
+		wantStatus    string
+	}{
+		{
+			name:       "all handlers succeed",
+			attempts:   1,
+			wantRun:    []string{"first", "second", "all"},
+			wantAsync:  true,
+			wantStatus: OutboxPublished,
+		},
+		{
+			name:          "a failing handler records the others",
+			attempts:      1,
+			failing:       "second",
+			wantRun:       []string{"first", "second", "all"},
+			wantAsync:     true,
+			wantDelivered: []string{"first", "async", "all"},
+			wantStatus:    OutboxPending,
+		},
+		{
+			name:          "a retry skips delivered handlers",
+			attempts:      2,
+			delivered:     []string{"first", "async", "all"},
+			wantRun:       []string{"second"},
+			wantDelivered: []string{"first", "async", "all"},
+			wantStatus:    OutboxPublished,
+		},
+		{
+			name:          "the last attempt fails the event",
+			attempts:      5,
+			delivered:     []string{"first", "async", "all"},
+			failing:       "second",
+			wantRun:       []string{"second"},
+			wantDelivered: []string{"first", "async", "all"},
+			wantStatus:    OutboxFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeOutboxStore{delivered: slices.Clone(tt.delivered)}
+			db := sqlx.NewDb(sql.OpenDB(store), "postgres")
+			defer db.Close()
+			log := logger.NewLogger(nil)
+
+			bus := New(nil, log)
+			outbox := NewOutbox(db, nil, log)
+			bus.UseOutbox(outbox)
+
+			var run []string
+			asyncRan := make(chan struct{}, 1)
+			handler := func(name string) func(context.Context, int, events.TaskCreated) error {
+				return func(context.Context, int, events.TaskCreated) error {
+					run = append(run, name)
+					if name == tt.failing {
+						return errors.New("handler failed")
+					}
+					return nil
+				}
+			}
+			Subscribe(bus, "first", handler("first"))
+			Subscribe(bus, "second", handler("second"))
+			SubscribeAsync(bus, "async", func(context.Context, int, events.TaskCreated) error {
+				asyncRan <- struct{}{}
+				return nil
+			}, nil)
+			bus.SubscribeAll("all", func(ctx context.Context, env Envelope) error {
+				return handler("all")(ctx, env.UserID, env.Event.(events.TaskCreated))
+			})
+
+			payload, _ := json.Marshal(events.TaskCreated{TaskID: 1})
+			outbox.deliver(context.Background(), outboxRow{
+				ID: 7, UserID: 1, Type: events.TaskCreated{}.EventType(), Payload: payload,
+				OccurredAt: time.Now(), Attempts: tt.attempts, MaxAttempts: 5,
+			})
+
+			if !slices.Equal(run, tt.wantRun) {
+				t.Errorf("handlers run = %v, want %v", run, tt.wantRun)
+			}
+			if tt.wantAsync {
+				select {
+				case <-asyncRan:
+				case <-time.After(time.Second):
+					t.Error("async handler was not queued")
+				}
+			}
+			if !slices.Equal(store.delivered, tt.wantDelivered) {
+				t.Errorf("delivered = %v, want %v", store.delivered, tt.wantDelivered)
+			}
+			if len(store.statuses) != 1 || store.statuses[0] != tt.wantStatus {
+				t.Errorf("status updates = %v, want [%s]", store.statuses, tt.wantStatus)
+			}
+			select {
+			case <-asyncRan:
+				t.Error("async handler was queued again")
+			case <-time.After(20 * time.Millisecond):
+			}
+		})
+	}
+}
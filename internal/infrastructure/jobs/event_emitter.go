@@ -3,6 +3,7 @@ package jobs
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/websocket"
 )
@@ -27,9 +28,9 @@ func (e *WebSocketJobEmitter) EmitJobStarted(ctx context.Context, jobName string
 		"action": "JOB_EVENT_STARTED",
 	})
 
-	msg := websocket.NewMessage(websocket.TypeJobStarted, 0, map[string]interface{}{
-		"job_name": jobName,
-		"status":   "running",
+	msg := websocket.NewMessage(0, events.JobStarted{
+		JobName: jobName,
+		Status:  "running",
 	})
 
 	e.hub.BroadcastToAll(msg)
@@ -42,10 +43,10 @@ func (e *WebSocketJobEmitter) EmitJobProgress(ctx context.Context, jobName strin
 		"action":   "JOB_EVENT_PROGRESS",
 	})
 
-	msg := websocket.NewMessage(websocket.TypeJobProgress, 0, map[string]interface{}{
-		"job_name": jobName,
-		"progress": progress,
-		"message":  message,
+	msg := websocket.NewMessage(0, events.JobProgress{
+		JobName:  jobName,
+		Progress: progress,
+		Message:  message,
 	})
 
 	e.hub.BroadcastToAll(msg)
@@ -57,10 +58,10 @@ func (e *WebSocketJobEmitter) EmitJobCompleted(ctx context.Context, jobName stri
 		"action": "JOB_EVENT_COMPLETED",
 	})
 
-	msg := websocket.NewMessage(websocket.TypeJobCompleted, 0, map[string]interface{}{
-		"job_name": jobName,
-		"status":   "completed",
-		"result":   result,
+	msg := websocket.NewMessage(0, events.JobCompleted{
+		JobName: jobName,
+		Status:  "completed",
+		Result:  result,
 	})
 
 	e.hub.BroadcastToAll(msg)
@@ -78,10 +79,10 @@ func (e *WebSocketJobEmitter) EmitJobFailed(ctx context.Context, jobName string,
 		errMsg = err.Error()
 	}
 
-	msg := websocket.NewMessage(websocket.TypeJobFailed, 0, map[string]interface{}{
-		"job_name": jobName,
-		"status":   "failed",
-		"error":    errMsg,
+	msg := websocket.NewMessage(0, events.JobFailed{
+		JobName: jobName,
+		Status:  "failed",
+		Error:   errMsg,
 	})
 
 	e.hub.BroadcastToAll(msg)
@@ -0,0 +1,37 @@
+package jwtkeys
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// clockSkewLeeway tolerates small clock differences when checking token timestamps
+const clockSkewLeeway = 30 * time.Second
+
+// Claims are the claims of an access token, as issued by the auth module and read by the
+// auth middleware and the WebSocket handler
+type Claims struct {
+	UserID      int      `json:"user_id"`
+	Email       string   `json:"email"`
+	Username    string   `json:"username"`
+	Role        string   `json:"role"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+	SessionID   string   `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Verify checks an access token's signature and timestamps and returns its claims. Every
+// consumer of access tokens verifies them here, so they agree on what a valid token is.
+func (m *Manager) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := m.Parse(tokenString, claims, jwt.WithExpirationRequired(), jwt.WithLeeway(clockSkewLeeway))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.UserID == 0 {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	return claims, nil
+}
@@ -0,0 +1,231 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/oidc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmEdDSA = "EdDSA" // Ed25519 keys
+	AlgorithmRS256 = "RS256" // RSA keys of at least 2048 bits
+)
+
+var (
+	ErrUnknownKey        = errors.New("jwt signed with an unknown key")
+	ErrAlgorithmMismatch = errors.New("jwt algorithm does not match its key")
+	ErrNoKeys            = errors.New("jwt keys: JWT_KEYS_DIR is required unless APP_ENV=development")
+)
+
+// Config says where the keys come from
+type Config struct {
+	Dir         string // Directory of PEM keys; the file name without ".pem" is the key id
+	ActiveKeyID string // Key new tokens are signed with; may be empty when Dir holds one private key
+	// LegacySecret, when set, keeps HS256 tokens without a key id valid while moving off a shared secret
+	LegacySecret string
+	// Development allows running without Dir on a key generated at startup
+	Development bool
+}
+
+// ConfigFromEnv reads JWT_KEYS_DIR and JWT_ACTIVE_KEY_ID. JWT_SECRET is accepted for HS256
+// tokens only when JWT_ACCEPT_HS256 is "true". APP_ENV=development allows an ephemeral key.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Dir:         os.Getenv("JWT_KEYS_DIR"),
+		ActiveKeyID: os.Getenv("JWT_ACTIVE_KEY_ID"),
+		Development: os.Getenv("APP_ENV") == "development",
+	}
+	if os.Getenv("JWT_ACCEPT_HS256") == "true" {
+		cfg.LegacySecret = os.Getenv("JWT_SECRET")
+	}
+	return cfg
+}
+
+// Key is a key tokens are verified with, and signed with when its private half is known
+type Key struct {
+	ID        string
+	Algorithm string
+	private   crypto.Signer
+	public    crypto.PublicKey
+}
+
+// CanSign reports whether the private key is loaded
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// Manager signs access tokens with the active key and verifies them against every loaded
+// key. Rotation is a matter of files: add the new key, make it active once every instance
+// has it, and drop the old private key (or keep only its public half) after the access
+// token lifetime has passed.
+type Manager struct {
+	keys      map[string]*Key
+	active    *Key
+	ephemeral bool
+
+	legacySecret []byte
+}
+
+// NewManager loads the keys of cfg. Without a key directory it fails, except in development
+// where it generates an Ed25519 key that lives as long as the process; see Ephemeral.
+func NewManager(cfg Config) (*Manager, error) {
+	m := &Manager{keys: make(map[string]*Key)}
+	if cfg.LegacySecret != "" {
+		m.legacySecret = []byte(cfg.LegacySecret)
+	}
+
+	if cfg.Dir == "" {
+		if !cfg.Development {
+			return nil, ErrNoKeys
+		}
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		suffix := make([]byte, 4)
+		if _, err := rand.Read(suffix); err != nil {
+			return nil, err
+		}
+		key := &Key{ID: "ephemeral-" + hex.EncodeToString(suffix), Algorithm: AlgorithmEdDSA, private: private, public: private.Public()}
+		m.keys[key.ID] = key
+		m.active = key
+		m.ephemeral = true
+		return m, nil
+	}
+
+	keys, err := loadDir(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		m.keys[key.ID] = key
+	}
+
+	activeID := cfg.ActiveKeyID
+	if activeID == "" {
+		var signers []string
+		for _, key := range keys {
+			if key.CanSign() {
+				signers = append(signers, key.ID)
+			}
+		}
+		if len(signers) != 1 {
+			return nil, fmt.Errorf("jwt keys: JWT_ACTIVE_KEY_ID must name one of the %d private keys in %s", len(signers), cfg.Dir)
+		}
+		activeID = signers[0]
+	}
+	active, ok := m.keys[activeID]
+	if !ok || !active.CanSign() {
+		return nil, fmt.Errorf("jwt keys: no private key %q in %s", activeID, cfg.Dir)
+	}
+	m.active = active
+
+	return m, nil
+}
+
+// Ephemeral reports whether the signing key was generated at startup. Its tokens stop
+// verifying on restart and on other instances.
+func (m *Manager) Ephemeral() bool {
+	return m.ephemeral
+}
+
+// ActiveKeyID is the id of the key new tokens are signed with
+func (m *Manager) ActiveKeyID() string {
+	return m.active.ID
+}
+
+// Sign signs claims with the active key and names it in the kid header
+func (m *Manager) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(m.active.Algorithm), claims)
+	token.Header["kid"] = m.active.ID
+	return token.SignedString(m.active.private)
+}
+
+// Parse verifies a token against the loaded keys and fills claims. Only the algorithms of
+// those keys are accepted, and a token must use the algorithm of the key it names.
+func (m *Manager) Parse(tokenString string, claims jwt.Claims, options ...jwt.ParserOption) (*jwt.Token, error) {
+	options = append(options, jwt.WithValidMethods(m.algorithms()))
+	return jwt.ParseWithClaims(tokenString, claims, m.verificationKey, options...)
+}
+
+func (m *Manager) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if m.legacySecret != nil && token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return m.legacySecret, nil
+		}
+		return nil, ErrUnknownKey
+	}
+
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, ErrAlgorithmMismatch
+	}
+	return key.public, nil
+}
+
+func (m *Manager) algorithms() []string {
+	var algorithms []string
+	for _, key := range m.keys {
+		if !slices.Contains(algorithms, key.Algorithm) {
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+	if m.legacySecret != nil {
+		algorithms = append(algorithms, jwt.SigningMethodHS256.Alg())
+	}
+	return algorithms
+}
+
+// JWKS returns the public half of every key, the active one first
+func (m *Manager) JWKS() []oidc.JWK {
+	ids := make([]string, 0, len(m.keys))
+	for id := range m.keys {
+		if id != m.active.ID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	ids = append([]string{m.active.ID}, ids...)
+
+	jwks := make([]oidc.JWK, 0, len(ids))
+	for _, id := range ids {
+		key := m.keys[id]
+		switch public := key.public.(type) {
+		case ed25519.PublicKey:
+			jwks = append(jwks, oidc.Ed25519PublicJWK(key.ID, public))
+		case *rsa.PublicKey:
+			jwks = append(jwks, oidc.RSAPublicJWK(key.ID, key.Algorithm, public))
+		}
+	}
+	return jwks
+}
+
+// ServeJWKS answers GET /.well-known/jwks.json with the verification keys
+func (m *Manager) ServeJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	// Short enough that a key added before rotation is picked up well before it signs anything
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": m.JWKS()})
+}
+
+// keyID names a key after its file
+func keyID(fileName string) string {
+	return strings.TrimSuffix(fileName, ".pem")
+}
@@ -0,0 +1,262 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writePrivateKey(t *testing.T, dir, id string, key crypto.Signer) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, dir, id, "PRIVATE KEY", der)
+}
+
+func writePublicKey(t *testing.T, dir, id string, key crypto.PublicKey) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, dir, id, "PUBLIC KEY", der)
+}
+
+func writePEM(t *testing.T, dir, id, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, id+".pem"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newEd25519(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func accessClaims(userID int, expiresIn time.Duration) *Claims {
+	now := time.Now()
+	return &Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "token-id",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		},
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir string)
+		cfg        Config
+		noDir      bool
+		wantActive string
+		wantErr    bool
+	}{
+		{name: "no keys outside development", noDir: true, wantErr: true},
+		{name: "ephemeral key in development", noDir: true, cfg: Config{Development: true}},
+		{
+			name:       "single private key is active",
+			setup:      func(t *testing.T, dir string) { writePrivateKey(t, dir, "k1", newEd25519(t)) },
+			wantActive: "k1",
+		},
+		{
+			name: "two private keys need an active id",
+			setup: func(t *testing.T, dir string) {
+				writePrivateKey(t, dir, "k1", newEd25519(t))
+				writePrivateKey(t, dir, "k2", rsaKey)
+			},
+			wantErr: true,
+		},
+		{
+			name: "active id picks the signer",
+			setup: func(t *testing.T, dir string) {
+				writePrivateKey(t, dir, "k1", newEd25519(t))
+				writePrivateKey(t, dir, "k2", rsaKey)
+			},
+			cfg:        Config{ActiveKeyID: "k2"},
+			wantActive: "k2",
+		},
+		{
+			name:    "active id naming a public key",
+			setup:   func(t *testing.T, dir string) { writePublicKey(t, dir, "old", newEd25519(t).Public()) },
+			cfg:     Config{ActiveKeyID: "old"},
+			wantErr: true,
+		},
+		{
+			name:    "rsa key below 2048 bits",
+			setup:   func(t *testing.T, dir string) { writePrivateKey(t, dir, "small", smallRSA) },
+			wantErr: true,
+		},
+		{name: "empty directory", setup: func(t *testing.T, dir string) {}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if !tt.noDir {
+				cfg.Dir = t.TempDir()
+				tt.setup(t, cfg.Dir)
+			}
+
+			m, err := NewManager(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewManager succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewManager: %v", err)
+			}
+			if tt.noDir != m.Ephemeral() {
+				t.Errorf("Ephemeral = %v", m.Ephemeral())
+			}
+			if tt.wantActive != "" && m.ActiveKeyID() != tt.wantActive {
+				t.Errorf("active key = %s, want %s", m.ActiveKeyID(), tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestRotationKeepsOldTokensValid(t *testing.T) {
+	oldKey, newKey := newEd25519(t), newEd25519(t)
+
+	dir := t.TempDir()
+	writePrivateKey(t, dir, "2025-01", oldKey)
+	before, err := NewManager(Config{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldToken, err := before.Sign(accessClaims(1, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The new key signs, the old one only verifies
+	if err := os.Remove(filepath.Join(dir, "2025-01.pem")); err != nil {
+		t.Fatal(err)
+	}
+	writePublicKey(t, dir, "2025-01", oldKey.Public())
+	writePrivateKey(t, dir, "2025-06", newKey)
+	after, err := NewManager(Config{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := after.Verify(oldToken); err != nil {
+		t.Fatalf("token of the retired key: %v", err)
+	}
+	newToken, err := after.Sign(accessClaims(1, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := before.Verify(newToken); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("instance without the new key: error = %v, want %v", err, ErrUnknownKey)
+	}
+
+	jwks := after.JWKS()
+	if len(jwks) != 2 || jwks[0].Kid != "2025-06" || jwks[1].Kid != "2025-01" {
+		t.Fatalf("JWKS = %+v, want the active key first, then the retired one", jwks)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key := newEd25519(t)
+	dir := t.TempDir()
+	writePrivateKey(t, dir, "k1", key)
+	m, err := NewManager(Config{Dir: dir, LegacySecret: "legacy-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict, err := NewManager(Config{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := func(claims *Claims) string {
+		token, err := m.Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	withHeader := func(method jwt.SigningMethod, kid string, claims *Claims, secret interface{}) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		s, err := token.SignedString(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	noExpiry := accessClaims(1, time.Hour)
+	noExpiry.ExpiresAt = nil
+
+	tests := []struct {
+		name     string
+		token    string
+		manager  *Manager
+		wantUser int
+		wantErr  bool
+	}{
+		{name: "valid", token: signed(accessClaims(7, time.Hour)), manager: m, wantUser: 7},
+		{name: "expired within the leeway", token: signed(accessClaims(7, -10*time.Second)), manager: m, wantUser: 7},
+		{name: "expired beyond the leeway", token: signed(accessClaims(7, -time.Minute)), manager: m, wantErr: true},
+		{name: "no expiry", token: signed(noExpiry), manager: m, wantErr: true},
+		{name: "no user", token: signed(accessClaims(0, time.Hour)), manager: m, wantErr: true},
+		{name: "unknown kid", token: withHeader(jwt.SigningMethodEdDSA, "k9", accessClaims(7, time.Hour), key), manager: m, wantErr: true},
+		{name: "algorithm other than the key's", token: withHeader(jwt.SigningMethodHS256, "k1", accessClaims(7, time.Hour), []byte("k1")), manager: m, wantErr: true},
+		{name: "legacy HS256 while migrating", token: withHeader(jwt.SigningMethodHS256, "", accessClaims(7, time.Hour), []byte("legacy-secret")), manager: m, wantUser: 7},
+		{name: "legacy HS256 after migrating", token: withHeader(jwt.SigningMethodHS256, "", accessClaims(7, time.Hour), []byte("legacy-secret")), manager: strict, wantErr: true},
+		{name: "garbage", token: "not-a-jwt", manager: m, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.manager.Verify(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Verify accepted the token")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if claims.UserID != tt.wantUser {
+				t.Fatalf("user = %d, want %d", claims.UserID, tt.wantUser)
+			}
+		})
+	}
+}
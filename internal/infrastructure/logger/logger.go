@@ -92,6 +92,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer (Flush, deadlines)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Middleware logs all HTTP requests
 func (l *ZapLogger) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -119,6 +124,7 @@ func (l *ZapLogger) Middleware(next http.Handler) http.Handler {
 			"duration":    duration.String(),
 			"duration_ms": float64(duration.Nanoseconds()) / 1e6,
 			"ip":          r.RemoteAddr,
+			"request_id":  rw.Header().Get("X-Request-ID"),
 			"action":      "HTTP_REQUEST",
 		}
 
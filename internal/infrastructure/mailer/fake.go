@@ -0,0 +1,230 @@
+package mailer
+
+import (
+	"bufio"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+)
+
+// CapturedMail is a message accepted by the FakeServer
+type CapturedMail struct {
+	From       string
+	To         []string
+	Subject    string
+	Data       string
+	ReceivedAt time.Time
+}
+
+// FakeServer is an in-process SMTP server that accepts every message and keeps it in memory.
+// It stands in for a real server in local development and tests; it does not relay anything.
+type FakeServer struct {
+	listener net.Listener
+	logger   *logger.ZapLogger
+
+	mu       sync.Mutex
+	messages []CapturedMail
+	failNext int
+	wg       sync.WaitGroup
+}
+
+// NewFakeServer starts a fake SMTP server on a random local port. The logger may be nil.
+func NewFakeServer(logger *logger.ZapLogger) (*FakeServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FakeServer{listener: listener, logger: logger}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Config returns SMTP settings pointing at this server
+func (s *FakeServer) Config(from string) Config {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return Config{Host: addr.IP.String(), Port: addr.Port, From: from}
+}
+
+// Messages returns a copy of every message received so far
+func (s *FakeServer) Messages() []CapturedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]CapturedMail, len(s.messages))
+	copy(result, s.messages)
+	return result
+}
+
+// Reset drops the captured messages and any pending failures
+func (s *FakeServer) Reset() {
+	s.mu.Lock()
+	s.messages = nil
+	s.failNext = 0
+	s.mu.Unlock()
+}
+
+// FailNext makes the next n messages fail with a temporary (451) error after DATA,
+// so tests can exercise retries
+func (s *FakeServer) FailNext(n int) {
+	s.mu.Lock()
+	s.failNext = n
+	s.mu.Unlock()
+}
+
+// Close stops accepting connections and waits for open sessions to finish
+func (s *FakeServer) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *FakeServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle speaks just enough SMTP for net/smtp: EHLO/HELO, MAIL, RCPT, DATA, RSET, NOOP, QUIT
+func (s *FakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Minute))
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 localhost fake SMTP ready")
+
+	var from string
+	var to []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(verb, "EHLO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(verb, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(verb, "MAIL FROM:"):
+			from = trimAddress(line[len("MAIL FROM:"):])
+			to = nil
+			reply("250 OK")
+		case strings.HasPrefix(verb, "RCPT TO:"):
+			to = append(to, trimAddress(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case verb == "DATA":
+			if from == "" || len(to) == 0 {
+				reply("503 need MAIL and RCPT first")
+				continue
+			}
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			if s.takeFailure() {
+				from, to = "", nil
+				reply("451 temporary failure, try again later")
+				continue
+			}
+			s.capture(from, to, data)
+			from, to = "", nil
+			reply("250 OK queued")
+		case verb == "RSET":
+			from, to = "", nil
+			reply("250 OK")
+		case verb == "NOOP":
+			reply("250 OK")
+		case verb == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func (s *FakeServer) takeFailure() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failNext == 0 {
+		return false
+	}
+	s.failNext--
+	return true
+}
+
+func (s *FakeServer) capture(from string, to []string, data string) {
+	captured := CapturedMail{From: from, To: to, Data: data, ReceivedAt: time.Now()}
+	if parsed, err := mail.ReadMessage(strings.NewReader(data)); err == nil {
+		subject := parsed.Header.Get("Subject")
+		if decoded, err := new(mime.WordDecoder).DecodeHeader(subject); err == nil {
+			subject = decoded
+		}
+		captured.Subject = subject
+	}
+
+	s.mu.Lock()
+	s.messages = append(s.messages, captured)
+	s.mu.Unlock()
+
+	if s.logger != nil {
+		s.logger.Info("Email captured by fake SMTP server", map[string]interface{}{
+			"to":      strings.Join(to, ","),
+			"subject": captured.Subject,
+			"action":  "EMAIL_CAPTURED",
+		})
+	}
+}
+
+// readData reads a DATA section up to the terminating "." line, undoing dot-stuffing
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "." {
+			return b.String(), nil
+		}
+		if strings.HasPrefix(trimmed, "..") {
+			trimmed = trimmed[1:]
+		}
+		b.WriteString(trimmed)
+		b.WriteString("\r\n")
+	}
+}
+
+func trimAddress(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, " "); i >= 0 {
+		s = s[:i] // drop ESMTP parameters such as BODY=8BITMIME
+	}
+	return strings.Trim(s, "<>")
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"strconv"
+)
+
+// Message is a single outgoing email with plain-text and HTML alternatives
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers an email synchronously
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Config holds SMTP connection settings
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// Development allows running without Host on the in-process FakeServer
+	Development bool
+}
+
+// ConfigFromEnv reads SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM.
+// An empty Host means no real SMTP server is configured, which only APP_ENV=development allows.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Host:        os.Getenv("SMTP_HOST"),
+		Port:        587,
+		Username:    os.Getenv("SMTP_USERNAME"),
+		Password:    os.Getenv("SMTP_PASSWORD"),
+		From:        os.Getenv("SMTP_FROM"),
+		Development: os.Getenv("APP_ENV") == "development",
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil && v > 0 {
+		cfg.Port = v
+	}
+	if cfg.From == "" {
+		cfg.From = "no-reply@localhost"
+	}
+
+	return cfg
+}
@@ -0,0 +1,231 @@
+package mailer
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/metrics"
+	"github.com/jmoiron/sqlx"
+)
+
+// Outbox statuses
+const (
+	StatusPending = "pending"
+	StatusSending = "sending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+const (
+	// dispatchBatchSize caps how many emails one Dispatch call sends
+	dispatchBatchSize = 50
+	// sendLease is how long a claimed email stays hidden from other dispatchers;
+	// if the process dies mid-send the row becomes due again after it
+	sendLease = 5 * time.Minute
+	// sendTimeout bounds a single SMTP delivery
+	sendTimeout = 30 * time.Second
+)
+
+// DefaultRetryPolicy returns the outbox retry schedule: 1m, 6m, 11m, 16m between attempts
+func DefaultRetryPolicy() *jobs.RetryPolicy {
+	return &jobs.RetryPolicy{
+		MaxRetries: 4,
+		Delay:      1 * time.Minute,
+		Backoff:    5 * time.Minute,
+	}
+}
+
+// outboxDB is the part of *sqlx.DB the outbox uses
+type outboxDB interface {
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type outboxRow struct {
+	ID          int    `db:"id"`
+	ToAddress   string `db:"to_address"`
+	Subject     string `db:"subject"`
+	TextBody    string `db:"text_body"`
+	HTMLBody    string `db:"html_body"`
+	Attempts    int    `db:"attempts"`
+	MaxAttempts int    `db:"max_attempts"`
+}
+
+// Outbox stores emails in the email_outbox table and delivers them with retries,
+// so a request never waits on (or fails because of) the SMTP server
+type Outbox struct {
+	db     outboxDB
+	mailer Mailer
+	retry  *jobs.RetryPolicy
+	logger *logger.ZapLogger
+}
+
+// NewOutbox creates an outbox; a nil retry policy uses DefaultRetryPolicy
+func NewOutbox(db *sqlx.DB, mailer Mailer, retry *jobs.RetryPolicy, logger *logger.ZapLogger) *Outbox {
+	if retry == nil {
+		retry = DefaultRetryPolicy()
+	}
+	return &Outbox{db: db, mailer: mailer, retry: retry, logger: logger}
+}
+
+// Enqueue stores an email for delivery by the next Dispatch. userID may be 0 for mail
+// not tied to an account.
+func (o *Outbox) Enqueue(ctx context.Context, userID int, msg *Message) (int, error) {
+	return o.EnqueueAt(ctx, userID, msg, time.Now())
+}
+
+// EnqueueAt stores an email that must not be sent before notBefore. Inside a unit of work
+// the email is only queued if the transaction commits.
+func (o *Outbox) EnqueueAt(ctx context.Context, userID int, msg *Message, notBefore time.Time) (int, error) {
+	var owner sql.NullInt64
+	if userID > 0 {
+		owner = sql.NullInt64{Int64: int64(userID), Valid: true}
+	}
+
+	var id int
+	err := o.conn(ctx).QueryRowxContext(ctx, `
+		INSERT INTO email_outbox (user_id, to_address, subject, text_body, html_body, max_attempts, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		owner, msg.To, msg.Subject, msg.Text, msg.HTML, o.retry.MaxRetries+1, notBefore,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	o.logger.Info("Email queued", map[string]interface{}{
+		"email_id": id,
+		"user_id":  userID,
+		"subject":  msg.Subject,
+		"action":   "EMAIL_QUEUED",
+	})
+	return id, nil
+}
+
+// Dispatch sends due emails. Failed sends are retried per the retry policy and marked
+// failed once attempts run out. Returns the number of emails sent.
+func (o *Outbox) Dispatch(ctx context.Context) (int, error) {
+	var rows []outboxRow
+	err := o.db.SelectContext(ctx, &rows, `
+		UPDATE email_outbox
+		SET status = $1, attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status IN ($3, $1) AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, to_address, subject, text_body, html_body, attempts, max_attempts`,
+		StatusSending, sendLease.Seconds(), StatusPending, dispatchBatchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			// Unsent rows keep their lease and are picked up again once it expires
+			return sent, ctx.Err()
+		}
+		if o.deliver(ctx, row) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (o *Outbox) deliver(ctx context.Context, row outboxRow) bool {
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := o.mailer.Send(sendCtx, &Message{To: row.ToAddress, Subject: row.Subject, Text: row.TextBody, HTML: row.HTMLBody})
+	cancel()
+
+	if err == nil {
+		o.markSent(ctx, row)
+		return true
+	}
+
+	if row.Attempts >= row.MaxAttempts {
+		o.markFailed(ctx, row, err)
+		return false
+	}
+
+	delay := o.retryDelay(row.Attempts)
+	if _, dbErr := o.db.ExecContext(ctx, `
+		UPDATE email_outbox
+		SET status = $1, last_error = $2, next_attempt_at = NOW() + make_interval(secs => $3)
+		WHERE id = $4`,
+		StatusPending, err.Error(), delay.Seconds(), row.ID,
+	); dbErr != nil {
+		o.logger.Error("Failed to reschedule email", dbErr, map[string]interface{}{
+			"email_id": row.ID,
+			"action":   "EMAIL_RESCHEDULE_FAILED",
+		})
+	}
+
+	metrics.EmailsTotal.WithLabelValues("retry").Inc()
+	o.logger.Error("Email send failed, will retry", err, map[string]interface{}{
+		"email_id": row.ID,
+		"attempt":  row.Attempts,
+		"retry_in": delay.String(),
+		"action":   "EMAIL_SEND_RETRY",
+	})
+	return false
+}
+
+// conn returns the transaction of the unit of work running in ctx, if any
+func (o *Outbox) conn(ctx context.Context) outboxDB {
+	if db, ok := o.db.(*sqlx.DB); ok {
+		return database.Conn(ctx, db)
+	}
+	return o.db
+}
+
+// retryDelay is how long to wait after the given (1-based) failed attempt
+func (o *Outbox) retryDelay(attempt int) time.Duration {
+	return o.retry.Delay + time.Duration(attempt-1)*o.retry.Backoff
+}
+
+func (o *Outbox) markSent(ctx context.Context, row outboxRow) {
+	if _, err := o.db.ExecContext(ctx, `
+		UPDATE email_outbox SET status = $1, sent_at = NOW(), last_error = NULL WHERE id = $2`,
+		StatusSent, row.ID,
+	); err != nil {
+		o.logger.Error("Failed to mark email as sent", err, map[string]interface{}{
+			"email_id": row.ID,
+			"action":   "EMAIL_MARK_SENT_FAILED",
+		})
+	}
+
+	metrics.EmailsTotal.WithLabelValues("sent").Inc()
+	o.logger.Info("Email sent", map[string]interface{}{
+		"email_id": row.ID,
+		"attempt":  row.Attempts,
+		"action":   "EMAIL_SENT",
+	})
+}
+
+func (o *Outbox) markFailed(ctx context.Context, row outboxRow, sendErr error) {
+	if _, err := o.db.ExecContext(ctx, `
+		UPDATE email_outbox SET status = $1, last_error = $2 WHERE id = $3`,
+		StatusFailed, sendErr.Error(), row.ID,
+	); err != nil {
+		o.logger.Error("Failed to mark email as failed", err, map[string]interface{}{
+			"email_id": row.ID,
+			"action":   "EMAIL_MARK_FAILED_FAILED",
+		})
+	}
+
+	metrics.EmailsTotal.WithLabelValues("failed").Inc()
+	o.logger.Error("Email permanently failed", sendErr, map[string]interface{}{
+		"email_id": row.ID,
+		"attempts": row.Attempts,
+		"action":   "EMAIL_SEND_FAILED",
+	})
+}
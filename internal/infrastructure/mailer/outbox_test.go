@@ -0,0 +1,152 @@
+package mailer
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+)
+
+// fakeOutboxRow is an email_outbox row; every pending row is treated as due
+type fakeOutboxRow struct {
+	outboxRow
+	status    string
+	lastError string
+	retryIn   time.Duration
+}
+
+// fakeOutboxDB plays the email_outbox table for Dispatch and the status updates after a send
+type fakeOutboxDB struct {
+	outboxDB
+	rows []*fakeOutboxRow
+}
+
+func (db *fakeOutboxDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	claimed := dest.(*[]outboxRow)
+	for _, row := range db.rows {
+		if row.status == StatusPending {
+			row.status = StatusSending
+			row.Attempts++
+			*claimed = append(*claimed, row.outboxRow)
+		}
+	}
+	return nil
+}
+
+func (db *fakeOutboxDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var row *fakeOutboxRow
+	find := func(id interface{}) {
+		for _, r := range db.rows {
+			if r.ID == id.(int) {
+				row = r
+			}
+		}
+	}
+
+	switch args[0] {
+	case StatusPending:
+		find(args[3])
+		row.lastError = args[1].(string)
+		row.retryIn = time.Duration(args[2].(float64)) * time.Second
+	case StatusSent:
+		find(args[1])
+		row.lastError = ""
+	case StatusFailed:
+		find(args[2])
+		row.lastError = args[1].(string)
+	}
+	row.status = args[0].(string)
+	return nil, nil
+}
+
+func TestOutboxRetriesFailedSend(t *testing.T) {
+	server, err := NewFakeServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	retry := DefaultRetryPolicy()
+	row := &fakeOutboxRow{
+		outboxRow: outboxRow{ID: 1, ToAddress: "ayse@example.com", Subject: "Şifre sıfırlama isteği", TextBody: "Merhaba\n", MaxAttempts: retry.MaxRetries + 1},
+		status:    StatusPending,
+	}
+	db := &fakeOutboxDB{rows: []*fakeOutboxRow{row}}
+	outbox := &Outbox{db: db, mailer: NewSMTPMailer(server.Config("no-reply@example.com")), retry: retry, logger: logger.NewLogger(nil)}
+
+	// The first two attempts are rejected by the server
+	server.FailNext(2)
+	for attempt, wantDelay := range []time.Duration{time.Minute, 6 * time.Minute} {
+		sent, err := outbox.Dispatch(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sent != 0 || row.status != StatusPending || row.retryIn != wantDelay || !strings.Contains(row.lastError, "451") {
+			t.Fatalf("attempt %d: sent=%d status=%s retry in %s error %q, want pending retry in %s",
+				attempt+1, sent, row.status, row.retryIn, row.lastError, wantDelay)
+		}
+	}
+	if n := len(server.Messages()); n != 0 {
+		t.Fatalf("server captured %d messages after failed attempts", n)
+	}
+
+	sent, err := outbox.Dispatch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || row.status != StatusSent || row.lastError != "" || row.Attempts != 3 {
+		t.Fatalf("third attempt: sent=%d status=%s attempts=%d error %q", sent, row.status, row.Attempts, row.lastError)
+	}
+	messages := server.Messages()
+	if len(messages) != 1 || messages[0].Subject != row.Subject || messages[0].To[0] != row.ToAddress {
+		t.Fatalf("server captured %+v", messages)
+	}
+
+	// Nothing is left to send
+	if sent, _ := outbox.Dispatch(context.Background()); sent != 0 || len(server.Messages()) != 1 {
+		t.Errorf("dispatch after delivery sent %d emails", sent)
+	}
+}
+
+func TestOutboxGivesUpAfterLastAttempt(t *testing.T) {
+	server, err := NewFakeServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	retry := DefaultRetryPolicy()
+	row := &fakeOutboxRow{
+		outboxRow: outboxRow{ID: 1, ToAddress: "ayse@example.com", Subject: "Plain", TextBody: "Hi\n", Attempts: retry.MaxRetries, MaxAttempts: retry.MaxRetries + 1},
+		status:    StatusPending,
+	}
+	db := &fakeOutboxDB{rows: []*fakeOutboxRow{row}}
+	outbox := &Outbox{db: db, mailer: NewSMTPMailer(server.Config("no-reply@example.com")), retry: retry, logger: logger.NewLogger(nil)}
+
+	server.FailNext(1)
+	if _, err := outbox.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if row.status != StatusFailed || !strings.Contains(row.lastError, "451") {
+		t.Fatalf("status=%s error %q, want failed", row.status, row.lastError)
+	}
+
+	// A failed email is not picked up again
+	if sent, _ := outbox.Dispatch(context.Background()); sent != 0 || len(server.Messages()) != 0 {
+		t.Errorf("failed email was sent again")
+	}
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	outbox := NewOutbox(nil, nil, nil, nil)
+
+	// 1m, 6m, 11m, 16m between the five attempts of the default policy
+	for attempt, want := range []time.Duration{time.Minute, 6 * time.Minute, 11 * time.Minute, 16 * time.Minute} {
+		if got := outbox.retryDelay(attempt + 1); got != want {
+			t.Errorf("after attempt %d: retry in %s, want %s", attempt+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// readParts parses a message built by buildMIME and returns its decoded parts by content type
+func readParts(t *testing.T, raw string) (*mail.Message, map[string]string) {
+	t.Helper()
+
+	parsed, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q (%v), want multipart/alternative", mediaType, err)
+	}
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(part) // quoted-printable is decoded by the reader
+		if err != nil {
+			t.Fatal(err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+	return parsed, parts
+}
+
+func TestBuildMIME(t *testing.T) {
+	long := strings.Repeat("çalışma ", 20)
+
+	tests := []struct {
+		name string
+		msg  Message
+		want map[string]string
+	}{
+		{
+			name: "text and html",
+			msg:  Message{To: "ayse@example.com", Subject: "Şifre sıfırlama isteği", Text: "Merhaba,\n" + long + "\n", HTML: "<p>Merhaba</p>"},
+			want: map[string]string{"text/plain": "Merhaba,\r\n" + long + "\r\n", "text/html": "<p>Merhaba</p>"},
+		},
+		{
+			name: "text only",
+			msg:  Message{To: "ayse@example.com", Subject: "Plain", Text: "Hi\n"},
+			want: map[string]string{"text/plain": "Hi\r\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := string(buildMIME("no-reply@example.com", &tt.msg))
+
+			for _, line := range strings.Split(strings.TrimSuffix(raw, "\r\n"), "\r\n") {
+				if strings.Contains(line, "\n") {
+					t.Fatalf("bare LF in %q", line)
+				}
+			}
+
+			parsed, parts := readParts(t, raw)
+			subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			if err != nil || subject != tt.msg.Subject {
+				t.Errorf("subject = %q (%v), want %q", subject, err, tt.msg.Subject)
+			}
+			if got := parsed.Header.Get("From"); got != "no-reply@example.com" {
+				t.Errorf("from = %q", got)
+			}
+			if got := parsed.Header.Get("To"); got != tt.msg.To {
+				t.Errorf("to = %q, want %q", got, tt.msg.To)
+			}
+			if len(parts) != len(tt.want) {
+				t.Fatalf("got %d parts, want %d", len(parts), len(tt.want))
+			}
+			for contentType, want := range tt.want {
+				if parts[contentType] != want {
+					t.Errorf("%s = %q, want %q", contentType, parts[contentType], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSMTPMailerSendsThroughFakeServer(t *testing.T) {
+	server, err := NewFakeServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	mailer := NewSMTPMailer(server.Config("no-reply@example.com"))
+	// A line with a lone dot would end DATA early without dot-stuffing
+	msg := &Message{To: "ayse@example.com", Subject: "Günlük özetiniz", Text: "Merhaba\n.\n..iki nokta\n"}
+
+	if err := mailer.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	got := messages[0]
+	if got.From != "no-reply@example.com" || len(got.To) != 1 || got.To[0] != "ayse@example.com" || got.Subject != msg.Subject {
+		t.Errorf("captured %q -> %v %q", got.From, got.To, got.Subject)
+	}
+	if _, parts := readParts(t, got.Data); parts["text/plain"] != "Merhaba\r\n.\r\n..iki nokta\r\n" {
+		t.Errorf("text = %q", parts["text/plain"])
+	}
+
+	// A rejected message reaches the caller as an error and is not captured
+	server.Reset()
+	server.FailNext(1)
+	if err := mailer.Send(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "451") {
+		t.Errorf("error = %v, want a 451 reply", err)
+	}
+	if err := mailer.Send(context.Background(), msg); err != nil {
+		t.Errorf("send after the failure: %v", err)
+	}
+	if n := len(server.Messages()); n != 1 {
+		t.Errorf("server received %d messages, want 1", n)
+	}
+}
@@ -0,0 +1,119 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Supported template languages; DefaultLanguage is used when a translation is missing
+const (
+	LanguageTurkish = "tr"
+	LanguageEnglish = "en"
+	DefaultLanguage = LanguageTurkish
+)
+
+//go:embed templates/*.html templates/*.txt
+var templateFS embed.FS
+
+// Renderer builds emails from the embedded templates.
+// Each template has a "<name>.<lang>.txt" file that defines "subject" and the plain-text body,
+// and an optional "<name>.<lang>.html" file that defines "content" for the shared layout.
+type Renderer struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// NewRenderer parses every embedded template
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+
+	layout, err := fs.ReadFile(templateFS, "templates/layout.html")
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := fs.Glob(templateFS, "templates/*.*.*")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		base := path.Base(file)
+		key := strings.TrimSuffix(base, path.Ext(base)) // "<name>.<lang>"
+		lang := path.Ext(key)[1:]
+
+		content, err := fs.ReadFile(templateFS, file)
+		if err != nil {
+			return nil, err
+		}
+
+		switch path.Ext(base) {
+		case ".txt":
+			t, err := texttemplate.New(base).Parse(string(content))
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %w", base, err)
+			}
+			if t.Lookup("subject") == nil {
+				return nil, fmt.Errorf("parse %s: missing subject", base)
+			}
+			r.text[key] = t
+		case ".html":
+			t := htmltemplate.New("layout").Funcs(htmltemplate.FuncMap{"lang": func() string { return lang }})
+			if _, err := t.Parse(string(layout)); err != nil {
+				return nil, fmt.Errorf("parse layout.html: %w", err)
+			}
+			if _, err := t.Parse(string(content)); err != nil {
+				return nil, fmt.Errorf("parse %s: %w", base, err)
+			}
+			r.html[key] = t
+		}
+	}
+
+	return r, nil
+}
+
+// Render builds a message from the named template in the given language, falling back to
+// DefaultLanguage. The recipient is left empty.
+func (r *Renderer) Render(name, lang string, data interface{}) (*Message, error) {
+	key := name + "." + lang
+	if _, ok := r.text[key]; !ok {
+		key = name + "." + DefaultLanguage
+	}
+
+	textTmpl, ok := r.text[key]
+	if !ok {
+		return nil, fmt.Errorf("email template not found: %s", name)
+	}
+
+	var subject, text bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	msg := &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}
+
+	if htmlTmpl, ok := r.html[key]; ok {
+		var html bytes.Buffer
+		if err := htmlTmpl.ExecuteTemplate(&html, "layout", data); err != nil {
+			return nil, err
+		}
+		msg.HTML = html.String()
+	}
+
+	return msg, nil
+}
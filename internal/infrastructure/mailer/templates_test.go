@@ -0,0 +1,81 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRendererLanguages(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reset := map[string]interface{}{"Name": "Ayşe", "Link": "https://app.example.com/reset?token=abc&x=1", "Minutes": 30}
+	invitation := map[string]interface{}{"Workspace": "Proje", "Inviter": "Ali", "Role": "editor", "Link": "https://app.example.com/invite", "Days": 7}
+
+	tests := []struct {
+		name     string
+		template string
+		lang     string
+		data     map[string]interface{}
+		subject  string
+		text     []string
+		htmlLang string
+	}{
+		{"turkish", "password_reset", LanguageTurkish, reset, "Şifre sıfırlama isteği", []string{"Merhaba Ayşe,", "30 dakika"}, "tr"},
+		{"english", "password_reset", LanguageEnglish, reset, "Password reset request", []string{"Hi Ayşe,", "30 minutes"}, "en"},
+		{"missing translation falls back to turkish", "password_reset", "de", reset, "Şifre sıfırlama isteği", []string{"Merhaba Ayşe,"}, "tr"},
+		{"turkish role", "workspace_invitation", LanguageTurkish, invitation, "Proje çalışma alanına davet edildiniz", []string{"Ali sizi Proje çalışma alanına düzenleyici olarak davet etti"}, "tr"},
+		{"english role", "workspace_invitation", LanguageEnglish, invitation, "You have been invited to Proje", []string{"Ali has invited you to the Proje workspace as an editor"}, "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := r.Render(tt.template, tt.lang, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg.Subject != tt.subject {
+				t.Errorf("subject = %q, want %q", msg.Subject, tt.subject)
+			}
+			for _, want := range tt.text {
+				if !strings.Contains(msg.Text, want) {
+					t.Errorf("text does not contain %q:\n%s", want, msg.Text)
+				}
+			}
+			if strings.Contains(msg.Text, "<no value>") {
+				t.Errorf("text has an unset field:\n%s", msg.Text)
+			}
+			if !strings.Contains(msg.HTML, `<html lang="`+tt.htmlLang+`">`) {
+				t.Errorf("html is not marked as %q", tt.htmlLang)
+			}
+			if link := tt.data["Link"].(string); !strings.Contains(msg.Text, link) {
+				t.Errorf("text does not contain the link %q", link)
+			}
+		})
+	}
+
+	if _, err := r.Render("missing", LanguageEnglish, nil); err == nil {
+		t.Error("rendering an unknown template succeeded")
+	}
+}
+
+func TestEveryTemplateHasBothLanguages(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key := range r.text {
+		name, _, _ := strings.Cut(key, ".")
+		for _, lang := range []string{LanguageTurkish, LanguageEnglish} {
+			if _, ok := r.text[name+"."+lang]; !ok {
+				t.Errorf("%s has no %s text template", name, lang)
+			}
+			if _, ok := r.html[name+"."+lang]; !ok {
+				t.Errorf("%s has no %s html template", name, lang)
+			}
+		}
+	}
+}
@@ -21,9 +21,98 @@ var (
 		},
 		[]string{"method", "endpoint"},
 	)
+
+	WSMessagesDroppedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ws_messages_dropped_total",
+			Help: "Total number of WebSocket messages dropped because a client's send buffer was full",
+		},
+	)
+
+	WSClientsEvictedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ws_clients_evicted_total",
+			Help: "Total number of WebSocket clients evicted by the server",
+		},
+		[]string{"reason"},
+	)
+
+	WSSendLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "ws_send_latency_seconds",
+			Help:    "Time between a WebSocket message being queued and written to the connection",
+			Buckets: prometheus.DefBuckets,
+		},
+	)
+
+	WSUserConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ws_user_connections",
+			Help: "Number of active WebSocket connections per user",
+		},
+		[]string{"user_id"},
+	)
+
+	EmailsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "emails_total",
+			Help: "Outbox email delivery attempts by result (sent, retry, failed)",
+		},
+		[]string{"result"},
+	)
+
+	WebhookDeliveriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_delivery_attempts_total",
+			Help: "Webhook delivery attempts by result (succeeded, retry, failed)",
+		},
+		[]string{"result"},
+	)
+
+	EventHandlersTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "event_handlers_total",
+			Help: "Event bus handler runs by subscriber and result (ok, error, dropped)",
+		},
+		[]string{"handler", "result"},
+	)
+
+	EventOutboxTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "event_outbox_total",
+			Help: "Outbox event relay attempts by result (published, retry, failed)",
+		},
+		[]string{"result"},
+	)
+
+	RateLimitedRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limited_requests_total",
+			Help: "Requests rejected with 429 by rate limit policy",
+		},
+		[]string{"policy"},
+	)
+
+	LoginLockoutsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "login_lockouts_total",
+			Help: "Login lockouts started after repeated failures, by scope (account, ip)",
+		},
+		[]string{"scope"},
+	)
 )
 
 func Init() {
 	prometheus.MustRegister(HttpRequestsTotal)
 	prometheus.MustRegister(HttpRequestDuration)
+	prometheus.MustRegister(WSMessagesDroppedTotal)
+	prometheus.MustRegister(WSClientsEvictedTotal)
+	prometheus.MustRegister(WSSendLatency)
+	prometheus.MustRegister(WSUserConnections)
+	prometheus.MustRegister(EmailsTotal)
+	prometheus.MustRegister(WebhookDeliveriesTotal)
+	prometheus.MustRegister(EventHandlersTotal)
+	prometheus.MustRegister(EventOutboxTotal)
+	prometheus.MustRegister(RateLimitedRequestsTotal)
+	prometheus.MustRegister(LoginLockoutsTotal)
 }
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+// AccessTokenIdentity is the caller behind a valid personal access token
+type AccessTokenIdentity struct {
+	TokenID   string
+	UserID    int
+	Scopes    []string
+	ExpiresAt time.Time // zero: never expires
+}
+
+// AccessTokenAuthenticator resolves personal access tokens. It returns nil for tokens that
+// are unknown, expired or revoked.
+type AccessTokenAuthenticator interface {
+	AuthenticateAccessToken(ctx context.Context, token string, ip string) (*AccessTokenIdentity, error)
+}
+
+// serveAccessToken authenticates a request carrying a personal access token. The route
+// must be open to tokens and covered by one of the token's scopes.
+func serveAccessToken(w http.ResponseWriter, r *http.Request, next http.Handler, tokens AccessTokenAuthenticator, token string) {
+	identity, err := tokens.AuthenticateAccessToken(r.Context(), token, utils.GetClientIPFromContext(r.Context()))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Token doğrulanamadı", err.Error())
+		return
+	}
+	if identity == nil {
+		resp := utils.ErrorResponse("UNAUTHORIZED", "Erişim token'ı geçersiz", "Token geçersiz, süresi dolmuş veya iptal edilmiş")
+		utils.Return(w, http.StatusUnauthorized, resp)
+		return
+	}
+
+	scope, ok := authz.ScopeFor(r.Method, r.URL.Path)
+	if !ok {
+		utils.ReturnError(w, "FORBIDDEN", "Bu işlem erişim token'ı ile yapılamaz", "route not available to personal access tokens")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), utils.RoleKey, authz.RoleUser)
+	ctx = context.WithValue(ctx, utils.UserIDKey, identity.UserID)
+	ctx = context.WithValue(ctx, utils.TokenIDKey, identity.TokenID)
+	ctx = context.WithValue(ctx, utils.ScopesKey, identity.Scopes)
+	// Zero for tokens that never expire; readers take a zero expiry as no deadline
+	ctx = context.WithValue(ctx, utils.TokenExpiresAtKey, identity.ExpiresAt)
+	if !authz.HasScope(ctx, scope) {
+		utils.ReturnError(w, "FORBIDDEN", "Erişim token'ının bu işlem için yetkisi yok", "missing scope: "+scope)
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
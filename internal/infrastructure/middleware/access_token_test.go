@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+type fakeAccessTokens map[string]*AccessTokenIdentity
+
+func (f fakeAccessTokens) AuthenticateAccessToken(ctx context.Context, token string, ip string) (*AccessTokenIdentity, error) {
+	return f[token], nil
+}
+
+func TestAccessTokenAuthentication(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	tokens := fakeAccessTokens{
+		"pat_reader":  {TokenID: "pat:1", UserID: 7, Scopes: []string{"tasks:read"}, ExpiresAt: expiresAt},
+		"pat_forever": {TokenID: "pat:2", UserID: 7, Scopes: []string{"events:write"}},
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		method     string
+		path       string
+		wantStatus int
+		wantExpiry time.Time
+	}{
+		{name: "unknown token", token: "pat_unknown", method: http.MethodGet, path: "/api/tasks", wantStatus: http.StatusUnauthorized},
+		{name: "scope covers the route", token: "pat_reader", method: http.MethodGet, path: "/api/tasks", wantStatus: http.StatusOK, wantExpiry: expiresAt},
+		{name: "read scope cannot write", token: "pat_reader", method: http.MethodPost, path: "/api/tasks", wantStatus: http.StatusForbidden},
+		{name: "other area", token: "pat_reader", method: http.MethodGet, path: "/api/finance", wantStatus: http.StatusForbidden},
+		{name: "route closed to tokens", token: "pat_reader", method: http.MethodGet, path: "/api/me/sessions", wantStatus: http.StatusForbidden},
+		{name: "event stream closed to tokens", token: "pat_forever", method: http.MethodGet, path: "/api/events/stream", wantStatus: http.StatusForbidden},
+		{name: "token without expiry", token: "pat_forever", method: http.MethodGet, path: "/api/schedule", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reached context.Context
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = r.Context()
+			})
+			handler := AuthMiddleware(nil, nil, tokens)(next)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if reached != nil {
+					t.Fatal("request reached the handler")
+				}
+				return
+			}
+
+			identity := tokens[tt.token]
+			if got := utils.GetUserIDFromContext(reached); got != identity.UserID {
+				t.Errorf("user id = %d, want %d", got, identity.UserID)
+			}
+			if scopes, _ := reached.Value(utils.ScopesKey).([]string); !slices.Equal(scopes, identity.Scopes) {
+				t.Errorf("scopes = %v, want %v", scopes, identity.Scopes)
+			}
+			expiry, ok := reached.Value(utils.TokenExpiresAtKey).(time.Time)
+			if !ok || !expiry.Equal(tt.wantExpiry) {
+				t.Errorf("token expiry = %v (set %v), want %v", expiry, ok, tt.wantExpiry)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+// Require lets only callers holding the permission reach the handler; everyone else gets 403.
+// Routes declare their requirement where they are registered:
+//
+//	router.HandleFunc("/users", middleware.Require(authz.UsersRead, h.GetAllUsers)).Methods("GET")
+func Require(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authz.Can(r.Context(), permission) {
+			utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", "missing permission: "+permission)
+			return
+		}
+		next(w, r)
+	}
+}
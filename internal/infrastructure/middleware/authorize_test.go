@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jwtkeys"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRequire(t *testing.T) {
+	keys, err := jwtkeys.NewManager(jwtkeys.Config{Development: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(roles, permissions []string, expiresIn time.Duration) string {
+		now := time.Now()
+		token, err := keys.Sign(&jwtkeys.Claims{
+			UserID:      1,
+			Roles:       roles,
+			Permissions: permissions,
+			RegisteredClaims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + token
+	}
+	tokens := fakeAccessTokens{"pat_admin": {TokenID: "pat:1", UserID: 1, Scopes: []string{"users:read"}}}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "role grants the permission", authorization: sign([]string{authz.RoleAdmin}, []string{authz.UsersRead, authz.JobsRead}, time.Hour), wantStatus: http.StatusOK},
+		{name: "role without the permission", authorization: sign([]string{authz.RoleUser}, nil, time.Hour), wantStatus: http.StatusForbidden},
+		{name: "other permissions only", authorization: sign([]string{authz.RoleAdmin}, []string{authz.JobsRead}, time.Hour), wantStatus: http.StatusForbidden},
+		{name: "role name alone is not a permission", authorization: sign([]string{authz.RoleAdmin}, nil, time.Hour), wantStatus: http.StatusForbidden},
+		{name: "personal access token", authorization: "Bearer pat_admin", wantStatus: http.StatusForbidden},
+		{name: "expired token", authorization: sign([]string{authz.RoleAdmin}, []string{authz.UsersRead}, -time.Hour), wantStatus: http.StatusUnauthorized},
+		{name: "no token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := AuthMiddleware(keys, nil, tokens)(Require(authz.UsersRead, func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if reached != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("handler reached = %v", reached)
+			}
+		})
+	}
+}
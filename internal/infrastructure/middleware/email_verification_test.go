@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+type fakeVerification map[int]bool
+
+func (f fakeVerification) EmailVerified(ctx context.Context, userID int) (bool, error) {
+	verified, ok := f[userID]
+	if !ok {
+		return false, errors.New("user lookup failed")
+	}
+	return verified, nil
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	// User 1 verified their email, user 2 did not, user 3 cannot be looked up
+	checker := fakeVerification{1: true, 2: false}
+
+	tests := []struct {
+		name       string
+		areas      []string
+		userID     int
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "covered area, verified", areas: []string{"webhooks"}, userID: 1, path: "/api/webhooks", wantStatus: http.StatusOK},
+		{name: "covered area, unverified", areas: []string{"webhooks"}, userID: 2, path: "/api/webhooks/4", wantStatus: http.StatusForbidden},
+		{name: "other area, unverified", areas: []string{"webhooks"}, userID: 2, path: "/api/tasks", wantStatus: http.StatusOK},
+		{name: "area prefix is not the area", areas: []string{"webhooks"}, userID: 2, path: "/api/webhooksx", wantStatus: http.StatusOK},
+		{name: "everything, unverified", areas: []string{"*"}, userID: 2, path: "/api/tasks", wantStatus: http.StatusForbidden},
+		{name: "everything still leaves the account open", areas: []string{"*"}, userID: 2, path: "/api/auth/email/resend", wantStatus: http.StatusOK},
+		{name: "everything still leaves the profile open", areas: []string{"*"}, userID: 2, path: "/api/me", wantStatus: http.StatusOK},
+		{name: "no policy", userID: 2, path: "/api/webhooks", wantStatus: http.StatusOK},
+		{name: "preflight", areas: []string{"*"}, userID: 2, method: http.MethodOptions, path: "/api/tasks", wantStatus: http.StatusOK},
+		{name: "not authenticated", areas: []string{"*"}, path: "/api/tasks", wantStatus: http.StatusOK},
+		{name: "lookup fails", areas: []string{"*"}, userID: 3, path: "/api/tasks", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { reached = true })
+			handler := RequireVerifiedEmail(EmailVerificationPolicy{Areas: tt.areas}, checker)(next)
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, nil)
+			if tt.userID > 0 {
+				req = req.WithContext(context.WithValue(req.Context(), utils.UserIDKey, tt.userID))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if reached != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("handler reached = %v", reached)
+			}
+		})
+	}
+}
+
+func TestEmailVerificationPolicyFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "", want: []string{"webhooks", "calendar"}},
+		{value: " Webhooks , finance ,", want: []string{"webhooks", "finance"}},
+		{value: "none", want: nil},
+		{value: "*", want: []string{"*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("EMAIL_VERIFICATION_REQUIRED_FOR", tt.value)
+			if got := EmailVerificationPolicyFromEnv().Areas; !slices.Equal(got, tt.want) {
+				t.Fatalf("areas = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,86 +2,86 @@ package middleware
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
-	"os"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jwtkeys"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/revocation"
 )
 
-var (
-	jwtKey  []byte
-	jwtOnce sync.Once
-)
+// AuthMiddleware authenticates requests with a Bearer access token verified by keys. Tokens found in
+// the revocation list (logged out, or issued before a logout everywhere) are rejected.
+// Bearer tokens starting with "pat_" are personal access tokens, resolved by tokens and
+// limited to the routes their scopes cover.
+func AuthMiddleware(keys *jwtkeys.Manager, revocations *revocation.List, tokens AccessTokenAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/auth/login" || r.URL.Path == "/api/auth/register" || r.URL.Path == "/api/auth/refresh" || r.URL.Path == "/api/auth/password/forgot" || r.URL.Path == "/api/auth/password/reset" || r.URL.Path == "/api/auth/email/verify" || r.URL.Path == "/api/auth/mfa/verify" || r.URL.Path == "/api/auth/oidc/login" || r.URL.Path == "/api/auth/oidc/callback" || r.URL.Path == "/auth/login" || r.URL.Path == "/auth/register" || r.URL.Path == "/health" || r.Method == "OPTIONS" {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-func getJWTKey() []byte {
-	jwtOnce.Do(func() {
-		key := os.Getenv("JWT_SECRET")
-		if key == "" {
-			log.Fatal("JWT_SECRET environment variable is not set")
-		}
-		jwtKey = []byte(key)
-	})
-	return jwtKey
-}
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				resp := utils.ErrorResponse("UNAUTHORIZED", "Giriş yapmanız gerekiyor", "Token eksik")
+				utils.Return(w, http.StatusUnauthorized, resp)
+				return
+			}
 
-type Claims struct {
-	UserID   int    `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
-	jwt.RegisteredClaims
-}
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				resp := utils.ErrorResponse("UNAUTHORIZED", "Geçersiz token formatı", "Bearer token bekleniyor")
+				utils.Return(w, http.StatusUnauthorized, resp)
+				return
+			}
+			tokenString := parts[1]
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/api/auth/login" || r.URL.Path == "/api/auth/register" || r.URL.Path == "/auth/login" || r.URL.Path == "/auth/register" || r.URL.Path == "/health" || r.Method == "OPTIONS" {
-			next.ServeHTTP(w, r)
-			return
-		}
+			if tokens != nil && strings.HasPrefix(tokenString, authz.PersonalAccessTokenPrefix) {
+				serveAccessToken(w, r, next, tokens, tokenString)
+				return
+			}
 
-		authHeader := r.Header.Get("Authorization")
-		fmt.Printf("DEBUG: Auth Header: %s\n", authHeader)
-		if authHeader == "" {
-			resp := utils.ErrorResponse("UNAUTHORIZED", "Giriş yapmanız gerekiyor", "Token eksik")
-			utils.Return(w, http.StatusUnauthorized, resp)
-			return
-		}
+			claims, err := keys.Verify(tokenString)
+			if err != nil {
+				resp := utils.ErrorResponse("UNAUTHORIZED", "Oturum süresi dolmuş", "Token geçersiz veya süresi dolmuş")
+				utils.Return(w, http.StatusUnauthorized, resp)
+				return
+			}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			resp := utils.ErrorResponse("UNAUTHORIZED", "Geçersiz token formatı", "Bearer token bekleniyor")
-			utils.Return(w, http.StatusUnauthorized, resp)
-			return
-		}
-		tokenString := parts[1]
+			if revocations != nil {
+				var issuedAt time.Time
+				if claims.IssuedAt != nil {
+					issuedAt = claims.IssuedAt.Time
+				}
+				if revocations.IsRevoked(claims.UserID, claims.ID, claims.SessionID, issuedAt) {
+					resp := utils.ErrorResponse("UNAUTHORIZED", "Oturum sonlandırılmış", "Token iptal edilmiş")
+					utils.Return(w, http.StatusUnauthorized, resp)
+					return
+				}
+			}
 
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return getJWTKey(), nil
-		}, jwt.WithLeeway(5*time.Minute))
-
-		if err != nil || !token.Valid {
-			resp := utils.ErrorResponse("UNAUTHORIZED", "Oturum süresi dolmuş", "Token geçersiz veya süresi dolmuş")
-			utils.Return(w, http.StatusUnauthorized, resp)
-			return
-		}
-
-		ctx := context.WithValue(r.Context(), utils.RoleKey, claims.Role)
-		ctx = context.WithValue(ctx, utils.UsernameKey, claims.Username)
-		ctx = context.WithValue(ctx, utils.UserIDKey, claims.UserID)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+			ctx := context.WithValue(r.Context(), utils.RoleKey, claims.Role)
+			ctx = context.WithValue(ctx, utils.RolesKey, claims.Roles)
+			ctx = context.WithValue(ctx, utils.PermissionsKey, claims.Permissions)
+			ctx = context.WithValue(ctx, utils.UsernameKey, claims.Username)
+			ctx = context.WithValue(ctx, utils.UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, utils.TokenIDKey, claims.ID)
+			ctx = context.WithValue(ctx, utils.SessionIDKey, claims.SessionID)
+			if claims.ExpiresAt != nil {
+				ctx = context.WithValue(ctx, utils.TokenExpiresAtKey, claims.ExpiresAt.Time)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 func TimeoutMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip timeout for WebSocket - WebSocket connections are long-lived
-		if r.URL.Path == "/ws" {
+		// Skip timeout for WebSocket and SSE - streaming connections are long-lived
+		if r.URL.Path == "/ws" || r.URL.Path == "/api/events/stream" {
 			next.ServeHTTP(w, r)
 			return
 		}
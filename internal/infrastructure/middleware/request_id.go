@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps client supplied request IDs; maxClientIPLength and
+// maxUserAgentLength cap the forwarded address and user agent, which are client controlled too
+const (
+	maxRequestIDLength = 64
+	maxClientIPLength  = 64
+	maxUserAgentLength = 512
+)
+
+// TrustedProxies are the reverse proxies whose X-Forwarded-For header is believed. With none,
+// the header is ignored and the connection address is the client IP.
+type TrustedProxies struct {
+	Prefixes []netip.Prefix
+}
+
+// TrustedProxiesFromEnv reads TRUSTED_PROXIES, a comma separated list of addresses or CIDR
+// ranges (e.g. "10.0.0.0/8,127.0.0.1"). Entries that do not parse are skipped.
+func TrustedProxiesFromEnv() TrustedProxies {
+	var proxies TrustedProxies
+	for _, entry := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			proxies.Prefixes = append(proxies.Prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			proxies.Prefixes = append(proxies.Prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		}
+	}
+	return proxies
+}
+
+// trusts reports whether ip is one of the trusted proxies
+func (p TrustedProxies) trusts(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p.Prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestIDMiddleware tags every request with an ID (the client's X-Request-ID or a new one)
+// and the client IP, echoes the ID in the response and stores both, with the user agent, in
+// the request context
+func RequestIDMiddleware(proxies TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if requestID == "" || len(requestID) > maxRequestIDLength {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			ctx := context.WithValue(r.Context(), utils.RequestIDKey, requestID)
+			ctx = context.WithValue(ctx, utils.ClientIPKey, clientIP(r, proxies))
+			ctx = context.WithValue(ctx, utils.UserAgentKey, userAgent(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// userAgent returns the User-Agent header, cut to what is worth storing
+func userAgent(r *http.Request) string {
+	ua := r.UserAgent()
+	if len(ua) > maxUserAgentLength {
+		ua = ua[:maxUserAgentLength]
+	}
+	return ua
+}
+
+// clientIP returns the connection address, unless it is a trusted proxy. Then X-Forwarded-For
+// is walked from the right, past the hops added by trusted proxies, to the first address a
+// trusted proxy saw connect; everything left of it was sent by the client and may be forged.
+func clientIP(r *http.Request, proxies TrustedProxies) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !proxies.trusts(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			// A malformed hop cannot come from a trusted proxy; stop at the last address known good
+			break
+		}
+		ip = hop
+		if !proxies.trusts(hop) {
+			break
+		}
+	}
+	if len(ip) > maxClientIPLength {
+		ip = ip[:maxClientIPLength]
+	}
+	return ip
+}
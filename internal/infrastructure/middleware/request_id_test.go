@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+)
+
+func TestClientIP(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 127.0.0.1, not-an-address")
+	proxies := TrustedProxiesFromEnv()
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.7:5000", want: "203.0.113.7"},
+		{name: "direct client spoofing the header", remoteAddr: "203.0.113.7:5000", forwarded: []string{"198.51.100.1"}, want: "203.0.113.7"},
+		{name: "through a trusted proxy", remoteAddr: "10.0.0.2:443", forwarded: []string{"203.0.113.7"}, want: "203.0.113.7"},
+		{name: "spoofed hop before the proxy's", remoteAddr: "10.0.0.2:443", forwarded: []string{"198.51.100.1, 203.0.113.7"}, want: "203.0.113.7"},
+		{name: "chain of trusted proxies", remoteAddr: "127.0.0.1:443", forwarded: []string{"198.51.100.1, 203.0.113.7, 10.1.1.1"}, want: "203.0.113.7"},
+		{name: "header split over lines", remoteAddr: "10.0.0.2:443", forwarded: []string{"198.51.100.1", "203.0.113.7"}, want: "203.0.113.7"},
+		{name: "malformed hop", remoteAddr: "10.0.0.2:443", forwarded: []string{"203.0.113.7, garbage"}, want: "10.0.0.2"},
+		{name: "trusted proxy without header", remoteAddr: "10.0.0.2:443", want: "10.0.0.2"},
+		{name: "only proxies in the header", remoteAddr: "10.0.0.2:443", forwarded: []string{"10.9.9.9"}, want: "10.9.9.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if got := clientIP(req, proxies); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	handler := RequestIDMiddleware(TrustedProxies{})(RateLimit(RateLimitPolicy{Name: "auth", PerMinute: 1, Burst: 2})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := utils.GetClientIPFromContext(r.Context()); ip != "203.0.113.7" {
+				t.Errorf("client IP = %q, want the connection address", ip)
+			}
+		})))
+
+	// A new forged address on every request must not get a fresh bucket
+	for i, forged := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", nil)
+		req.RemoteAddr = "203.0.113.7:5000"
+		req.Header.Set("X-Forwarded-For", forged)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if rec.Code != want {
+			t.Fatalf("request %d: status = %d, want %d", i+1, rec.Code, want)
+		}
+	}
+}
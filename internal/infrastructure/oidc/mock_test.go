@@ -0,0 +1,162 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	mockClientID     = "mock-client"
+	mockClientSecret = "mock-secret"
+	mockKeyID        = "mock-key"
+)
+
+type mockCode struct {
+	redirectURI string
+	nonce       string
+	challenge   string
+}
+
+// mockProvider is an OpenID Connect provider for tests. It approves every authorization
+// request for one fixed account; tamper changes the ID token claims before signing so tests
+// can issue tokens a real provider would not.
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]mockCode
+	tamper func(*Claims)
+	// signWith signs ID tokens with another key than the published one
+	signWith *rsa.PrivateKey
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &mockProvider{key: key, codes: make(map[string]mockCode)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *mockProvider) issuer() string {
+	return p.server.URL
+}
+
+func (p *mockProvider) config() Config {
+	return Config{
+		Issuer:       p.issuer(),
+		ClientID:     mockClientID,
+		ClientSecret: mockClientSecret,
+		RedirectURL:  "http://app.test/api/auth/oidc/callback",
+		Scopes:       []string{"openid", "email"},
+	}
+}
+
+func (p *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeMockJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                 p.issuer(),
+		"authorization_endpoint": p.issuer() + "/authorize",
+		"token_endpoint":         p.issuer() + "/token",
+		"jwks_uri":               p.issuer() + "/jwks",
+	})
+}
+
+func (p *mockProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeMockJSON(w, http.StatusOK, jwks{Keys: []JWK{RSAPublicJWK(mockKeyID, "RS256", &p.key.PublicKey)}})
+}
+
+func (p *mockProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || q.Get("client_id") != mockClientID || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	code, _ := RandomString(24)
+	p.mu.Lock()
+	p.codes[code] = mockCode{redirectURI: q.Get("redirect_uri"), nonce: q.Get("nonce"), challenge: q.Get("code_challenge")}
+	p.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	clientID, secret, _ := r.BasicAuth()
+	if clientID != mockClientID || secret != mockClientSecret {
+		writeMockJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	code, found := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	tamper, signWith := p.tamper, p.signWith
+	p.mu.Unlock()
+	if !found || code.redirectURI != r.PostForm.Get("redirect_uri") || S256Challenge(r.PostForm.Get("code_verifier")) != code.challenge {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := Claims{
+		Nonce:         code.nonce,
+		Email:         "oidc.user@example.com",
+		EmailVerified: true,
+		Name:          "OIDC User",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.issuer(),
+			Subject:   "mock-user",
+			Audience:  jwt.ClaimStrings{mockClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		},
+	}
+	if tamper != nil {
+		tamper(&claims)
+	}
+	if signWith == nil {
+		signWith = p.key
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = mockKeyID
+	signed, err := idToken.SignedString(signWith)
+	if err != nil {
+		writeMockJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeMockJSON(w, http.StatusOK, map[string]interface{}{"access_token": "unused", "token_type": "Bearer", "id_token": signed})
+}
+
+func writeMockJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,131 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// authorize runs the browser part of the flow against the mock provider and returns the code
+// and state it redirected back with
+func authorize(t *testing.T, client *Client, state, nonce, verifier string) (string, string) {
+	t.Helper()
+	authURL, err := client.AuthCodeURL(context.Background(), state, nonce, S256Challenge(verifier), "")
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	browser := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := browser.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestExchange(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		// tamper changes the ID token the provider signs
+		tamper   func(*Claims)
+		signWith *rsa.PrivateKey
+		// nonce and verifier sent at the callback instead of the ones the login started with
+		nonce    string
+		verifier string
+		wantErr  string
+	}{
+		{name: "valid token"},
+		{name: "nonce mismatch", nonce: "another-nonce", wantErr: "nonce mismatch"},
+		{name: "wrong audience", tamper: func(c *Claims) { c.Audience = jwt.ClaimStrings{"someone-else"} }, wantErr: "invalid id token"},
+		{name: "extra audience without azp", tamper: func(c *Claims) { c.Audience = append(c.Audience, "someone-else") }, wantErr: "azp mismatch"},
+		{name: "wrong issuer", tamper: func(c *Claims) { c.Issuer = "https://evil.example.com" }, wantErr: "invalid id token"},
+		{name: "expired", tamper: func(c *Claims) {
+			c.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Minute))
+		}, wantErr: "token is expired"},
+		{name: "no expiry", tamper: func(c *Claims) { c.ExpiresAt = nil }, wantErr: "invalid id token"},
+		{name: "issued in the future", tamper: func(c *Claims) { c.IssuedAt = jwt.NewNumericDate(time.Now().Add(time.Hour)) }, wantErr: "invalid id token"},
+		{name: "no subject", tamper: func(c *Claims) { c.Subject = "" }, wantErr: "no subject"},
+		{name: "signed with an unpublished key", signWith: otherKey, wantErr: "invalid id token"},
+		{name: "PKCE verifier mismatch", verifier: "not-the-verifier", wantErr: "status 400"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newMockProvider(t)
+			provider.tamper = tt.tamper
+			provider.signWith = tt.signWith
+			client := NewClient(provider.config())
+
+			state, nonce, verifier := "state-123", "nonce-123", "verifier-0123456789-0123456789-0123456789"
+			code, returnedState := authorize(t, client, state, nonce, verifier)
+			if returnedState != state {
+				t.Fatalf("state = %q, want %q", returnedState, state)
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+
+			claims, err := client.Exchange(context.Background(), code, verifier, nonce)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Exchange error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			if claims.Subject != "mock-user" || claims.Email != "oidc.user@example.com" || !claims.EmailVerified {
+				t.Fatalf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsReusedCode(t *testing.T) {
+	provider := newMockProvider(t)
+	client := NewClient(provider.config())
+	verifier := "verifier-0123456789-0123456789-0123456789"
+	code, _ := authorize(t, client, "state", "nonce", verifier)
+
+	if _, err := client.Exchange(context.Background(), code, verifier, "nonce"); err != nil {
+		t.Fatalf("first exchange: %v", err)
+	}
+	if _, err := client.Exchange(context.Background(), code, verifier, "nonce"); err == nil {
+		t.Fatal("second exchange of the same code succeeded")
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	provider := newMockProvider(t)
+	config := provider.config()
+	config.Issuer += "/tenant"
+	client := NewClient(config)
+
+	if _, err := client.AuthCodeURL(context.Background(), "state", "nonce", "challenge", ""); err == nil {
+		t.Fatal("AuthCodeURL accepted a provider whose discovery document names another issuer")
+	}
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type failures struct {
+	count       int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// Lockout counts consecutive failures per key, e.g. wrong passwords for an account. From the
+// threshold-th failure on, every failure locks the key, for base at first and twice as long
+// each time after, up to max. A key's failures are forgotten once it has gone forget without
+// one, or on Reset. State is kept in memory, so each instance counts on its own.
+type Lockout struct {
+	threshold int
+	base      time.Duration
+	max       time.Duration
+	forget    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*failures
+	calls   int
+}
+
+// NewLockout creates a lockout that starts locking keys at threshold failures
+func NewLockout(threshold int, base, max, forget time.Duration) *Lockout {
+	return &Lockout{
+		threshold: threshold,
+		base:      base,
+		max:       max,
+		forget:    forget,
+		entries:   make(map[string]*failures),
+	}
+}
+
+// Locked reports whether key is locked and for how much longer
+func (l *Lockout) Locked(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, ok := l.entries[key]
+	if !ok || !now.Before(f.lockedUntil) {
+		return false, 0
+	}
+	return true, f.lockedUntil.Sub(now)
+}
+
+// Fail records a failure for key. It returns the number of consecutive failures and, when
+// this failure locks the key, for how long.
+func (l *Lockout) Fail(key string) (int, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	f, ok := l.entries[key]
+	if !ok || l.forgotten(f, now) {
+		f = &failures{}
+		l.entries[key] = f
+	}
+	f.count++
+	f.last = now
+
+	if f.count < l.threshold {
+		return f.count, 0
+	}
+
+	duration := l.base
+	for i := l.threshold; i < f.count && duration < l.max; i++ {
+		duration *= 2
+	}
+	duration = min(duration, l.max)
+	f.lockedUntil = now.Add(duration)
+	return f.count, duration
+}
+
+// Reset forgets the failures recorded for key, e.g. after a successful login
+func (l *Lockout) Reset(key string) {
+	l.mu.Lock()
+	delete(l.entries, key)
+	l.mu.Unlock()
+}
+
+func (l *Lockout) forgotten(f *failures, now time.Time) bool {
+	return now.Sub(f.last) >= l.forget && !now.Before(f.lockedUntil)
+}
+
+// sweep drops forgotten keys; caller must hold the lock
+func (l *Lockout) sweep(now time.Time) {
+	for key, f := range l.entries {
+		if l.forgotten(f, now) {
+			delete(l.entries, key)
+		}
+	}
+}
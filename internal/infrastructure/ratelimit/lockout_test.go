@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLockout(t *testing.T) {
+	tests := []struct {
+		name string
+		// forget is how long a key goes without failures before they are forgotten
+		forget time.Duration
+		// steps run in order: "fail", "reset" or "wait" (past the forget period)
+		steps []string
+		// wantLocks are the lock durations the failures returned, in order
+		wantLocks  []time.Duration
+		wantLocked bool
+	}{
+		{name: "below the threshold", forget: time.Hour, steps: []string{"fail", "fail"}, wantLocks: []time.Duration{0, 0}},
+		{
+			name:       "locks at the threshold and doubles up to the maximum",
+			forget:     time.Hour,
+			steps:      []string{"fail", "fail", "fail", "fail", "fail", "fail"},
+			wantLocks:  []time.Duration{0, 0, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute},
+			wantLocked: true,
+		},
+		{
+			name:      "reset forgets the failures",
+			forget:    time.Hour,
+			steps:     []string{"fail", "fail", "fail", "reset", "fail"},
+			wantLocks: []time.Duration{0, 0, time.Minute, 0},
+		},
+		{
+			name:      "failures are forgotten after a quiet period",
+			forget:    20 * time.Millisecond,
+			steps:     []string{"fail", "fail", "wait", "fail"},
+			wantLocks: []time.Duration{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLockout(3, time.Minute, 5*time.Minute, tt.forget)
+			var locks []time.Duration
+			for _, step := range tt.steps {
+				switch step {
+				case "fail":
+					_, lockedFor := l.Fail("key")
+					locks = append(locks, lockedFor)
+				case "reset":
+					l.Reset("key")
+				case "wait":
+					time.Sleep(2 * tt.forget)
+				}
+			}
+
+			if !slices.Equal(locks, tt.wantLocks) {
+				t.Errorf("locks = %v, want %v", locks, tt.wantLocks)
+			}
+			locked, retryAfter := l.Locked("key")
+			if locked != tt.wantLocked || (locked && retryAfter <= 0) {
+				t.Errorf("Locked = %v, %v, want locked %v", locked, retryAfter, tt.wantLocked)
+			}
+			if other, _ := l.Locked("other"); other {
+				t.Error("a key without failures is locked")
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many calls pass between sweeps of expired windows
+const sweepEvery = 1024
+
+type bucket struct {
+	start time.Time
+	count int
+}
+
+// Limiter allows up to limit attempts per key in a fixed window. State is kept in memory,
+// so each instance counts on its own.
+type Limiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*bucket
+	calls   int
+}
+
+// New creates a limiter allowing limit attempts per key every window
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{limit: limit, window: window, windows: make(map[string]*bucket)}
+}
+
+// Allow records an attempt for key. It reports whether the attempt is within the limit and,
+// when it is not, how long until the key's window resets.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &bucket{start: now}
+		l.windows[key] = w
+	}
+
+	if w.count >= l.limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// Reset forgets the attempts recorded for key
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	delete(l.windows, key)
+	l.mu.Unlock()
+}
+
+// sweep drops windows that have ended; caller must hold the lock
+func (l *Limiter) sweep(now time.Time) {
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.window {
+			delete(l.windows, key)
+		}
+	}
+}
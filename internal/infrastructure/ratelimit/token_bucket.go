@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type tokens struct {
+	available float64
+	updated   time.Time
+}
+
+// TokenBucket allows bursts of up to burst requests per key, refilled at rate requests per
+// second. Unlike Limiter it has no window edges, so it suits steady request traffic. State is
+// kept in memory, so each instance counts on its own.
+type TokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*tokens
+	calls   int
+}
+
+// NewTokenBucket creates a bucket per key holding burst tokens, refilled at rate per second
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{rate: rate, burst: float64(burst), buckets: make(map[string]*tokens)}
+}
+
+// Allow takes a token for key. It reports whether one was available and, when it was not,
+// how long until the next one is.
+func (b *TokenBucket) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.calls++
+	if b.calls%sweepEvery == 0 {
+		b.sweep(now)
+	}
+
+	t, ok := b.buckets[key]
+	if !ok {
+		t = &tokens{available: b.burst, updated: now}
+		b.buckets[key] = t
+	} else {
+		t.available = math.Min(b.burst, t.available+now.Sub(t.updated).Seconds()*b.rate)
+		t.updated = now
+	}
+
+	if t.available < 1 {
+		return false, time.Duration((1 - t.available) / b.rate * float64(time.Second))
+	}
+	t.available--
+	return true, 0
+}
+
+// sweep drops buckets that have refilled completely, which is the state a new key starts
+// in; caller must hold the lock
+func (b *TokenBucket) sweep(now time.Time) {
+	for key, t := range b.buckets {
+		if t.available+now.Sub(t.updated).Seconds()*b.rate >= b.burst {
+			delete(b.buckets, key)
+		}
+	}
+}
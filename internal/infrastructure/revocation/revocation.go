@@ -0,0 +1,210 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// reloadInterval bounds how long a revocation made by another instance takes to apply here
+	reloadInterval = 30 * time.Second
+	reloadTimeout  = 10 * time.Second
+)
+
+type entry struct {
+	UserID        int        `db:"user_id"`
+	TokenID       *string    `db:"token_id"`
+	RevokedBefore *time.Time `db:"revoked_before"`
+	ExpiresAt     time.Time  `db:"expires_at"`
+}
+
+// List holds the access tokens revoked before their expiry. Entries are stored in the
+// token_revocations table and cached in memory so checks never hit the database.
+// An entry either names a single token (by jti), every token of a login session (by
+// sid, stored as "session:<sid>") or revokes every token a user was issued up to a
+// point in time (logout everywhere). Entries are dropped once the tokens they cover
+// have expired anyway.
+type List struct {
+	db     *sqlx.DB
+	logger *logger.ZapLogger
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time // jti or session key -> token expiry
+	users  map[int]time.Time    // user ID -> tokens issued up to this instant are revoked
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewList creates an empty revocation list; call Load or Start to fill it from the database
+func NewList(db *sqlx.DB, logger *logger.ZapLogger) *List {
+	return &List{
+		db:     db,
+		logger: logger,
+		tokens: make(map[string]time.Time),
+		users:  make(map[int]time.Time),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Load replaces the cache with the unexpired entries in the database
+func (l *List) Load(ctx context.Context) error {
+	var rows []entry
+	if err := l.db.SelectContext(ctx, &rows, `
+		SELECT user_id, token_id, revoked_before, expires_at
+		FROM token_revocations
+		WHERE expires_at > $1`, time.Now()); err != nil {
+		return err
+	}
+
+	tokens := make(map[string]time.Time, len(rows))
+	users := make(map[int]time.Time)
+	for _, row := range rows {
+		if row.TokenID != nil {
+			tokens[*row.TokenID] = row.ExpiresAt
+		}
+		if row.RevokedBefore != nil && row.RevokedBefore.After(users[row.UserID]) {
+			users[row.UserID] = *row.RevokedBefore
+		}
+	}
+
+	l.mu.Lock()
+	l.tokens = tokens
+	l.users = users
+	l.mu.Unlock()
+	return nil
+}
+
+// Start loads the list and keeps reloading it until Stop is called
+func (l *List) Start() {
+	go l.run()
+}
+
+// Stop ends the reload loop
+func (l *List) Stop() {
+	close(l.stop)
+	<-l.done
+}
+
+func (l *List) run() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+		if err := l.Load(ctx); err != nil {
+			l.logger.Error("Token revocation list reload failed", err, map[string]interface{}{
+				"action": "TOKEN_REVOCATIONS_RELOAD_FAILED",
+			})
+		}
+		cancel()
+
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RevokeToken revokes a single access token until it expires
+func (l *List) RevokeToken(ctx context.Context, userID int, tokenID string, expiresAt time.Time) error {
+	if tokenID == "" || !expiresAt.After(time.Now()) {
+		return nil
+	}
+
+	if _, err := l.db.ExecContext(ctx, `
+		INSERT INTO token_revocations (user_id, token_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token_id) DO NOTHING`,
+		userID, tokenID, expiresAt, time.Now(),
+	); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.tokens[tokenID] = expiresAt
+	l.mu.Unlock()
+	return nil
+}
+
+// RevokeSession revokes every access token issued to a login session. until must be at
+// least as late as the expiry of the longest-lived token the session was issued.
+func (l *List) RevokeSession(ctx context.Context, userID int, sessionID string, until time.Time) error {
+	if sessionID == "" {
+		return nil
+	}
+	return l.RevokeToken(ctx, userID, sessionKey(sessionID), until)
+}
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// RevokeUser revokes every access token issued to the user so far. until must be at
+// least as late as the expiry of the longest-lived token issued before now.
+func (l *List) RevokeUser(ctx context.Context, userID int, until time.Time) error {
+	now := time.Now()
+	if _, err := l.db.ExecContext(ctx, `
+		INSERT INTO token_revocations (user_id, revoked_before, expires_at, created_at)
+		VALUES ($1, $2, $3, $2)`,
+		userID, now, until,
+	); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	if now.After(l.users[userID]) {
+		l.users[userID] = now
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// IsRevoked reports whether the token with the given jti and session, issued to the user at
+// issuedAt, was revoked. Token timestamps have second precision, so a token issued in the same
+// second as a logout everywhere counts as revoked.
+func (l *List) IsRevoked(userID int, tokenID, sessionID string, issuedAt time.Time) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if tokenID != "" {
+		if _, revoked := l.tokens[tokenID]; revoked {
+			return true
+		}
+	}
+	if sessionID != "" {
+		if _, revoked := l.tokens[sessionKey(sessionID)]; revoked {
+			return true
+		}
+	}
+	if cutoff, ok := l.users[userID]; ok && issuedAt.Unix() <= cutoff.Unix() {
+		return true
+	}
+	return false
+}
+
+// Purge deletes entries whose tokens expired before the given time
+func (l *List) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := l.db.ExecContext(ctx, `DELETE FROM token_revocations WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	for tokenID, expiresAt := range l.tokens {
+		if expiresAt.Before(before) {
+			delete(l.tokens, tokenID)
+		}
+	}
+	l.mu.Unlock()
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,67 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+)
+
+func TestIsRevoked(t *testing.T) {
+	logoutAt := time.Date(2026, 10, 1, 12, 0, 0, 500_000_000, time.UTC)
+	l := NewList(nil, logger.NewLogger(nil))
+	l.tokens["jti-revoked"] = logoutAt.Add(time.Hour)
+	l.tokens[sessionKey("sid-revoked")] = logoutAt.Add(time.Hour)
+	l.users[3] = logoutAt
+
+	tests := []struct {
+		name      string
+		userID    int
+		tokenID   string
+		sessionID string
+		issuedAt  time.Time
+		want      bool
+	}{
+		{name: "revoked token", userID: 1, tokenID: "jti-revoked", issuedAt: logoutAt, want: true},
+		{name: "token of a revoked session", userID: 1, tokenID: "jti-live", sessionID: "sid-revoked", issuedAt: logoutAt, want: true},
+		{name: "session id is not a token id", userID: 1, tokenID: "sid-revoked", issuedAt: logoutAt},
+		{name: "issued before logout everywhere", userID: 3, tokenID: "jti-live", issuedAt: logoutAt.Add(-time.Minute), want: true},
+		{name: "issued in the same second", userID: 3, tokenID: "jti-live", issuedAt: logoutAt.Truncate(time.Second), want: true},
+		{name: "issued after logout everywhere", userID: 3, tokenID: "jti-live", issuedAt: logoutAt.Add(time.Second)},
+		{name: "other user", userID: 4, tokenID: "jti-live", issuedAt: logoutAt.Add(-time.Minute)},
+		{name: "token without ids", userID: 1, issuedAt: logoutAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.IsRevoked(tt.userID, tt.tokenID, tt.sessionID, tt.issuedAt); got != tt.want {
+				t.Fatalf("IsRevoked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevokeTokenSkipsTokensThatCannotBeUsed(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenID   string
+		expiresAt time.Time
+	}{
+		{name: "no token id", expiresAt: time.Now().Add(time.Hour)},
+		{name: "already expired", tokenID: "jti-old", expiresAt: time.Now().Add(-time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Writing the entry would panic on the nil database
+			l := NewList(nil, logger.NewLogger(nil))
+			if err := l.RevokeToken(context.Background(), 1, tt.tokenID, tt.expiresAt); err != nil {
+				t.Fatal(err)
+			}
+			if len(l.tokens) != 0 {
+				t.Fatalf("entries = %v, want none", l.tokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed of the RFC 6238 test vectors, "12345678901234567890"
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		at       time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		// RFC 6238 appendix B, cut to the last 6 digits
+		{name: "rfc vector 59", secret: rfcSecret, code: "287082", at: time.Unix(59, 0), wantStep: 1, wantOK: true},
+		{name: "rfc vector 1111111109", secret: rfcSecret, code: "081804", at: time.Unix(1111111109, 0), wantStep: 37037036, wantOK: true},
+		{name: "rfc vector 1234567890", secret: rfcSecret, code: "005924", at: time.Unix(1234567890, 0), wantStep: 41152263, wantOK: true},
+		{name: "rfc vector 2000000000", secret: rfcSecret, code: "279037", at: time.Unix(2000000000, 0), wantStep: 66666666, wantOK: true},
+		{name: "lowercase secret", secret: strings.ToLower(rfcSecret), code: "287082", at: time.Unix(59, 0), wantStep: 1, wantOK: true},
+		{name: "previous period within the skew", secret: rfcSecret, code: "287082", at: time.Unix(59+30, 0), wantStep: 1, wantOK: true},
+		{name: "next period within the skew", secret: rfcSecret, code: "081804", at: time.Unix(1111111109-30, 0), wantStep: 37037036, wantOK: true},
+		{name: "beyond the skew", secret: rfcSecret, code: "287082", at: time.Unix(59+60, 0)},
+		{name: "wrong code", secret: rfcSecret, code: "287083", at: time.Unix(59, 0)},
+		{name: "eight digit code", secret: rfcSecret, code: "94287082", at: time.Unix(59, 0)},
+		{name: "empty code", secret: rfcSecret, code: "", at: time.Unix(59, 0)},
+		{name: "invalid secret", secret: "not base32!", code: "287082", at: time.Unix(59, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, tt.at)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Fatalf("Validate = %d, %v, want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Fatal("two secrets are equal")
+	}
+	key, err := encoding.DecodeString(a)
+	if err != nil || len(key) != secretSize {
+		t.Fatalf("secret decodes to %d bytes (%v), want %d", len(key), err, secretSize)
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("Acme App", "ada@example.com", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Acme App:ada@example.com" {
+		t.Fatalf("URI = %s", u)
+	}
+	params := u.Query()
+	for name, want := range map[string]string{"secret": rfcSecret, "issuer": "Acme App", "digits": "6", "period": "30", "algorithm": "SHA1"} {
+		if params.Get(name) != want {
+			t.Errorf("%s = %q, want %q", name, params.Get(name), want)
+		}
+	}
+}
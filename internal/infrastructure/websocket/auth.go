@@ -0,0 +1,178 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jwtkeys"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// authSubprotocol is the Sec-WebSocket-Protocol marker that precedes the token:
+	//   Sec-WebSocket-Protocol: bearer, <jwt>
+	authSubprotocol = "bearer"
+
+	// ticketTTL is how long a one-time connection ticket stays valid
+	ticketTTL = 30 * time.Second
+)
+
+var (
+	ErrTicketInvalid = errors.New("ticket invalid or expired")
+	ErrTokenMissing  = errors.New("token required")
+	ErrTokenRevoked  = errors.New("token revoked")
+)
+
+// ticket is a single-use credential that lets a browser open /ws without putting the JWT in the URL
+type ticket struct {
+	credentials Credentials
+	expiresAt   time.Time
+}
+
+// TicketStore keeps short-lived, single-use connection tickets in memory
+type TicketStore struct {
+	tickets map[string]ticket
+	ttl     time.Duration
+	mu      sync.Mutex
+}
+
+// NewTicketStore creates a new ticket store
+func NewTicketStore(ttl time.Duration) *TicketStore {
+	return &TicketStore{
+		tickets: make(map[string]ticket),
+		ttl:     ttl,
+	}
+}
+
+// Issue creates a new ticket for the credentials of the JWT used to request it. The
+// connection opened with it still expires together with that JWT, and the ticket does not
+// outlive it either; a zero ExpiresAt is a credential that does not expire.
+func (s *TicketStore) Issue(credentials Credentials) (string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	value := hex.EncodeToString(buf)
+	expiresAt := time.Now().Add(s.ttl)
+	if !credentials.ExpiresAt.IsZero() && credentials.ExpiresAt.Before(expiresAt) {
+		expiresAt = credentials.ExpiresAt
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired()
+	s.tickets[value] = ticket{
+		credentials: credentials,
+		expiresAt:   expiresAt,
+	}
+
+	return value, expiresAt, nil
+}
+
+// Redeem consumes a ticket and returns the credentials it was issued for
+func (s *TicketStore) Redeem(value string) (Credentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, exists := s.tickets[value]
+	if !exists {
+		return Credentials{}, ErrTicketInvalid
+	}
+	delete(s.tickets, value)
+
+	if time.Now().After(t.expiresAt) {
+		return Credentials{}, ErrTicketInvalid
+	}
+
+	return t.credentials, nil
+}
+
+// purgeExpired drops stale tickets; caller must hold the lock
+func (s *TicketStore) purgeExpired() {
+	now := time.Now()
+	for value, t := range s.tickets {
+		if now.After(t.expiresAt) {
+			delete(s.tickets, value)
+		}
+	}
+}
+
+// accessToken holds the access token claims the WebSocket layer relies on
+type accessToken struct {
+	userID    int
+	tokenID   string
+	sessionID string
+	issuedAt  time.Time
+	expiresAt time.Time
+}
+
+// parseToken verifies an access token with the shared key manager, the same way the auth
+// middleware does
+func parseToken(keys *jwtkeys.Manager, tokenString string) (accessToken, error) {
+	claims, err := keys.Verify(tokenString)
+	if err != nil {
+		return accessToken{}, err
+	}
+	parsed := accessToken{
+		userID:    claims.UserID,
+		tokenID:   claims.ID,
+		sessionID: claims.SessionID,
+		expiresAt: claims.ExpiresAt.Time,
+	}
+	if claims.IssuedAt != nil {
+		parsed.issuedAt = claims.IssuedAt.Time
+	}
+	return parsed, nil
+}
+
+// tokenFromSubprotocol extracts the JWT from "Sec-WebSocket-Protocol: bearer, <jwt>"
+func tokenFromSubprotocol(r *http.Request) string {
+	protocols := websocket.Subprotocols(r)
+	for i := 0; i < len(protocols)-1; i++ {
+		if protocols[i] == authSubprotocol {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
+
+// loadAllowedOrigins reads WS_ALLOWED_ORIGINS (comma separated, e.g. "https://app.example.com")
+func loadAllowedOrigins() map[string]bool {
+	origins := make(map[string]bool)
+	for _, origin := range strings.Split(os.Getenv("WS_ALLOWED_ORIGINS"), ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			origins[strings.ToLower(origin)] = true
+		}
+	}
+	return origins
+}
+
+// checkOrigin allows requests without an Origin header (non-browser clients),
+// origins on the allow-list, and same-host origins when no allow-list is configured
+func checkOrigin(allowed map[string]bool) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		if len(allowed) > 0 {
+			return allowed[strings.ToLower(strings.TrimRight(origin, "/"))]
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		return strings.EqualFold(u.Host, r.Host)
+	}
+}
@@ -1,8 +1,12 @@
 package websocket
 
 import (
+	"encoding/json"
+	"sync"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/metrics"
 	"github.com/gorilla/websocket"
 )
 
@@ -10,25 +14,145 @@ const (
 	writeWait      = 10 * time.Second
 	pongWait       = 60 * time.Second
 	pingPeriod     = (pongWait * 9) / 10
-	maxMessageSize = 512
+	maxMessageSize = 4096
+
+	// reauthWindow is how long before token expiry the client is asked to re-authenticate
+	reauthWindow = 2 * time.Minute
+)
+
+// Application close codes (4000-4999 are reserved for private use by RFC 6455)
+const (
+	CloseTokenExpired = 4001
+	CloseTokenRevoked = 4003
 )
 
+// Credentials describe what the access token behind a connection grants: the user, the
+// login session the token belongs to (empty if it has none) and when the token expires
+type Credentials struct {
+	UserID    int
+	SessionID string
+	ExpiresAt time.Time
+}
+
+// TokenValidator validates an access token and returns its credentials
+type TokenValidator func(token string) (Credentials, error)
+
+// closeRequest asks the write pump to send a close frame and drop the connection
+type closeRequest struct {
+	code   int
+	reason string
+}
+
+// outbound is a serialized message waiting in a client's send buffer
+type outbound struct {
+	id       string
+	data     []byte
+	queuedAt time.Time
+}
+
+// inboundMessage is a message sent by the client over the socket
+type inboundMessage struct {
+	Type  string `json:"type"`
+	Token string `json:"token,omitempty"`
+}
+
 type Client struct {
-	hub    *Hub
-	conn   *websocket.Conn
-	send   chan []byte
-	userID int
+	hub       *Hub
+	conn      *websocket.Conn
+	send      chan outbound
+	userID    int
+	sessionID string
+	validate  TokenValidator
+	expiresAt time.Time
+	refreshed chan struct{}
+	closing   chan closeRequest
+	mu        sync.RWMutex
+
+	// Backpressure state, guarded by sendMu
+	sendMu         sync.Mutex
+	dropped        int
+	saturatedSince time.Time
+	evicted        bool
 }
 
-func NewClient(hub *Hub, conn *websocket.Conn, userID int) *Client {
+func NewClient(hub *Hub, conn *websocket.Conn, credentials Credentials, validate TokenValidator) *Client {
 	return &Client{
-		hub:    hub,
-		conn:   conn,
-		send:   make(chan []byte, 256),
-		userID: userID,
+		hub:       hub,
+		conn:      conn,
+		send:      make(chan outbound, hub.config.SendBufferSize),
+		userID:    credentials.UserID,
+		sessionID: credentials.SessionID,
+		validate:  validate,
+		expiresAt: credentials.ExpiresAt,
+		refreshed: make(chan struct{}, 1),
+		closing:   make(chan closeRequest, 1),
+	}
+}
+
+// ExpiresAt returns when the client's current access token expires
+func (c *Client) ExpiresAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.expiresAt
+}
+
+// SessionID returns the login session of the client's current access token
+func (c *Client) SessionID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sessionID
+}
+
+// Close asks the client's write pump to close the connection with the given code and reason (non-blocking)
+func (c *Client) Close(code int, reason string) {
+	select {
+	case c.closing <- closeRequest{code: code, reason: reason}:
+	default:
+		// A close is already pending
 	}
 }
 
+// enqueue offers a message to the client's send buffer without blocking.
+// A client whose buffer stays full for too long, or that drops too many
+// messages in a row, is evicted with a CloseSlowConsumer close frame.
+func (c *Client) enqueue(msg outbound) bool {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.evicted {
+		return false
+	}
+
+	select {
+	case c.send <- msg:
+		c.dropped = 0
+		c.saturatedSince = time.Time{}
+		return true
+	default:
+	}
+
+	metrics.WSMessagesDroppedTotal.Inc()
+	c.dropped++
+	if c.saturatedSince.IsZero() {
+		c.saturatedSince = time.Now()
+	}
+
+	cfg := c.hub.config
+	if c.dropped >= cfg.MaxDroppedMessages || time.Since(c.saturatedSince) >= cfg.SlowConsumerTimeout {
+		c.evicted = true
+		metrics.WSClientsEvictedTotal.WithLabelValues("slow_consumer").Inc()
+		c.hub.logger.Error("Evicting slow WebSocket consumer", nil, map[string]interface{}{
+			"user_id":         c.userID,
+			"dropped":         c.dropped,
+			"saturated_since": c.saturatedSince,
+			"action":          "WS_CLIENT_EVICTED",
+		})
+		c.Close(CloseSlowConsumer, "slow consumer")
+	}
+
+	return false
+}
+
 func (c *Client) ReadPump() {
 	defer func() {
 		c.hub.unregister <- c
@@ -43,7 +167,7 @@ func (c *Client) ReadPump() {
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				c.hub.logger.Error("WebSocket read error", err, map[string]interface{}{
@@ -53,13 +177,61 @@ func (c *Client) ReadPump() {
 			}
 			break
 		}
+
+		var msg inboundMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.Type == TypeAuth {
+			c.reauthenticate(msg.Token)
+		}
+	}
+}
+
+// reauthenticate swaps in a fresh access token so the connection outlives the original one
+func (c *Client) reauthenticate(token string) {
+	if c.validate == nil {
+		return
 	}
+
+	credentials, err := c.validate(token)
+	if err != nil || credentials.UserID != c.userID {
+		c.hub.logger.Error("WebSocket re-auth failed", err, map[string]interface{}{
+			"user_id": c.userID,
+			"action":  "WS_REAUTH_FAILED",
+		})
+		c.hub.PublishToUser(c.userID, NewMessage(c.userID, events.Error{
+			Message: "Re-authentication failed",
+		}))
+		return
+	}
+
+	c.mu.Lock()
+	c.expiresAt = credentials.ExpiresAt
+	c.sessionID = credentials.SessionID
+	c.mu.Unlock()
+
+	select {
+	case c.refreshed <- struct{}{}:
+	default:
+	}
+
+	c.hub.logger.Info("WebSocket client re-authenticated", map[string]interface{}{
+		"user_id":    c.userID,
+		"expires_at": credentials.ExpiresAt,
+		"action":     "WS_REAUTH",
+	})
 }
 
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
+	expiry := time.NewTimer(0)
+	reauth := time.NewTimer(0)
+	armExpiry(expiry, reauth, c.ExpiresAt())
 	defer func() {
 		ticker.Stop()
+		expiry.Stop()
+		reauth.Stop()
 		c.conn.Close()
 	}()
 
@@ -76,23 +248,75 @@ func (c *Client) WritePump() {
 			if err != nil {
 				return
 			}
-			w.Write(message)
+			w.Write(message.data)
+			batch := []outbound{message}
 
 			n := len(c.send)
 			for i := 0; i < n; i++ {
+				next := <-c.send
 				w.Write([]byte{'\n'})
-				w.Write(<-c.send)
+				w.Write(next.data)
+				batch = append(batch, next)
 			}
 
 			if err := w.Close(); err != nil {
 				return
 			}
 
+			now := time.Now()
+			for _, m := range batch {
+				metrics.WSSendLatency.Observe(now.Sub(m.queuedAt).Seconds())
+			}
+
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
+
+		case <-c.refreshed:
+			armExpiry(expiry, reauth, c.ExpiresAt())
+
+		case <-reauth.C:
+			c.writeJSON(NewMessage(c.userID, events.AuthExpiring{
+				ExpiresAt: c.ExpiresAt(),
+			}))
+
+		case <-expiry.C:
+			c.writeClose(CloseTokenExpired, "token expired")
+			return
+
+		case req := <-c.closing:
+			c.writeClose(req.code, req.reason)
+			return
 		}
 	}
 }
+
+// armExpiry sets the timers that close the connection when its token expires and ask for a
+// fresh token shortly before. Both stay stopped for a zero expiry, a credential that does not expire.
+func armExpiry(expiry, reauth *time.Timer, expiresAt time.Time) {
+	expiry.Stop()
+	reauth.Stop()
+	if expiresAt.IsZero() {
+		return
+	}
+	expiry.Reset(time.Until(expiresAt))
+	reauth.Reset(time.Until(expiresAt.Add(-reauthWindow)))
+}
+
+// writeJSON writes a message directly to the connection, bypassing the send buffer
+func (c *Client) writeJSON(msg *Message) {
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	c.conn.WriteJSON(msg)
+}
+
+func (c *Client) writeClose(code int, reason string) {
+	c.hub.logger.Info("Closing WebSocket connection", map[string]interface{}{
+		"user_id": c.userID,
+		"code":    code,
+		"reason":  reason,
+		"action":  "WS_CLIENT_CLOSED_BY_SERVER",
+	})
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+}
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jwtkeys"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+)
+
+func TestConnectionExpiry(t *testing.T) {
+	keys, err := jwtkeys.NewManager(jwtkeys.Config{Development: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := logger.NewLogger(nil)
+	hub := NewHub(log, DefaultConfig())
+	go hub.Run()
+	handler := NewHandler(hub, log, keys, nil)
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleConnection))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	tests := []struct {
+		name string
+		dial func(t *testing.T) (*websocket.Conn, error)
+		// wantClose is the close code expected within closeWithin; 0 expects the connection to stay open
+		wantClose   int
+		closeWithin time.Duration
+	}{
+		{
+			name: "token expiry closes the connection",
+			dial: func(t *testing.T) (*websocket.Conn, error) {
+				now := time.Now()
+				token, err := keys.Sign(&jwtkeys.Claims{UserID: 5, RegisteredClaims: jwt.RegisteredClaims{
+					IssuedAt:  jwt.NewNumericDate(now),
+					ExpiresAt: jwt.NewNumericDate(now.Add(time.Second)),
+				}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				dialer := websocket.Dialer{Subprotocols: []string{authSubprotocol, token}}
+				conn, _, err := dialer.Dial(wsURL, nil)
+				return conn, err
+			},
+			wantClose:   CloseTokenExpired,
+			closeWithin: 3 * time.Second,
+		},
+		{
+			name: "credentials without expiry stay connected",
+			dial: func(t *testing.T) (*websocket.Conn, error) {
+				ticket, _, err := handler.tickets.Issue(Credentials{UserID: 6})
+				if err != nil {
+					t.Fatal(err)
+				}
+				conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?ticket="+ticket, nil)
+				return conn, err
+			},
+			closeWithin: 500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := tt.dial(t)
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+
+			conn.SetReadDeadline(time.Now().Add(tt.closeWithin))
+			for {
+				_, _, err = conn.ReadMessage()
+				if err != nil {
+					break
+				}
+			}
+
+			var netErr net.Error
+			if tt.wantClose == 0 {
+				if !errors.As(err, &netErr) || !netErr.Timeout() {
+					t.Fatalf("connection ended with %v, want it still open", err)
+				}
+				return
+			}
+			if !websocket.IsCloseError(err, tt.wantClose) {
+				t.Fatalf("connection ended with %v, want close code %d", err, tt.wantClose)
+			}
+		})
+	}
+}
+
+func TestTicketStore(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials Credentials
+		wait        time.Duration
+		redeemTwice bool
+		wantErr     bool
+	}{
+		{name: "redeems once", credentials: Credentials{UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}},
+		{name: "credentials without expiry", credentials: Credentials{UserID: 1}},
+		{name: "second redeem", credentials: Credentials{UserID: 1}, redeemTwice: true, wantErr: true},
+		{name: "ticket expired", credentials: Credentials{UserID: 1}, wait: 80 * time.Millisecond, wantErr: true},
+		{name: "token expires before the ticket", credentials: Credentials{UserID: 1, ExpiresAt: time.Now().Add(20 * time.Millisecond)}, wait: 40 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewTicketStore(50 * time.Millisecond)
+			value, expiresAt, err := store.Issue(tt.credentials)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.credentials.ExpiresAt.IsZero() && expiresAt.After(tt.credentials.ExpiresAt) {
+				t.Fatalf("ticket expires at %v, after its token (%v)", expiresAt, tt.credentials.ExpiresAt)
+			}
+			time.Sleep(tt.wait)
+
+			got, err := store.Redeem(value)
+			if tt.redeemTwice && err == nil {
+				got, err = store.Redeem(value)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrTicketInvalid) {
+					t.Fatalf("Redeem error = %v, want %v", err, ErrTicketInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Redeem: %v", err)
+			}
+			if got != tt.credentials {
+				t.Fatalf("credentials = %+v, want %+v", got, tt.credentials)
+			}
+		})
+	}
+}
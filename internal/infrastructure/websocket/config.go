@@ -0,0 +1,53 @@
+package websocket
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// CloseSlowConsumer is sent when a client cannot keep up with its message stream
+const CloseSlowConsumer = 4008
+
+// Config tunes per-client buffering and slow-consumer eviction
+type Config struct {
+	SendBufferSize      int           // Messages buffered per client before drops start
+	MaxDroppedMessages  int           // Consecutive drops after which a client is evicted
+	SlowConsumerTimeout time.Duration // How long a client may stay saturated before eviction
+	ReplayBufferSize    int           // Recent messages kept per user for stream resume
+	ReplayTTL           time.Duration // How long an idle user's replay buffer is kept
+}
+
+// DefaultConfig returns sensible defaults for hub configuration
+func DefaultConfig() Config {
+	return Config{
+		SendBufferSize:      256,
+		MaxDroppedMessages:  100,
+		SlowConsumerTimeout: 10 * time.Second,
+		ReplayBufferSize:    100,
+		ReplayTTL:           10 * time.Minute,
+	}
+}
+
+// ConfigFromEnv reads WS_SEND_BUFFER_SIZE, WS_MAX_DROPPED_MESSAGES,
+// WS_SLOW_CONSUMER_TIMEOUT (Go duration, e.g. "10s") and WS_REPLAY_BUFFER_SIZE,
+// falling back to defaults
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+
+	if v, err := strconv.Atoi(os.Getenv("WS_SEND_BUFFER_SIZE")); err == nil && v > 0 {
+		cfg.SendBufferSize = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("WS_MAX_DROPPED_MESSAGES")); err == nil && v > 0 {
+		cfg.MaxDroppedMessages = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("WS_SLOW_CONSUMER_TIMEOUT")); err == nil && v > 0 {
+		cfg.SlowConsumerTimeout = v
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("WS_REPLAY_BUFFER_SIZE")); err == nil && v >= 0 {
+		cfg.ReplayBufferSize = v
+	}
+
+	return cfg
+}
@@ -2,39 +2,72 @@ package websocket
 
 import (
 	"net/http"
-	"os"
-	"strconv"
+	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jwtkeys"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/revocation"
+	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for now
-	},
+type Handler struct {
+	hub         *Hub
+	logger      *logger.ZapLogger
+	tickets     *TicketStore
+	keys        *jwtkeys.Manager
+	revocations *revocation.List
+	upgrader    websocket.Upgrader
 }
 
-type Handler struct {
-	hub    *Hub
-	logger *logger.ZapLogger
+// NewHandler creates the WebSocket handler. Tokens are verified by keys; those in the
+// revocation list are refused both when connecting and when re-authenticating an open connection
+func NewHandler(hub *Hub, logger *logger.ZapLogger, keys *jwtkeys.Manager, revocations *revocation.List) *Handler {
+	return &Handler{
+		hub:         hub,
+		logger:      logger,
+		tickets:     NewTicketStore(ticketTTL),
+		keys:        keys,
+		revocations: revocations,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			Subprotocols:    []string{authSubprotocol},
+			CheckOrigin:     checkOrigin(loadAllowedOrigins()),
+		},
+	}
 }
 
-func NewHandler(hub *Hub, logger *logger.ZapLogger) *Handler {
-	return &Handler{hub: hub, logger: logger}
+// RegisterRoutes registers the protected ticket endpoint (the /ws route itself is registered before middleware)
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/ws/ticket", h.IssueTicket).Methods("POST")
 }
 
-func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
-	token := r.URL.Query().Get("token")
-	if token == "" {
-		http.Error(w, "Token required", http.StatusUnauthorized)
+// IssueTicket returns a single-use ticket for opening /ws?ticket=...
+// POST /api/ws/ticket
+func (h *Handler) IssueTicket(w http.ResponseWriter, r *http.Request) {
+	tokenExpiresAt, _ := r.Context().Value(utils.TokenExpiresAtKey).(time.Time)
+
+	value, expiresAt, err := h.tickets.Issue(Credentials{
+		UserID:    utils.GetUserIDFromContext(r.Context()),
+		SessionID: utils.GetSessionIDFromContext(r.Context()),
+		ExpiresAt: tokenExpiresAt,
+	})
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Bağlantı bileti oluşturulamadı", err.Error())
 		return
 	}
 
-	userID, err := h.validateToken(token)
+	utils.WriteJson(w, map[string]interface{}{
+		"ticket":     value,
+		"expires_at": expiresAt,
+	}, http.StatusOK, "Bağlantı bileti oluşturuldu")
+}
+
+func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
+	credentials, err := h.authenticate(r)
 	if err != nil {
 		h.logger.Error("WebSocket auth failed", err, map[string]interface{}{
 			"action": "WS_AUTH_FAILED",
@@ -43,7 +76,9 @@ func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	conn, err := upgrader.Upgrade(w, r, nil)
+	userID, expiresAt := credentials.UserID, credentials.ExpiresAt
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		h.logger.Error("WebSocket upgrade failed", err, map[string]interface{}{
 			"user_id": userID,
@@ -52,36 +87,42 @@ func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := NewClient(h.hub, conn, userID)
+	client := NewClient(h.hub, conn, credentials, h.validateToken)
 	h.hub.register <- client
 
 	go client.WritePump()
 	go client.ReadPump()
 
 	// Send welcome message
-	h.hub.PublishToUser(userID, NewMessage(TypeConnected, userID, map[string]interface{}{
-		"message": "Connected to WebSocket",
+	h.hub.PublishToUser(userID, NewMessage(userID, events.Connected{
+		Message:   "Connected to WebSocket",
+		ExpiresAt: expiresAt,
 	}))
 }
 
-func (h *Handler) validateToken(tokenString string) (int, error) {
-	jwtKey := []byte(os.Getenv("JWT_SECRET"))
-
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
-	if err != nil {
-		return 0, err
+// authenticate accepts either a one-time ticket (?ticket=) or a JWT sent as
+// "Sec-WebSocket-Protocol: bearer, <jwt>". Tokens in the query string are not accepted.
+func (h *Handler) authenticate(r *http.Request) (Credentials, error) {
+	if value := r.URL.Query().Get("ticket"); value != "" {
+		return h.tickets.Redeem(value)
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if userIDFloat, ok := claims["user_id"].(float64); ok {
-			return int(userIDFloat), nil
-		}
-		if userIDStr, ok := claims["user_id"].(string); ok {
-			return strconv.Atoi(userIDStr)
-		}
+	token := tokenFromSubprotocol(r)
+	if token == "" {
+		return Credentials{}, ErrTokenMissing
 	}
 
-	return 0, jwt.ErrSignatureInvalid
+	return h.validateToken(token)
+}
+
+// validateToken verifies an access token and checks it against the revocation list
+func (h *Handler) validateToken(tokenString string) (Credentials, error) {
+	token, err := parseToken(h.keys, tokenString)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if h.revocations != nil && h.revocations.IsRevoked(token.userID, token.tokenID, token.sessionID, token.issuedAt) {
+		return Credentials{}, ErrTokenRevoked
+	}
+	return Credentials{UserID: token.userID, SessionID: token.sessionID, ExpiresAt: token.expiresAt}, nil
 }
@@ -2,34 +2,48 @@ package websocket
 
 import (
 	"encoding/json"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/metrics"
 )
 
+// DeliveryFilter decides whether a message sent to everyone should reach a given user
+type DeliveryFilter func(userID int, eventType string) bool
+
 // Hub manages WebSocket connections and message broadcasting
 type Hub struct {
-	rooms      map[int]*Room // userID -> Room
+	rooms      map[int]*Room         // userID -> Room
+	history    map[int]*replayBuffer // userID -> recent messages
 	broadcast  chan *Message
 	register   chan *Client
 	unregister chan *Client
 	mu         sync.RWMutex
 	logger     *logger.ZapLogger
+	config     Config
+	filter     DeliveryFilter
 }
 
 // NewHub creates a new WebSocket hub
-func NewHub(logger *logger.ZapLogger) *Hub {
+func NewHub(logger *logger.ZapLogger, config Config) *Hub {
 	return &Hub{
 		rooms:      make(map[int]*Room),
+		history:    make(map[int]*replayBuffer),
 		broadcast:  make(chan *Message, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		logger:     logger,
+		config:     config,
 	}
 }
 
 // Run starts the hub's main event loop
 func (h *Hub) Run() {
+	pruneTicker := time.NewTicker(time.Minute)
+	defer pruneTicker.Stop()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -38,6 +52,8 @@ func (h *Hub) Run() {
 			h.removeClient(client)
 		case message := <-h.broadcast:
 			h.sendToUser(message.UserID, message)
+		case <-pruneTicker.C:
+			h.pruneHistory()
 		}
 	}
 }
@@ -47,12 +63,34 @@ func (h *Hub) addClient(client *Client) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	h.addClientLocked(client)
+}
+
+// subscribe registers a client and returns the messages it missed since lastEventID.
+// Both happen under the hub lock so no message is lost or duplicated in between.
+func (h *Hub) subscribe(client *Client, lastEventID string) (missed []outbound, found bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if lastEventID != "" {
+		if buffer, exists := h.history[client.userID]; exists {
+			missed, found = buffer.since(lastEventID)
+		}
+	}
+
+	h.addClientLocked(client)
+	return missed, found
+}
+
+// addClientLocked adds a client to its room; caller must hold the lock
+func (h *Hub) addClientLocked(client *Client) {
 	room, exists := h.rooms[client.userID]
 	if !exists {
 		room = NewRoom(client.userID)
 		h.rooms[client.userID] = room
 	}
 	room.AddClient(client)
+	metrics.WSUserConnections.WithLabelValues(strconv.Itoa(client.userID)).Set(float64(room.ClientCount()))
 
 	h.logger.Info("WebSocket client connected", map[string]interface{}{
 		"user_id":       client.userID,
@@ -72,6 +110,9 @@ func (h *Hub) removeClient(client *Client) {
 
 		if room.IsEmpty() {
 			delete(h.rooms, client.userID)
+			metrics.WSUserConnections.DeleteLabelValues(strconv.Itoa(client.userID))
+		} else {
+			metrics.WSUserConnections.WithLabelValues(strconv.Itoa(client.userID)).Set(float64(room.ClientCount()))
 		}
 	}
 
@@ -83,14 +124,6 @@ func (h *Hub) removeClient(client *Client) {
 
 // sendToUser sends a message to all clients of a specific user
 func (h *Hub) sendToUser(userID int, message *Message) {
-	h.mu.RLock()
-	room, exists := h.rooms[userID]
-	h.mu.RUnlock()
-
-	if !exists {
-		return
-	}
-
 	data, err := json.Marshal(message)
 	if err != nil {
 		h.logger.Error("Failed to marshal WebSocket message", err, map[string]interface{}{
@@ -101,7 +134,27 @@ func (h *Hub) sendToUser(userID int, message *Message) {
 		return
 	}
 
-	room.Broadcast(data, h.logger)
+	msg := outbound{id: message.MessageID, data: data, queuedAt: time.Now()}
+
+	// Recording and fan-out happen under the lock so a resuming subscriber
+	// sees each message exactly once, either replayed or live
+	h.mu.Lock()
+	buffer, exists := h.history[userID]
+	if !exists {
+		buffer = newReplayBuffer(h.config.ReplayBufferSize)
+		h.history[userID] = buffer
+	}
+	buffer.add(msg)
+
+	room, exists := h.rooms[userID]
+	if exists {
+		room.Broadcast(msg, h.logger)
+	}
+	h.mu.Unlock()
+
+	if !exists {
+		return
+	}
 
 	h.logger.Info("WebSocket message sent", map[string]interface{}{
 		"user_id": userID,
@@ -110,6 +163,22 @@ func (h *Hub) sendToUser(userID int, message *Message) {
 	})
 }
 
+// pruneHistory drops replay buffers of users without connections that have been idle past ReplayTTL
+func (h *Hub) pruneHistory() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := time.Now().Add(-h.config.ReplayTTL)
+	for userID, buffer := range h.history {
+		if _, connected := h.rooms[userID]; connected {
+			continue
+		}
+		if buffer.lastActivity().Before(cutoff) {
+			delete(h.history, userID)
+		}
+	}
+}
+
 // PublishToUser queues a message for broadcast to a specific user (non-blocking)
 func (h *Hub) PublishToUser(userID int, msg *Message) {
 	msg.UserID = userID
@@ -138,7 +207,13 @@ func (h *Hub) BroadcastToUsers(userIDs []int, message *Message) {
 	}
 }
 
-// BroadcastToAll sends a message to all connected users
+// SetDeliveryFilter installs a per-user filter for BroadcastToAll (e.g. notification preferences).
+// Must be called before the hub starts serving.
+func (h *Hub) SetDeliveryFilter(filter DeliveryFilter) {
+	h.filter = filter
+}
+
+// BroadcastToAll sends a message to all connected users that the delivery filter allows
 func (h *Hub) BroadcastToAll(message *Message) {
 	h.mu.RLock()
 	userIDs := make([]int, 0, len(h.rooms))
@@ -148,7 +223,12 @@ func (h *Hub) BroadcastToAll(message *Message) {
 	h.mu.RUnlock()
 
 	for _, userID := range userIDs {
-		h.BroadcastToUser(userID, message)
+		if h.filter != nil && !h.filter(userID, message.Type) {
+			continue
+		}
+		// Each user gets a copy since BroadcastToUser stamps the recipient on the message
+		userMessage := *message
+		h.BroadcastToUser(userID, &userMessage)
 	}
 }
 
@@ -180,6 +260,56 @@ func (h *Hub) GetConnectedUserCount() int {
 	return len(h.rooms)
 }
 
+// DisconnectUser closes every connection of a user, e.g. after their token was revoked
+func (h *Hub) DisconnectUser(userID int, code int, reason string) {
+	h.mu.RLock()
+	room, exists := h.rooms[userID]
+	h.mu.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	for _, client := range room.GetClients() {
+		client.Close(code, reason)
+	}
+
+	h.logger.Info("WebSocket user disconnected by server", map[string]interface{}{
+		"user_id": userID,
+		"code":    code,
+		"reason":  reason,
+		"action":  "WS_USER_DISCONNECTED",
+	})
+}
+
+// DisconnectSession closes the connections opened with tokens of one login session,
+// e.g. after the user signed that session out
+func (h *Hub) DisconnectSession(userID int, sessionID string, code int, reason string) {
+	h.mu.RLock()
+	room, exists := h.rooms[userID]
+	h.mu.RUnlock()
+
+	if !exists || sessionID == "" {
+		return
+	}
+
+	closed := 0
+	for _, client := range room.GetClients() {
+		if client.SessionID() == sessionID {
+			client.Close(code, reason)
+			closed++
+		}
+	}
+
+	h.logger.Info("WebSocket session disconnected by server", map[string]interface{}{
+		"user_id": userID,
+		"closed":  closed,
+		"code":    code,
+		"reason":  reason,
+		"action":  "WS_SESSION_DISCONNECTED",
+	})
+}
+
 // Register adds a client to the hub (called externally)
 func (h *Hub) Register(client *Client) {
 	h.register <- client
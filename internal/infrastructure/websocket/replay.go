@@ -0,0 +1,50 @@
+package websocket
+
+import "time"
+
+// replayBuffer keeps the most recent messages of a user so stream
+// consumers can resume after a reconnect (SSE Last-Event-ID)
+type replayBuffer struct {
+	entries []outbound
+	size    int
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{
+		entries: make([]outbound, 0, size),
+		size:    size,
+	}
+}
+
+// add appends a message, discarding the oldest one once the buffer is full
+func (b *replayBuffer) add(msg outbound) {
+	if b.size <= 0 {
+		return
+	}
+	if len(b.entries) == b.size {
+		copy(b.entries, b.entries[1:])
+		b.entries = b.entries[:b.size-1]
+	}
+	b.entries = append(b.entries, msg)
+}
+
+// since returns all messages after the given ID. found is false when the ID
+// is no longer (or never was) in the buffer, meaning the consumer missed messages.
+func (b *replayBuffer) since(id string) (messages []outbound, found bool) {
+	for i, entry := range b.entries {
+		if entry.id == id {
+			result := make([]outbound, len(b.entries)-i-1)
+			copy(result, b.entries[i+1:])
+			return result, true
+		}
+	}
+	return nil, false
+}
+
+// lastActivity returns when the newest message was recorded
+func (b *replayBuffer) lastActivity() time.Time {
+	if len(b.entries) == 0 {
+		return time.Time{}
+	}
+	return b.entries[len(b.entries)-1].queuedAt
+}
@@ -50,16 +50,13 @@ func (r *Room) ClientCount() int {
 }
 
 // Broadcast sends a message to all clients in the room
-func (r *Room) Broadcast(data []byte, logger *logger.ZapLogger) {
+func (r *Room) Broadcast(msg outbound, logger *logger.ZapLogger) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	for client := range r.clients {
-		select {
-		case client.send <- data:
-			// Message queued successfully
-		default:
-			// Client buffer full, will be cleaned up by hub
+		if !client.enqueue(msg) {
+			// Client buffer full; saturated clients are evicted by enqueue
 			logger.Error("Client send buffer full, dropping message", nil, map[string]interface{}{
 				"user_id": r.userID,
 				"action":  "WS_BUFFER_FULL",
@@ -0,0 +1,158 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/metrics"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// sseHeartbeatPeriod keeps idle proxies from closing the stream
+	sseHeartbeatPeriod = 15 * time.Second
+
+	// sseRetry is the reconnect delay suggested to EventSource clients
+	sseRetry = 3 * time.Second
+)
+
+// SSEHandler streams hub messages as Server-Sent Events for clients that cannot use WebSocket
+type SSEHandler struct {
+	hub    *Hub
+	logger *logger.ZapLogger
+}
+
+// NewSSEHandler creates a new SSE handler
+func NewSSEHandler(hub *Hub, logger *logger.ZapLogger) *SSEHandler {
+	return &SSEHandler{hub: hub, logger: logger}
+}
+
+// RegisterRoutes registers the SSE stream route on the protected /api router
+func (h *SSEHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/events/stream", h.Stream).Methods("GET")
+}
+
+// Stream delivers the same messages as /ws over a text/event-stream response
+// GET /api/events/stream
+func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+	expiresAt, _ := r.Context().Value(utils.TokenExpiresAtKey).(time.Time)
+
+	rc := http.NewResponseController(w)
+	// The stream outlives the server's WriteTimeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Akış başlatılamadı", err.Error())
+		return
+	}
+
+	// EventSource sends Last-Event-ID on reconnect; the query parameter covers the first connect
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	client := NewClient(h.hub, nil, Credentials{
+		UserID:    userID,
+		SessionID: utils.GetSessionIDFromContext(r.Context()),
+		ExpiresAt: expiresAt,
+	}, nil)
+	missed, found := h.hub.subscribe(client, lastEventID)
+	defer h.hub.Unregister(client)
+
+	h.logger.Info("SSE client connected", map[string]interface{}{
+		"user_id":       userID,
+		"last_event_id": lastEventID,
+		"replayed":      len(missed),
+		"action":        "SSE_CLIENT_CONNECTED",
+	})
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+
+	if lastEventID != "" && !found {
+		// The client missed more than we buffered; ask it to refetch state
+		resync := NewMessage(userID, events.DeviceSync{
+			Reason: "replay_gap",
+		})
+		writeSSEMessage(w, resync)
+	}
+	for _, msg := range missed {
+		writeSSEEvent(w, msg)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatPeriod)
+	defer heartbeat.Stop()
+
+	// A zero expiry means the credential does not expire; the nil channel never fires
+	var expired <-chan time.Time
+	if !expiresAt.IsZero() {
+		expiry := time.NewTimer(time.Until(expiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case msg, ok := <-client.send:
+			if !ok {
+				return
+			}
+			writeSSEEvent(w, msg)
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			metrics.WSSendLatency.Observe(time.Since(msg.queuedAt).Seconds())
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			if err := rc.Flush(); err != nil {
+				return
+			}
+
+		case <-expired:
+			writeSSEMessage(w, NewMessage(userID, events.AuthExpiring{
+				ExpiresAt: expiresAt,
+			}))
+			rc.Flush()
+			return
+
+		case req := <-client.closing:
+			h.logger.Info("Closing SSE stream", map[string]interface{}{
+				"user_id": userID,
+				"code":    req.code,
+				"reason":  req.reason,
+				"action":  "SSE_CLIENT_CLOSED_BY_SERVER",
+			})
+			return
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, msg outbound) {
+	fmt.Fprintf(w, "id: %s\ndata: %s\n\n", msg.id, msg.data)
+}
+
+// writeSSEMessage writes a control message without an id so it does not move the client's resume point
+func writeSSEMessage(w http.ResponseWriter, msg *Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
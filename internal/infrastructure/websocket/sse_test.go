@@ -0,0 +1,109 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+)
+
+// readSSEData returns the data lines of the stream as they arrive; the channel closes with it
+func readSSEData(t *testing.T, resp *http.Response) <-chan string {
+	t.Helper()
+	lines := make(chan string, 16)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				lines <- data
+			}
+		}
+	}()
+	return lines
+}
+
+func TestSSEStreamExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn time.Duration // 0: a credential without expiry
+		wantEnd   bool
+	}{
+		{name: "closes when the token expires", expiresIn: 100 * time.Millisecond, wantEnd: true},
+		{name: "stays open without an expiry", expiresIn: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.NewLogger(nil)
+			hub := NewHub(log, DefaultConfig())
+			go hub.Run()
+			handler := NewSSEHandler(hub, log)
+
+			var expiresAt time.Time
+			if tt.expiresIn > 0 {
+				expiresAt = time.Now().Add(tt.expiresIn)
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), utils.UserIDKey, 5)
+				ctx = context.WithValue(ctx, utils.TokenExpiresAtKey, expiresAt)
+				handler.Stream(w, r.WithContext(ctx))
+			}))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			lines := readSSEData(t, resp)
+
+			if tt.wantEnd {
+				select {
+				case data := <-lines:
+					if !strings.Contains(data, events.TypeAuthExpiring) {
+						t.Fatalf("first message = %s, want %s", data, events.TypeAuthExpiring)
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatal("stream did not announce the expiry")
+				}
+				select {
+				case _, open := <-lines:
+					if open {
+						t.Fatal("stream sent more after the expiry")
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatal("stream stayed open after the expiry")
+				}
+				return
+			}
+
+			// Past the point an expiry would have fired, the stream is still open and delivering
+			time.Sleep(200 * time.Millisecond)
+			select {
+			case data, open := <-lines:
+				t.Fatalf("stream without expiry sent %q (open %v)", data, open)
+			default:
+			}
+			if hub.GetActiveConnections(5) != 1 {
+				t.Fatal("stream without expiry is not connected")
+			}
+			hub.PublishToUser(5, NewMessage(5, events.DeviceSync{Reason: "test"}))
+			select {
+			case data, open := <-lines:
+				if !open || !strings.Contains(data, events.TypeDeviceSync) {
+					t.Fatalf("got %q (open %v), want the published message", data, open)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("stream without expiry did not deliver")
+			}
+		})
+	}
+}
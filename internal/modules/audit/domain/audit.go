@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Operations recorded in the audit log
+const (
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+	OperationRestore = "restore"
+	OperationPurge   = "purge"
+	// OperationSecurity records a security event, such as a failed login or a new password;
+	// it has no snapshots and secrets it touched are named without their values
+	OperationSecurity = "security"
+)
+
+// Entities lists the entity types that have an audit trail
+var Entities = []string{
+	"component", "course", "event", "goal", "habit", "journal", "lifearea",
+	"note", "note_link", "person", "schedule", "task", "token", "transaction",
+	"user", "webhook", "workspace",
+}
+
+// IsEntity reports whether an entity type has an audit trail
+func IsEntity(entityType string) bool {
+	for _, e := range Entities {
+		if e == entityType {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredFields change on every write and would clutter each diff
+var ignoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+// Entry is one mutation of an entity. Before and After are the entity's snapshots around
+// the change (nil before a create and after a delete or purge); Changes lists the differing fields.
+type Entry struct {
+	ID         int64
+	UserID     int
+	ActorID    int
+	EntityType string
+	EntityID   int
+	Operation  string
+	Changes    []Change
+	Before     map[string]interface{}
+	After      map[string]interface{}
+	EventType  string
+	EventID    int64
+	RequestID  string
+	IP         string
+	CreatedAt  time.Time
+}
+
+// Change is one field's value before and after a mutation
+type Change struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Filter narrows an audit listing
+type Filter struct {
+	EntityType string
+	Limit      int
+	Offset     int
+}
+
+// Diff compares two snapshots field by field, sorted by field name
+func Diff(before, after map[string]interface{}) []Change {
+	fields := make(map[string]bool, len(before)+len(after))
+	for f := range before {
+		fields[f] = true
+	}
+	for f := range after {
+		fields[f] = true
+	}
+
+	changes := []Change{}
+	for f := range fields {
+		if ignoredFields[f] {
+			continue
+		}
+		if !reflect.DeepEqual(before[f], after[f]) {
+			changes = append(changes, Change{Field: f, Before: before[f], After: after[f]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
@@ -0,0 +1,9 @@
+package dto
+
+// ListAuditRequest is built from the query string of GET /audit
+type ListAuditRequest struct {
+	Entity   string `json:"entity,omitempty"`
+	EntityID int    `json:"id,omitempty" validate:"min=0"`
+	Page     int    `json:"page" validate:"min=1"`
+	Limit    int    `json:"limit" validate:"min=1,max=100"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/domain"
+)
+
+type EntryResponse struct {
+	ID         int64                  `json:"id"`
+	EntityType string                 `json:"entity"`
+	EntityID   int                    `json:"entity_id"`
+	Operation  string                 `json:"operation"`
+	EventType  string                 `json:"event_type"`
+	ActorID    *int                   `json:"actor_id"`
+	Changes    []domain.Change        `json:"changes"`
+	Before     map[string]interface{} `json:"before"`
+	After      map[string]interface{} `json:"after"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	IP         string                 `json:"ip,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+type AuditListResponse struct {
+	Items []*EntryResponse `json:"items"`
+	Total int              `json:"total"`
+	Page  int              `json:"page"`
+	Limit int              `json:"limit"`
+}
+
+// TimelineResponse is the change history of one entity, oldest first
+type TimelineResponse struct {
+	EntityType string           `json:"entity"`
+	EntityID   int              `json:"entity_id"`
+	Entries    []*EntryResponse `json:"entries"`
+}
+
+func ToEntryResponse(e *domain.Entry) *EntryResponse {
+	if e == nil {
+		return nil
+	}
+	resp := &EntryResponse{ID: e.ID, EntityType: e.EntityType, EntityID: e.EntityID, Operation: e.Operation, EventType: e.EventType, Changes: e.Changes, Before: e.Before, After: e.After, RequestID: e.RequestID, IP: e.IP, CreatedAt: e.CreatedAt}
+	if e.ActorID != 0 {
+		actorID := e.ActorID
+		resp.ActorID = &actorID
+	}
+	if resp.Changes == nil {
+		resp.Changes = []domain.Change{}
+	}
+	return resp
+}
+
+func ToEntryResponseList(entries []*domain.Entry) []*EntryResponse {
+	result := make([]*EntryResponse, len(entries))
+	for i, e := range entries {
+		result[i] = ToEntryResponse(e)
+	}
+	return result
+}
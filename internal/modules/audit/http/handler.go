@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/dto"
+	auditService "github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/service"
+	"github.com/gorilla/mux"
+)
+
+const defaultPageSize = 20
+
+type Handler struct {
+	service auditService.AuditService
+}
+
+func NewHandler(service auditService.AuditService) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/audit", h.List).Methods("GET")
+}
+
+func (h *Handler) getUserID(r *http.Request) int {
+	return utils.GetUserIDFromContext(r.Context())
+}
+
+// List returns the user's audit log, newest first, or with entity and id the change
+// timeline of one entity, oldest first
+// GET /api/audit?entity=task&page=1&limit=20
+// GET /api/audit?entity=task&id=12
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.ListAuditRequest{
+		Entity: query.Get("entity"),
+		Page:   1,
+		Limit:  defaultPageSize,
+	}
+
+	if idStr := query.Get("id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+			return
+		}
+		if req.Entity == "" {
+			utils.ReturnError(w, "BAD_REQUEST", "ID ile birlikte varlık tipi belirtilmelidir", "entity is required with id")
+			return
+		}
+		req.EntityID = id
+	}
+	if pageStr := query.Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sayfa", err.Error())
+			return
+		}
+		req.Page = page
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz limit", err.Error())
+			return
+		}
+		req.Limit = limit
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	if req.EntityID != 0 {
+		timeline, err := h.service.History(r.Context(), req.Entity, req.EntityID, h.getUserID(r))
+		if err != nil {
+			h.handleError(w, err, "Değişiklik geçmişi getirilemedi")
+			return
+		}
+		utils.WriteJson(w, timeline, http.StatusOK, "Değişiklik geçmişi getirildi")
+		return
+	}
+
+	entries, err := h.service.List(r.Context(), &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Denetim kayıtları getirilemedi")
+		return
+	}
+	utils.WriteJson(w, entries, http.StatusOK, "Denetim kayıtları getirildi")
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string) {
+	switch err.Error() {
+	case "audit history not found":
+		utils.ReturnError(w, "NOT_FOUND", "Değişiklik geçmişi bulunamadı", err.Error())
+	case "invalid entity type":
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz varlık tipi", err.Error())
+	default:
+		utils.ReturnError(w, "INTERNAL_ERROR", message, err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/domain"
+)
+
+type EntryModel struct {
+	ID         int64     `db:"id"`
+	UserID     int       `db:"user_id"`
+	ActorID    *int      `db:"actor_id"`
+	EntityType string    `db:"entity_type"`
+	EntityID   int       `db:"entity_id"`
+	Operation  string    `db:"operation"`
+	Changes    []byte    `db:"changes"`
+	Before     []byte    `db:"before_state"`
+	After      []byte    `db:"after_state"`
+	EventType  string    `db:"event_type"`
+	EventID    *int64    `db:"event_id"`
+	RequestID  *string   `db:"request_id"`
+	IP         *string   `db:"ip"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (m *EntryModel) ToDomain() *domain.Entry {
+	if m == nil {
+		return nil
+	}
+	e := &domain.Entry{ID: m.ID, UserID: m.UserID, EntityType: m.EntityType, EntityID: m.EntityID, Operation: m.Operation, EventType: m.EventType, CreatedAt: m.CreatedAt}
+	if m.ActorID != nil {
+		e.ActorID = *m.ActorID
+	}
+	if m.EventID != nil {
+		e.EventID = *m.EventID
+	}
+	if m.RequestID != nil {
+		e.RequestID = *m.RequestID
+	}
+	if m.IP != nil {
+		e.IP = *m.IP
+	}
+	json.Unmarshal(m.Changes, &e.Changes)
+	e.Before = decodeState(m.Before)
+	e.After = decodeState(m.After)
+	return e
+}
+
+func FromDomain(e *domain.Entry) (*EntryModel, error) {
+	if e == nil {
+		return nil, nil
+	}
+	changes := e.Changes
+	if changes == nil {
+		changes = []domain.Change{}
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+	before, err := encodeState(e.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := encodeState(e.After)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &EntryModel{ID: e.ID, UserID: e.UserID, EntityType: e.EntityType, EntityID: e.EntityID, Operation: e.Operation, Changes: changesJSON, Before: before, After: after, EventType: e.EventType, CreatedAt: e.CreatedAt}
+	if e.ActorID != 0 {
+		m.ActorID = &e.ActorID
+	}
+	if e.EventID != 0 {
+		m.EventID = &e.EventID
+	}
+	if e.RequestID != "" {
+		m.RequestID = &e.RequestID
+	}
+	if e.IP != "" {
+		m.IP = &e.IP
+	}
+	return m, nil
+}
+
+func encodeState(state map[string]interface{}) ([]byte, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
+
+func decodeState(raw []byte) map[string]interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil
+	}
+	return state
+}
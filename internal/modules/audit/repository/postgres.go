@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresRepository struct{ db *sqlx.DB }
+
+func NewPostgresRepository(db *sqlx.DB) AuditRepository { return &postgresRepository{db: db} }
+
+const entryColumns = `id, user_id, actor_id, entity_type, entity_id, operation, changes, before_state, after_state,
+	event_type, event_id, request_id, ip, created_at`
+
+func (r *postgresRepository) Create(ctx context.Context, e *domain.Entry) error {
+	model, err := FromDomain(e)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO audit_log (user_id, actor_id, entity_type, entity_id, operation, changes, before_state, after_state,
+			event_type, event_id, request_id, ip, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (event_id) WHERE event_id IS NOT NULL DO NOTHING`
+	_, err = r.db.ExecContext(ctx, query, model.UserID, model.ActorID, model.EntityType, model.EntityID, model.Operation,
+		model.Changes, model.Before, model.After, model.EventType, model.EventID, model.RequestID, model.IP, model.CreatedAt)
+	return err
+}
+
+func (r *postgresRepository) LatestState(ctx context.Context, entityType string, entityID int) (map[string]interface{}, error) {
+	var state []byte
+	err := r.db.GetContext(ctx, &state, `SELECT COALESCE(after_state, before_state) FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2 AND (after_state IS NOT NULL OR before_state IS NOT NULL)
+		ORDER BY id DESC LIMIT 1`, entityType, entityID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeState(state), nil
+}
+
+// History returns the entity's entries oldest first
+func (r *postgresRepository) History(ctx context.Context, userID int, entityType string, entityID int, limit int) ([]*domain.Entry, error) {
+	query := `SELECT ` + entryColumns + ` FROM audit_log
+		WHERE user_id = $1 AND entity_type = $2 AND entity_id = $3 ORDER BY id LIMIT $4`
+	var models []EntryModel
+	if err := r.db.SelectContext(ctx, &models, query, userID, entityType, entityID, limit); err != nil {
+		return nil, err
+	}
+	entries := make([]*domain.Entry, len(models))
+	for i := range models {
+		entries[i] = models[i].ToDomain()
+	}
+	return entries, nil
+}
+
+// List returns one page of the user's audit log, newest first, together with the total matching the filter
+func (r *postgresRepository) List(ctx context.Context, userID int, filter domain.Filter) ([]*domain.Entry, int, error) {
+	where := `WHERE user_id = $1`
+	args := []interface{}{userID}
+	if filter.EntityType != "" {
+		args = append(args, filter.EntityType)
+		where += fmt.Sprintf(` AND entity_type = $%d`, len(args))
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM audit_log `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM audit_log %s ORDER BY id DESC LIMIT $%d OFFSET $%d`,
+		entryColumns, where, len(args)-1, len(args))
+	var models []EntryModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, 0, err
+	}
+	entries := make([]*domain.Entry, len(models))
+	for i := range models {
+		entries[i] = models[i].ToDomain()
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/domain"
+)
+
+type AuditRepository interface {
+	// Create stores an entry; an entry for an event already recorded is ignored
+	Create(ctx context.Context, e *domain.Entry) error
+	// LatestState returns the entity's last known snapshot, nil if none. For an entity in the
+	// trash it is the snapshot it was deleted with.
+	LatestState(ctx context.Context, entityType string, entityID int) (map[string]interface{}, error)
+	History(ctx context.Context, userID int, entityType string, entityID int, limit int) ([]*domain.Entry, error)
+	List(ctx context.Context, userID int, filter domain.Filter) ([]*domain.Entry, int, error)
+}
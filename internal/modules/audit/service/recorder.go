@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/repository"
+)
+
+// operations maps the action part of an entity event ("task.completed") to the audited operation
+var operations = map[string]string{
+	"created":   domain.OperationCreate,
+	"updated":   domain.OperationUpdate,
+	"completed": domain.OperationUpdate,
+	"skipped":   domain.OperationUpdate,
+	"graded":    domain.OperationUpdate,
+	"deleted":   domain.OperationDelete,
+	"revoked":   domain.OperationDelete,
+}
+
+// Recorder writes an audit entry for every entity mutation published on the event bus.
+// Entity events carry the entity's ID as "<entity>_id" and, except for deletes, its
+// snapshot after the change as "<entity>"; the snapshot before the change is the one the
+// publisher read from its repository in the same unit of work (eventbus.WithBefore).
+type Recorder struct {
+	repo   repository.AuditRepository
+	logger *logger.ZapLogger
+}
+
+func NewRecorder(repo repository.AuditRepository, logger *logger.ZapLogger) *Recorder {
+	return &Recorder{repo: repo, logger: logger}
+}
+
+// Subscribe records every event on the bus. Entries are keyed by outbox ID, so a
+// redelivered event is stored once.
+func (r *Recorder) Subscribe(bus *eventbus.Bus) {
+	bus.SubscribeAll("audit.record", r.HandleEvent)
+}
+
+// HandleEvent stores an audit entry for an entity event or a trash restore/purge and
+// ignores every other event
+func (r *Recorder) HandleEvent(ctx context.Context, env eventbus.Envelope) error {
+	eventType := env.Event.EventType()
+	entityType, entityID, operation, after, err := resolve(env.Event)
+	if err != nil || entityID == 0 {
+		return err
+	}
+
+	var before map[string]interface{}
+	if env.Before != nil {
+		if before, err = toMap(env.Before); err != nil {
+			return err
+		}
+	}
+
+	entry := &domain.Entry{
+		UserID:     env.UserID,
+		ActorID:    env.ActorID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Operation:  operation,
+		EventType:  eventType,
+		EventID:    env.ID,
+		RequestID:  env.RequestID,
+		IP:         env.IP,
+		CreatedAt:  env.OccurredAt,
+	}
+	switch operation {
+	case domain.OperationCreate:
+		entry.After = after
+		entry.Changes = domain.Diff(nil, after)
+	case domain.OperationUpdate:
+		entry.Before = before
+		entry.After = after
+		if before == nil || after == nil {
+			// Events that describe the change rather than carry both snapshots (a completion
+			// raised by a job, a member's new role) are recorded as they are. A snapshot
+			// without a before-state is a companion of the event that carries the diff (the
+			// task.completed raised next to task.updated) and is not recorded twice.
+			entry.Changes = eventChanges(env.Event)
+			if after != nil && len(entry.Changes) == 0 {
+				return nil
+			}
+			break
+		}
+		entry.Changes = domain.Diff(before, after)
+		if len(entry.Changes) == 0 {
+			return nil
+		}
+	case domain.OperationDelete:
+		entry.Before = before
+		entry.Changes = domain.Diff(before, nil)
+	case domain.OperationRestore, domain.OperationPurge:
+		// The trash does not know the entity's fields: it comes back, or goes, as it was
+		// deleted, and the delete entry holds that snapshot as read from the repository
+		state, err := r.repo.LatestState(ctx, entityType, entityID)
+		if err != nil {
+			return err
+		}
+		if operation == domain.OperationRestore {
+			entry.After = state
+			entry.Changes = domain.Diff(nil, state)
+		} else {
+			entry.Before = state
+			entry.Changes = domain.Diff(state, nil)
+		}
+	case domain.OperationSecurity:
+		entry.Changes = eventChanges(env.Event)
+	}
+
+	if err := r.repo.Create(ctx, entry); err != nil {
+		r.logger.Error("Failed to record audit entry", err, map[string]interface{}{
+			"user_id":    env.UserID,
+			"entity":     entityType,
+			"entity_id":  entityID,
+			"event_type": eventType,
+			"action":     "AUDIT_RECORD_FAILED",
+		})
+		return err
+	}
+	return nil
+}
+
+// resolve finds which entity an event changed and how; entityID is 0 for events that are not audited
+func resolve(event events.Event) (entityType string, entityID int, operation string, after map[string]interface{}, err error) {
+	switch e := event.(type) {
+	case events.TrashRestored:
+		return e.Entity, e.EntityID, domain.OperationRestore, nil, nil
+	case events.TrashPurged:
+		return e.Entity, e.EntityID, domain.OperationPurge, nil, nil
+	case events.LoginFailed:
+		return "user", e.UserID, domain.OperationSecurity, nil, nil
+	case events.AccountLocked:
+		return "user", e.UserID, domain.OperationSecurity, nil, nil
+	case events.MFAEnabled:
+		return "user", e.UserID, domain.OperationSecurity, nil, nil
+	case events.MFADisabled:
+		return "user", e.UserID, domain.OperationSecurity, nil, nil
+	case events.PasswordChanged:
+		return "user", e.UserID, domain.OperationSecurity, nil, nil
+	case events.PasswordReset:
+		return "user", e.UserID, domain.OperationSecurity, nil, nil
+	case events.RolesChanged:
+		return "user", e.UserID, domain.OperationSecurity, nil, nil
+	case events.WebhookSecretRotated:
+		return "webhook", e.WebhookID, domain.OperationSecurity, nil, nil
+	case events.WorkspaceMemberAdded:
+		return "workspace", e.WorkspaceID, domain.OperationUpdate, nil, nil
+	case events.WorkspaceMemberUpdated:
+		return "workspace", e.WorkspaceID, domain.OperationUpdate, nil, nil
+	case events.WorkspaceMemberRemoved:
+		return "workspace", e.WorkspaceID, domain.OperationUpdate, nil, nil
+	}
+
+	entityType, action, ok := strings.Cut(event.EventType(), ".")
+	if !ok || !domain.IsEntity(entityType) {
+		return "", 0, "", nil, nil
+	}
+	operation, ok = operations[action]
+	if !ok {
+		return "", 0, "", nil, nil
+	}
+
+	payload, err := toMap(event)
+	if err != nil {
+		return "", 0, "", nil, err
+	}
+	idValue, _ := payload[entityType+"_id"].(float64)
+	after, _ = payload[entityType].(map[string]interface{})
+	return entityType, int(idValue), operation, after, nil
+}
+
+// eventChanges describes an event without snapshots as the fields it touched. Secrets are
+// named but their values are never recorded.
+func eventChanges(event events.Event) []domain.Change {
+	switch e := event.(type) {
+	case events.LoginFailed:
+		return []domain.Change{{Field: "failed_logins", Before: e.Attempts - 1, After: e.Attempts}}
+	case events.AccountLocked:
+		return []domain.Change{{Field: "locked_until", Before: nil, After: e.LockedUntil}}
+	case events.MFAEnabled:
+		return []domain.Change{{Field: "mfa_enabled", Before: false, After: true}}
+	case events.MFADisabled:
+		return []domain.Change{{Field: "mfa_enabled", Before: true, After: false}}
+	case events.PasswordChanged, events.PasswordReset:
+		return []domain.Change{{Field: "password"}}
+	case events.RolesChanged:
+		return []domain.Change{{Field: "roles", Before: e.PreviousRoles, After: e.Roles}}
+	case events.WebhookSecretRotated:
+		return []domain.Change{{Field: "secret"}}
+	case events.WorkspaceMemberAdded:
+		return []domain.Change{{Field: memberField(e.MemberID), Before: nil, After: e.Role}}
+	case events.WorkspaceMemberUpdated:
+		return []domain.Change{{Field: memberField(e.MemberID), Before: e.PreviousRole, After: e.Role}}
+	case events.WorkspaceMemberRemoved:
+		return []domain.Change{{Field: memberField(e.MemberID), Before: e.PreviousRole, After: nil}}
+	}
+	return nil
+}
+
+// memberField names a workspace member's role in a change
+func memberField(userID int) string {
+	return fmt.Sprintf("members.%d.role", userID)
+}
+
+// toMap turns an event payload into its JSON object form so snapshots compare field by field
+func toMap(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/repository"
+)
+
+// fakeAuditRepository keeps the recorded entries; LatestState is not expected to be
+// consulted outside restore and purge
+type fakeAuditRepository struct {
+	repository.AuditRepository
+	entries []*domain.Entry
+}
+
+func (r *fakeAuditRepository) Create(ctx context.Context, e *domain.Entry) error {
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func TestRecorderHandleEvent(t *testing.T) {
+	task := func(title string, done bool) map[string]interface{} {
+		return map[string]interface{}{"id": 7, "title": title, "is_completed": done, "updated_at": title}
+	}
+
+	tests := []struct {
+		name      string
+		env       eventbus.Envelope
+		want      []domain.Change
+		operation string
+		entityID  int
+		skipped   bool
+	}{
+		{
+			name:      "update diffs the repository snapshot",
+			env:       eventbus.Envelope{Event: events.TaskUpdated{TaskID: 7, Task: task("new", false)}, Before: task("old", false)},
+			operation: domain.OperationUpdate,
+			entityID:  7,
+			want:      []domain.Change{{Field: "title", Before: "old", After: "new"}},
+		},
+		{
+			name:    "update that changed nothing",
+			env:     eventbus.Envelope{Event: events.TaskUpdated{TaskID: 7, Task: task("same", false)}, Before: task("same", false)},
+			skipped: true,
+		},
+		{
+			name:    "companion of the event carrying the diff",
+			env:     eventbus.Envelope{Event: events.TaskCompleted{TaskID: 7, Task: task("new", true)}},
+			skipped: true,
+		},
+		{
+			name:      "completion without snapshots",
+			env:       eventbus.Envelope{Event: events.HabitCompleted{HabitID: 3, Title: "Run", Streak: 4}},
+			operation: domain.OperationUpdate,
+			entityID:  3,
+		},
+		{
+			name:      "delete keeps the snapshot read before it",
+			env:       eventbus.Envelope{Event: events.TaskDeleted{TaskID: 7, Title: "old"}, Before: map[string]interface{}{"title": "old"}},
+			operation: domain.OperationDelete,
+			entityID:  7,
+			want:      []domain.Change{{Field: "title", Before: "old", After: nil}},
+		},
+		{
+			name:      "member role change",
+			env:       eventbus.Envelope{Event: events.WorkspaceMemberUpdated{WorkspaceID: 2, MemberID: 9, PreviousRole: "viewer", Role: "editor"}},
+			operation: domain.OperationUpdate,
+			entityID:  2,
+			want:      []domain.Change{{Field: "members.9.role", Before: "viewer", After: "editor"}},
+		},
+		{
+			name:      "password change names the field only",
+			env:       eventbus.Envelope{Event: events.PasswordChanged{UserID: 5}},
+			operation: domain.OperationSecurity,
+			entityID:  5,
+			want:      []domain.Change{{Field: "password"}},
+		},
+		{
+			name:      "revoked token",
+			env:       eventbus.Envelope{Event: events.TokenRevoked{TokenID: 4, Name: "ci"}, Before: map[string]interface{}{"name": "ci"}},
+			operation: domain.OperationDelete,
+			entityID:  4,
+			want:      []domain.Change{{Field: "name", Before: "ci", After: nil}},
+		},
+		{
+			name:      "role change",
+			env:       eventbus.Envelope{Event: events.RolesChanged{UserID: 5, ActorID: 1, PreviousRoles: []string{"user"}, Roles: []string{"admin", "user"}}, ActorID: 1},
+			operation: domain.OperationSecurity,
+			entityID:  5,
+			want:      []domain.Change{{Field: "roles", Before: []string{"user"}, After: []string{"admin", "user"}}},
+		},
+		{
+			name:    "event that is not audited",
+			env:     eventbus.Envelope{Event: events.HabitLogged{HabitID: 3}},
+			skipped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAuditRepository{}
+			if err := NewRecorder(repo, logger.NewLogger(nil)).HandleEvent(context.Background(), tt.env); err != nil {
+				t.Fatalf("HandleEvent: %v", err)
+			}
+			if tt.skipped {
+				if len(repo.entries) != 0 {
+					t.Fatalf("recorded %d entries, want none", len(repo.entries))
+				}
+				return
+			}
+			if len(repo.entries) != 1 {
+				t.Fatalf("recorded %d entries, want 1", len(repo.entries))
+			}
+			entry := repo.entries[0]
+			if entry.Operation != tt.operation || entry.EntityID != tt.entityID {
+				t.Errorf("entry = %s %d, want %s %d", entry.Operation, entry.EntityID, tt.operation, tt.entityID)
+			}
+			if len(entry.Changes) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(entry.Changes, tt.want) {
+					t.Errorf("changes = %+v, want %+v", entry.Changes, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/dto"
+)
+
+type AuditService interface {
+	// List returns one page of the user's audit log, newest first
+	List(ctx context.Context, req *dto.ListAuditRequest, userID int) (*dto.AuditListResponse, error)
+	// History returns the change timeline of one entity, oldest first
+	History(ctx context.Context, entityType string, entityID, userID int) (*dto.TimelineResponse, error)
+}
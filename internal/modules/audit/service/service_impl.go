@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/audit/repository"
+)
+
+// maxHistoryEntries caps one entity timeline
+const maxHistoryEntries = 500
+
+type auditService struct {
+	repo   repository.AuditRepository
+	logger *logger.ZapLogger
+}
+
+func NewAuditService(repo repository.AuditRepository, logger *logger.ZapLogger) AuditService {
+	return &auditService{repo: repo, logger: logger}
+}
+
+func (s *auditService) List(ctx context.Context, req *dto.ListAuditRequest, userID int) (*dto.AuditListResponse, error) {
+	if req.Entity != "" && !domain.IsEntity(req.Entity) {
+		return nil, errors.New("invalid entity type")
+	}
+	filter := domain.Filter{
+		EntityType: req.Entity,
+		Limit:      req.Limit,
+		Offset:     (req.Page - 1) * req.Limit,
+	}
+	entries, total, err := s.repo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuditListResponse{
+		Items: dto.ToEntryResponseList(entries),
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+	}, nil
+}
+
+func (s *auditService) History(ctx context.Context, entityType string, entityID, userID int) (*dto.TimelineResponse, error) {
+	if !domain.IsEntity(entityType) {
+		return nil, errors.New("invalid entity type")
+	}
+	entries, err := s.repo.History(ctx, userID, entityType, entityID, maxHistoryEntries)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("audit history not found")
+	}
+	return &dto.TimelineResponse{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Entries:    dto.ToEntryResponseList(entries),
+	}, nil
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// AccessToken is a personal access token a user created for scripts and integrations.
+// Only the SHA-256 hash of the token is kept; Prefix is its first characters, shown so
+// the user can tell tokens apart.
+type AccessToken struct {
+	ID         int64
+	UserID     int
+	Name       string
+	TokenHash  string
+	Prefix     string
+	Scopes     []string
+	ExpiresAt  *time.Time // nil: never expires
+	LastUsedAt *time.Time
+	LastUsedIP string
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Active reports whether the token is accepted
+func (t *AccessToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// MaxChallengeAttempts is how many codes may be tried against one MFA challenge
+const MaxChallengeAttempts = 5
+
+// MFA is a user's TOTP enrollment. Secret is encrypted at rest. Until EnabledAt is set the
+// enrollment is pending: the user scanned the secret but has not confirmed it with a code.
+type MFA struct {
+	UserID       int
+	Secret       string
+	EnabledAt    *time.Time
+	LastUsedStep int64 // Last accepted TOTP time step; older or equal steps are replays
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Enabled reports whether login requires a second factor
+func (m *MFA) Enabled() bool {
+	return m != nil && m.EnabledAt != nil
+}
+
+// MFAChallenge is the pending second step of a password login. Only the SHA-256 hash of
+// the challenge token is kept.
+type MFAChallenge struct {
+	ID        int64
+	UserID    int
+	TokenHash string
+	Attempts  int
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	IP        string
+	CreatedAt time.Time
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// OIDCLoginState is a login that was sent to the OIDC provider and has not come back yet.
+// Only the SHA-256 hash of the state parameter is kept; nonce and PKCE verifier are needed
+// in plain text to finish the login and live only for a few minutes.
+type OIDCLoginState struct {
+	ID           int64
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// OIDCIdentity links an account at an OIDC provider (issuer + subject) to a user
+type OIDCIdentity struct {
+	ID          int64
+	UserID      int
+	Issuer      string
+	Subject     string
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// PasswordResetToken is a single-use token mailed to a user who forgot their password.
+// Only the SHA-256 hash of the token is kept.
+type PasswordResetToken struct {
+	ID        int64
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	IP        string
+	CreatedAt time.Time
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Reasons recorded when a refresh token is revoked
+const (
+	RevokeLogout    = "logout"
+	RevokeLogoutAll = "logout_all"
+	RevokeReuse     = "reuse"
+	RevokeSession   = "session_revoked"
+)
+
+// RefreshToken is a stored refresh token. Only the SHA-256 hash of the token is kept.
+// Tokens rotate on every use; all tokens descending from one login share a FamilyID,
+// so presenting an already rotated token revokes the whole family.
+type RefreshToken struct {
+	ID           int64
+	UserID       int
+	FamilyID     string
+	TokenHash    string
+	ExpiresAt    time.Time
+	RotatedAt    *time.Time
+	RevokedAt    *time.Time
+	RevokeReason string
+	IP           string
+	CreatedAt    time.Time
+}
+
+// Active reports whether the token can still be exchanged
+func (t *RefreshToken) Active(now time.Time) bool {
+	return t.RotatedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// Reused reports whether the token was already rotated or revoked, i.e. it is being replayed
+func (t *RefreshToken) Reused() bool {
+	return t.RotatedAt != nil || t.RevokedAt != nil
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Session is one login of a user on a device. It is identified towards the refresh tokens
+// and the access tokens it issues by FamilyID, and stays signed in while its refresh token
+// family has a live token; ExpiresAt is when that token expires.
+type Session struct {
+	ID         int64
+	UserID     int
+	FamilyID   string
+	DeviceName string
+	UserAgent  string
+	IP         string // Address of the latest login or token refresh
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
@@ -2,10 +2,18 @@ package dto
 
 import "time"
 
+// AuthResponse carries the tokens of a finished login. When the user has 2FA enabled a
+// password login instead returns only the MFA fields; the tokens follow from /api/auth/mfa/verify.
 type AuthResponse struct {
-	Token     string       `json:"token"`
-	ExpiresAt time.Time    `json:"expires_at"`
-	User      UserResponse `json:"user"`
+	Token                 string       `json:"token,omitempty"`
+	ExpiresAt             time.Time    `json:"expires_at,omitzero"`
+	RefreshToken          string       `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at,omitzero"`
+	User                  UserResponse `json:"user,omitzero"`
+
+	MFARequired       bool      `json:"mfa_required,omitempty"`
+	MFAToken          string    `json:"mfa_token,omitempty"`
+	MFATokenExpiresAt time.Time `json:"mfa_token_expires_at,omitzero"`
 }
 
 type UserResponse struct {
@@ -16,3 +24,54 @@ type UserResponse struct {
 	Timezone  string    `json:"timezone"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+type LogoutAllResponse struct {
+	RevokedSessions int64 `json:"revoked_sessions"`
+}
+
+type SessionResponse struct {
+	ID         int64     `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	Current    bool      `json:"current"` // The session of the access token making the request
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type MFAStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	Pending                bool       `json:"pending"` // Enrolled but not confirmed with a code yet
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// MFAEnrollResponse is shown once; OTPAuthURI is the QR code payload for authenticator apps
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse is the only time recovery codes are shown in plain text
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type AccessTokenResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	Expired    bool       `json:"expired"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAccessTokenResponse is the only time the token itself is shown
+type CreatedAccessTokenResponse struct {
+	AccessTokenResponse
+	Token string `json:"token"`
+}
@@ -2,26 +2,64 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/service"
+	userDto "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
 	"github.com/gorilla/mux"
 )
 
 type Handler struct {
-	service service.AuthService
+	service      service.AuthService
+	passwords    service.PasswordService
+	mfa          service.MFAService
+	accessTokens service.AccessTokenService
+	oidc         service.OIDCService
+	sessions     service.SessionService
+	verification service.EmailVerificationService
 }
 
-func NewHandler(service service.AuthService) *Handler {
-	return &Handler{service: service}
+func NewHandler(service service.AuthService, passwords service.PasswordService, mfa service.MFAService, accessTokens service.AccessTokenService, oidc service.OIDCService, sessions service.SessionService, verification service.EmailVerificationService) *Handler {
+	return &Handler{service: service, passwords: passwords, mfa: mfa, accessTokens: accessTokens, oidc: oidc, sessions: sessions, verification: verification}
 }
 
 func (h *Handler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/api/auth/login", h.Login).Methods("POST")
 	router.HandleFunc("/api/auth/register", h.Register).Methods("POST")
+	router.HandleFunc("/api/auth/refresh", h.Refresh).Methods("POST")
+	router.HandleFunc("/api/auth/password/forgot", h.ForgotPassword).Methods("POST")
+	router.HandleFunc("/api/auth/password/reset", h.ResetPassword).Methods("POST")
+	router.HandleFunc("/api/auth/email/verify", h.VerifyEmail).Methods("POST")
+	router.HandleFunc("/api/auth/mfa/verify", h.VerifyMFA).Methods("POST")
+	router.HandleFunc("/api/auth/oidc/login", h.OIDCLogin).Methods("GET")
+	router.HandleFunc("/api/auth/oidc/callback", h.OIDCCallback).Methods("GET", "POST")
+}
+
+// RegisterProtectedRoutes registers the routes that need a valid access token on the protected /api router
+func (h *Handler) RegisterProtectedRoutes(router *mux.Router) {
+	router.HandleFunc("/auth/logout", h.Logout).Methods("POST")
+	router.HandleFunc("/auth/logout-all", h.LogoutAll).Methods("POST")
+	router.HandleFunc("/me/password", h.ChangePassword).Methods("POST")
+	router.HandleFunc("/me/email/verification", h.ResendEmailVerification).Methods("POST")
+	router.HandleFunc("/me/mfa", h.MFAStatus).Methods("GET")
+	router.HandleFunc("/me/mfa/enroll", h.EnrollMFA).Methods("POST")
+	router.HandleFunc("/me/mfa/enable", h.EnableMFA).Methods("POST")
+	router.HandleFunc("/me/mfa/disable", h.DisableMFA).Methods("POST")
+	router.HandleFunc("/me/mfa/recovery-codes", h.RegenerateRecoveryCodes).Methods("POST")
+	router.HandleFunc("/me/tokens", h.ListAccessTokens).Methods("GET")
+	router.HandleFunc("/me/tokens", h.CreateAccessToken).Methods("POST")
+	router.HandleFunc("/me/tokens/scopes", h.ListAccessTokenScopes).Methods("GET")
+	router.HandleFunc("/me/tokens/{id}", h.RevokeAccessToken).Methods("DELETE")
+	router.HandleFunc("/me/sessions", h.ListSessions).Methods("GET")
+	router.HandleFunc("/me/sessions", h.RevokeOtherSessions).Methods("DELETE")
+	router.HandleFunc("/me/sessions/{id}", h.RevokeSession).Methods("DELETE")
 }
 
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
@@ -38,6 +76,9 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.service.Login(r.Context(), &req)
 	if err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
 		if err.Error() == "invalid email or password" {
 			utils.ReturnError(w, "UNAUTHORIZED", "Geçersiz e-posta veya şifre", err.Error())
 			return
@@ -46,6 +87,11 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if response.MFARequired {
+		utils.WriteJson(w, response, http.StatusOK, "İki adımlı doğrulama kodu gerekli")
+		return
+	}
+
 	utils.WriteJson(w, response, http.StatusOK, "Giriş başarılı")
 }
 
@@ -73,3 +119,564 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJson(w, response, http.StatusCreated, "Kayıt başarılı")
 }
+
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	response, err := h.service.Refresh(r.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid refresh token":
+			utils.ReturnError(w, "UNAUTHORIZED", "Oturum süresi dolmuş, tekrar giriş yapın", err.Error())
+		case "refresh token reuse detected":
+			utils.ReturnError(w, "UNAUTHORIZED", "Oturum güvenlik nedeniyle sonlandırıldı, tekrar giriş yapın", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Oturum yenilenemedi", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "Oturum yenilendi")
+}
+
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	// The body is optional: without a refresh token only the calling access token is revoked
+	var req dto.LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(r.Context())
+	if err := h.service.Logout(r.Context(), &req, userID); err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Çıkış yapılamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, nil, http.StatusOK, "Çıkış yapıldı")
+}
+
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.service.LogoutAll(r.Context(), userID)
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Tüm oturumlar kapatılamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "Tüm oturumlardan çıkış yapıldı")
+}
+
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	if err := h.passwords.Forgot(r.Context(), &req); err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		utils.ReturnError(w, "INTERNAL_ERROR", "Şifre sıfırlama isteği işlenemedi", err.Error())
+		return
+	}
+
+	// Same answer whether or not the email is registered
+	utils.WriteJson(w, nil, http.StatusOK, "E-posta adresi kayıtlıysa şifre sıfırlama bağlantısı gönderildi")
+}
+
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	if err := h.passwords.Reset(r.Context(), &req); err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		if err.Error() == "invalid or expired reset token" {
+			utils.ReturnError(w, "BAD_REQUEST", "Şifre sıfırlama bağlantısı geçersiz veya süresi dolmuş", err.Error())
+			return
+		}
+		utils.ReturnError(w, "INTERNAL_ERROR", "Şifre sıfırlanamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, nil, http.StatusOK, "Şifre sıfırlandı, yeni şifrenizle giriş yapın")
+}
+
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	if err := h.verification.Verify(r.Context(), &req); err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		if err.Error() == "invalid or expired verification token" {
+			utils.ReturnError(w, "BAD_REQUEST", "Doğrulama bağlantısı geçersiz veya süresi dolmuş", err.Error())
+			return
+		}
+		utils.ReturnError(w, "INTERNAL_ERROR", "E-posta doğrulanamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, nil, http.StatusOK, "E-posta adresiniz doğrulandı")
+}
+
+func (h *Handler) ResendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+	if err := h.verification.Resend(r.Context(), userID); err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "email already verified":
+			utils.ReturnError(w, "BAD_REQUEST", "E-posta adresiniz zaten doğrulanmış", err.Error())
+		case "user not found":
+			utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Doğrulama e-postası gönderilemedi", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, nil, http.StatusAccepted, "Doğrulama e-postası gönderildi")
+}
+
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req userDto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(r.Context())
+	if err := h.passwords.Change(r.Context(), &req, userID); err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid current password":
+			utils.ReturnError(w, "BAD_REQUEST", "Mevcut şifre hatalı", err.Error())
+		case "new password must differ":
+			utils.ReturnError(w, "BAD_REQUEST", "Yeni şifre mevcut şifreden farklı olmalı", err.Error())
+		case "user not found":
+			utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Şifre değiştirilemedi", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, nil, http.StatusOK, "Şifre değiştirildi, tüm oturumlar kapatıldı")
+}
+
+func (h *Handler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerifyMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	response, err := h.service.VerifyMFA(r.Context(), &req)
+	if err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid mfa code":
+			utils.ReturnError(w, "UNAUTHORIZED", "Doğrulama kodu hatalı", err.Error())
+		case "invalid mfa challenge", "mfa not enabled":
+			utils.ReturnError(w, "UNAUTHORIZED", "Doğrulama süresi dolmuş veya deneme hakkı bitmiş, tekrar giriş yapın", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Giriş yapılamadı", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "Giriş başarılı")
+}
+
+// oidcStateCookie binds an OIDC login to the browser that started it, so that a login started
+// elsewhere cannot be finished in this browser
+const oidcStateCookie = "oidc_state"
+
+// OIDCLogin redirects the browser to the OIDC provider
+func (h *Handler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	authURL, state, err := h.oidc.AuthorizationURL(r.Context(), r.URL.Query().Get("login_hint"))
+	if err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		if err.Error() == "oidc not configured" {
+			utils.ReturnError(w, "NOT_FOUND", "Kurumsal giriş yapılandırılmamış", err.Error())
+			return
+		}
+		utils.ReturnError(w, "INTERNAL_ERROR", "Kimlik sağlayıcıya bağlanılamadı", err.Error())
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/auth/oidc",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   secureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func secureRequest(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// OIDCCallback finishes an OIDC login. The provider redirects here with code and state in the
+// query (GET); a frontend that receives the redirect itself can post them as JSON instead.
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	var req dto.OIDCCallbackRequest
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		if providerErr := q.Get("error"); providerErr != "" {
+			utils.ReturnError(w, "UNAUTHORIZED", "Kimlik sağlayıcı girişi reddetti", providerErr+": "+q.Get("error_description"))
+			return
+		}
+		req = dto.OIDCCallbackRequest{Code: q.Get("code"), State: q.Get("state")}
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	browserState := ""
+	if cookie, err := r.Cookie(oidcStateCookie); err == nil {
+		browserState = cookie.Value
+	}
+	// The state is single use whatever the outcome
+	http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: "/api/auth/oidc", MaxAge: -1, HttpOnly: true, Secure: secureRequest(r), SameSite: http.SameSiteLaxMode})
+
+	response, err := h.oidc.Callback(r.Context(), &req, browserState)
+	if err != nil {
+		switch {
+		case err.Error() == "oidc not configured":
+			utils.ReturnError(w, "NOT_FOUND", "Kurumsal giriş yapılandırılmamış", err.Error())
+		case err.Error() == "invalid oidc state":
+			utils.ReturnError(w, "BAD_REQUEST", "Giriş isteği geçersiz veya süresi dolmuş, tekrar deneyin", err.Error())
+		case err.Error() == "oidc email not verified":
+			utils.ReturnError(w, "FORBIDDEN", "Kimlik sağlayıcıdaki e-posta adresi doğrulanmamış", err.Error())
+		case err.Error() == "oidc registration disabled":
+			utils.ReturnError(w, "FORBIDDEN", "Bu e-posta ile kayıtlı bir hesap yok", err.Error())
+		case err.Error() == "oidc account link requires verified email":
+			utils.ReturnError(w, "FORBIDDEN", "Bu e-posta ile açılmış hesap doğrulanmamış; şifrenizle giriş yapıp e-postanızı doğruladıktan (veya şifre sıfırladıktan) sonra tekrar deneyin", err.Error())
+		case strings.HasPrefix(err.Error(), "oidc login failed"):
+			utils.ReturnError(w, "UNAUTHORIZED", "Kimlik sağlayıcı ile giriş doğrulanamadı", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Giriş yapılamadı", err.Error())
+		}
+		return
+	}
+
+	if response.MFARequired {
+		utils.WriteJson(w, response, http.StatusOK, "İki adımlı doğrulama kodu gerekli")
+		return
+	}
+	utils.WriteJson(w, response, http.StatusOK, "Giriş başarılı")
+}
+
+func (h *Handler) MFAStatus(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.mfa.Status(r.Context(), userID)
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "İki adımlı doğrulama durumu alınamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "İki adımlı doğrulama durumu")
+}
+
+func (h *Handler) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.mfa.Enroll(r.Context(), userID)
+	if err != nil {
+		switch err.Error() {
+		case "mfa already enabled":
+			utils.ReturnError(w, "BAD_REQUEST", "İki adımlı doğrulama zaten açık", err.Error())
+		case "user not found":
+			utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "İki adımlı doğrulama kurulamadı", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "Kodu doğrulama uygulamanıza ekleyip bir kodla onaylayın")
+}
+
+func (h *Handler) EnableMFA(w http.ResponseWriter, r *http.Request) {
+	var req dto.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.mfa.Enable(r.Context(), &req, userID)
+	if err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid mfa code":
+			utils.ReturnError(w, "BAD_REQUEST", "Doğrulama kodu hatalı", err.Error())
+		case "mfa not enrolled":
+			utils.ReturnError(w, "BAD_REQUEST", "Önce iki adımlı doğrulama kurulumunu başlatın", err.Error())
+		case "mfa already enabled":
+			utils.ReturnError(w, "BAD_REQUEST", "İki adımlı doğrulama zaten açık", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "İki adımlı doğrulama açılamadı", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "İki adımlı doğrulama açıldı, kurtarma kodlarınızı saklayın")
+}
+
+func (h *Handler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	var req dto.DisableMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(r.Context())
+	if err := h.mfa.Disable(r.Context(), &req, userID); err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid current password":
+			utils.ReturnError(w, "BAD_REQUEST", "Mevcut şifre hatalı", err.Error())
+		case "invalid mfa code":
+			utils.ReturnError(w, "BAD_REQUEST", "Doğrulama kodu hatalı", err.Error())
+		case "mfa not enabled":
+			utils.ReturnError(w, "BAD_REQUEST", "İki adımlı doğrulama açık değil", err.Error())
+		case "user not found":
+			utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "İki adımlı doğrulama kapatılamadı", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, nil, http.StatusOK, "İki adımlı doğrulama kapatıldı")
+}
+
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var req dto.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.mfa.RegenerateRecoveryCodes(r.Context(), &req, userID)
+	if err != nil {
+		if tooManyAttempts(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid mfa code":
+			utils.ReturnError(w, "BAD_REQUEST", "Doğrulama kodu hatalı", err.Error())
+		case "mfa not enabled":
+			utils.ReturnError(w, "BAD_REQUEST", "İki adımlı doğrulama açık değil", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Kurtarma kodları oluşturulamadı", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "Yeni kurtarma kodları oluşturuldu, eskileri geçersiz")
+}
+
+func (h *Handler) ListAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.accessTokens.List(r.Context(), userID)
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Erişim token'ları alınamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "Erişim token'ları")
+}
+
+func (h *Handler) CreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.accessTokens.Create(r.Context(), &req, userID)
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "unknown scope"):
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz yetki kapsamı", err.Error())
+		case err.Error() == "expiry must be in the future":
+			utils.ReturnError(w, "BAD_REQUEST", "Son kullanma tarihi gelecekte olmalı", err.Error())
+		case err.Error() == "too many access tokens":
+			utils.ReturnError(w, "BAD_REQUEST", "Erişim token'ı sınırına ulaşıldı", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Erişim token'ı oluşturulamadı", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusCreated, "Erişim token'ı oluşturuldu, token yalnızca bir kez gösterilir")
+}
+
+func (h *Handler) ListAccessTokenScopes(w http.ResponseWriter, r *http.Request) {
+	utils.WriteJson(w, h.accessTokens.Scopes(), http.StatusOK, "Kullanılabilir yetki kapsamları")
+}
+
+func (h *Handler) RevokeAccessToken(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(r.Context())
+	if err := h.accessTokens.Revoke(r.Context(), id, userID); err != nil {
+		if err.Error() == "access token not found" {
+			utils.ReturnError(w, "NOT_FOUND", "Erişim token'ı bulunamadı", err.Error())
+			return
+		}
+		utils.ReturnError(w, "INTERNAL_ERROR", "Erişim token'ı iptal edilemedi", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, nil, http.StatusOK, "Erişim token'ı iptal edildi")
+}
+
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.sessions.List(r.Context(), userID, utils.GetSessionIDFromContext(r.Context()))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Oturumlar alınamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "Oturumlar")
+}
+
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(r.Context())
+	if err := h.sessions.Revoke(r.Context(), id, userID); err != nil {
+		if err.Error() == "session not found" {
+			utils.ReturnError(w, "NOT_FOUND", "Oturum bulunamadı", err.Error())
+			return
+		}
+		utils.ReturnError(w, "INTERNAL_ERROR", "Oturum kapatılamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, nil, http.StatusOK, "Oturum kapatıldı")
+}
+
+func (h *Handler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+	response, err := h.sessions.RevokeOthers(r.Context(), userID, utils.GetSessionIDFromContext(r.Context()))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Diğer oturumlar kapatılamadı", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, response, http.StatusOK, "Diğer oturumlardan çıkış yapıldı")
+}
+
+// tooManyAttempts answers 429 with a Retry-After header when err is a rate limit error
+func tooManyAttempts(w http.ResponseWriter, err error) bool {
+	var limited *service.TooManyAttemptsError
+	if !errors.As(err, &limited) {
+		return false
+	}
+	seconds := int(limited.RetryAfter.Seconds()) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	utils.ReturnError(w, "TOO_MANY_REQUESTS", "Çok fazla deneme yapıldı, lütfen daha sonra tekrar deneyin", err.Error())
+	return true
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/lib/pq"
+)
+
+type RefreshTokenModel struct {
+	ID           int64      `db:"id"`
+	UserID       int        `db:"user_id"`
+	FamilyID     string     `db:"family_id"`
+	TokenHash    string     `db:"token_hash"`
+	ExpiresAt    time.Time  `db:"expires_at"`
+	RotatedAt    *time.Time `db:"rotated_at"`
+	RevokedAt    *time.Time `db:"revoked_at"`
+	RevokeReason *string    `db:"revoke_reason"`
+	IP           *string    `db:"ip"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (m *RefreshTokenModel) ToDomain() *domain.RefreshToken {
+	if m == nil {
+		return nil
+	}
+	token := &domain.RefreshToken{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		FamilyID:  m.FamilyID,
+		TokenHash: m.TokenHash,
+		ExpiresAt: m.ExpiresAt,
+		RotatedAt: m.RotatedAt,
+		RevokedAt: m.RevokedAt,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.RevokeReason != nil {
+		token.RevokeReason = *m.RevokeReason
+	}
+	if m.IP != nil {
+		token.IP = *m.IP
+	}
+	return token
+}
+
+type MFAModel struct {
+	UserID       int        `db:"user_id"`
+	Secret       string     `db:"secret"`
+	EnabledAt    *time.Time `db:"enabled_at"`
+	LastUsedStep int64      `db:"last_used_step"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+func (m *MFAModel) ToDomain() *domain.MFA {
+	if m == nil {
+		return nil
+	}
+	return &domain.MFA{
+		UserID:       m.UserID,
+		Secret:       m.Secret,
+		EnabledAt:    m.EnabledAt,
+		LastUsedStep: m.LastUsedStep,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+type MFAChallengeModel struct {
+	ID        int64      `db:"id"`
+	UserID    int        `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	Attempts  int        `db:"attempts"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	IP        *string    `db:"ip"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+func (m *MFAChallengeModel) ToDomain() *domain.MFAChallenge {
+	if m == nil {
+		return nil
+	}
+	challenge := &domain.MFAChallenge{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		TokenHash: m.TokenHash,
+		Attempts:  m.Attempts,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.IP != nil {
+		challenge.IP = *m.IP
+	}
+	return challenge
+}
+
+type AccessTokenModel struct {
+	ID          int64          `db:"id"`
+	UserID      int            `db:"user_id"`
+	Name        string         `db:"name"`
+	TokenHash   string         `db:"token_hash"`
+	TokenPrefix string         `db:"token_prefix"`
+	Scopes      pq.StringArray `db:"scopes"`
+	ExpiresAt   *time.Time     `db:"expires_at"`
+	LastUsedAt  *time.Time     `db:"last_used_at"`
+	LastUsedIP  *string        `db:"last_used_ip"`
+	RevokedAt   *time.Time     `db:"revoked_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+func (m *AccessTokenModel) ToDomain() *domain.AccessToken {
+	if m == nil {
+		return nil
+	}
+	token := &domain.AccessToken{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Name:       m.Name,
+		TokenHash:  m.TokenHash,
+		Prefix:     m.TokenPrefix,
+		Scopes:     []string(m.Scopes),
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+	if m.LastUsedIP != nil {
+		token.LastUsedIP = *m.LastUsedIP
+	}
+	return token
+}
+
+type OIDCLoginStateModel struct {
+	ID           int64     `db:"id"`
+	StateHash    string    `db:"state_hash"`
+	Nonce        string    `db:"nonce"`
+	CodeVerifier string    `db:"code_verifier"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func (m *OIDCLoginStateModel) ToDomain() *domain.OIDCLoginState {
+	if m == nil {
+		return nil
+	}
+	return &domain.OIDCLoginState{
+		ID:           m.ID,
+		StateHash:    m.StateHash,
+		Nonce:        m.Nonce,
+		CodeVerifier: m.CodeVerifier,
+		ExpiresAt:    m.ExpiresAt,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+type OIDCIdentityModel struct {
+	ID          int64     `db:"id"`
+	UserID      int       `db:"user_id"`
+	Issuer      string    `db:"issuer"`
+	Subject     string    `db:"subject"`
+	Email       *string   `db:"email"`
+	CreatedAt   time.Time `db:"created_at"`
+	LastLoginAt time.Time `db:"last_login_at"`
+}
+
+func (m *OIDCIdentityModel) ToDomain() *domain.OIDCIdentity {
+	if m == nil {
+		return nil
+	}
+	identity := &domain.OIDCIdentity{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Issuer:      m.Issuer,
+		Subject:     m.Subject,
+		CreatedAt:   m.CreatedAt,
+		LastLoginAt: m.LastLoginAt,
+	}
+	if m.Email != nil {
+		identity.Email = *m.Email
+	}
+	return identity
+}
+
+type SessionModel struct {
+	ID         int64     `db:"id"`
+	UserID     int       `db:"user_id"`
+	FamilyID   string    `db:"family_id"`
+	DeviceName string    `db:"device_name"`
+	UserAgent  *string   `db:"user_agent"`
+	IP         *string   `db:"ip"`
+	CreatedAt  time.Time `db:"created_at"`
+	LastSeenAt time.Time `db:"last_seen_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+func (m *SessionModel) ToDomain() *domain.Session {
+	if m == nil {
+		return nil
+	}
+	session := &domain.Session{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		FamilyID:   m.FamilyID,
+		DeviceName: m.DeviceName,
+		CreatedAt:  m.CreatedAt,
+		LastSeenAt: m.LastSeenAt,
+		ExpiresAt:  m.ExpiresAt,
+	}
+	if m.UserAgent != nil {
+		session.UserAgent = *m.UserAgent
+	}
+	if m.IP != nil {
+		session.IP = *m.IP
+	}
+	return session
+}
@@ -0,0 +1,512 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type postgresRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresRepository(db *sqlx.DB) RefreshTokenRepository {
+	return &postgresRepository{db: db}
+}
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, expires_at, rotated_at, revoked_at, revoke_reason, ip, created_at`
+
+func (r *postgresRepository) Create(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	query := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, ip, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+		RETURNING ` + refreshTokenColumns
+
+	var model RefreshTokenModel
+	err := r.conn(ctx).GetContext(ctx, &model, query,
+		token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.IP, token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *postgresRepository) GetByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`
+
+	var model RefreshTokenModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, hash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *postgresRepository) MarkRotated(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE refresh_tokens SET rotated_at = $1 WHERE id = $2`, at, id)
+	return err
+}
+
+func (r *postgresRepository) RevokeFamily(ctx context.Context, familyID, reason string) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = $1, revoke_reason = $2
+		WHERE family_id = $3 AND revoked_at IS NULL`,
+		time.Now(), reason, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *postgresRepository) RevokeAllForUser(ctx context.Context, userID int, reason string) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = $1, revoke_reason = $2
+		WHERE user_id = $3 AND revoked_at IS NULL AND expires_at > $1`,
+		time.Now(), reason, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *postgresRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM refresh_tokens WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type passwordResetRepository struct{ db *sqlx.DB }
+
+func NewPasswordResetRepository(db *sqlx.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *passwordResetRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, ip, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)`,
+		token.UserID, token.TokenHash, token.ExpiresAt, token.IP, token.CreatedAt)
+	return err
+}
+
+func (r *passwordResetRepository) Consume(ctx context.Context, hash string, at time.Time) (int, error) {
+	var userID int
+	err := r.conn(ctx).GetContext(ctx, &userID, `
+		UPDATE password_reset_tokens SET used_at = $1
+		WHERE token_hash = $2 AND used_at IS NULL AND expires_at > $1
+		RETURNING user_id`,
+		at, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return userID, err
+}
+
+func (r *passwordResetRepository) InvalidateForUser(ctx context.Context, userID int, at time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE password_reset_tokens SET used_at = $1
+		WHERE user_id = $2 AND used_at IS NULL`,
+		at, userID)
+	return err
+}
+
+func (r *passwordResetRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type mfaRepository struct{ db *sqlx.DB }
+
+func NewMFARepository(db *sqlx.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *mfaRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *mfaRepository) Get(ctx context.Context, userID int) (*domain.MFA, error) {
+	var model MFAModel
+	err := r.conn(ctx).GetContext(ctx, &model, `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at, updated_at
+		FROM user_mfa WHERE user_id = $1 FOR UPDATE`, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *mfaRepository) Save(ctx context.Context, mfa *domain.MFA) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO user_mfa (user_id, secret, enabled_at, last_used_step, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, enabled_at = EXCLUDED.enabled_at,
+		    last_used_step = EXCLUDED.last_used_step, updated_at = EXCLUDED.updated_at`,
+		mfa.UserID, mfa.Secret, mfa.EnabledAt, mfa.LastUsedStep, mfa.CreatedAt, mfa.UpdatedAt)
+	return err
+}
+
+func (r *mfaRepository) Delete(ctx context.Context, userID int) error {
+	if _, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID)
+	return err
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string, at time.Time) error {
+	if _, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO mfa_recovery_codes (user_id, code_hash, created_at)
+		SELECT $1, UNNEST($2::TEXT[]), $3`,
+		userID, pq.StringArray(hashes), at)
+	return err
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID int, hash string, at time.Time) (bool, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE mfa_recovery_codes SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL`,
+		at, userID, hash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *mfaRepository) CountRecoveryCodes(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.conn(ctx).GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID)
+	return count, err
+}
+
+func (r *mfaRepository) CreateChallenge(ctx context.Context, challenge *domain.MFAChallenge) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO mfa_challenges (user_id, token_hash, expires_at, ip, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)`,
+		challenge.UserID, challenge.TokenHash, challenge.ExpiresAt, challenge.IP, challenge.CreatedAt)
+	return err
+}
+
+func (r *mfaRepository) AttemptChallenge(ctx context.Context, hash string, at time.Time) (*domain.MFAChallenge, error) {
+	var model MFAChallengeModel
+	err := r.conn(ctx).GetContext(ctx, &model, `
+		UPDATE mfa_challenges SET attempts = attempts + 1
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 AND attempts < $3
+		RETURNING id, user_id, token_hash, attempts, expires_at, used_at, ip, created_at`,
+		hash, at, domain.MaxChallengeAttempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *mfaRepository) UseChallenge(ctx context.Context, id int64, at time.Time) (bool, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE mfa_challenges SET used_at = $1 WHERE id = $2 AND used_at IS NULL`, at, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *mfaRepository) DeleteExpiredChallenges(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM mfa_challenges WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type accessTokenRepository struct{ db *sqlx.DB }
+
+func NewAccessTokenRepository(db *sqlx.DB) AccessTokenRepository {
+	return &accessTokenRepository{db: db}
+}
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *accessTokenRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+const accessTokenColumns = `id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, last_used_ip, revoked_at, created_at`
+
+func (r *accessTokenRepository) Create(ctx context.Context, token *domain.AccessToken) (*domain.AccessToken, error) {
+	query := `
+		INSERT INTO personal_access_tokens (user_id, name, token_hash, token_prefix, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + accessTokenColumns
+
+	var model AccessTokenModel
+	err := r.conn(ctx).GetContext(ctx, &model, query,
+		token.UserID, token.Name, token.TokenHash, token.Prefix, pq.StringArray(token.Scopes), token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *accessTokenRepository) ListByUser(ctx context.Context, userID int) ([]*domain.AccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC, id DESC`
+
+	var models []AccessTokenModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*domain.AccessToken, len(models))
+	for i := range models {
+		tokens[i] = models[i].ToDomain()
+	}
+	return tokens, nil
+}
+
+func (r *accessTokenRepository) CountActive(ctx context.Context, userID int, at time.Time) (int, error) {
+	var count int
+	err := r.conn(ctx).GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)`,
+		userID, at)
+	return count, err
+}
+
+func (r *accessTokenRepository) GetByHash(ctx context.Context, hash string) (*domain.AccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM personal_access_tokens WHERE token_hash = $1`
+
+	var model AccessTokenModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, hash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *accessTokenRepository) GetByID(ctx context.Context, id int64) (*domain.AccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM personal_access_tokens WHERE id = $1`
+
+	var model AccessTokenModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *accessTokenRepository) MarkUsed(ctx context.Context, id int64, ip string, at time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE personal_access_tokens SET last_used_at = $1, last_used_ip = NULLIF($2, '')
+		WHERE id = $3 AND (last_used_at IS NULL OR last_used_at < $1 - INTERVAL '1 minute')`,
+		at, ip, id)
+	return err
+}
+
+func (r *accessTokenRepository) Revoke(ctx context.Context, id int64, userID int, at time.Time) (bool, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE personal_access_tokens SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`,
+		at, id, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *accessTokenRepository) DeleteInactive(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		DELETE FROM personal_access_tokens WHERE revoked_at < $1 OR expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type oidcRepository struct{ db *sqlx.DB }
+
+func NewOIDCRepository(db *sqlx.DB) OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *oidcRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *oidcRepository) CreateState(ctx context.Context, state *domain.OIDCLoginState) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		state.StateHash, state.Nonce, state.CodeVerifier, state.ExpiresAt, state.CreatedAt)
+	return err
+}
+
+func (r *oidcRepository) ConsumeState(ctx context.Context, hash string, at time.Time) (*domain.OIDCLoginState, error) {
+	var model OIDCLoginStateModel
+	err := r.conn(ctx).GetContext(ctx, &model, `
+		DELETE FROM oidc_login_states WHERE state_hash = $1
+		RETURNING id, state_hash, nonce, code_verifier, expires_at, created_at`, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !model.ExpiresAt.After(at) {
+		return nil, nil
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *oidcRepository) DeleteExpiredStates(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM oidc_login_states WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *oidcRepository) GetIdentity(ctx context.Context, issuer, subject string) (*domain.OIDCIdentity, error) {
+	var model OIDCIdentityModel
+	err := r.conn(ctx).GetContext(ctx, &model, `
+		SELECT id, user_id, issuer, subject, email, created_at, last_login_at
+		FROM oidc_identities WHERE issuer = $1 AND subject = $2`, issuer, subject)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *oidcRepository) CreateIdentity(ctx context.Context, identity *domain.OIDCIdentity) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO oidc_identities (user_id, issuer, subject, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $5)`,
+		identity.UserID, identity.Issuer, identity.Subject, identity.Email, identity.CreatedAt)
+	return err
+}
+
+func (r *oidcRepository) TouchIdentity(ctx context.Context, id int64, email string, at time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE oidc_identities SET email = NULLIF($1, ''), last_login_at = $2 WHERE id = $3`,
+		email, at, id)
+	return err
+}
+
+type sessionRepository struct{ db *sqlx.DB }
+
+func NewSessionRepository(db *sqlx.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *sessionRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+// activeSessions selects live sessions with the expiry of their live refresh token; $1 is the current time
+const activeSessions = `
+	SELECT s.id, s.user_id, s.family_id, s.device_name, s.user_agent, s.ip, s.created_at, s.last_seen_at,
+		t.expires_at
+	FROM sessions s
+	JOIN refresh_tokens t ON t.family_id = s.family_id
+		AND t.rotated_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > $1`
+
+func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO sessions (user_id, family_id, device_name, user_agent, ip, created_at, last_seen_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $6)`,
+		session.UserID, session.FamilyID, session.DeviceName, session.UserAgent, session.IP, session.CreatedAt)
+	return err
+}
+
+func (r *sessionRepository) ListActive(ctx context.Context, userID int, at time.Time) ([]*domain.Session, error) {
+	var models []SessionModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, activeSessions+`
+		WHERE s.user_id = $2 ORDER BY s.last_seen_at DESC, s.id DESC`, at, userID); err != nil {
+		return nil, err
+	}
+	sessions := make([]*domain.Session, len(models))
+	for i := range models {
+		sessions[i] = models[i].ToDomain()
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) GetActive(ctx context.Context, id int64, userID int, at time.Time) (*domain.Session, error) {
+	var model SessionModel
+	err := r.conn(ctx).GetContext(ctx, &model, activeSessions+`
+		WHERE s.id = $2 AND s.user_id = $3`, at, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, familyID, ip string, at time.Time) (bool, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE sessions SET last_seen_at = $1, ip = COALESCE(NULLIF($2, ''), ip) WHERE family_id = $3`,
+		at, ip, familyID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func (r *sessionRepository) DeleteOrphaned(ctx context.Context, createdBefore time.Time) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		DELETE FROM sessions s
+		WHERE s.created_at < $1 AND NOT EXISTS (SELECT 1 FROM refresh_tokens t WHERE t.family_id = s.family_id)`,
+		createdBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
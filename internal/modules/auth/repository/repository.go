@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error)
+	// GetByHash returns the token with the given hash, locked for the caller's unit of work; nil if unknown
+	GetByHash(ctx context.Context, hash string) (*domain.RefreshToken, error)
+	MarkRotated(ctx context.Context, id int64, at time.Time) error
+	// RevokeFamily revokes every live token of a family and returns how many were revoked
+	RevokeFamily(ctx context.Context, familyID, reason string) (int64, error)
+	// RevokeAllForUser revokes every live token of a user and returns how many were revoked
+	RevokeAllForUser(ctx context.Context, userID int, reason string) (int64, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *domain.PasswordResetToken) error
+	// Consume marks the token used if it is unused and unexpired and returns its user; 0 when it cannot be used
+	Consume(ctx context.Context, hash string, at time.Time) (int, error)
+	// InvalidateForUser marks every unused token of the user used
+	InvalidateForUser(ctx context.Context, userID int, at time.Time) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type MFARepository interface {
+	// Get returns the user's enrollment, locked for the caller's unit of work; nil if none
+	Get(ctx context.Context, userID int) (*domain.MFA, error)
+	// Save creates or replaces the user's enrollment
+	Save(ctx context.Context, mfa *domain.MFA) error
+	// Delete removes the enrollment and the recovery codes
+	Delete(ctx context.Context, userID int) error
+
+	// ReplaceRecoveryCodes drops the user's recovery codes and stores the given hashes
+	ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string, at time.Time) error
+	// UseRecoveryCode marks an unused code used; false if there is no such code
+	UseRecoveryCode(ctx context.Context, userID int, hash string, at time.Time) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID int) (int, error)
+
+	CreateChallenge(ctx context.Context, challenge *domain.MFAChallenge) error
+	// AttemptChallenge counts an attempt on a live challenge and returns it; nil when the
+	// challenge is unknown, used, expired or out of attempts
+	AttemptChallenge(ctx context.Context, hash string, at time.Time) (*domain.MFAChallenge, error)
+	// UseChallenge marks the challenge used; false if it already was
+	UseChallenge(ctx context.Context, id int64, at time.Time) (bool, error)
+	DeleteExpiredChallenges(ctx context.Context, before time.Time) (int64, error)
+}
+
+type AccessTokenRepository interface {
+	Create(ctx context.Context, token *domain.AccessToken) (*domain.AccessToken, error)
+	// ListByUser returns the user's tokens that were not revoked, newest first
+	ListByUser(ctx context.Context, userID int) ([]*domain.AccessToken, error)
+	CountActive(ctx context.Context, userID int, at time.Time) (int, error)
+	// GetByHash returns the token with the given hash; nil if unknown
+	GetByHash(ctx context.Context, hash string) (*domain.AccessToken, error)
+	// GetByID returns the token with the given ID, revoked or not; nil if unknown
+	GetByID(ctx context.Context, id int64) (*domain.AccessToken, error)
+	// MarkUsed records a use; uses within a minute of the last recorded one are skipped
+	MarkUsed(ctx context.Context, id int64, ip string, at time.Time) error
+	// Revoke revokes one of the user's tokens; false if the user has no such live token
+	Revoke(ctx context.Context, id int64, userID int, at time.Time) (bool, error)
+	// DeleteInactive deletes tokens that expired or were revoked before the given time
+	DeleteInactive(ctx context.Context, before time.Time) (int64, error)
+}
+
+type OIDCRepository interface {
+	CreateState(ctx context.Context, state *domain.OIDCLoginState) error
+	// ConsumeState deletes the state and returns it if it had not expired; nil otherwise
+	ConsumeState(ctx context.Context, hash string, at time.Time) (*domain.OIDCLoginState, error)
+	DeleteExpiredStates(ctx context.Context, before time.Time) (int64, error)
+
+	// GetIdentity returns the identity of the provider account; nil if it is not linked
+	GetIdentity(ctx context.Context, issuer, subject string) (*domain.OIDCIdentity, error)
+	CreateIdentity(ctx context.Context, identity *domain.OIDCIdentity) error
+	TouchIdentity(ctx context.Context, id int64, email string, at time.Time) error
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *domain.Session) error
+	// ListActive returns the user's sessions whose refresh token family has a live token,
+	// most recently seen first
+	ListActive(ctx context.Context, userID int, at time.Time) ([]*domain.Session, error)
+	// GetActive returns one of the user's live sessions; nil if unknown, another user's or signed out
+	GetActive(ctx context.Context, id int64, userID int, at time.Time) (*domain.Session, error)
+	// Touch records that the session refreshed its tokens from ip; false if there is no such session
+	Touch(ctx context.Context, familyID, ip string, at time.Time) (bool, error)
+	// DeleteOrphaned removes sessions created before the given time whose refresh tokens have all been deleted
+	DeleteOrphaned(ctx context.Context, createdBefore time.Time) (int64, error)
+}
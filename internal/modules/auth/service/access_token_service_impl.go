@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/middleware"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+)
+
+const (
+	maxAccessTokensPerUser = 50
+	// accessTokenPrefixLen is how much of a token is kept in plain text to identify it
+	accessTokenPrefixLen = len(authz.PersonalAccessTokenPrefix) + 8
+	// inactiveAccessTokenRetention is how long expired and revoked tokens are kept
+	inactiveAccessTokenRetention = 30 * 24 * time.Hour
+)
+
+type accessTokenService struct {
+	repo   repository.AccessTokenRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
+}
+
+func NewAccessTokenService(repo repository.AccessTokenRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) AccessTokenService {
+	return &accessTokenService{repo: repo, uow: uow, logger: logger, bus: bus}
+}
+
+func (s *accessTokenService) List(ctx context.Context, userID int) ([]dto.AccessTokenResponse, error) {
+	tokens, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	response := make([]dto.AccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		response[i] = toAccessTokenResponse(token, now)
+	}
+	return response, nil
+}
+
+func (s *accessTokenService) Create(ctx context.Context, req *dto.CreateAccessTokenRequest, userID int) (*dto.CreatedAccessTokenResponse, error) {
+	now := time.Now()
+
+	var scopes []string
+	for _, scope := range req.Scopes {
+		if !slices.Contains(authz.Scopes, scope) {
+			return nil, fmt.Errorf("unknown scope: %s", scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	slices.Sort(scopes)
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	count, err := s.repo.CountActive(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAccessTokensPerUser {
+		return nil, errors.New("too many access tokens")
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	token := authz.PersonalAccessTokenPrefix + secret
+
+	var created *domain.AccessToken
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.repo.Create(ctx, &domain.AccessToken{
+			UserID:    userID,
+			Name:      strings.TrimSpace(req.Name),
+			TokenHash: hashToken(token),
+			Prefix:    token[:accessTokenPrefixLen],
+			Scopes:    scopes,
+			ExpiresAt: req.ExpiresAt,
+			CreatedAt: now,
+		})
+		if err != nil || s.bus == nil {
+			return err
+		}
+		return s.bus.Publish(ctx, userID, events.TokenCreated{
+			TokenID: int(created.ID),
+			Token:   toAccessTokenResponse(created, now),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("personal access token created", map[string]interface{}{
+		"user_id":  userID,
+		"token_id": created.ID,
+		"scopes":   scopes,
+		"action":   "ACCESS_TOKEN_CREATED",
+	})
+
+	return &dto.CreatedAccessTokenResponse{
+		AccessTokenResponse: toAccessTokenResponse(created, now),
+		Token:               token,
+	}, nil
+}
+
+func (s *accessTokenService) Revoke(ctx context.Context, id int64, userID int) error {
+	now := time.Now()
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		token, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if token == nil || token.UserID != userID {
+			return errors.New("access token not found")
+		}
+		revoked, err := s.repo.Revoke(ctx, id, userID, now)
+		if err != nil {
+			return err
+		}
+		if !revoked {
+			return errors.New("access token not found")
+		}
+		if s.bus == nil {
+			return nil
+		}
+		return s.bus.Publish(eventbus.WithBefore(ctx, toAccessTokenResponse(token, now)), userID, events.TokenRevoked{
+			TokenID: int(id),
+			Name:    token.Name,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("personal access token revoked", map[string]interface{}{
+		"user_id":  userID,
+		"token_id": id,
+		"action":   "ACCESS_TOKEN_REVOKED",
+	})
+
+	return nil
+}
+
+func (s *accessTokenService) Scopes() []string {
+	return authz.Scopes
+}
+
+func (s *accessTokenService) AuthenticateAccessToken(ctx context.Context, token string, ip string) (*middleware.AccessTokenIdentity, error) {
+	stored, err := s.repo.GetByHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if stored == nil || !stored.Active(now) {
+		return nil, nil
+	}
+
+	// Last-used tracking must not fail the request
+	if err := s.repo.MarkUsed(ctx, stored.ID, ip, now); err != nil {
+		s.logger.Error("Failed to record access token use", err, map[string]interface{}{
+			"token_id": stored.ID,
+			"action":   "ACCESS_TOKEN_MARK_USED_FAILED",
+		})
+	}
+
+	identity := &middleware.AccessTokenIdentity{
+		TokenID: "pat:" + strconv.FormatInt(stored.ID, 10),
+		UserID:  stored.UserID,
+		Scopes:  stored.Scopes,
+	}
+	if stored.ExpiresAt != nil {
+		identity.ExpiresAt = *stored.ExpiresAt
+	}
+	return identity, nil
+}
+
+func (s *accessTokenService) PurgeInactive(ctx context.Context) (int64, error) {
+	return s.repo.DeleteInactive(ctx, time.Now().Add(-inactiveAccessTokenRetention))
+}
+
+func toAccessTokenResponse(token *domain.AccessToken, now time.Time) dto.AccessTokenResponse {
+	return dto.AccessTokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		Expired:    !token.Active(now),
+		LastUsedAt: token.LastUsedAt,
+		LastUsedIP: token.LastUsedIP,
+		CreatedAt:  token.CreatedAt,
+	}
+}
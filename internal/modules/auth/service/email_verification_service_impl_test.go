@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	userDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	config := EmailVerificationConfig{TTL: time.Hour, Key: []byte("verification-key")}
+	signer := &emailVerificationService{config: config}
+	otherKey := &emailVerificationService{config: EmailVerificationConfig{Key: []byte("another-key")}}
+	valid := time.Now().Add(time.Hour)
+	verifiedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		token       string
+		verified    bool
+		wantErr     error
+		wantUpdated bool
+	}{
+		{name: "valid link", token: signer.sign(3, "ada@example.com", valid), wantUpdated: true},
+		{name: "email case does not matter", token: signer.sign(3, "ADA@example.com", valid), wantUpdated: true},
+		{name: "already verified", token: signer.sign(3, "ada@example.com", valid), verified: true},
+		{name: "expired link", token: signer.sign(3, "ada@example.com", time.Now().Add(-time.Second)), wantErr: errInvalidVerificationToken},
+		{name: "link sent to a previous address", token: signer.sign(3, "old@example.com", valid), wantErr: errInvalidVerificationToken},
+		{name: "link of another user", token: signer.sign(4, "ada@example.com", valid), wantErr: errInvalidVerificationToken},
+		{name: "signed with another key", token: otherKey.sign(3, "ada@example.com", valid), wantErr: errInvalidVerificationToken},
+		{
+			name:    "extended expiry",
+			token:   strings.Replace(signer.sign(3, "ada@example.com", valid), ".", ".9", 1),
+			wantErr: errInvalidVerificationToken,
+		},
+		{name: "malformed", token: "3.not-a-time.sig", wantErr: errInvalidVerificationToken},
+		{name: "empty", token: "", wantErr: errInvalidVerificationToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &userDomain.User{ID: 3, Email: "Ada@Example.com"}
+			if tt.verified {
+				user.EmailVerifiedAt = &verifiedAt
+			}
+			users := &fakeUserRepo{user: user}
+			s := NewEmailVerificationService(users, nil, nil, nil, config, logger.NewLogger(nil), nil)
+
+			err := s.Verify(context.Background(), &dto.VerifyEmailRequest{Token: tt.token})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.wantErr)
+			}
+			if users.updated != tt.wantUpdated {
+				t.Fatalf("user updated = %v, want %v", users.updated, tt.wantUpdated)
+			}
+		})
+	}
+}
+
+func TestVerifyEmailRollsBackWhenPublishFails(t *testing.T) {
+	config := EmailVerificationConfig{TTL: time.Hour, Key: []byte("verification-key")}
+	log := logger.NewLogger(nil)
+
+	tests := []struct {
+		name        string
+		failOn      string
+		wantErr     bool
+		wantOutcome string
+	}{
+		{name: "event recorded", wantOutcome: "commit"},
+		{name: "outbox insert fails", failOn: "INSERT INTO event_outbox", wantErr: true, wantOutcome: "rollback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+			fake.failOn = tt.failOn
+			bus := eventbus.New(nil, log)
+			bus.UseOutbox(eventbus.NewOutbox(db, eventbus.DefaultOutboxRetryPolicy(), log))
+
+			users := &fakeUserRepo{user: &userDomain.User{ID: 3, Email: "ada@example.com"}}
+			s := NewEmailVerificationService(users, database.NewUnitOfWork(db), nil, nil, config, log, bus)
+			token := s.(*emailVerificationService).sign(3, "ada@example.com", time.Now().Add(time.Hour))
+
+			err := s.Verify(context.Background(), &dto.VerifyEmailRequest{Token: token})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(fake.outcomes) != 1 || fake.outcomes[0] != tt.wantOutcome {
+				t.Fatalf("transaction outcomes = %v, want [%s]", fake.outcomes, tt.wantOutcome)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/ratelimit"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/totp"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+	userRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	errInvalidMFACode  = errors.New("invalid mfa code")
+	errMFANotEnrolled  = errors.New("mfa not enrolled")
+	errMFANotEnabled   = errors.New("mfa not enabled")
+	errMFAAlreadyOn    = errors.New("mfa already enabled")
+	errInvalidPassword = errors.New("invalid current password")
+)
+
+type mfaService struct {
+	userRepo userRepo.UserRepository
+	mfaRepo  repository.MFARepository
+	uow      *database.UnitOfWork
+	secrets  *secretBox
+	config   MFAConfig
+	logger   *logger.ZapLogger
+	bus      *eventbus.Bus
+
+	attemptsByUser *ratelimit.Limiter
+}
+
+func NewMFAService(userRepo userRepo.UserRepository, mfaRepo repository.MFARepository, uow *database.UnitOfWork, config MFAConfig, logger *logger.ZapLogger, bus *eventbus.Bus) (MFAService, error) {
+	secrets, err := newSecretBox(config.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &mfaService{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		uow:      uow,
+		secrets:  secrets,
+		config:   config,
+		logger:   logger,
+		bus:      bus,
+
+		attemptsByUser: ratelimit.New(5, 15*time.Minute),
+	}, nil
+}
+
+func (s *mfaService) Status(ctx context.Context, userID int) (*dto.MFAStatusResponse, error) {
+	mfa, err := s.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return &dto.MFAStatusResponse{}, nil
+	}
+
+	remaining, err := s.mfaRepo.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAStatusResponse{
+		Enabled:                mfa.Enabled(),
+		Pending:                !mfa.Enabled(),
+		EnabledAt:              mfa.EnabledAt,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+func (s *mfaService) Enroll(ctx context.Context, userID int) (*dto.MFAEnrollResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.secrets.seal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		existing, err := s.mfaRepo.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if existing.Enabled() {
+			return errMFAAlreadyOn
+		}
+		// Enrolling again replaces a pending secret that was never confirmed
+		now := time.Now()
+		return s.mfaRepo.Save(ctx, &domain.MFA{UserID: userID, Secret: sealed, CreatedAt: now, UpdatedAt: now})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("mfa enrollment started", map[string]interface{}{
+		"user_id": userID,
+		"action":  "MFA_ENROLL",
+	})
+
+	return &dto.MFAEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.config.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (s *mfaService) Enable(ctx context.Context, req *dto.MFACodeRequest, userID int) (*dto.RecoveryCodesResponse, error) {
+	if err := allow(s.attemptsByUser, fmt.Sprint(userID)); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		mfa, err := s.mfaRepo.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if mfa == nil {
+			return errMFANotEnrolled
+		}
+		if mfa.Enabled() {
+			return errMFAAlreadyOn
+		}
+
+		// Only an authenticator code proves the secret was set up
+		secret, err := s.secrets.open(mfa.Secret)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		step, ok := totp.Validate(secret, normalizeCode(req.Code), now)
+		if !ok {
+			return errInvalidMFACode
+		}
+
+		mfa.EnabledAt = &now
+		mfa.LastUsedStep = step
+		mfa.UpdatedAt = now
+		if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+			return err
+		}
+
+		if codes, err = s.replaceRecoveryCodes(ctx, userID, now); err != nil || s.bus == nil {
+			return err
+		}
+		return s.bus.Publish(ctx, userID, events.MFAEnabled{UserID: userID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.attemptsByUser.Reset(fmt.Sprint(userID))
+	s.logger.Info("mfa enabled", map[string]interface{}{
+		"user_id": userID,
+		"action":  "MFA_ENABLED",
+	})
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *mfaService) Disable(ctx context.Context, req *dto.DisableMFARequest, userID int) error {
+	if err := allow(s.attemptsByUser, fmt.Sprint(userID)); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return errInvalidPassword
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.Verify(ctx, userID, req.Code); err != nil {
+			return err
+		}
+		if err := s.mfaRepo.Delete(ctx, userID); err != nil || s.bus == nil {
+			return err
+		}
+		return s.bus.Publish(ctx, userID, events.MFADisabled{UserID: userID})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("mfa disabled", map[string]interface{}{
+		"user_id": userID,
+		"action":  "MFA_DISABLED",
+	})
+
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, req *dto.MFACodeRequest, userID int) (*dto.RecoveryCodesResponse, error) {
+	if err := allow(s.attemptsByUser, fmt.Sprint(userID)); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.Verify(ctx, userID, req.Code); err != nil {
+			return err
+		}
+		var err error
+		codes, err = s.replaceRecoveryCodes(ctx, userID, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("mfa recovery codes regenerated", map[string]interface{}{
+		"user_id": userID,
+		"action":  "MFA_RECOVERY_CODES_REGENERATED",
+	})
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// replaceRecoveryCodes stores new recovery codes for the user and returns them in plain text
+func (s *mfaService) replaceRecoveryCodes(ctx context.Context, userID int, at time.Time) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := randomToken(5)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashToken(raw)
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes, at); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *mfaService) Enabled(ctx context.Context, userID int) (bool, error) {
+	mfa, err := s.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return mfa.Enabled(), nil
+}
+
+func (s *mfaService) Verify(ctx context.Context, userID int, code string) error {
+	mfa, err := s.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !mfa.Enabled() {
+		return errMFANotEnabled
+	}
+
+	code = normalizeCode(code)
+	now := time.Now()
+
+	if len(code) == totp.Digits {
+		secret, err := s.secrets.open(mfa.Secret)
+		if err != nil {
+			return err
+		}
+		step, ok := totp.Validate(secret, code, now)
+		if !ok || step <= mfa.LastUsedStep {
+			return errInvalidMFACode
+		}
+		mfa.LastUsedStep = step
+		mfa.UpdatedAt = now
+		return s.mfaRepo.Save(ctx, mfa)
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(ctx, userID, hashToken(code), now)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errInvalidMFACode
+	}
+	return nil
+}
+
+// normalizeCode strips the separators users type or paste with codes
+func normalizeCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// secretBox encrypts TOTP secrets at rest with AES-256-GCM
+type secretBox struct {
+	aead cipher.AEAD
+}
+
+func newSecretBox(key []byte) (*secretBox, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &secretBox{aead: aead}, nil
+}
+
+func (b *secretBox) seal(plain string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (b *secretBox) open(sealed string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < b.aead.NonceSize() {
+		return "", errors.New("mfa secret is corrupt")
+	}
+	nonce, ciphertext := raw[:b.aead.NonceSize()], raw[b.aead.NonceSize():]
+	plain, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
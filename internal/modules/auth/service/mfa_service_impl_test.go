@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/totp"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+)
+
+// totpCode computes the code an authenticator app shows for secret at the given time
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix()/int64(totp.Period/time.Second)))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+type fakeMFARepo struct {
+	repository.MFARepository
+	mfa      *domain.MFA
+	recovery map[string]bool // code hash -> used
+	saved    *domain.MFA
+}
+
+func (r *fakeMFARepo) Get(ctx context.Context, userID int) (*domain.MFA, error) {
+	if r.mfa == nil {
+		return nil, nil
+	}
+	copied := *r.mfa
+	return &copied, nil
+}
+
+func (r *fakeMFARepo) Save(ctx context.Context, mfa *domain.MFA) error {
+	r.saved = mfa
+	return nil
+}
+
+func (r *fakeMFARepo) UseRecoveryCode(ctx context.Context, userID int, hash string, at time.Time) (bool, error) {
+	used, ok := r.recovery[hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recovery[hash] = true
+	return true, nil
+}
+
+func TestMFAVerify(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	step := now.Unix() / int64(totp.Period/time.Second)
+	enabledAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		enrolled     bool
+		enabled      bool
+		lastUsedStep int64
+		code         string
+		wantErr      error
+		wantStep     int64
+	}{
+		{name: "current code", enrolled: true, enabled: true, code: totpCode(t, secret, now), wantStep: step},
+		{name: "code of the previous period", enrolled: true, enabled: true, code: totpCode(t, secret, now.Add(-totp.Period)), wantStep: step - 1},
+		{name: "replayed code", enrolled: true, enabled: true, lastUsedStep: step, code: totpCode(t, secret, now), wantErr: errInvalidMFACode},
+		{name: "code older than the last used one", enrolled: true, enabled: true, lastUsedStep: step, code: totpCode(t, secret, now.Add(-totp.Period)), wantErr: errInvalidMFACode},
+		{name: "code too far in the past", enrolled: true, enabled: true, code: totpCode(t, secret, now.Add(-3*totp.Period)), wantErr: errInvalidMFACode},
+		{name: "recovery code with separators", enrolled: true, enabled: true, code: " ABCD-EFGH-12 "},
+		{name: "used recovery code", enrolled: true, enabled: true, code: "used-code-99", wantErr: errInvalidMFACode},
+		{name: "unknown recovery code", enrolled: true, enabled: true, code: "zzzz-zzzz-zz", wantErr: errInvalidMFACode},
+		{name: "enrolled but not confirmed", enrolled: true, code: totpCode(t, secret, now), wantErr: errMFANotEnabled},
+		{name: "not enrolled", code: totpCode(t, secret, now), wantErr: errMFANotEnabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := make([]byte, 32)
+			box, err := newSecretBox(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sealed, err := box.seal(secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			repo := &fakeMFARepo{recovery: map[string]bool{
+				hashToken("abcdefgh12"): false,
+				hashToken("usedcode99"): true,
+			}}
+			if tt.enrolled {
+				repo.mfa = &domain.MFA{UserID: 1, Secret: sealed, LastUsedStep: tt.lastUsedStep}
+				if tt.enabled {
+					repo.mfa.EnabledAt = &enabledAt
+				}
+			}
+			s, err := NewMFAService(nil, repo, nil, MFAConfig{EncryptionKey: key}, logger.NewLogger(nil), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = s.Verify(context.Background(), 1, tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantStep != 0 && (repo.saved == nil || repo.saved.LastUsedStep != tt.wantStep) {
+				t.Fatalf("saved enrollment = %+v, want last used step %d", repo.saved, tt.wantStep)
+			}
+			if tt.wantErr != nil && repo.saved != nil {
+				t.Fatal("a rejected code was saved as used")
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/oidc"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/ratelimit"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+	userDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+	userDto "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
+	userRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// oidcStateTTL is how long a user may take at the provider before the login is dropped
+const oidcStateTTL = 10 * time.Minute
+
+var (
+	errOIDCDisabled          = errors.New("oidc not configured")
+	errInvalidOIDCState      = errors.New("invalid oidc state")
+	errOIDCEmailUnverified   = errors.New("oidc email not verified")
+	errOIDCRegistrationOff   = errors.New("oidc registration disabled")
+	errOIDCProviderRejected  = errors.New("oidc login failed")
+	errOIDCAccountUnverified = errors.New("oidc account link requires verified email")
+)
+
+type oidcService struct {
+	client       *oidc.Client
+	repo         repository.OIDCRepository
+	userRepo     userRepo.UserRepository
+	uow          *database.UnitOfWork
+	auth         AuthService
+	autoRegister bool
+	logger       *logger.ZapLogger
+	bus          *eventbus.Bus
+
+	startsByIP *ratelimit.Limiter
+}
+
+// NewOIDCService creates the OIDC login flow; a nil client leaves it disabled
+func NewOIDCService(client *oidc.Client, repo repository.OIDCRepository, userRepo userRepo.UserRepository, uow *database.UnitOfWork, auth AuthService, autoRegister bool, logger *logger.ZapLogger, bus *eventbus.Bus) OIDCService {
+	return &oidcService{
+		client:       client,
+		repo:         repo,
+		userRepo:     userRepo,
+		uow:          uow,
+		auth:         auth,
+		autoRegister: autoRegister,
+		logger:       logger,
+		bus:          bus,
+
+		startsByIP: ratelimit.New(30, 15*time.Minute),
+	}
+}
+
+func (s *oidcService) Enabled() bool {
+	return s.client != nil
+}
+
+func (s *oidcService) AuthorizationURL(ctx context.Context, loginHint string) (string, string, error) {
+	if !s.Enabled() {
+		return "", "", errOIDCDisabled
+	}
+	if err := allow(s.startsByIP, utils.GetClientIPFromContext(ctx)); err != nil {
+		return "", "", err
+	}
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.RandomString(48)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := s.client.AuthCodeURL(ctx, state, nonce, oidc.S256Challenge(verifier), loginHint)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	if err := s.repo.CreateState(ctx, &domain.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(oidcStateTTL),
+		CreatedAt:    now,
+	}); err != nil {
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+func (s *oidcService) Callback(ctx context.Context, req *dto.OIDCCallbackRequest, browserState string) (*dto.AuthResponse, error) {
+	if !s.Enabled() {
+		return nil, errOIDCDisabled
+	}
+	// A state that did not start in this browser is someone else's login being finished here
+	if browserState == "" || subtle.ConstantTimeCompare([]byte(browserState), []byte(req.State)) != 1 {
+		return nil, errInvalidOIDCState
+	}
+
+	state, err := s.repo.ConsumeState(ctx, hashToken(req.State), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, errInvalidOIDCState
+	}
+
+	claims, err := s.client.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		s.logger.Error("OIDC login rejected", err, map[string]interface{}{
+			"issuer": s.client.Issuer(),
+			"ip":     utils.GetClientIPFromContext(ctx),
+			"action": "OIDC_LOGIN_FAILED",
+		})
+		return nil, fmt.Errorf("%w: %v", errOIDCProviderRejected, err)
+	}
+
+	var (
+		user    *userDomain.User
+		created bool
+	)
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if user, created, err = s.resolveUser(ctx, claims); err != nil || !created || s.bus == nil {
+			return err
+		}
+		return s.bus.Publish(ctx, user.ID, events.UserCreated{
+			UserID: user.ID,
+			User:   userDto.ToUserResponse(user),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.auth.CompleteLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user logged in with OIDC", map[string]interface{}{
+		"user_id":      user.ID,
+		"issuer":       s.client.Issuer(),
+		"registered":   created,
+		"mfa_required": response.MFARequired,
+		"action":       "OIDC_LOGIN",
+	})
+
+	return response, nil
+}
+
+// resolveUser finds the user of a provider account: by linked identity first, then by
+// verified email (linking the account), and creates one when allowed
+func (s *oidcService) resolveUser(ctx context.Context, claims *oidc.Claims) (*userDomain.User, bool, error) {
+	issuer := s.client.Issuer()
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	now := time.Now()
+
+	identity, err := s.repo.GetIdentity(ctx, issuer, claims.Subject)
+	if err != nil {
+		return nil, false, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, false, err
+		}
+		if user == nil {
+			return nil, false, errors.New("user not found")
+		}
+		if err := s.repo.TouchIdentity(ctx, identity.ID, email, now); err != nil {
+			return nil, false, err
+		}
+		return user, false, nil
+	}
+
+	// Linking by email is only safe when the provider vouches for the address
+	if email == "" || !claims.EmailVerified {
+		return nil, false, errOIDCEmailUnverified
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, false, err
+	}
+
+	created := false
+	if user == nil {
+		if !s.autoRegister {
+			return nil, false, errOIDCRegistrationOff
+		}
+		if user, err = s.createUser(ctx, email, claims.Name, now); err != nil {
+			return nil, false, err
+		}
+		created = true
+	} else if !user.EmailVerified() {
+		// Anyone can register an address they do not own. Linking would hand the owner an account
+		// whose password, access tokens and webhooks the registrant still holds, so the account
+		// must prove the address itself first.
+		s.logger.Info("OIDC link refused - local account unverified", map[string]interface{}{
+			"user_id": user.ID,
+			"issuer":  issuer,
+			"action":  "OIDC_LINK_REFUSED",
+		})
+		return nil, false, errOIDCAccountUnverified
+	}
+
+	if err := s.repo.CreateIdentity(ctx, &domain.OIDCIdentity{
+		UserID:    user.ID,
+		Issuer:    issuer,
+		Subject:   claims.Subject,
+		Email:     email,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, false, err
+	}
+
+	s.logger.Info("OIDC account linked", map[string]interface{}{
+		"user_id": user.ID,
+		"issuer":  issuer,
+		"created": created,
+		"action":  "OIDC_ACCOUNT_LINKED",
+	})
+
+	return user, created, nil
+}
+
+// createUser registers an OIDC user. The account gets a random password nobody knows;
+// the user can set one through the forgot-password flow.
+func (s *oidcService) createUser(ctx context.Context, email, name string, now time.Time) (*userDomain.User, error) {
+	password, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.Create(ctx, &userDomain.User{
+		Email:           email,
+		PasswordHash:    string(hashedPassword),
+		FullName:        name,
+		Timezone:        "Europe/Istanbul",
+		EmailVerifiedAt: &now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	})
+}
+
+func (s *oidcService) PurgeExpiredStates(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpiredStates(ctx, time.Now())
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/oidc"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+	userDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// consumeRecorder fails the test if the callback reaches the stored states
+type consumeRecorder struct {
+	repository.OIDCRepository
+	consumed int
+}
+
+func (r *consumeRecorder) ConsumeState(ctx context.Context, hash string, at time.Time) (*domain.OIDCLoginState, error) {
+	r.consumed++
+	return nil, nil
+}
+
+func TestOIDCCallbackRejectsStateFromAnotherBrowser(t *testing.T) {
+	tests := []struct {
+		name         string
+		browserState string
+		state        string
+		wantConsumed bool
+	}{
+		{name: "no state cookie", browserState: "", state: "state-a"},
+		{name: "cookie from another login", browserState: "state-b", state: "state-a"},
+		{name: "matching cookie reaches the store", browserState: "state-a", state: "state-a", wantConsumed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &consumeRecorder{}
+			s := &oidcService{client: oidc.NewClient(oidc.Config{Issuer: "http://provider.test"}), repo: repo}
+
+			_, err := s.Callback(context.Background(), &dto.OIDCCallbackRequest{Code: "code", State: tt.state}, tt.browserState)
+			if !errors.Is(err, errInvalidOIDCState) {
+				t.Fatalf("Callback error = %v, want %v", err, errInvalidOIDCState)
+			}
+			if consumed := repo.consumed > 0; consumed != tt.wantConsumed {
+				t.Fatalf("state consumed = %v, want %v", consumed, tt.wantConsumed)
+			}
+		})
+	}
+}
+
+// identityRecorder has no linked identities and records the ones created
+type identityRecorder struct {
+	repository.OIDCRepository
+	linked []*domain.OIDCIdentity
+}
+
+func (r *identityRecorder) GetIdentity(ctx context.Context, issuer, subject string) (*domain.OIDCIdentity, error) {
+	return nil, nil
+}
+
+func (r *identityRecorder) CreateIdentity(ctx context.Context, identity *domain.OIDCIdentity) error {
+	r.linked = append(r.linked, identity)
+	return nil
+}
+
+func TestOIDCResolveUserLinksOnlyVerifiedAccounts(t *testing.T) {
+	verifiedAt := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name       string
+		verifiedAt *time.Time
+		wantErr    error
+		wantLinked bool
+	}{
+		// Someone registered the address before its owner signed in with the provider; the
+		// owner must not inherit an account its registrant can still use
+		{name: "unverified local account", wantErr: errOIDCAccountUnverified},
+		{name: "verified local account", verifiedAt: &verifiedAt, wantLinked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &identityRecorder{}
+			users := &fakeUserRepo{user: &userDomain.User{ID: 7, Email: "ada@example.com", PasswordHash: "squatter", EmailVerifiedAt: tt.verifiedAt}}
+			s := &oidcService{
+				client:   oidc.NewClient(oidc.Config{Issuer: "http://provider.test"}),
+				repo:     repo,
+				userRepo: users,
+				logger:   logger.NewLogger(nil),
+			}
+
+			user, created, err := s.resolveUser(context.Background(), &oidc.Claims{Email: "Ada@example.com", EmailVerified: true, RegisteredClaims: jwt.RegisteredClaims{Subject: "sub-1"}})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolveUser error = %v, want %v", err, tt.wantErr)
+			}
+			if linked := len(repo.linked) > 0; linked != tt.wantLinked {
+				t.Fatalf("identity linked = %v, want %v", linked, tt.wantLinked)
+			}
+			if users.updated {
+				t.Fatal("local account was modified")
+			}
+			if tt.wantLinked && (user == nil || user.ID != 7 || created) {
+				t.Fatalf("resolveUser = %+v, created %v; want the existing user", user, created)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/mailer"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/ratelimit"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+	userDto "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
+	userRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const passwordResetTemplate = "password_reset"
+
+type passwordService struct {
+	userRepo  userRepo.UserRepository
+	resetRepo repository.PasswordResetRepository
+	uow       *database.UnitOfWork
+	auth      AuthService
+	outbox    *mailer.Outbox
+	renderer  *mailer.Renderer
+	config    PasswordConfig
+	logger    *logger.ZapLogger
+	bus       *eventbus.Bus
+
+	forgotByIP    *ratelimit.Limiter
+	forgotByEmail *ratelimit.Limiter
+	resetByIP     *ratelimit.Limiter
+	changeByUser  *ratelimit.Limiter
+}
+
+func NewPasswordService(userRepo userRepo.UserRepository, resetRepo repository.PasswordResetRepository, uow *database.UnitOfWork, auth AuthService, outbox *mailer.Outbox, renderer *mailer.Renderer, config PasswordConfig, logger *logger.ZapLogger, bus *eventbus.Bus) PasswordService {
+	return &passwordService{
+		userRepo:  userRepo,
+		resetRepo: resetRepo,
+		uow:       uow,
+		auth:      auth,
+		outbox:    outbox,
+		renderer:  renderer,
+		config:    config,
+		logger:    logger,
+		bus:       bus,
+
+		forgotByIP:    ratelimit.New(5, 15*time.Minute),
+		forgotByEmail: ratelimit.New(3, time.Hour),
+		resetByIP:     ratelimit.New(10, 15*time.Minute),
+		changeByUser:  ratelimit.New(5, 15*time.Minute),
+	}
+}
+
+// passwordResetEmail is the data of the password_reset template
+type passwordResetEmail struct {
+	Name    string
+	Link    string
+	Minutes int
+}
+
+func (s *passwordService) Forgot(ctx context.Context, req *dto.ForgotPasswordRequest) error {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if err := allow(s.forgotByIP, utils.GetClientIPFromContext(ctx)); err != nil {
+		return err
+	}
+	if err := allow(s.forgotByEmail, email); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		s.logger.Info("password reset requested for unknown email", map[string]interface{}{
+			"ip":     utils.GetClientIPFromContext(ctx),
+			"action": "PASSWORD_RESET_UNKNOWN_EMAIL",
+		})
+		return nil
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Only the newest link works
+		if err := s.resetRepo.InvalidateForUser(ctx, user.ID, now); err != nil {
+			return err
+		}
+		return s.resetRepo.Create(ctx, &domain.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hashToken(token),
+			ExpiresAt: now.Add(s.config.ResetTTL),
+			IP:        utils.GetClientIPFromContext(ctx),
+			CreatedAt: now,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.renderer.Render(passwordResetTemplate, user.Language, passwordResetEmail{
+		Name:    user.FullName,
+		Link:    s.config.ResetLink + token,
+		Minutes: int(s.config.ResetTTL / time.Minute),
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	if _, err := s.outbox.Enqueue(ctx, user.ID, msg); err != nil {
+		return err
+	}
+
+	s.logger.Info("password reset requested", map[string]interface{}{
+		"user_id": user.ID,
+		"action":  "PASSWORD_RESET_REQUESTED",
+	})
+
+	return nil
+}
+
+func (s *passwordService) Reset(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	if err := allow(s.resetByIP, utils.GetClientIPFromContext(ctx)); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	var userID int
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		userID, err = s.resetRepo.Consume(ctx, hashToken(req.Token), now)
+		if err != nil {
+			return err
+		}
+		if userID == 0 {
+			return errors.New("invalid or expired reset token")
+		}
+		if err := s.resetRepo.InvalidateForUser(ctx, userID, now); err != nil {
+			return err
+		}
+		return s.setPassword(ctx, userID, string(hashedPassword), events.PasswordReset{UserID: userID})
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.auth.LogoutAll(ctx, userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("password reset", map[string]interface{}{
+		"user_id": userID,
+		"action":  "PASSWORD_RESET",
+	})
+
+	return nil
+}
+
+func (s *passwordService) Change(ctx context.Context, req *userDto.ChangePasswordRequest, userID int) error {
+	if err := allow(s.changeByUser, fmt.Sprint(userID)); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
+		s.logger.Info("password change failed - invalid current password", map[string]interface{}{
+			"user_id": userID,
+			"action":  "PASSWORD_CHANGE_FAILED",
+		})
+		return errors.New("invalid current password")
+	}
+	if req.OldPassword == req.NewPassword {
+		return errors.New("new password must differ")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.setPassword(ctx, userID, string(hashedPassword), events.PasswordChanged{UserID: userID}); err != nil {
+			return err
+		}
+		// A reset link requested before the change must not undo it
+		return s.resetRepo.InvalidateForUser(ctx, userID, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.auth.LogoutAll(ctx, userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("password changed", map[string]interface{}{
+		"user_id": userID,
+		"action":  "PASSWORD_CHANGED",
+	})
+
+	return nil
+}
+
+func (s *passwordService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.resetRepo.DeleteExpired(ctx, time.Now())
+}
+
+// setPassword stores the new hash and records the change for the audit log in the caller's unit of work
+func (s *passwordService) setPassword(ctx context.Context, userID int, passwordHash string, event events.Event) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	user.PasswordHash = passwordHash
+	if err := s.userRepo.Update(ctx, user); err != nil || s.bus == nil {
+		return err
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
+
+// allow applies a limiter to key; an empty key (e.g. unknown client IP) is not limited
+func allow(limiter *ratelimit.Limiter, key string) error {
+	if key == "" {
+		return nil
+	}
+	if ok, retryAfter := limiter.Allow(key); !ok {
+		return &TooManyAttemptsError{RetryAfter: retryAfter}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+	userDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+	userDto "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
+	userRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeTxDB records the outcome of each transaction and the statements executed; it
+// returns no rows and fails the statements containing failOn
+type fakeTxDB struct {
+	outcomes   []string
+	statements []string
+	failOn     string
+}
+
+func (d *fakeTxDB) Connect(context.Context) (driver.Conn, error) { return fakeTxConn{d}, nil }
+func (d *fakeTxDB) Driver() driver.Driver                        { return nil }
+
+type fakeTxConn struct{ db *fakeTxDB }
+
+func (c fakeTxConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not supported")
+}
+func (c fakeTxConn) Close() error              { return nil }
+func (c fakeTxConn) Begin() (driver.Tx, error) { return fakeTx(c), nil }
+
+func (c fakeTxConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.statements = append(c.db.statements, strings.Join(strings.Fields(query), " "))
+	if c.db.failOn != "" && strings.Contains(query, c.db.failOn) {
+		return nil, errors.New("statement failed")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTx struct{ db *fakeTxDB }
+
+func (t fakeTx) Commit() error   { t.db.outcomes = append(t.db.outcomes, "commit"); return nil }
+func (t fakeTx) Rollback() error { t.db.outcomes = append(t.db.outcomes, "rollback"); return nil }
+
+func newFakeDB(t *testing.T) (*sqlx.DB, *fakeTxDB) {
+	t.Helper()
+	fake := &fakeTxDB{}
+	db := sqlx.NewDb(sql.OpenDB(fake), "postgres")
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+func newFakeUnitOfWork(t *testing.T) (*database.UnitOfWork, *fakeTxDB) {
+	t.Helper()
+	db, fake := newFakeDB(t)
+	return database.NewUnitOfWork(db), fake
+}
+
+type fakeUserRepo struct {
+	userRepo.UserRepository
+	user      *userDomain.User
+	updateErr error
+	updated   bool
+	// lookups are the emails GetByEmail was called with
+	lookups []string
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*userDomain.User, error) {
+	r.lookups = append(r.lookups, email)
+	if r.user == nil || !strings.EqualFold(r.user.Email, email) {
+		return nil, nil
+	}
+	copied := *r.user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id int) (*userDomain.User, error) {
+	if r.user == nil || r.user.ID != id {
+		return nil, nil
+	}
+	copied := *r.user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *userDomain.User) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	r.updated = true
+	return nil
+}
+
+type fakeResetRepo struct {
+	repository.PasswordResetRepository
+	invalidateErr error
+	invalidated   bool
+}
+
+func (r *fakeResetRepo) InvalidateForUser(ctx context.Context, userID int, at time.Time) error {
+	if r.invalidateErr != nil {
+		return r.invalidateErr
+	}
+	r.invalidated = true
+	return nil
+}
+
+// logoutRecorder counts the LogoutAll calls the password service makes
+type logoutRecorder struct {
+	AuthService
+	loggedOut int
+}
+
+func (a *logoutRecorder) LogoutAll(ctx context.Context, userID int) (*dto.LogoutAllResponse, error) {
+	a.loggedOut++
+	return &dto.LogoutAllResponse{}, nil
+}
+
+func TestChangePassword(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		req           userDto.ChangePasswordRequest
+		updateErr     error
+		invalidateErr error
+		wantErr       bool
+		wantOutcomes  []string
+		wantLogout    bool
+	}{
+		{
+			name:         "password and reset links change together",
+			req:          userDto.ChangePasswordRequest{OldPassword: "old-secret", NewPassword: "new-secret"},
+			wantOutcomes: []string{"commit"},
+			wantLogout:   true,
+		},
+		{
+			name:          "failing to invalidate reset links rolls the password back",
+			req:           userDto.ChangePasswordRequest{OldPassword: "old-secret", NewPassword: "new-secret"},
+			invalidateErr: errors.New("database down"),
+			wantErr:       true,
+			wantOutcomes:  []string{"rollback"},
+		},
+		{
+			name:         "failing to store the password",
+			req:          userDto.ChangePasswordRequest{OldPassword: "old-secret", NewPassword: "new-secret"},
+			updateErr:    errors.New("database down"),
+			wantErr:      true,
+			wantOutcomes: []string{"rollback"},
+		},
+		{
+			name:    "wrong current password",
+			req:     userDto.ChangePasswordRequest{OldPassword: "guess", NewPassword: "new-secret"},
+			wantErr: true,
+		},
+		{
+			name:    "unchanged password",
+			req:     userDto.ChangePasswordRequest{OldPassword: "old-secret", NewPassword: "old-secret"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, db := newFakeUnitOfWork(t)
+			users := &fakeUserRepo{user: &userDomain.User{ID: 3, PasswordHash: string(hash)}, updateErr: tt.updateErr}
+			resets := &fakeResetRepo{invalidateErr: tt.invalidateErr}
+			auth := &logoutRecorder{}
+			s := NewPasswordService(users, resets, uow, auth, nil, nil, PasswordConfig{}, logger.NewLogger(nil), nil)
+
+			err := s.Change(context.Background(), &tt.req, 3)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Change error = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(db.outcomes, tt.wantOutcomes) {
+				t.Errorf("transactions = %v, want %v", db.outcomes, tt.wantOutcomes)
+			}
+			if loggedOut := auth.loggedOut > 0; loggedOut != tt.wantLogout {
+				t.Errorf("logged out = %v, want %v", loggedOut, tt.wantLogout)
+			}
+		})
+	}
+}
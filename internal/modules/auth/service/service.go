@@ -2,11 +2,250 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/middleware"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	userDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+	userDto "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
 )
 
+// TokenConfig sets the lifetime of issued tokens
+type TokenConfig struct {
+	AccessTTL  time.Duration // Lifetime of a JWT access token
+	RefreshTTL time.Duration // Lifetime of a refresh token; every rotation starts a new one
+	// Lifetime of the challenge a password login returns to users with 2FA enabled
+	MFAChallengeTTL time.Duration
+}
+
+// DefaultTokenConfig returns the default token lifetimes
+func DefaultTokenConfig() TokenConfig {
+	return TokenConfig{
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 30 * 24 * time.Hour,
+
+		MFAChallengeTTL: 5 * time.Minute,
+	}
+}
+
+// TokenConfigFromEnv reads ACCESS_TOKEN_TTL and REFRESH_TOKEN_TTL (Go durations, e.g. "15m", "720h"),
+// falling back to defaults
+func TokenConfigFromEnv() TokenConfig {
+	cfg := DefaultTokenConfig()
+
+	if v, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_TTL")); err == nil && v > 0 {
+		cfg.AccessTTL = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_TTL")); err == nil && v > 0 {
+		cfg.RefreshTTL = v
+	}
+
+	return cfg
+}
+
 type AuthService interface {
+	// Login checks the password. For users with 2FA enabled it returns an MFA challenge
+	// instead of tokens, to be completed with VerifyMFA.
 	Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error)
+	// VerifyMFA completes a login with a TOTP or recovery code
+	VerifyMFA(ctx context.Context, req *dto.VerifyMFARequest) (*dto.AuthResponse, error)
+	// CompleteLogin finishes a login whose first factor was checked elsewhere, such as an
+	// OIDC provider: an MFA challenge when the user has 2FA enabled, tokens otherwise
+	CompleteLogin(ctx context.Context, user *userDomain.User) (*dto.AuthResponse, error)
 	Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error)
+	// Refresh exchanges a refresh token for a new access token and a new refresh token
+	Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.AuthResponse, error)
+	// Logout ends the session of the calling access token and, when given, the session of
+	// the refresh token
+	Logout(ctx context.Context, req *dto.LogoutRequest, userID int) error
+	// LogoutAll revokes every session and access token of the user
+	LogoutAll(ctx context.Context, userID int) (*dto.LogoutAllResponse, error)
+	// RevokeAccessTokens rejects every access token issued to the user so far; sessions stay
+	// and the next refresh issues a token with the user's current roles
+	RevokeAccessTokens(ctx context.Context, userID int) error
+
+	// PurgeExpiredTokens deletes refresh tokens, revocation entries and MFA challenges that
+	// have expired, and the sessions left without refresh tokens
+	PurgeExpiredTokens(ctx context.Context) (int64, error)
+}
+
+// SessionService lists the devices a user is logged in on and signs them out
+type SessionService interface {
+	// List returns the user's live sessions, most recently seen first; currentSessionID
+	// marks the caller's own
+	List(ctx context.Context, userID int, currentSessionID string) ([]dto.SessionResponse, error)
+	// Revoke signs a session out: its refresh and access tokens stop working and its
+	// WebSocket/SSE connections are closed
+	Revoke(ctx context.Context, id int64, userID int) error
+	// RevokeOthers signs out every session of the user except currentSessionID
+	RevokeOthers(ctx context.Context, userID int, currentSessionID string) (*dto.LogoutAllResponse, error)
+}
+
+// PasswordConfig configures password recovery
+type PasswordConfig struct {
+	ResetTTL  time.Duration // How long a reset link stays valid
+	ResetLink string        // Base of the link mailed to the user; the token is appended
+}
+
+// PasswordConfigFromEnv reads PASSWORD_RESET_TTL (Go duration, default 1h) and APP_BASE_URL,
+// the frontend that serves /reset-password (default http://localhost:3000)
+func PasswordConfigFromEnv() PasswordConfig {
+	cfg := PasswordConfig{ResetTTL: time.Hour}
+
+	if v, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL")); err == nil && v > 0 {
+		cfg.ResetTTL = v
+	}
+
+	cfg.ResetLink = appBaseURL() + "/reset-password?token="
+
+	return cfg
+}
+
+// appBaseURL is the frontend the links in emails point to: APP_BASE_URL, default http://localhost:3000
+func appBaseURL() string {
+	baseURL := strings.TrimRight(os.Getenv("APP_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = "http://localhost:3000"
+	}
+	return baseURL
+}
+
+// TooManyAttemptsError is returned when a rate limit is hit
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string { return "too many attempts" }
+
+type PasswordService interface {
+	// Forgot mails a reset link if the email belongs to an account. It succeeds either way,
+	// so the response does not reveal which emails are registered.
+	Forgot(ctx context.Context, req *dto.ForgotPasswordRequest) error
+	// Reset sets a new password with a reset token and ends every session of the user
+	Reset(ctx context.Context, req *dto.ResetPasswordRequest) error
+	// Change sets a new password after checking the current one and ends every session of the user
+	Change(ctx context.Context, req *userDto.ChangePasswordRequest, userID int) error
+
+	// PurgeExpired deletes expired reset tokens
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// EmailVerificationConfig configures the links that verify a user's email address
+type EmailVerificationConfig struct {
+	TTL  time.Duration // How long a verification link stays valid
+	Link string        // Base of the link mailed to the user; the token is appended
+	Key  []byte        // HMAC key the links are signed with
+}
+
+// EmailVerificationConfigFromEnv reads EMAIL_VERIFICATION_TTL (Go duration, default 48h),
+// APP_BASE_URL, the frontend that serves /verify-email, and EMAIL_VERIFICATION_KEY. Without
+// EMAIL_VERIFICATION_KEY the key is derived from JWT_SECRET; changing it voids links already sent.
+func EmailVerificationConfigFromEnv() EmailVerificationConfig {
+	cfg := EmailVerificationConfig{TTL: 48 * time.Hour}
+
+	if v, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_TTL")); err == nil && v > 0 {
+		cfg.TTL = v
+	}
+	cfg.Link = appBaseURL() + "/verify-email?token="
+
+	secret := os.Getenv("EMAIL_VERIFICATION_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	// Prefixed so the key differs from others derived from JWT_SECRET
+	key := sha256.Sum256([]byte("email-verification:" + secret))
+	cfg.Key = key[:]
+
+	return cfg
+}
+
+type EmailVerificationService interface {
+	// Send mails the user a link that verifies their current email address
+	Send(ctx context.Context, user *userDomain.User) error
+	// Resend mails a new link to a user whose email is not verified yet
+	Resend(ctx context.Context, userID int) error
+	// Verify marks the email of the link's user verified. A link stops working when it
+	// expires or the user changes their email.
+	Verify(ctx context.Context, req *dto.VerifyEmailRequest) error
+	// EmailVerified reports whether the user has verified their email address
+	EmailVerified(ctx context.Context, userID int) (bool, error)
+}
+
+// MFAConfig configures TOTP two-factor authentication
+type MFAConfig struct {
+	Issuer        string // Account issuer shown in authenticator apps
+	EncryptionKey []byte // AES-256 key the TOTP secrets are encrypted with
+}
+
+// MFAConfigFromEnv reads MFA_ISSUER (default "Go Modular Monolith") and MFA_ENCRYPTION_KEY.
+// Without MFA_ENCRYPTION_KEY the key is derived from JWT_SECRET; changing the key makes
+// existing enrollments unusable.
+func MFAConfigFromEnv() MFAConfig {
+	cfg := MFAConfig{Issuer: os.Getenv("MFA_ISSUER")}
+	if cfg.Issuer == "" {
+		cfg.Issuer = "Go Modular Monolith"
+	}
+
+	secret := os.Getenv("MFA_ENCRYPTION_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	key := sha256.Sum256([]byte(secret))
+	cfg.EncryptionKey = key[:]
+
+	return cfg
+}
+
+type MFAService interface {
+	Status(ctx context.Context, userID int) (*dto.MFAStatusResponse, error)
+	// Enroll creates a new pending TOTP secret; 2FA is enforced only after Enable
+	Enroll(ctx context.Context, userID int) (*dto.MFAEnrollResponse, error)
+	// Enable confirms the pending secret with a code and returns fresh recovery codes
+	Enable(ctx context.Context, req *dto.MFACodeRequest, userID int) (*dto.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, req *dto.DisableMFARequest, userID int) error
+	RegenerateRecoveryCodes(ctx context.Context, req *dto.MFACodeRequest, userID int) (*dto.RecoveryCodesResponse, error)
+
+	// Enabled reports whether the user has confirmed 2FA
+	Enabled(ctx context.Context, userID int) (bool, error)
+	// Verify accepts and spends a current TOTP code or an unused recovery code. Call it inside
+	// a unit of work: the enrollment is locked so the same TOTP code cannot be used twice.
+	Verify(ctx context.Context, userID int, code string) error
+}
+
+type AccessTokenService interface {
+	List(ctx context.Context, userID int) ([]dto.AccessTokenResponse, error)
+	Create(ctx context.Context, req *dto.CreateAccessTokenRequest, userID int) (*dto.CreatedAccessTokenResponse, error)
+	Revoke(ctx context.Context, id int64, userID int) error
+	// Scopes lists the scopes a token can be given
+	Scopes() []string
+
+	// AuthenticateAccessToken resolves a bearer token for AuthMiddleware and records its use
+	AuthenticateAccessToken(ctx context.Context, token string, ip string) (*middleware.AccessTokenIdentity, error)
+	// PurgeInactive deletes tokens that expired or were revoked more than 30 days ago
+	PurgeInactive(ctx context.Context) (int64, error)
+}
+
+// OIDCAutoRegisterFromEnv reads OIDC_AUTO_REGISTER: unless it is "false", an OIDC login with
+// a verified email that matches no user creates an account
+func OIDCAutoRegisterFromEnv() bool {
+	return os.Getenv("OIDC_AUTO_REGISTER") != "false"
+}
+
+type OIDCService interface {
+	// Enabled reports whether an OIDC provider is configured
+	Enabled() bool
+	// AuthorizationURL starts a login and returns the provider URL to send the user to and the
+	// state, which the caller binds to the browser; loginHint (optional) is passed on to
+	// pre-fill the provider's login form
+	AuthorizationURL(ctx context.Context, loginHint string) (authURL, state string, err error)
+	// Callback finishes a login with the code the provider returned. browserState is the state
+	// bound to the browser that started the login and must match req.State. The provider
+	// account is matched by its linked identity, then by verified email; otherwise a user is created.
+	Callback(ctx context.Context, req *dto.OIDCCallbackRequest, browserState string) (*dto.AuthResponse, error)
+
+	// PurgeExpiredStates deletes logins that never came back from the provider
+	PurgeExpiredStates(ctx context.Context) (int64, error)
 }
@@ -2,62 +2,143 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jwtkeys"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/metrics"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/ratelimit"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/revocation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
 	userDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+	userDto "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
 	userRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Disconnector closes a user's live connections once their tokens are revoked
+type Disconnector interface {
+	DisconnectUser(userID int, code int, reason string)
+	DisconnectSession(userID int, sessionID string, code int, reason string)
+}
+
+// closeTokenRevoked is the WebSocket close code sent to connections of a user who logged out everywhere
+const closeTokenRevoked = 4003
+
+var (
+	errInvalidRefreshToken = errors.New("invalid refresh token")
+	errRefreshTokenReused  = errors.New("refresh token reuse detected")
+	errInvalidMFAChallenge = errors.New("invalid mfa challenge")
+	errInvalidCredentials  = errors.New("invalid email or password")
+)
+
 type authService struct {
-	userRepo userRepo.UserRepository
-	logger   *logger.ZapLogger
+	userRepo     userRepo.UserRepository
+	roleRepo     userRepo.RoleRepository
+	tokenRepo    repository.RefreshTokenRepository
+	sessionRepo  repository.SessionRepository
+	mfaRepo      repository.MFARepository
+	mfa          MFAService
+	verification EmailVerificationService
+	uow          *database.UnitOfWork
+	revocations  *revocation.List
+	disconnector Disconnector
+	keys         *jwtkeys.Manager
+	config       TokenConfig
+	logger       *logger.ZapLogger
+	bus          *eventbus.Bus
+
+	mfaAttemptsByIP      *ratelimit.Limiter
+	loginFailuresByEmail *ratelimit.Lockout
+	loginFailuresByIP    *ratelimit.Lockout
 }
 
-func NewAuthService(userRepo userRepo.UserRepository, logger *logger.ZapLogger) AuthService {
+func NewAuthService(userRepo userRepo.UserRepository, roleRepo userRepo.RoleRepository, tokenRepo repository.RefreshTokenRepository, sessionRepo repository.SessionRepository, mfaRepo repository.MFARepository, mfa MFAService, verification EmailVerificationService, uow *database.UnitOfWork, revocations *revocation.List, disconnector Disconnector, keys *jwtkeys.Manager, config TokenConfig, logger *logger.ZapLogger, bus *eventbus.Bus) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		logger:   logger,
-	}
-}
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		tokenRepo:    tokenRepo,
+		sessionRepo:  sessionRepo,
+		mfaRepo:      mfaRepo,
+		mfa:          mfa,
+		verification: verification,
+		uow:          uow,
+		revocations:  revocations,
+		disconnector: disconnector,
+		keys:         keys,
+		config:       config,
+		logger:       logger,
+		bus:          bus,
 
-type Claims struct {
-	UserID   int    `json:"user_id"`
-	Email    string `json:"email"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
-	jwt.RegisteredClaims
+		mfaAttemptsByIP: ratelimit.New(20, 15*time.Minute),
+		// Failed password logins lock the account from the 5th and the client IP from the 20th
+		// failure, for a minute at first and twice as long with each further failure, up to an hour
+		loginFailuresByEmail: ratelimit.NewLockout(5, time.Minute, time.Hour, 24*time.Hour),
+		loginFailuresByIP:    ratelimit.NewLockout(20, time.Minute, time.Hour, time.Hour),
+	}
 }
 
 func (s *authService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
+	// The lockout key and the lookup use the same form so that variants of one address share a counter
+	email := strings.ToLower(strings.TrimSpace(req.Email))
 	s.logger.Info("Login attempt", map[string]interface{}{
-		"email":  req.Email,
+		"email":  email,
 		"action": "LOGIN",
 	})
 
-	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	ip := utils.GetClientIPFromContext(ctx)
+	if err := s.checkLoginLockout(email, ip); err != nil {
+		s.logger.Info("login blocked - locked out", map[string]interface{}{
+			"email":  email,
+			"ip":     ip,
+			"action": "LOGIN_LOCKED_OUT",
+		})
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("invalid email or password")
+		s.recordLoginFailure(ctx, nil, email, ip)
+		return nil, errInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
 		s.logger.Info("login failed - invalid password", map[string]interface{}{
 			"user_id": user.ID,
-			"email":   req.Email,
+			"email":   email,
 			"action":  "LOGIN_FAILED",
 		})
-		return nil, errors.New("invalid email or password")
+		s.recordLoginFailure(ctx, user, email, ip)
+		return nil, errInvalidCredentials
 	}
+	s.loginFailuresByEmail.Reset(email)
 
-	token, expiresAt, err := s.generateToken(user)
+	mfaEnabled, err := s.mfa.Enabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		return s.startMFAChallenge(ctx, user.ID)
+	}
+
+	response, err := s.issueTokens(ctx, user, "", req.DeviceName)
 	if err != nil {
 		return nil, err
 	}
@@ -68,18 +149,168 @@ func (s *authService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 		"action":  "LOGIN",
 	})
 
-	return &dto.AuthResponse{
-		Token:     token,
+	return response, nil
+}
+
+// checkLoginLockout fails while the email or the client IP is locked out after failed logins
+func (s *authService) checkLoginLockout(email, ip string) error {
+	if locked, retryAfter := s.loginFailuresByEmail.Locked(email); locked {
+		return &TooManyAttemptsError{RetryAfter: retryAfter}
+	}
+	if ip == "" {
+		return nil
+	}
+	if locked, retryAfter := s.loginFailuresByIP.Locked(ip); locked {
+		return &TooManyAttemptsError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed password login against the email and the client IP and
+// audits it on the account when the email belongs to one. Unknown emails are counted the same
+// way, so lockouts do not reveal which emails are registered.
+func (s *authService) recordLoginFailure(ctx context.Context, user *userDomain.User, email, ip string) {
+	attempts, lockedFor := s.loginFailuresByEmail.Fail(email)
+	if lockedFor > 0 {
+		metrics.LoginLockoutsTotal.WithLabelValues("account").Inc()
+		s.logger.Info("account locked after failed logins", map[string]interface{}{
+			"email":      email,
+			"attempts":   attempts,
+			"locked_for": lockedFor.String(),
+			"action":     "ACCOUNT_LOCKED",
+		})
+	}
+
+	if ip != "" {
+		if ipAttempts, ipLockedFor := s.loginFailuresByIP.Fail(ip); ipLockedFor > 0 {
+			metrics.LoginLockoutsTotal.WithLabelValues("ip").Inc()
+			s.logger.Info("client IP locked after failed logins", map[string]interface{}{
+				"ip":         ip,
+				"attempts":   ipAttempts,
+				"locked_for": ipLockedFor.String(),
+				"action":     "IP_LOCKED",
+			})
+		}
+	}
+
+	if user == nil || s.bus == nil {
+		return
+	}
+	published := []events.Event{events.LoginFailed{UserID: user.ID, Attempts: attempts}}
+	if lockedFor > 0 {
+		published = append(published, events.AccountLocked{
+			UserID:      user.ID,
+			Attempts:    attempts,
+			LockedUntil: time.Now().Add(lockedFor),
+		})
+	}
+	// The login already failed; losing the notification must not change its answer
+	for _, event := range published {
+		if err := s.bus.Publish(ctx, user.ID, event); err != nil {
+			s.logger.Error("Failed to publish login failure", err, map[string]interface{}{
+				"user_id": user.ID,
+				"type":    event.EventType(),
+				"action":  "LOGIN_FAILURE_PUBLISH_FAILED",
+			})
+		}
+	}
+}
+
+func (s *authService) CompleteLogin(ctx context.Context, user *userDomain.User) (*dto.AuthResponse, error) {
+	mfaEnabled, err := s.mfa.Enabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		return s.startMFAChallenge(ctx, user.ID)
+	}
+	return s.issueTokens(ctx, user, "", "")
+}
+
+// startMFAChallenge returns the token that lets the user finish a password login with a second factor
+func (s *authService) startMFAChallenge(ctx context.Context, userID int) (*dto.AuthResponse, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.config.MFAChallengeTTL)
+	if err := s.mfaRepo.CreateChallenge(ctx, &domain.MFAChallenge{
+		UserID:    userID,
+		TokenHash: hashToken(token),
 		ExpiresAt: expiresAt,
-		User: dto.UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			FullName:  user.FullName,
-			AvatarURL: user.AvatarURL,
-			Timezone:  user.Timezone,
-			CreatedAt: user.CreatedAt,
-		},
-	}, nil
+		IP:        utils.GetClientIPFromContext(ctx),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("login requires second factor", map[string]interface{}{
+		"user_id": userID,
+		"action":  "LOGIN_MFA_REQUIRED",
+	})
+
+	return &dto.AuthResponse{MFARequired: true, MFAToken: token, MFATokenExpiresAt: expiresAt}, nil
+}
+
+func (s *authService) VerifyMFA(ctx context.Context, req *dto.VerifyMFARequest) (*dto.AuthResponse, error) {
+	if err := allow(s.mfaAttemptsByIP, utils.GetClientIPFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	// Counted outside the unit of work so a wrong code still uses up an attempt
+	now := time.Now()
+	challenge, err := s.mfaRepo.AttemptChallenge(ctx, hashToken(req.MFAToken), now)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, errInvalidMFAChallenge
+	}
+
+	var response *dto.AuthResponse
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.mfa.Verify(ctx, challenge.UserID, req.Code); err != nil {
+			return err
+		}
+		used, err := s.mfaRepo.UseChallenge(ctx, challenge.ID, now)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errInvalidMFAChallenge
+		}
+
+		user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return errInvalidMFAChallenge
+		}
+		response, err = s.issueTokens(ctx, user, "", req.DeviceName)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidMFACode) {
+			s.logger.Info("login failed - invalid second factor", map[string]interface{}{
+				"user_id":  challenge.UserID,
+				"attempts": challenge.Attempts,
+				"action":   "LOGIN_MFA_FAILED",
+			})
+		}
+		return nil, err
+	}
+
+	s.logger.Info("user logged in", map[string]interface{}{
+		"user_id": response.User.ID,
+		"email":   response.User.Email,
+		"mfa":     true,
+		"action":  "LOGIN",
+	})
+
+	return response, nil
 }
 
 func (s *authService) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error) {
@@ -116,7 +347,20 @@ func (s *authService) Register(ctx context.Context, req *dto.RegisterRequest) (*
 		UpdatedAt:    now,
 	}
 
-	created, err := s.userRepo.Create(ctx, user)
+	var created *userDomain.User
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		if s.bus == nil {
+			return nil
+		}
+		return s.bus.Publish(ctx, created.ID, events.UserCreated{
+			UserID: created.ID,
+			User:   userDto.ToUserResponse(created),
+		})
+	})
 	if err != nil {
 		s.logger.Error("failed to create user", err, map[string]interface{}{
 			"email":  req.Email,
@@ -125,7 +369,7 @@ func (s *authService) Register(ctx context.Context, req *dto.RegisterRequest) (*
 		return nil, err
 	}
 
-	token, expiresAt, err := s.generateToken(created)
+	response, err := s.issueTokens(ctx, created, "", req.DeviceName)
 	if err != nil {
 		return nil, err
 	}
@@ -136,41 +380,305 @@ func (s *authService) Register(ctx context.Context, req *dto.RegisterRequest) (*
 		"action":  "REGISTER",
 	})
 
+	// Registration succeeds even when the email cannot be queued; the user can ask for another link
+	if err := s.verification.Send(ctx, created); err != nil {
+		s.logger.Error("failed to send email verification", err, map[string]interface{}{
+			"user_id": created.ID,
+			"action":  "EMAIL_VERIFICATION_FAILED",
+		})
+	}
+
+	return response, nil
+}
+
+func (s *authService) Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.AuthResponse, error) {
+	var (
+		response *dto.AuthResponse
+		reused   *domain.RefreshToken
+	)
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		current, err := s.tokenRepo.GetByHash(ctx, hashToken(req.RefreshToken))
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return errInvalidRefreshToken
+		}
+		if current.Reused() {
+			// Revoked outside this unit of work so the revocation is not rolled back with the error
+			reused = current
+			return nil
+		}
+		now := time.Now()
+		if !current.Active(now) {
+			return errInvalidRefreshToken
+		}
+
+		user, err := s.userRepo.GetByID(ctx, current.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return errInvalidRefreshToken
+		}
+
+		if err := s.tokenRepo.MarkRotated(ctx, current.ID, now); err != nil {
+			return err
+		}
+		touched, err := s.sessionRepo.Touch(ctx, current.FamilyID, utils.GetClientIPFromContext(ctx), now)
+		if err != nil {
+			return err
+		}
+		if !touched {
+			// Logins from before sessions were recorded get one on their next refresh
+			if err := s.createSession(ctx, user.ID, current.FamilyID, "", now); err != nil {
+				return err
+			}
+		}
+		response, err = s.issueTokens(ctx, user, current.FamilyID, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reused != nil {
+		revoked, err := s.tokenRepo.RevokeFamily(ctx, reused.FamilyID, domain.RevokeReuse)
+		if err != nil {
+			return nil, err
+		}
+		s.logger.Info("refresh token reuse detected, session revoked", map[string]interface{}{
+			"user_id":   reused.UserID,
+			"family_id": reused.FamilyID,
+			"revoked":   revoked,
+			"ip":        utils.GetClientIPFromContext(ctx),
+			"action":    "REFRESH_TOKEN_REUSE",
+		})
+		return nil, errRefreshTokenReused
+	}
+
+	s.logger.Info("access token refreshed", map[string]interface{}{
+		"user_id": response.User.ID,
+		"action":  "TOKEN_REFRESH",
+	})
+
+	return response, nil
+}
+
+func (s *authService) Logout(ctx context.Context, req *dto.LogoutRequest, userID int) error {
+	if req.RefreshToken != "" {
+		token, err := s.tokenRepo.GetByHash(ctx, hashToken(req.RefreshToken))
+		if err != nil {
+			return err
+		}
+		// Tokens of other users are ignored rather than reported, so logout reveals nothing about them
+		if token != nil && token.UserID == userID {
+			if _, err := s.tokenRepo.RevokeFamily(ctx, token.FamilyID, domain.RevokeLogout); err != nil {
+				return err
+			}
+		}
+	}
+
+	expiresAt, _ := ctx.Value(utils.TokenExpiresAtKey).(time.Time)
+	if err := s.revocations.RevokeToken(ctx, userID, utils.GetTokenIDFromContext(ctx), expiresAt); err != nil {
+		return err
+	}
+
+	// The rest of the caller's session ends too: its refresh tokens and other access tokens
+	if sessionID := utils.GetSessionIDFromContext(ctx); sessionID != "" {
+		if _, err := s.tokenRepo.RevokeFamily(ctx, sessionID, domain.RevokeLogout); err != nil {
+			return err
+		}
+		if err := s.revocations.RevokeSession(ctx, userID, sessionID, time.Now().Add(s.config.AccessTTL+time.Minute)); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("user logged out", map[string]interface{}{
+		"user_id": userID,
+		"action":  "LOGOUT",
+	})
+
+	return nil
+}
+
+func (s *authService) LogoutAll(ctx context.Context, userID int) (*dto.LogoutAllResponse, error) {
+	revoked, err := s.tokenRepo.RevokeAllForUser(ctx, userID, domain.RevokeLogoutAll)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RevokeAccessTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if s.disconnector != nil {
+		s.disconnector.DisconnectUser(userID, closeTokenRevoked, "logged out")
+	}
+
+	s.logger.Info("user logged out of all sessions", map[string]interface{}{
+		"user_id": userID,
+		"revoked": revoked,
+		"action":  "LOGOUT_ALL",
+	})
+
+	return &dto.LogoutAllResponse{RevokedSessions: revoked}, nil
+}
+
+func (s *authService) RevokeAccessTokens(ctx context.Context, userID int) error {
+	// The entry must outlive every access token issued so far, including the clock skew leeway
+	until := time.Now().Add(s.config.AccessTTL + time.Minute)
+	return s.revocations.RevokeUser(ctx, userID, until)
+}
+
+func (s *authService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	tokens, err := s.tokenRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	revocations, err := s.revocations.Purge(ctx, now)
+	if err != nil {
+		return tokens, err
+	}
+	challenges, err := s.mfaRepo.DeleteExpiredChallenges(ctx, now)
+	if err != nil {
+		return tokens + revocations, err
+	}
+	// Sessions are only created together with their first refresh token; the margin keeps a
+	// login in progress from losing its session
+	sessions, err := s.sessionRepo.DeleteOrphaned(ctx, now.Add(-time.Hour))
+	if err != nil {
+		return tokens + revocations + challenges, err
+	}
+
+	return tokens + revocations + challenges + sessions, nil
+}
+
+// issueTokens creates an access token and a refresh token for the user. An empty
+// familyID starts a new session named deviceName, or after the user agent when that is
+// empty; otherwise the tokens continue that session.
+func (s *authService) issueTokens(ctx context.Context, user *userDomain.User, familyID, deviceName string) (*dto.AuthResponse, error) {
+	now := time.Now()
+	if familyID == "" {
+		var err error
+		if familyID, err = randomToken(16); err != nil {
+			return nil, err
+		}
+		if err := s.createSession(ctx, user.ID, familyID, deviceName, now); err != nil {
+			return nil, err
+		}
+	}
+
+	token, expiresAt, err := s.generateToken(ctx, user, familyID)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.tokenRepo.Create(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.config.RefreshTTL),
+		IP:        utils.GetClientIPFromContext(ctx),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.AuthResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
+		Token:                 token,
+		ExpiresAt:             expiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: stored.ExpiresAt,
 		User: dto.UserResponse{
-			ID:        created.ID,
-			Email:     created.Email,
-			FullName:  created.FullName,
-			AvatarURL: created.AvatarURL,
-			Timezone:  created.Timezone,
-			CreatedAt: created.CreatedAt,
+			ID:        user.ID,
+			Email:     user.Email,
+			FullName:  user.FullName,
+			AvatarURL: user.AvatarURL,
+			Timezone:  user.Timezone,
+			CreatedAt: user.CreatedAt,
 		},
 	}, nil
 }
 
-func (s *authService) generateToken(user *userDomain.User) (string, time.Time, error) {
-	jwtKey := []byte(os.Getenv("JWT_SECRET"))
-	expiresAt := time.Now().Add(24 * time.Hour)
+// createSession records the device a refresh token family was issued to. An empty
+// deviceName is derived from the user agent.
+func (s *authService) createSession(ctx context.Context, userID int, familyID, deviceName string, now time.Time) error {
+	userAgent := utils.GetUserAgentFromContext(ctx)
+	if deviceName = strings.TrimSpace(deviceName); deviceName == "" {
+		deviceName = describeDevice(userAgent)
+	}
+	return s.sessionRepo.Create(ctx, &domain.Session{
+		UserID:     userID,
+		FamilyID:   familyID,
+		DeviceName: deviceName,
+		UserAgent:  userAgent,
+		IP:         utils.GetClientIPFromContext(ctx),
+		CreatedAt:  now,
+	})
+}
 
-	claims := &Claims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Username: user.Email,
-		Role:     "user",
+func (s *authService) generateToken(ctx context.Context, user *userDomain.User, sessionID string) (string, time.Time, error) {
+	// Roles and permissions are read at issue time; changing them revokes the user's access tokens
+	access, err := s.roleRepo.GetAccess(ctx, user.ID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	role := authz.RoleUser
+	if slices.Contains(access.Roles, authz.RoleAdmin) {
+		role = authz.RoleAdmin
+	}
+	now := time.Now()
+	expiresAt := now.Add(s.config.AccessTTL)
+
+	tokenID, err := randomToken(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := &jwtkeys.Claims{
+		UserID:      user.ID,
+		Email:       user.Email,
+		Username:    user.Email,
+		Role:        role,
+		Roles:       access.Roles,
+		Permissions: access.Permissions,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-1 * time.Minute)),
-			NotBefore: jwt.NewNumericDate(time.Now().Add(-1 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now.Add(-1 * time.Minute)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
+	tokenString, err := s.keys.Sign(claims)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
 	return tokenString, expiresAt, nil
 }
+
+// randomToken returns n random bytes, hex encoded
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken is how refresh tokens are stored: they are high-entropy, so a plain SHA-256 suffices
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
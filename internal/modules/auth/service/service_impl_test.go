@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jwtkeys"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/revocation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+	userDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+	userRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// mfaRequired makes every password login stop at the second factor, so a successful
+// login needs no token issuing
+type mfaRequired struct{ MFAService }
+
+func (mfaRequired) Enabled(ctx context.Context, userID int) (bool, error) { return true, nil }
+
+type challengeStore struct{ repository.MFARepository }
+
+func (challengeStore) CreateChallenge(ctx context.Context, challenge *domain.MFAChallenge) error {
+	return nil
+}
+
+// loginAttempt is one call to Login from ip
+type loginAttempt struct {
+	email    string
+	password string
+	ip       string
+}
+
+func TestLoginLockout(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repeat := func(n int, attempt loginAttempt) []loginAttempt {
+		attempts := make([]loginAttempt, n)
+		for i := range attempts {
+			attempts[i] = attempt
+		}
+		return attempts
+	}
+	wrong := loginAttempt{email: "ada@example.com", password: "guess", ip: "10.0.0.1"}
+	// Guessing 5 accounts, 4 times each, stays below every account's threshold
+	var sprayed []loginAttempt
+	for i := range 20 {
+		sprayed = append(sprayed, loginAttempt{email: fmt.Sprintf("user%d@example.com", i/4), password: "guess", ip: "10.0.0.2"})
+	}
+
+	tests := []struct {
+		name     string
+		before   []loginAttempt
+		attempt  loginAttempt
+		wantLock bool
+	}{
+		{name: "below the threshold", before: repeat(4, wrong), attempt: loginAttempt{email: "ada@example.com", password: "secret"}},
+		{name: "email is normalized before the lookup", attempt: loginAttempt{email: " ADA@example.com ", password: "secret"}},
+		{name: "wrong passwords lock the account", before: repeat(5, wrong), attempt: loginAttempt{email: "ada@example.com", password: "secret"}, wantLock: true},
+		{
+			name: "case and spacing variants share the lockout",
+			before: []loginAttempt{
+				{email: "Ada@Example.com", password: "guess"},
+				{email: " ada@example.com ", password: "guess"},
+				{email: "ADA@EXAMPLE.COM", password: "guess"},
+				{email: "ada@Example.com", password: "guess"},
+				{email: "aDa@example.com", password: "guess"},
+			},
+			attempt:  loginAttempt{email: "ada@example.com", password: "secret"},
+			wantLock: true,
+		},
+		{
+			name:     "unknown emails lock too",
+			before:   repeat(5, loginAttempt{email: "Ghost@example.com", password: "guess"}),
+			attempt:  loginAttempt{email: "ghost@example.com", password: "guess"},
+			wantLock: true,
+		},
+		{
+			name:    "a successful login resets the count",
+			before:  append(append(repeat(4, wrong), loginAttempt{email: "ada@example.com", password: "secret"}), repeat(4, wrong)...),
+			attempt: loginAttempt{email: "ada@example.com", password: "secret"},
+		},
+		{
+			name:     "one client trying many accounts",
+			before:   sprayed,
+			attempt:  loginAttempt{email: "ada@example.com", password: "secret", ip: "10.0.0.2"},
+			wantLock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &fakeUserRepo{user: &userDomain.User{ID: 3, Email: "Ada@Example.com", PasswordHash: string(hash)}}
+			s := NewAuthService(users, nil, nil, nil, challengeStore{}, mfaRequired{}, nil, nil, nil, nil, nil, TokenConfig{}, logger.NewLogger(nil), nil)
+			login := func(attempt loginAttempt) (*dto.AuthResponse, error) {
+				ctx := context.WithValue(context.Background(), utils.ClientIPKey, attempt.ip)
+				return s.Login(ctx, &dto.LoginRequest{Email: attempt.email, Password: attempt.password})
+			}
+
+			for _, attempt := range tt.before {
+				login(attempt)
+			}
+			resp, err := login(tt.attempt)
+
+			var tooMany *TooManyAttemptsError
+			if tt.wantLock {
+				if !errors.As(err, &tooMany) || tooMany.RetryAfter <= 0 {
+					t.Fatalf("Login error = %v, want a lockout", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+			if !resp.MFARequired {
+				t.Fatalf("Login = %+v, want the second factor step", resp)
+			}
+			for _, email := range users.lookups {
+				if email != "ada@example.com" {
+					t.Fatalf("looked up %q, want the normalized email", email)
+				}
+			}
+		})
+	}
+}
+
+// fakeRefreshTokens keeps refresh tokens in memory by hash
+type fakeRefreshTokens struct {
+	repository.RefreshTokenRepository
+	byHash map[string]*domain.RefreshToken
+	nextID int64
+}
+
+func (r *fakeRefreshTokens) Create(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	r.nextID++
+	token.ID = r.nextID
+	r.byHash[token.TokenHash] = token
+	return token, nil
+}
+
+func (r *fakeRefreshTokens) GetByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	if token, ok := r.byHash[hash]; ok {
+		copied := *token
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeRefreshTokens) MarkRotated(ctx context.Context, id int64, at time.Time) error {
+	for _, token := range r.byHash {
+		if token.ID == id {
+			token.RotatedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokens) RevokeFamily(ctx context.Context, familyID, reason string) (int64, error) {
+	var revoked int64
+	now := time.Now()
+	for _, token := range r.byHash {
+		if token.FamilyID == familyID && token.RevokedAt == nil && token.RotatedAt == nil {
+			token.RevokedAt, token.RevokeReason = &now, reason
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+type fakeSessions struct {
+	repository.SessionRepository
+	known   map[string]bool // families Touch finds
+	created []string
+	active  []*domain.Session
+}
+
+func (r *fakeSessions) Touch(ctx context.Context, familyID, ip string, at time.Time) (bool, error) {
+	return r.known[familyID], nil
+}
+
+func (r *fakeSessions) Create(ctx context.Context, session *domain.Session) error {
+	r.created = append(r.created, session.FamilyID)
+	return nil
+}
+
+type fakeRoles struct{ userRepo.RoleRepository }
+
+func (fakeRoles) GetAccess(ctx context.Context, userID int) (*userDomain.Access, error) {
+	return &userDomain.Access{Roles: []string{authz.RoleAdmin}, Permissions: []string{authz.UsersRead}}, nil
+}
+
+func TestRefresh(t *testing.T) {
+	keys, err := jwtkeys.NewManager(jwtkeys.Config{Development: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	past := now.Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		// stored is the presented token as stored; nil leaves it unknown
+		stored *domain.RefreshToken
+		// sibling is another live token of the same family
+		sibling     bool
+		userMissing bool
+		wantErr     error
+		// wantSessionCreated: the family had no session record yet
+		wantSessionCreated bool
+	}{
+		{name: "rotates into a new pair", stored: &domain.RefreshToken{FamilyID: "fam", ExpiresAt: now.Add(time.Hour)}},
+		{name: "session recorded on the first refresh", stored: &domain.RefreshToken{FamilyID: "old", ExpiresAt: now.Add(time.Hour)}, wantSessionCreated: true},
+		{name: "unknown token", wantErr: errInvalidRefreshToken},
+		{name: "expired token", stored: &domain.RefreshToken{FamilyID: "fam", ExpiresAt: past}, wantErr: errInvalidRefreshToken},
+		{name: "deleted user", stored: &domain.RefreshToken{FamilyID: "fam", ExpiresAt: now.Add(time.Hour)}, userMissing: true, wantErr: errInvalidRefreshToken},
+		{name: "rotated token is a reuse", stored: &domain.RefreshToken{FamilyID: "fam", ExpiresAt: now.Add(time.Hour), RotatedAt: &past}, sibling: true, wantErr: errRefreshTokenReused},
+		{name: "revoked token is a reuse", stored: &domain.RefreshToken{FamilyID: "fam", ExpiresAt: now.Add(time.Hour), RevokedAt: &past}, sibling: true, wantErr: errRefreshTokenReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &fakeRefreshTokens{byHash: map[string]*domain.RefreshToken{}}
+			if tt.stored != nil {
+				tt.stored.UserID = 3
+				tt.stored.TokenHash = hashToken("presented")
+				tokens.Create(context.Background(), tt.stored)
+			}
+			if tt.sibling {
+				tokens.Create(context.Background(), &domain.RefreshToken{UserID: 3, FamilyID: "fam", TokenHash: hashToken("sibling"), ExpiresAt: now.Add(time.Hour)})
+			}
+			users := &fakeUserRepo{user: &userDomain.User{ID: 3, Email: "ada@example.com"}}
+			if tt.userMissing {
+				users.user = nil
+			}
+			sessions := &fakeSessions{known: map[string]bool{"fam": true}}
+			uow, _ := newFakeUnitOfWork(t)
+			s := NewAuthService(users, fakeRoles{}, tokens, sessions, nil, nil, nil, uow, nil, nil, keys, DefaultTokenConfig(), logger.NewLogger(nil), nil)
+
+			resp, err := s.Refresh(context.Background(), &dto.RefreshRequest{RefreshToken: "presented"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == errRefreshTokenReused {
+				for hash, token := range tokens.byHash {
+					if token.Active(time.Now()) {
+						t.Errorf("token %s of the replayed family is still live", hash[:8])
+					}
+				}
+				return
+			}
+			if tt.wantErr != nil {
+				if len(tokens.byHash) > 1 {
+					t.Fatal("a rejected refresh issued a token")
+				}
+				return
+			}
+
+			if tokens.byHash[hashToken("presented")].RotatedAt == nil {
+				t.Error("presented token was not rotated")
+			}
+			next := tokens.byHash[hashToken(resp.RefreshToken)]
+			if next == nil || next.FamilyID != tt.stored.FamilyID || !next.Active(time.Now()) {
+				t.Fatalf("new refresh token = %+v, want a live token of family %s", next, tt.stored.FamilyID)
+			}
+			claims, err := keys.Verify(resp.Token)
+			if err != nil {
+				t.Fatalf("access token: %v", err)
+			}
+			if claims.UserID != 3 || claims.SessionID != tt.stored.FamilyID || !slices.Equal(claims.Permissions, []string{authz.UsersRead}) {
+				t.Errorf("claims = %+v, want the user's session and permissions", claims)
+			}
+			if created := slices.Contains(sessions.created, tt.stored.FamilyID); created != tt.wantSessionCreated {
+				t.Errorf("session created = %v, want %v", created, tt.wantSessionCreated)
+			}
+		})
+	}
+}
+
+func (r *fakeRefreshTokens) RevokeAllForUser(ctx context.Context, userID int, reason string) (int64, error) {
+	var revoked int64
+	for _, token := range r.byHash {
+		if token.UserID == userID {
+			n, _ := r.RevokeFamily(ctx, token.FamilyID, reason)
+			revoked += n
+		}
+	}
+	return revoked, nil
+}
+
+// disconnectRecorder records whose live connections were closed
+type disconnectRecorder struct{ users []int }
+
+func (d *disconnectRecorder) DisconnectUser(userID int, code int, reason string) {
+	d.users = append(d.users, userID)
+}
+
+func (d *disconnectRecorder) DisconnectSession(userID int, sessionID string, code int, reason string) {
+	d.users = append(d.users, userID)
+}
+
+// revocationCheck is an access token of user 3 and whether it should be rejected
+type revocationCheck struct {
+	tokenID   string
+	sessionID string
+	issuedAt  time.Time
+	want      bool
+}
+
+func TestLogoutRevocation(t *testing.T) {
+	keys, err := jwtkeys.NewManager(jwtkeys.Config{Development: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issued := time.Now().Add(-time.Minute)
+	later := time.Now().Add(2 * time.Second)
+
+	tests := []struct {
+		name           string
+		logout         func(ctx context.Context, s AuthService) error
+		checks         []revocationCheck
+		wantLive       []string // refresh token families still live afterwards
+		wantDisconnect bool
+	}{
+		{
+			name: "logout ends the caller's session",
+			logout: func(ctx context.Context, s AuthService) error {
+				return s.Logout(ctx, &dto.LogoutRequest{RefreshToken: "mine"}, 3)
+			},
+			checks: []revocationCheck{
+				{tokenID: "jti-current", sessionID: "fam", issuedAt: issued, want: true},
+				{tokenID: "jti-sibling", sessionID: "fam", issuedAt: issued, want: true},
+				{tokenID: "jti-other-device", sessionID: "fam2", issuedAt: issued},
+			},
+			wantLive: []string{"fam2", "theirs"},
+		},
+		{
+			name: "another user's refresh token is ignored",
+			logout: func(ctx context.Context, s AuthService) error {
+				return s.Logout(ctx, &dto.LogoutRequest{RefreshToken: "theirs"}, 3)
+			},
+			checks: []revocationCheck{
+				{tokenID: "jti-current", sessionID: "fam", issuedAt: issued, want: true},
+			},
+			wantLive: []string{"fam2", "theirs"},
+		},
+		{
+			name: "logout everywhere",
+			logout: func(ctx context.Context, s AuthService) error {
+				_, err := s.LogoutAll(ctx, 3)
+				return err
+			},
+			checks: []revocationCheck{
+				{tokenID: "jti-current", sessionID: "fam", issuedAt: issued, want: true},
+				{tokenID: "jti-other-device", sessionID: "fam2", issuedAt: issued, want: true},
+				{tokenID: "jti-next-login", sessionID: "fam3", issuedAt: later},
+			},
+			wantLive:       []string{"theirs"},
+			wantDisconnect: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &fakeRefreshTokens{byHash: map[string]*domain.RefreshToken{}}
+			for token, owner := range map[string]struct {
+				user   int
+				family string
+			}{"mine": {3, "fam"}, "other-device": {3, "fam2"}, "theirs": {9, "theirs"}} {
+				tokens.Create(context.Background(), &domain.RefreshToken{UserID: owner.user, FamilyID: owner.family, TokenHash: hashToken(token), ExpiresAt: time.Now().Add(time.Hour)})
+			}
+			db, _ := newFakeDB(t)
+			revocations := revocation.NewList(db, logger.NewLogger(nil))
+			disconnector := &disconnectRecorder{}
+			s := NewAuthService(nil, nil, tokens, nil, nil, nil, nil, nil, revocations, disconnector, keys, DefaultTokenConfig(), logger.NewLogger(nil), nil)
+
+			ctx := context.WithValue(context.Background(), utils.TokenIDKey, "jti-current")
+			ctx = context.WithValue(ctx, utils.SessionIDKey, "fam")
+			ctx = context.WithValue(ctx, utils.TokenExpiresAtKey, time.Now().Add(time.Hour))
+			if err := tt.logout(ctx, s); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, check := range tt.checks {
+				if got := revocations.IsRevoked(3, check.tokenID, check.sessionID, check.issuedAt); got != check.want {
+					t.Errorf("%s (session %s) revoked = %v, want %v", check.tokenID, check.sessionID, got, check.want)
+				}
+			}
+			var live []string
+			for _, token := range tokens.byHash {
+				if token.Active(time.Now()) {
+					live = append(live, token.FamilyID)
+				}
+			}
+			slices.Sort(live)
+			if !slices.Equal(live, tt.wantLive) {
+				t.Errorf("live families = %v, want %v", live, tt.wantLive)
+			}
+			if disconnected := slices.Contains(disconnector.users, 3); disconnected != tt.wantDisconnect {
+				t.Errorf("disconnected = %v, want %v", disconnected, tt.wantDisconnect)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/revocation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/repository"
+)
+
+var errSessionNotFound = errors.New("session not found")
+
+type sessionService struct {
+	repo         repository.SessionRepository
+	tokenRepo    repository.RefreshTokenRepository
+	revocations  *revocation.List
+	disconnector Disconnector
+	config       TokenConfig
+	logger       *logger.ZapLogger
+}
+
+func NewSessionService(repo repository.SessionRepository, tokenRepo repository.RefreshTokenRepository, revocations *revocation.List, disconnector Disconnector, config TokenConfig, logger *logger.ZapLogger) SessionService {
+	return &sessionService{
+		repo:         repo,
+		tokenRepo:    tokenRepo,
+		revocations:  revocations,
+		disconnector: disconnector,
+		config:       config,
+		logger:       logger,
+	}
+}
+
+func (s *sessionService) List(ctx context.Context, userID int, currentSessionID string) ([]dto.SessionResponse, error) {
+	sessions, err := s.repo.ListActive(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = dto.SessionResponse{
+			ID:         session.ID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			Current:    session.FamilyID == currentSessionID,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+	return response, nil
+}
+
+func (s *sessionService) Revoke(ctx context.Context, id int64, userID int) error {
+	session, err := s.repo.GetActive(ctx, id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return errSessionNotFound
+	}
+	return s.revoke(ctx, session)
+}
+
+func (s *sessionService) RevokeOthers(ctx context.Context, userID int, currentSessionID string) (*dto.LogoutAllResponse, error) {
+	sessions, err := s.repo.ListActive(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var revoked int64
+	for _, session := range sessions {
+		if session.FamilyID == currentSessionID {
+			continue
+		}
+		if err := s.revoke(ctx, session); err != nil {
+			return nil, err
+		}
+		revoked++
+	}
+	return &dto.LogoutAllResponse{RevokedSessions: revoked}, nil
+}
+
+// revoke ends a session everywhere: the refresh token family, every access token carrying
+// the session ID and the live connections opened with them
+func (s *sessionService) revoke(ctx context.Context, session *domain.Session) error {
+	if _, err := s.tokenRepo.RevokeFamily(ctx, session.FamilyID, domain.RevokeSession); err != nil {
+		return err
+	}
+	// The entry must outlive every access token issued to the session, including the clock skew leeway
+	until := time.Now().Add(s.config.AccessTTL + time.Minute)
+	if err := s.revocations.RevokeSession(ctx, session.UserID, session.FamilyID, until); err != nil {
+		return err
+	}
+	if s.disconnector != nil {
+		s.disconnector.DisconnectSession(session.UserID, session.FamilyID, closeTokenRevoked, "session revoked")
+	}
+
+	s.logger.Info("session revoked", map[string]interface{}{
+		"user_id":    session.UserID,
+		"session_id": session.ID,
+		"device":     session.DeviceName,
+		"action":     "SESSION_REVOKED",
+	})
+	return nil
+}
+
+// deviceBrowsers and devicePlatforms are matched in order against the User-Agent; the first
+// hit wins, so more specific names come before the ones they contain (Edge mentions Chrome)
+var (
+	deviceBrowsers = []struct{ token, name string }{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Firefox/", "Firefox"}, {"Chrome/", "Chrome"},
+		{"Safari/", "Safari"}, {"okhttp", "Android app"}, {"CFNetwork", "iOS app"},
+		{"curl/", "curl"}, {"PostmanRuntime", "Postman"},
+	}
+	devicePlatforms = []struct{ token, name string }{
+		{"iPhone", "iPhone"}, {"iPad", "iPad"}, {"Android", "Android"}, {"Windows", "Windows"},
+		{"Mac OS X", "macOS"}, {"CrOS", "ChromeOS"}, {"Linux", "Linux"},
+	}
+)
+
+// describeDevice names a session after its User-Agent, e.g. "Chrome on Windows"
+func describeDevice(userAgent string) string {
+	var browser, platform string
+	for _, b := range deviceBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, p := range devicePlatforms {
+		if strings.Contains(userAgent, p.token) {
+			platform = p.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
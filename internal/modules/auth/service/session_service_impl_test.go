@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/revocation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/domain"
+)
+
+func (r *fakeSessions) ListActive(ctx context.Context, userID int, at time.Time) ([]*domain.Session, error) {
+	var sessions []*domain.Session
+	for _, session := range r.active {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *fakeSessions) GetActive(ctx context.Context, id int64, userID int, at time.Time) (*domain.Session, error) {
+	for _, session := range r.active {
+		if session.ID == id && session.UserID == userID {
+			return session, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestSessionRevocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		revoke  func(ctx context.Context, s SessionService) error
+		wantErr error
+		// wantRevoked lists the sessions whose refresh tokens, access tokens and connections are cut
+		wantRevoked []string
+	}{
+		{
+			name:        "revoke one of the user's sessions",
+			revoke:      func(ctx context.Context, s SessionService) error { return s.Revoke(ctx, 2, 3) },
+			wantRevoked: []string{"phone"},
+		},
+		{
+			name:    "another user's session",
+			revoke:  func(ctx context.Context, s SessionService) error { return s.Revoke(ctx, 4, 3) },
+			wantErr: errSessionNotFound,
+		},
+		{
+			name:    "unknown session",
+			revoke:  func(ctx context.Context, s SessionService) error { return s.Revoke(ctx, 99, 3) },
+			wantErr: errSessionNotFound,
+		},
+		{
+			name: "revoke every other session",
+			revoke: func(ctx context.Context, s SessionService) error {
+				resp, err := s.RevokeOthers(ctx, 3, "laptop")
+				if err == nil && resp.RevokedSessions != 2 {
+					t.Errorf("revoked sessions = %d, want 2", resp.RevokedSessions)
+				}
+				return err
+			},
+			wantRevoked: []string{"phone", "tablet"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := &fakeSessions{active: []*domain.Session{
+				{ID: 1, UserID: 3, FamilyID: "laptop"},
+				{ID: 2, UserID: 3, FamilyID: "phone"},
+				{ID: 3, UserID: 3, FamilyID: "tablet"},
+				{ID: 4, UserID: 9, FamilyID: "theirs"},
+			}}
+			tokens := &fakeRefreshTokens{byHash: map[string]*domain.RefreshToken{}}
+			for _, session := range sessions.active {
+				tokens.Create(context.Background(), &domain.RefreshToken{UserID: session.UserID, FamilyID: session.FamilyID, TokenHash: hashToken(session.FamilyID), ExpiresAt: time.Now().Add(time.Hour)})
+			}
+			db, _ := newFakeDB(t)
+			revocations := revocation.NewList(db, logger.NewLogger(nil))
+			disconnector := &disconnectRecorder{}
+			s := NewSessionService(sessions, tokens, revocations, disconnector, DefaultTokenConfig(), logger.NewLogger(nil))
+
+			if err := tt.revoke(context.Background(), s); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, session := range sessions.active {
+				want := slices.Contains(tt.wantRevoked, session.FamilyID)
+				if live := tokens.byHash[hashToken(session.FamilyID)].Active(time.Now()); live == want {
+					t.Errorf("session %s refresh token live = %v, want %v", session.FamilyID, live, !want)
+				}
+				if revoked := revocations.IsRevoked(session.UserID, "jti", session.FamilyID, time.Now()); revoked != want {
+					t.Errorf("session %s access tokens revoked = %v, want %v", session.FamilyID, revoked, want)
+				}
+			}
+			if len(disconnector.users) != len(tt.wantRevoked) {
+				t.Errorf("disconnected %d sessions, want %d", len(disconnector.users), len(tt.wantRevoked))
+			}
+		})
+	}
+}
+
+func TestDescribeDevice(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36 Edg/120.0", "Edge on Windows"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15", "Safari on macOS"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148 Safari/604.1", "Safari on iPhone"},
+		{"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox on Linux"},
+		{"curl/8.4.0", "curl"},
+		{"", "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := describeDevice(tt.userAgent); got != tt.want {
+				t.Fatalf("describeDevice = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
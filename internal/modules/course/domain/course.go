@@ -1,10 +1,14 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type Course struct {
 	ID          int
 	UserID      int
+	WorkspaceID *int
 	Name        string
 	Code        string
 	Instructor  string
@@ -15,10 +19,13 @@ type Course struct {
 	SyllabusURL string
 	FinalGrade  string
 	IsActive    bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Components  []*Component
-	Schedules   []*Schedule
+	// CurrentGrade and GradedWeight are the course's grade stats, see GradeStats
+	CurrentGrade *float64
+	GradedWeight float64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Components   []*Component
+	Schedules    []*Schedule
 }
 
 type Component struct {
@@ -51,3 +58,21 @@ type Schedule struct {
 func (c *Course) IsCompleted() bool {
 	return c.FinalGrade != ""
 }
+
+// GradeStats computes the weighted grade, out of 100 and rounded to two decimals as stored,
+// over the components that are completed and scored, and the weight those components cover.
+// The grade is nil until one is scored.
+func GradeStats(components []*Component) (*float64, float64) {
+	var totalWeight, weightedScore float64
+	for _, comp := range components {
+		if comp.AchievedScore != nil && comp.IsCompleted && comp.MaxScore > 0 {
+			totalWeight += comp.Weight
+			weightedScore += (*comp.AchievedScore / comp.MaxScore) * comp.Weight
+		}
+	}
+	if totalWeight <= 0 {
+		return nil, totalWeight
+	}
+	grade := math.Round(weightedScore/totalWeight*10000) / 100
+	return &grade, totalWeight
+}
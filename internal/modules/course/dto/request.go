@@ -9,6 +9,7 @@ type CreateCourseRequest struct {
 	Type        string  `json:"type,omitempty" validate:"omitempty,max=50"`
 	Color       string  `json:"color,omitempty" validate:"omitempty,max=7"`
 	SyllabusURL string  `json:"syllabus_url,omitempty" validate:"omitempty,url,max=500"`
+	WorkspaceID *int    `json:"workspace_id,omitempty" validate:"omitempty,min=1"`
 }
 
 type UpdateCourseRequest struct {
@@ -22,6 +23,8 @@ type UpdateCourseRequest struct {
 	SyllabusURL *string  `json:"syllabus_url,omitempty" validate:"omitempty,url,max=500"`
 	FinalGrade  *string  `json:"final_grade,omitempty" validate:"omitempty,max=10"`
 	IsActive    *bool    `json:"is_active,omitempty"`
+	// WorkspaceID moves the course to a workspace, or back to personal with 0
+	WorkspaceID *int `json:"workspace_id,omitempty" validate:"omitempty,min=0"`
 }
 
 type CreateComponentRequest struct {
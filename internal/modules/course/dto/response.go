@@ -3,27 +3,39 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/domain"
 )
 
 type CourseResponse struct {
-	ID          int                  `json:"id"`
-	UserID      int                  `json:"user_id"`
-	Name        string               `json:"name"`
-	Code        string               `json:"code,omitempty"`
-	Instructor  string               `json:"instructor,omitempty"`
-	Credits     float64              `json:"credits,omitempty"`
-	Semester    string               `json:"semester,omitempty"`
-	Type        string               `json:"type,omitempty"`
-	Color       string               `json:"color,omitempty"`
-	SyllabusURL string               `json:"syllabus_url,omitempty"`
-	FinalGrade  string               `json:"final_grade,omitempty"`
-	IsActive    bool                 `json:"is_active"`
-	IsCompleted bool                 `json:"is_completed"`
-	CreatedAt   time.Time            `json:"created_at"`
-	UpdatedAt   time.Time            `json:"updated_at"`
-	Components  []*ComponentResponse `json:"components,omitempty"`
-	Schedules   []*ScheduleResponse  `json:"schedules,omitempty"`
+	ID          int     `json:"id"`
+	UserID      int     `json:"user_id"`
+	WorkspaceID *int    `json:"workspace_id,omitempty"`
+	Name        string  `json:"name"`
+	Code        string  `json:"code,omitempty"`
+	Instructor  string  `json:"instructor,omitempty"`
+	Credits     float64 `json:"credits,omitempty"`
+	Semester    string  `json:"semester,omitempty"`
+	Type        string  `json:"type,omitempty"`
+	Color       string  `json:"color,omitempty"`
+	SyllabusURL string  `json:"syllabus_url,omitempty"`
+	FinalGrade  string  `json:"final_grade,omitempty"`
+	IsActive    bool    `json:"is_active"`
+	IsCompleted bool    `json:"is_completed"`
+	// CurrentGrade is the weighted grade over the completed, scored components; GradedWeight
+	// is the weight they cover
+	CurrentGrade *float64             `json:"current_grade,omitempty"`
+	GradedWeight float64              `json:"graded_weight"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+	Components   []*ComponentResponse `json:"components,omitempty"`
+	Schedules    []*ScheduleResponse  `json:"schedules,omitempty"`
+}
+
+// CourseListResponse is one page of GET /courses
+type CourseListResponse struct {
+	Items []*CourseResponse `json:"items"`
+	query.PageInfo
 }
 
 type ComponentResponse struct {
@@ -81,23 +93,26 @@ func ToScheduleResponseList(schedules []*domain.Schedule) []*ScheduleResponse {
 
 func ToCourseResponse(c *domain.Course) *CourseResponse {
 	return &CourseResponse{
-		ID:          c.ID,
-		UserID:      c.UserID,
-		Name:        c.Name,
-		Code:        c.Code,
-		Instructor:  c.Instructor,
-		Credits:     c.Credits,
-		Semester:    c.Semester,
-		Type:        c.Type,
-		Color:       c.Color,
-		SyllabusURL: c.SyllabusURL,
-		FinalGrade:  c.FinalGrade,
-		IsActive:    c.IsActive,
-		IsCompleted: c.IsCompleted(),
-		CreatedAt:   c.CreatedAt,
-		UpdatedAt:   c.UpdatedAt,
-		Components:  ToComponentResponseList(c.Components),
-		Schedules:   ToScheduleResponseList(c.Schedules),
+		ID:           c.ID,
+		UserID:       c.UserID,
+		WorkspaceID:  c.WorkspaceID,
+		Name:         c.Name,
+		Code:         c.Code,
+		Instructor:   c.Instructor,
+		Credits:      c.Credits,
+		Semester:     c.Semester,
+		Type:         c.Type,
+		Color:        c.Color,
+		SyllabusURL:  c.SyllabusURL,
+		FinalGrade:   c.FinalGrade,
+		IsActive:     c.IsActive,
+		IsCompleted:  c.IsCompleted(),
+		CurrentGrade: c.CurrentGrade,
+		GradedWeight: c.GradedWeight,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+		Components:   ToComponentResponseList(c.Components),
+		Schedules:    ToScheduleResponseList(c.Schedules),
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/service"
 	"github.com/gorilla/mux"
 )
@@ -56,6 +57,10 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	userID := h.getUserID(r)
 	course, err := h.service.Create(r.Context(), &req, userID)
 	if err != nil {
+		if err.Error() == "unauthorized" {
+			utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
+			return
+		}
 		utils.ReturnError(w, "INTERNAL_ERROR", "Ders oluşturulamadı", err.Error())
 		return
 	}
@@ -325,9 +330,17 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, course, http.StatusOK, "Ders getirildi")
 }
 
+// GetAll returns one page of the caller's courses
+// GET /api/courses?semester=2026-fall&is_active=true&sort=-current_grade&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+
 	userID := h.getUserID(r)
-	courses, err := h.service.GetAll(r.Context(), userID)
+	courses, err := h.service.GetAll(r.Context(), q, userID)
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Dersler getirilemedi", err.Error())
 		return
@@ -7,38 +7,44 @@ import (
 )
 
 type CourseModel struct {
-	ID          int       `db:"id"`
-	UserID      int       `db:"user_id"`
-	Name        string    `db:"name"`
-	Code        *string   `db:"code"`
-	Instructor  *string   `db:"instructor"`
-	Credits     *float64  `db:"credits"`
-	Semester    *string   `db:"semester"`
-	Type        *string   `db:"type"`
-	Color       *string   `db:"color"`
-	SyllabusURL *string   `db:"syllabus_url"`
-	FinalGrade  *string   `db:"final_grade"`
-	IsActive    bool      `db:"is_active"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID           int       `db:"id"`
+	UserID       int       `db:"user_id"`
+	WorkspaceID  *int      `db:"workspace_id"`
+	Name         string    `db:"name"`
+	Code         *string   `db:"code"`
+	Instructor   *string   `db:"instructor"`
+	Credits      *float64  `db:"credits"`
+	Semester     *string   `db:"semester"`
+	Type         *string   `db:"type"`
+	Color        *string   `db:"color"`
+	SyllabusURL  *string   `db:"syllabus_url"`
+	FinalGrade   *string   `db:"final_grade"`
+	IsActive     bool      `db:"is_active"`
+	CurrentGrade *float64  `db:"current_grade"`
+	GradedWeight float64   `db:"graded_weight"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
 }
 
 func (m *CourseModel) ToDomain() *domain.Course {
 	return &domain.Course{
-		ID:          m.ID,
-		UserID:      m.UserID,
-		Name:        m.Name,
-		Code:        derefString(m.Code),
-		Instructor:  derefString(m.Instructor),
-		Credits:     derefFloat(m.Credits),
-		Semester:    derefString(m.Semester),
-		Type:        derefString(m.Type),
-		Color:       derefString(m.Color),
-		SyllabusURL: derefString(m.SyllabusURL),
-		FinalGrade:  derefString(m.FinalGrade),
-		IsActive:    m.IsActive,
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
+		ID:           m.ID,
+		UserID:       m.UserID,
+		WorkspaceID:  m.WorkspaceID,
+		Name:         m.Name,
+		Code:         derefString(m.Code),
+		Instructor:   derefString(m.Instructor),
+		Credits:      derefFloat(m.Credits),
+		Semester:     derefString(m.Semester),
+		Type:         derefString(m.Type),
+		Color:        derefString(m.Color),
+		SyllabusURL:  derefString(m.SyllabusURL),
+		FinalGrade:   derefString(m.FinalGrade),
+		IsActive:     m.IsActive,
+		CurrentGrade: m.CurrentGrade,
+		GradedWeight: m.GradedWeight,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
 	}
 }
 
@@ -46,6 +52,7 @@ func FromDomain(c *domain.Course) *CourseModel {
 	return &CourseModel{
 		ID:          c.ID,
 		UserID:      c.UserID,
+		WorkspaceID: c.WorkspaceID,
 		Name:        c.Name,
 		Code:        refString(c.Code),
 		Instructor:  refString(c.Instructor),
@@ -6,10 +6,15 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/domain"
 	"github.com/jmoiron/sqlx"
 )
 
+// visibleTo matches the personal courses of user $1 and every course in the workspaces they belong to
+const visibleTo = `(workspace_id IS NULL AND user_id = $1 OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1))`
+
 type postgresRepository struct {
 	db *sqlx.DB
 }
@@ -18,19 +23,25 @@ func NewPostgresRepository(db *sqlx.DB) CourseRepository {
 	return &postgresRepository{db: db}
 }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, course *domain.Course) (*domain.Course, error) {
 	query := `
-		INSERT INTO courses (user_id, name, code, instructor, credits, semester, type, color, syllabus_url, final_grade, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		INSERT INTO courses (user_id, workspace_id, name, code, instructor, credits, semester, type, color, syllabus_url, final_grade, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at, updated_at
 	`
 
 	now := time.Now()
 	model := FromDomain(course)
 
-	err := r.db.QueryRowxContext(
+	err := r.conn(ctx).QueryRowxContext(
 		ctx, query,
 		model.UserID,
+		model.WorkspaceID,
 		model.Name,
 		model.Code,
 		model.Instructor,
@@ -54,13 +65,13 @@ func (r *postgresRepository) Create(ctx context.Context, course *domain.Course)
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Course, error) {
 	query := `
-		SELECT id, user_id, name, code, instructor, credits, semester, type, color, syllabus_url, final_grade, is_active, created_at, updated_at
+		SELECT id, user_id, workspace_id, name, code, instructor, credits, semester, type, color, syllabus_url, final_grade, is_active, current_grade, graded_weight, created_at, updated_at
 		FROM courses
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var model CourseModel
-	err := r.db.GetContext(ctx, &model, query, id)
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -71,18 +82,24 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Cours
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Course, error) {
-	query := `
-		SELECT id, user_id, name, code, instructor, credits, semester, type, color, syllabus_url, final_grade, is_active, created_at, updated_at
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.Course]) ([]*domain.Course, int, error) {
+	where, args := q.Where(`WHERE `+visibleTo+` AND deleted_at IS NULL`, userID)
+
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM courses `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	page, args := q.Paginate(where, args)
+	sqlQuery := `
+		SELECT id, user_id, workspace_id, name, code, instructor, credits, semester, type, color, syllabus_url, final_grade, is_active, current_grade, graded_weight, created_at, updated_at
 		FROM courses
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-	`
+		` + page
 
 	var models []CourseModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	courses := make([]*domain.Course, len(models))
@@ -90,19 +107,19 @@ func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*do
 		courses[i] = m.ToDomain()
 	}
 
-	return courses, nil
+	return courses, total, nil
 }
 
 func (r *postgresRepository) GetActiveCourses(ctx context.Context, userID int) ([]*domain.Course, error) {
 	query := `
-		SELECT id, user_id, name, code, instructor, credits, semester, type, color, syllabus_url, final_grade, is_active, created_at, updated_at
+		SELECT id, user_id, workspace_id, name, code, instructor, credits, semester, type, color, syllabus_url, final_grade, is_active, current_grade, graded_weight, created_at, updated_at
 		FROM courses
-		WHERE user_id = $1 AND is_active = true
+		WHERE ` + visibleTo + ` AND is_active = true AND deleted_at IS NULL
 		ORDER BY name ASC
 	`
 
 	var models []CourseModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -118,12 +135,12 @@ func (r *postgresRepository) GetActiveCourses(ctx context.Context, userID int) (
 func (r *postgresRepository) Update(ctx context.Context, course *domain.Course) error {
 	query := `
 		UPDATE courses
-		SET name = $1, code = $2, instructor = $3, credits = $4, semester = $5, type = $6, color = $7, syllabus_url = $8, final_grade = $9, is_active = $10, updated_at = $11
-		WHERE id = $12
+		SET name = $1, code = $2, instructor = $3, credits = $4, semester = $5, type = $6, color = $7, syllabus_url = $8, final_grade = $9, is_active = $10, workspace_id = $11, updated_at = $12
+		WHERE id = $13
 	`
 
 	model := FromDomain(course)
-	_, err := r.db.ExecContext(
+	_, err := r.conn(ctx).ExecContext(
 		ctx, query,
 		model.Name,
 		model.Code,
@@ -135,6 +152,7 @@ func (r *postgresRepository) Update(ctx context.Context, course *domain.Course)
 		model.SyllabusURL,
 		model.FinalGrade,
 		model.IsActive,
+		model.WorkspaceID,
 		time.Now(),
 		model.ID,
 	)
@@ -142,9 +160,15 @@ func (r *postgresRepository) Update(ctx context.Context, course *domain.Course)
 	return err
 }
 
+func (r *postgresRepository) UpdateGradeStats(ctx context.Context, id int, grade *float64, gradedWeight float64) error {
+	query := `UPDATE courses SET current_grade = $1, graded_weight = $2 WHERE id = $3`
+	_, err := r.conn(ctx).ExecContext(ctx, query, grade, gradedWeight, id)
+	return err
+}
+
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM courses WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	query := `UPDATE courses SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.conn(ctx).ExecContext(ctx, query, time.Now(), id)
 	return err
 }
 
@@ -158,7 +182,7 @@ func (r *postgresRepository) CreateComponent(ctx context.Context, comp *domain.C
 	now := time.Now()
 	model := FromDomainComponent(comp)
 
-	err := r.db.QueryRowxContext(
+	err := r.conn(ctx).QueryRowxContext(
 		ctx, query,
 		model.CourseID,
 		model.Type,
@@ -189,7 +213,7 @@ func (r *postgresRepository) UpdateComponent(ctx context.Context, comp *domain.C
 	`
 
 	model := FromDomainComponent(comp)
-	_, err := r.db.ExecContext(
+	_, err := r.conn(ctx).ExecContext(
 		ctx, query,
 		model.Type,
 		model.Name,
@@ -210,7 +234,7 @@ func (r *postgresRepository) UpdateComponent(ctx context.Context, comp *domain.C
 
 func (r *postgresRepository) DeleteComponent(ctx context.Context, id int) error {
 	query := `DELETE FROM course_components WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := r.conn(ctx).ExecContext(ctx, query, id)
 	return err
 }
 
@@ -223,7 +247,7 @@ func (r *postgresRepository) GetComponents(ctx context.Context, courseID int) ([
 	`
 
 	var models []ComponentModel
-	err := r.db.SelectContext(ctx, &models, query, courseID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, courseID)
 	if err != nil {
 		return nil, err
 	}
@@ -244,7 +268,7 @@ func (r *postgresRepository) GetComponentByID(ctx context.Context, id int) (*dom
 	`
 
 	var model ComponentModel
-	err := r.db.GetContext(ctx, &model, query, id)
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -265,7 +289,7 @@ func (r *postgresRepository) CreateSchedule(ctx context.Context, sched *domain.S
 	now := time.Now()
 	model := FromDomainSchedule(sched)
 
-	err := r.db.QueryRowxContext(
+	err := r.conn(ctx).QueryRowxContext(
 		ctx, query,
 		model.CourseID,
 		model.DayOfWeek,
@@ -290,7 +314,7 @@ func (r *postgresRepository) UpdateSchedule(ctx context.Context, sched *domain.S
 	`
 
 	model := FromDomainSchedule(sched)
-	_, err := r.db.ExecContext(
+	_, err := r.conn(ctx).ExecContext(
 		ctx, query,
 		model.DayOfWeek,
 		model.StartTime,
@@ -304,7 +328,7 @@ func (r *postgresRepository) UpdateSchedule(ctx context.Context, sched *domain.S
 
 func (r *postgresRepository) DeleteSchedule(ctx context.Context, id int) error {
 	query := `DELETE FROM course_schedules WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := r.conn(ctx).ExecContext(ctx, query, id)
 	return err
 }
 
@@ -335,7 +359,7 @@ func (r *postgresRepository) GetSchedules(ctx context.Context, courseID int) ([]
 	`
 
 	var models []ScheduleModel
-	err := r.db.SelectContext(ctx, &models, query, courseID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, courseID)
 	if err != nil {
 		return nil, err
 	}
@@ -363,7 +387,7 @@ func (r *postgresRepository) GetScheduleByID(ctx context.Context, id int) (*doma
 	`
 
 	var model ScheduleModel
-	err := r.db.GetContext(ctx, &model, query, id)
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -3,15 +3,41 @@ package repository
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/domain"
 )
 
+// ListSpec whitelists the filters and sort keys of GET /courses
+var ListSpec = &query.Spec[domain.Course]{
+	Filters: map[string]query.Filter{
+		"is_active":    {Column: "is_active", Op: query.Eq, Kind: query.Bool},
+		"is_completed": {Column: "final_grade", Op: query.Present},
+		"semester":     {Column: "semester", Op: query.Eq},
+		"type":         {Column: "type", Op: query.Eq},
+		"is_shared":    {Column: "workspace_id", Op: query.Present},
+		"workspace_id": {Column: "workspace_id", Op: query.Eq, Kind: query.Int},
+		"q":            {Column: "name", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.Course]{
+		"created_at":    {Column: "created_at", Kind: query.Time, Value: func(c *domain.Course) any { return c.CreatedAt }},
+		"updated_at":    {Column: "updated_at", Kind: query.Time, Value: func(c *domain.Course) any { return c.UpdatedAt }},
+		"name":          {Column: "name", Value: func(c *domain.Course) any { return c.Name }},
+		"current_grade": {Column: "current_grade", Kind: query.Float, Nullable: true, Value: func(c *domain.Course) any { return c.CurrentGrade }},
+	},
+	DefaultSort: "-created_at",
+	ID:          func(c *domain.Course) int { return c.ID },
+}
+
 type CourseRepository interface {
 	Create(ctx context.Context, course *domain.Course) (*domain.Course, error)
 	GetByID(ctx context.Context, id int) (*domain.Course, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.Course, error)
+	// List returns one page of the user's personal courses and those of the workspaces they belong to,
+	// together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.Course]) ([]*domain.Course, int, error)
 	GetActiveCourses(ctx context.Context, userID int) ([]*domain.Course, error)
 	Update(ctx context.Context, course *domain.Course) error
+	// UpdateGradeStats stores the course's grade stats; they are not written by Update
+	UpdateGradeStats(ctx context.Context, id int, grade *float64, gradedWeight float64) error
 	Delete(ctx context.Context, id int) error
 
 	CreateComponent(ctx context.Context, comp *domain.Component) (*domain.Component, error)
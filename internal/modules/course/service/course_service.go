@@ -3,13 +3,18 @@ package service
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/dto"
 )
 
 type CourseService interface {
 	Create(ctx context.Context, req *dto.CreateCourseRequest, userID int) (*dto.CourseResponse, error)
 	GetByID(ctx context.Context, id, userID int) (*dto.CourseResponse, error)
-	GetAll(ctx context.Context, userID int) ([]*dto.CourseResponse, error)
+	GetAll(ctx context.Context, q *query.Query[domain.Course], userID int) (*dto.CourseListResponse, error)
 	GetActive(ctx context.Context, userID int) ([]*dto.CourseResponse, error)
 	Update(ctx context.Context, id int, req *dto.UpdateCourseRequest, userID int) (*dto.CourseResponse, error)
 	Delete(ctx context.Context, id, userID int) error
@@ -25,4 +30,15 @@ type CourseService interface {
 	GetSchedules(ctx context.Context, courseID, userID int) ([]*dto.ScheduleResponse, error)
 	UpdateSchedule(ctx context.Context, id int, req *dto.UpdateScheduleRequest, userID int) (*dto.ScheduleResponse, error)
 	DeleteSchedule(ctx context.Context, id, userID int) error
+
+	// Grade stats, kept up to date by Subscribe
+	HandleComponentGraded(ctx context.Context, userID int, event events.ComponentGraded) error
+	HandleComponentDeleted(ctx context.Context, userID int, event events.ComponentDeleted) error
+}
+
+// Subscribe registers the course module's reactions to component events. Grade stats are
+// recomputed on the job pool and retried, so grading never waits for them.
+func Subscribe(bus *eventbus.Bus, svc CourseService) {
+	eventbus.SubscribeAsync(bus, "course.component_graded", svc.HandleComponentGraded, jobs.DefaultRetryPolicy())
+	eventbus.SubscribeAsync(bus, "course.component_deleted", svc.HandleComponentDeleted, jobs.DefaultRetryPolicy())
 }
@@ -7,12 +7,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace"
 )
 
 // normalizeTime ensures time string is in HH:MM format
@@ -49,16 +53,22 @@ func normalizeTime(timeStr string) string {
 }
 
 type courseService struct {
-	repo        repository.CourseRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.CourseRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
+	access *workspace.Access
 }
 
-func NewCourseService(repo repository.CourseRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) CourseService {
+// NewCourseService creates the course service. Components and schedules share the access
+// of their course, which may belong to a workspace.
+func NewCourseService(repo repository.CourseRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus, access *workspace.Access) CourseService {
 	return &courseService{
-		repo:        repo,
-		logger:      logger,
-		broadcaster: broadcaster,
+		repo:   repo,
+		uow:    uow,
+		logger: logger,
+		bus:    bus,
+		access: access,
 	}
 }
 
@@ -70,9 +80,16 @@ func (s *courseService) Create(ctx context.Context, req *dto.CreateCourseRequest
 		"action":  "CREATE_COURSE",
 	})
 
+	if req.WorkspaceID != nil {
+		if err := s.access.CheckWorkspace(ctx, *req.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return nil, err
+		}
+	}
+
 	now := time.Now()
 	course := &domain.Course{
 		UserID:      userID,
+		WorkspaceID: req.WorkspaceID,
 		Name:        req.Name,
 		Code:        req.Code,
 		Instructor:  req.Instructor,
@@ -86,7 +103,21 @@ func (s *courseService) Create(ctx context.Context, req *dto.CreateCourseRequest
 		UpdatedAt:   now,
 	}
 
-	created, err := s.repo.Create(ctx, course)
+	var (
+		created  *domain.Course
+		response *dto.CourseResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, course); err != nil {
+			return err
+		}
+		response = dto.ToCourseResponse(created)
+		return s.access.Publish(ctx, created.UserID, created.WorkspaceID, events.CourseCreated{
+			CourseID: created.ID,
+			Course:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("failed to create course", err, map[string]interface{}{
 			"user_id": userID,
@@ -102,12 +133,7 @@ func (s *courseService) Create(ctx context.Context, req *dto.CreateCourseRequest
 		"action":    "CREATE_COURSE",
 	})
 
-	response := dto.ToCourseResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventCourseCreated, map[string]interface{}{
-			"course_id": created.ID,
-			"course":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventCourseCreated,
 			"user_id":    userID,
@@ -127,8 +153,8 @@ func (s *courseService) GetByID(ctx context.Context, id, userID int) (*dto.Cours
 	if course == nil {
 		return nil, errors.New("course not found")
 	}
-	if course.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleViewer); err != nil {
+		return nil, err
 	}
 
 	// Load components and schedules
@@ -153,11 +179,12 @@ func (s *courseService) GetByID(ctx context.Context, id, userID int) (*dto.Cours
 	return dto.ToCourseResponse(course), nil
 }
 
-func (s *courseService) GetAll(ctx context.Context, userID int) ([]*dto.CourseResponse, error) {
-	courses, err := s.repo.GetByUserID(ctx, userID)
+func (s *courseService) GetAll(ctx context.Context, q *query.Query[domain.Course], userID int) (*dto.CourseListResponse, error) {
+	courses, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
+	courses, page := q.Result(courses, total)
 
 	// Load components and schedules for all courses
 	for _, course := range courses {
@@ -180,7 +207,7 @@ func (s *courseService) GetAll(ctx context.Context, userID int) ([]*dto.CourseRe
 		course.Schedules = schedules
 	}
 
-	return dto.ToCourseResponseList(courses), nil
+	return &dto.CourseListResponse{Items: dto.ToCourseResponseList(courses), PageInfo: page}, nil
 }
 
 func (s *courseService) GetActive(ctx context.Context, userID int) ([]*dto.CourseResponse, error) {
@@ -220,51 +247,70 @@ func (s *courseService) Update(ctx context.Context, id int, req *dto.UpdateCours
 		"action":    "UPDATE_COURSE",
 	})
 
-	course, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if course == nil {
-		return nil, errors.New("course not found")
-	}
-	if course.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
+	var response *dto.CourseResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		course, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if course == nil {
+			return errors.New("course not found")
+		}
+		if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := dto.ToCourseResponse(course)
 
-	if req.Name != nil {
-		course.Name = *req.Name
-	}
-	if req.Code != nil {
-		course.Code = *req.Code
-	}
-	if req.Instructor != nil {
-		course.Instructor = *req.Instructor
-	}
-	if req.Credits != nil {
-		course.Credits = *req.Credits
-	}
-	if req.Semester != nil {
-		course.Semester = *req.Semester
-	}
-	if req.Type != nil {
-		course.Type = *req.Type
-	}
-	if req.Color != nil {
-		course.Color = *req.Color
-	}
-	if req.SyllabusURL != nil {
-		course.SyllabusURL = *req.SyllabusURL
-	}
-	if req.FinalGrade != nil {
-		course.FinalGrade = *req.FinalGrade
-	}
-	if req.IsActive != nil {
-		course.IsActive = *req.IsActive
-	}
+		if req.WorkspaceID != nil {
+			target, err := s.access.CheckMove(ctx, course.UserID, *req.WorkspaceID, userID)
+			if err != nil {
+				return err
+			}
+			course.WorkspaceID = target
+		}
+		if req.Name != nil {
+			course.Name = *req.Name
+		}
+		if req.Code != nil {
+			course.Code = *req.Code
+		}
+		if req.Instructor != nil {
+			course.Instructor = *req.Instructor
+		}
+		if req.Credits != nil {
+			course.Credits = *req.Credits
+		}
+		if req.Semester != nil {
+			course.Semester = *req.Semester
+		}
+		if req.Type != nil {
+			course.Type = *req.Type
+		}
+		if req.Color != nil {
+			course.Color = *req.Color
+		}
+		if req.SyllabusURL != nil {
+			course.SyllabusURL = *req.SyllabusURL
+		}
+		if req.FinalGrade != nil {
+			course.FinalGrade = *req.FinalGrade
+		}
+		if req.IsActive != nil {
+			course.IsActive = *req.IsActive
+		}
 
-	course.UpdatedAt = time.Now()
+		course.UpdatedAt = time.Now()
 
-	if err := s.repo.Update(ctx, course); err != nil {
+		response = dto.ToCourseResponse(course)
+		if err := s.repo.Update(ctx, course); err != nil {
+			return err
+		}
+		return s.access.Publish(eventbus.WithBefore(ctx, before), course.UserID, course.WorkspaceID, events.CourseUpdated{
+			CourseID: id,
+			Course:   response,
+		})
+	})
+	if err != nil {
 		s.logger.Error("failed to update course", err, map[string]interface{}{
 			"course_id": id,
 			"user_id":   userID,
@@ -279,12 +325,7 @@ func (s *courseService) Update(ctx context.Context, id int, req *dto.UpdateCours
 		"action":    "UPDATE_COURSE",
 	})
 
-	response := dto.ToCourseResponse(course)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventCourseUpdated, map[string]interface{}{
-			"course_id": id,
-			"course":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventCourseUpdated,
 			"user_id":    userID,
@@ -303,18 +344,27 @@ func (s *courseService) Delete(ctx context.Context, id, userID int) error {
 		"action":    "DELETE_COURSE",
 	})
 
-	course, err := s.repo.GetByID(ctx, id)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		course, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if course == nil {
+			return errors.New("course not found")
+		}
+		if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := dto.ToCourseResponse(course)
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.access.Publish(eventbus.WithBefore(ctx, before), course.UserID, course.WorkspaceID, events.CourseDeleted{
+			CourseID: id,
+			Name:     course.Name,
+		})
+	})
 	if err != nil {
-		return err
-	}
-	if course == nil {
-		return errors.New("course not found")
-	}
-	if course.UserID != userID {
-		return errors.New("unauthorized")
-	}
-
-	if err := s.repo.Delete(ctx, id); err != nil {
 		s.logger.Error("failed to delete course", err, map[string]interface{}{
 			"course_id": id,
 			"user_id":   userID,
@@ -329,11 +379,7 @@ func (s *courseService) Delete(ctx context.Context, id, userID int) error {
 		"action":    "DELETE_COURSE",
 	})
 
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventCourseDeleted, map[string]interface{}{
-			"course_id": id,
-			"name":      course.Name,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventCourseDeleted,
 			"user_id":    userID,
@@ -362,8 +408,8 @@ func (s *courseService) CreateComponent(ctx context.Context, req *dto.CreateComp
 	if course == nil {
 		return nil, errors.New("course not found")
 	}
-	if course.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
@@ -401,7 +447,22 @@ func (s *courseService) CreateComponent(ctx context.Context, req *dto.CreateComp
 		UpdatedAt:     now,
 	}
 
-	created, err := s.repo.CreateComponent(ctx, component)
+	var (
+		created  *domain.Component
+		response *dto.ComponentResponse
+	)
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.CreateComponent(ctx, component); err != nil {
+			return err
+		}
+		response = dto.ToComponentResponse(created)
+		return s.access.Publish(ctx, course.UserID, course.WorkspaceID, events.ComponentCreated{
+			ComponentID: created.ID,
+			CourseID:    req.CourseID,
+			Component:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("failed to create component", err, map[string]interface{}{
 			"user_id":   userID,
@@ -418,13 +479,7 @@ func (s *courseService) CreateComponent(ctx context.Context, req *dto.CreateComp
 		"action":       "CREATE_COMPONENT",
 	})
 
-	response := dto.ToComponentResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventComponentCreated, map[string]interface{}{
-			"component_id": created.ID,
-			"course_id":    req.CourseID,
-			"component":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventComponentCreated,
 			"user_id":    userID,
@@ -445,8 +500,8 @@ func (s *courseService) GetComponents(ctx context.Context, courseID, userID int)
 	if course == nil {
 		return nil, errors.New("course not found")
 	}
-	if course.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleViewer); err != nil {
+		return nil, err
 	}
 
 	components, err := s.repo.GetComponents(ctx, courseID)
@@ -464,77 +519,108 @@ func (s *courseService) UpdateComponent(ctx context.Context, id int, req *dto.Up
 		"action":       "UPDATE_COMPONENT",
 	})
 
-	component, err := s.repo.GetComponentByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if component == nil {
-		return nil, errors.New("component not found")
-	}
+	var (
+		response *dto.ComponentResponse
+		graded   bool
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		component, err := s.repo.GetComponentByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if component == nil {
+			return errors.New("component not found")
+		}
 
-	// Verify course belongs to user
-	course, err := s.repo.GetByID(ctx, component.CourseID)
-	if err != nil {
-		return nil, err
-	}
-	if course == nil {
-		return nil, errors.New("course not found")
-	}
-	if course.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
+		// Verify course belongs to user
+		course, err := s.repo.GetByID(ctx, component.CourseID)
+		if err != nil {
+			return err
+		}
+		if course == nil {
+			return errors.New("course not found")
+		}
+		if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := dto.ToComponentResponse(component)
 
-	// Update fields
-	if req.Type != nil {
-		component.Type = *req.Type
-	}
-	if req.Name != nil {
-		component.Name = *req.Name
-	}
-	if req.Weight != nil {
-		component.Weight = *req.Weight
-	}
-	if req.MaxScore != nil {
-		component.MaxScore = *req.MaxScore
-	}
-	if req.AchievedScore != nil {
-		component.AchievedScore = req.AchievedScore
-	}
-	if req.DueDate != nil {
-		if *req.DueDate == "" {
-			component.DueDate = nil
-		} else {
-			parsed, err := time.Parse("2006-01-02", *req.DueDate)
-			if err != nil {
-				return nil, errors.New("invalid due_date format, use YYYY-MM-DD")
+		// Update fields
+		if req.Type != nil {
+			component.Type = *req.Type
+		}
+		if req.Name != nil {
+			component.Name = *req.Name
+		}
+		if req.Weight != nil {
+			component.Weight = *req.Weight
+		}
+		if req.MaxScore != nil {
+			component.MaxScore = *req.MaxScore
+		}
+		if req.AchievedScore != nil {
+			component.AchievedScore = req.AchievedScore
+		}
+		if req.DueDate != nil {
+			if *req.DueDate == "" {
+				component.DueDate = nil
+			} else {
+				parsed, err := time.Parse("2006-01-02", *req.DueDate)
+				if err != nil {
+					return errors.New("invalid due_date format, use YYYY-MM-DD")
+				}
+				component.DueDate = &parsed
 			}
-			component.DueDate = &parsed
 		}
-	}
-	if req.CompletionDate != nil {
-		if *req.CompletionDate == "" {
-			component.CompletionDate = nil
-		} else {
-			parsed, err := time.Parse("2006-01-02", *req.CompletionDate)
-			if err != nil {
-				return nil, errors.New("invalid completion_date format, use YYYY-MM-DD")
+		if req.CompletionDate != nil {
+			if *req.CompletionDate == "" {
+				component.CompletionDate = nil
+			} else {
+				parsed, err := time.Parse("2006-01-02", *req.CompletionDate)
+				if err != nil {
+					return errors.New("invalid completion_date format, use YYYY-MM-DD")
+				}
+				component.CompletionDate = &parsed
 			}
-			component.CompletionDate = &parsed
 		}
-	}
-	if req.IsCompleted != nil {
-		component.IsCompleted = *req.IsCompleted
-	}
-	if req.Notes != nil {
-		component.Notes = *req.Notes
-	}
-	if req.DisplayOrder != nil {
-		component.DisplayOrder = *req.DisplayOrder
-	}
+		if req.IsCompleted != nil {
+			component.IsCompleted = *req.IsCompleted
+		}
+		if req.Notes != nil {
+			component.Notes = *req.Notes
+		}
+		if req.DisplayOrder != nil {
+			component.DisplayOrder = *req.DisplayOrder
+		}
 
-	component.UpdatedAt = time.Now()
+		component.UpdatedAt = time.Now()
 
-	if err := s.repo.UpdateComponent(ctx, component); err != nil {
+		response = dto.ToComponentResponse(component)
+		// The course grade follows a component's score and whatever weighs a scored component
+		graded = req.AchievedScore != nil ||
+			component.AchievedScore != nil && (req.Weight != nil || req.MaxScore != nil || req.IsCompleted != nil)
+		if err := s.repo.UpdateComponent(ctx, component); err != nil {
+			return err
+		}
+
+		// The course module's grade subscriber recomputes the course's grade stats
+		if graded {
+			if err := s.access.Publish(ctx, course.UserID, course.WorkspaceID, events.ComponentGraded{
+				ComponentID: id,
+				CourseID:    component.CourseID,
+				Component:   response,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return s.access.Publish(eventbus.WithBefore(ctx, before), course.UserID, course.WorkspaceID, events.ComponentUpdated{
+			ComponentID: id,
+			CourseID:    component.CourseID,
+			Component:   response,
+		})
+	})
+	if err != nil {
 		s.logger.Error("failed to update component", err, map[string]interface{}{
 			"component_id": id,
 			"user_id":      userID,
@@ -549,47 +635,16 @@ func (s *courseService) UpdateComponent(ctx context.Context, id int, req *dto.Up
 		"action":       "UPDATE_COMPONENT",
 	})
 
-	response := dto.ToComponentResponse(component)
-	
-	// Check if grade was updated - broadcast grade change
-	if req.AchievedScore != nil && s.broadcaster != nil {
-		// Recalculate course grade
-		course, _ := s.repo.GetByID(ctx, component.CourseID)
-		if course != nil {
-			components, _ := s.repo.GetComponents(ctx, component.CourseID)
-			var totalWeight, weightedScore float64
-			for _, comp := range components {
-				if comp.AchievedScore != nil && comp.IsCompleted {
-					totalWeight += comp.Weight
-					weightedScore += (*comp.AchievedScore / comp.MaxScore) * comp.Weight
-				}
-			}
-			var newGrade float64
-			if totalWeight > 0 {
-				newGrade = (weightedScore / totalWeight) * 100
-			}
-			
-			s.broadcaster.Publish(userID, notification.EventComponentGraded, map[string]interface{}{
-				"component_id": id,
-				"course_id":    component.CourseID,
-				"component":     response,
-				"new_grade":    newGrade,
-			})
-			s.logger.Info("WebSocket event published", map[string]interface{}{
-				"event_type": notification.EventComponentGraded,
-				"user_id":    userID,
-				"entity_id":  id,
-				"action":     "WS_EVENT_PUBLISHED",
-			})
-		}
-	}
-	
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventComponentUpdated, map[string]interface{}{
-			"component_id": id,
-			"course_id":   component.CourseID,
-			"component":   response,
+	if graded && s.bus != nil {
+		s.logger.Info("WebSocket event published", map[string]interface{}{
+			"event_type": notification.EventComponentGraded,
+			"user_id":    userID,
+			"entity_id":  id,
+			"action":     "WS_EVENT_PUBLISHED",
 		})
+	}
+
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventComponentUpdated,
 			"user_id":    userID,
@@ -608,27 +663,37 @@ func (s *courseService) DeleteComponent(ctx context.Context, id, userID int) err
 		"action":       "DELETE_COMPONENT",
 	})
 
-	component, err := s.repo.GetComponentByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if component == nil {
-		return errors.New("component not found")
-	}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		component, err := s.repo.GetComponentByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if component == nil {
+			return errors.New("component not found")
+		}
 
-	// Verify course belongs to user
-	course, err := s.repo.GetByID(ctx, component.CourseID)
-	if err != nil {
-		return err
-	}
-	if course == nil {
-		return errors.New("course not found")
-	}
-	if course.UserID != userID {
-		return errors.New("unauthorized")
-	}
+		// Verify course belongs to user
+		course, err := s.repo.GetByID(ctx, component.CourseID)
+		if err != nil {
+			return err
+		}
+		if course == nil {
+			return errors.New("course not found")
+		}
+		if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := dto.ToComponentResponse(component)
 
-	if err := s.repo.DeleteComponent(ctx, id); err != nil {
+		if err := s.repo.DeleteComponent(ctx, id); err != nil {
+			return err
+		}
+		return s.access.Publish(eventbus.WithBefore(ctx, before), course.UserID, course.WorkspaceID, events.ComponentDeleted{
+			ComponentID: id,
+			CourseID:    component.CourseID,
+		})
+	})
+	if err != nil {
 		s.logger.Error("failed to delete component", err, map[string]interface{}{
 			"component_id": id,
 			"user_id":      userID,
@@ -643,6 +708,15 @@ func (s *courseService) DeleteComponent(ctx context.Context, id, userID int) err
 		"action":       "DELETE_COMPONENT",
 	})
 
+	if s.bus != nil {
+		s.logger.Info("WebSocket event published", map[string]interface{}{
+			"event_type": notification.EventComponentDeleted,
+			"user_id":    userID,
+			"entity_id":  id,
+			"action":     "WS_EVENT_PUBLISHED",
+		})
+	}
+
 	return nil
 }
 
@@ -662,8 +736,8 @@ func (s *courseService) CreateSchedule(ctx context.Context, req *dto.CreateSched
 	if course == nil {
 		return nil, errors.New("course not found")
 	}
-	if course.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+		return nil, err
 	}
 
 	// Validate and normalize time format (HH:MM)
@@ -679,7 +753,22 @@ func (s *courseService) CreateSchedule(ctx context.Context, req *dto.CreateSched
 		CreatedAt: time.Now(),
 	}
 
-	created, err := s.repo.CreateSchedule(ctx, schedule)
+	var (
+		created  *domain.Schedule
+		response *dto.ScheduleResponse
+	)
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.CreateSchedule(ctx, schedule); err != nil {
+			return err
+		}
+		response = dto.ToScheduleResponse(created)
+		return s.access.Publish(ctx, course.UserID, course.WorkspaceID, events.ScheduleCreated{
+			ScheduleID: created.ID,
+			CourseID:   req.CourseID,
+			Schedule:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("failed to create schedule", err, map[string]interface{}{
 			"user_id":   userID,
@@ -696,13 +785,7 @@ func (s *courseService) CreateSchedule(ctx context.Context, req *dto.CreateSched
 		"action":      "CREATE_SCHEDULE",
 	})
 
-	response := dto.ToScheduleResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventScheduleCreated, map[string]interface{}{
-			"schedule_id": created.ID,
-			"course_id":   req.CourseID,
-			"schedule":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventScheduleCreated,
 			"user_id":    userID,
@@ -723,8 +806,8 @@ func (s *courseService) GetSchedules(ctx context.Context, courseID, userID int)
 	if course == nil {
 		return nil, errors.New("course not found")
 	}
-	if course.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleViewer); err != nil {
+		return nil, err
 	}
 
 	schedules, err := s.repo.GetSchedules(ctx, courseID)
@@ -742,51 +825,64 @@ func (s *courseService) UpdateSchedule(ctx context.Context, id int, req *dto.Upd
 		"action":      "UPDATE_SCHEDULE",
 	})
 
-	schedule, err := s.repo.GetScheduleByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if schedule == nil {
-		return nil, errors.New("schedule not found")
-	}
+	var response *dto.ScheduleResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		schedule, err := s.repo.GetScheduleByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if schedule == nil {
+			return errors.New("schedule not found")
+		}
 
-	// Verify course belongs to user
-	course, err := s.repo.GetByID(ctx, schedule.CourseID)
-	if err != nil {
-		return nil, err
-	}
-	if course == nil {
-		return nil, errors.New("course not found")
-	}
-	if course.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
+		// Verify course belongs to user
+		course, err := s.repo.GetByID(ctx, schedule.CourseID)
+		if err != nil {
+			return err
+		}
+		if course == nil {
+			return errors.New("course not found")
+		}
+		if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := dto.ToScheduleResponse(schedule)
 
-	// Update fields
-	if req.DayOfWeek != nil {
-		schedule.DayOfWeek = *req.DayOfWeek
-	}
-	if req.StartTime != nil {
-		normalizedStart := normalizeTime(*req.StartTime)
-		s.logger.Info("Normalizing start time", map[string]interface{}{
-			"original":  *req.StartTime,
-			"normalized": normalizedStart,
-		})
-		schedule.StartTime = normalizedStart
-	}
-	if req.EndTime != nil {
-		normalizedEnd := normalizeTime(*req.EndTime)
-		s.logger.Info("Normalizing end time", map[string]interface{}{
-			"original":  *req.EndTime,
-			"normalized": normalizedEnd,
-		})
-		schedule.EndTime = normalizedEnd
-	}
-	if req.Location != nil {
-		schedule.Location = *req.Location
-	}
+		// Update fields
+		if req.DayOfWeek != nil {
+			schedule.DayOfWeek = *req.DayOfWeek
+		}
+		if req.StartTime != nil {
+			normalizedStart := normalizeTime(*req.StartTime)
+			s.logger.Info("Normalizing start time", map[string]interface{}{
+				"original":  *req.StartTime,
+				"normalized": normalizedStart,
+			})
+			schedule.StartTime = normalizedStart
+		}
+		if req.EndTime != nil {
+			normalizedEnd := normalizeTime(*req.EndTime)
+			s.logger.Info("Normalizing end time", map[string]interface{}{
+				"original":  *req.EndTime,
+				"normalized": normalizedEnd,
+			})
+			schedule.EndTime = normalizedEnd
+		}
+		if req.Location != nil {
+			schedule.Location = *req.Location
+		}
 
-	if err := s.repo.UpdateSchedule(ctx, schedule); err != nil {
+		response = dto.ToScheduleResponse(schedule)
+		if err := s.repo.UpdateSchedule(ctx, schedule); err != nil {
+			return err
+		}
+		return s.access.Publish(eventbus.WithBefore(ctx, before), course.UserID, course.WorkspaceID, events.ScheduleUpdated{
+			ScheduleID: id,
+			CourseID:   schedule.CourseID,
+			Schedule:   response,
+		})
+	})
+	if err != nil {
 		s.logger.Error("failed to update schedule", err, map[string]interface{}{
 			"schedule_id": id,
 			"user_id":     userID,
@@ -801,13 +897,7 @@ func (s *courseService) UpdateSchedule(ctx context.Context, id int, req *dto.Upd
 		"action":      "UPDATE_SCHEDULE",
 	})
 
-	response := dto.ToScheduleResponse(schedule)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventScheduleUpdated, map[string]interface{}{
-			"schedule_id": id,
-			"course_id":   schedule.CourseID,
-			"schedule":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventScheduleUpdated,
 			"user_id":    userID,
@@ -826,27 +916,37 @@ func (s *courseService) DeleteSchedule(ctx context.Context, id, userID int) erro
 		"action":      "DELETE_SCHEDULE",
 	})
 
-	schedule, err := s.repo.GetScheduleByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if schedule == nil {
-		return errors.New("schedule not found")
-	}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		schedule, err := s.repo.GetScheduleByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if schedule == nil {
+			return errors.New("schedule not found")
+		}
 
-	// Verify course belongs to user
-	course, err := s.repo.GetByID(ctx, schedule.CourseID)
+		// Verify course belongs to user
+		course, err := s.repo.GetByID(ctx, schedule.CourseID)
+		if err != nil {
+			return err
+		}
+		if course == nil {
+			return errors.New("course not found")
+		}
+		if err := s.access.Check(ctx, course.UserID, course.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := dto.ToScheduleResponse(schedule)
+		if err := s.repo.DeleteSchedule(ctx, id); err != nil {
+			return err
+		}
+		return s.access.Publish(eventbus.WithBefore(ctx, before), course.UserID, course.WorkspaceID, events.ScheduleDeleted{
+			ScheduleID: id,
+			CourseID:   schedule.CourseID,
+			DayOfWeek:  schedule.DayOfWeek,
+		})
+	})
 	if err != nil {
-		return err
-	}
-	if course == nil {
-		return errors.New("course not found")
-	}
-	if course.UserID != userID {
-		return errors.New("unauthorized")
-	}
-
-	if err := s.repo.DeleteSchedule(ctx, id); err != nil {
 		s.logger.Error("failed to delete schedule", err, map[string]interface{}{
 			"schedule_id": id,
 			"user_id":     userID,
@@ -861,12 +961,7 @@ func (s *courseService) DeleteSchedule(ctx context.Context, id, userID int) erro
 		"action":      "DELETE_SCHEDULE",
 	})
 
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventScheduleDeleted, map[string]interface{}{
-			"schedule_id": id,
-			"course_id":   schedule.CourseID,
-			"day_of_week": schedule.DayOfWeek,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventScheduleDeleted,
 			"user_id":    userID,
@@ -877,3 +972,57 @@ func (s *courseService) DeleteSchedule(ctx context.Context, id, userID int) erro
 
 	return nil
 }
+
+// HandleComponentGraded recomputes the grade stats of the course a component was graded in
+func (s *courseService) HandleComponentGraded(ctx context.Context, userID int, event events.ComponentGraded) error {
+	return s.refreshGrade(ctx, event.CourseID)
+}
+
+// HandleComponentDeleted recomputes the grade stats of the course that lost a component
+func (s *courseService) HandleComponentDeleted(ctx context.Context, userID int, event events.ComponentDeleted) error {
+	return s.refreshGrade(ctx, event.CourseID)
+}
+
+// refreshGrade stores the course's grade stats computed from its components and pushes the
+// course to everyone who sees it when they changed. Handlers may see an event twice, and
+// a course that has been trashed since is left alone.
+func (s *courseService) refreshGrade(ctx context.Context, courseID int) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		course, err := s.repo.GetByID(ctx, courseID)
+		if err != nil || course == nil {
+			return err
+		}
+		components, err := s.repo.GetComponents(ctx, courseID)
+		if err != nil {
+			return err
+		}
+		grade, gradedWeight := domain.GradeStats(components)
+		if sameGrade(course.CurrentGrade, grade) && course.GradedWeight == gradedWeight {
+			return nil
+		}
+
+		before := dto.ToCourseResponse(course)
+		course.CurrentGrade, course.GradedWeight = grade, gradedWeight
+		if err := s.repo.UpdateGradeStats(ctx, courseID, grade, gradedWeight); err != nil {
+			return err
+		}
+
+		s.logger.Info("Course grade updated", map[string]interface{}{
+			"user_id":       course.UserID,
+			"course_id":     courseID,
+			"graded_weight": gradedWeight,
+			"action":        "COURSE_GRADE_UPDATED",
+		})
+		return s.access.Publish(eventbus.WithBefore(ctx, before), course.UserID, course.WorkspaceID, events.CourseUpdated{
+			CourseID: courseID,
+			Course:   dto.ToCourseResponse(course),
+		})
+	})
+}
+
+func sameGrade(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
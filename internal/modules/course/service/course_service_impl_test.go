@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/course/repository"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace"
+)
+
+// fakeCourses holds one course and its components
+type fakeCourses struct {
+	repository.CourseRepository
+	course     *domain.Course
+	components []*domain.Component
+	writes     int
+}
+
+func (r *fakeCourses) GetByID(ctx context.Context, id int) (*domain.Course, error) {
+	if r.course == nil || r.course.ID != id {
+		return nil, nil
+	}
+	course := *r.course
+	return &course, nil
+}
+
+func (r *fakeCourses) GetComponents(ctx context.Context, courseID int) ([]*domain.Component, error) {
+	return r.components, nil
+}
+
+func (r *fakeCourses) UpdateGradeStats(ctx context.Context, id int, grade *float64, gradedWeight float64) error {
+	r.course.CurrentGrade, r.course.GradedWeight = grade, gradedWeight
+	r.writes++
+	return nil
+}
+
+func score(v float64) *float64 { return &v }
+
+func TestHandleComponentGraded(t *testing.T) {
+	repo := &fakeCourses{
+		course: &domain.Course{ID: 3, UserID: 1},
+		components: []*domain.Component{
+			{ID: 1, CourseID: 3, Weight: 40, MaxScore: 100, AchievedScore: score(80), IsCompleted: true},
+			{ID: 2, CourseID: 3, Weight: 20, MaxScore: 50, AchievedScore: score(25), IsCompleted: true},
+			// Not completed yet: its score does not count
+			{ID: 3, CourseID: 3, Weight: 40, MaxScore: 100, AchievedScore: score(10)},
+		},
+	}
+	bus := eventbus.New(nil, logger.NewLogger(nil))
+	var updates []events.CourseUpdated
+	eventbus.Subscribe(bus, "test", func(ctx context.Context, userID int, event events.CourseUpdated) error {
+		updates = append(updates, event)
+		return nil
+	})
+	svc := NewCourseService(repo, nil, logger.NewLogger(nil), bus, workspace.NewAccess(nil, bus))
+	graded := events.ComponentGraded{ComponentID: 2, CourseID: 3}
+
+	if err := svc.HandleComponentGraded(context.Background(), 1, graded); err != nil {
+		t.Fatal(err)
+	}
+	// (0.8*40 + 0.5*20) / 60 = 70%
+	if repo.course.CurrentGrade == nil || *repo.course.CurrentGrade != 70 || repo.course.GradedWeight != 60 {
+		t.Fatalf("stats = %v / %v, want 70 / 60", repo.course.CurrentGrade, repo.course.GradedWeight)
+	}
+	if len(updates) != 1 {
+		t.Fatalf("published %d course updates, want 1", len(updates))
+	}
+	if got := updates[0].Course.(*dto.CourseResponse).CurrentGrade; got == nil || *got != 70 {
+		t.Errorf("published grade = %v, want 70", got)
+	}
+
+	// At least once delivery: the same event again changes nothing
+	if err := svc.HandleComponentGraded(context.Background(), 1, graded); err != nil {
+		t.Fatal(err)
+	}
+	if repo.writes != 1 || len(updates) != 1 {
+		t.Errorf("repeated event wrote %d times and published %d updates, want 1 and 1", repo.writes, len(updates))
+	}
+
+	// Removing the last scored component clears the grade
+	repo.components = nil
+	if err := svc.HandleComponentDeleted(context.Background(), 1, events.ComponentDeleted{ComponentID: 2, CourseID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.course.CurrentGrade != nil || repo.course.GradedWeight != 0 || len(updates) != 2 {
+		t.Errorf("after delete: stats = %v / %v with %d updates, want none / 0 with 2", repo.course.CurrentGrade, repo.course.GradedWeight, len(updates))
+	}
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Digest kinds
+const (
+	KindDaily  = "daily"
+	KindWeekly = "weekly"
+)
+
+// Windows covered by each kind, counted from the user's local today
+const (
+	dailyDeadlineDays  = 3
+	weeklyDeadlineDays = 7
+	weeklyBirthdayDays = 7
+	weeklySpendingDays = 7
+)
+
+// Settings controls when a user receives digests. SendTime ("HH:MM") and WeeklyDay
+// (0 = Sunday) are interpreted in the user's timezone.
+type Settings struct {
+	UserID        int
+	DailyEnabled  bool
+	WeeklyEnabled bool
+	SendTime      string
+	WeeklyDay     int
+	EmailEnabled  bool
+	LastDailyOn   *time.Time
+	LastWeeklyOn  *time.Time
+	UpdatedAt     time.Time
+}
+
+// DefaultSettings are used for users who never saved digest settings: both digests off
+func DefaultSettings(userID int) *Settings {
+	return &Settings{UserID: userID, SendTime: "07:00", WeeklyDay: int(time.Monday), EmailEnabled: true}
+}
+
+// DueKinds returns the digests that should go out at localNow: the send time has passed
+// and the digest was not sent yet for that local date. Weekly digests only go out on WeeklyDay.
+func (s *Settings) DueKinds(localNow time.Time) []string {
+	sendAt, err := time.Parse("15:04", s.SendTime)
+	if err != nil {
+		return nil
+	}
+	if localNow.Hour()*60+localNow.Minute() < sendAt.Hour()*60+sendAt.Minute() {
+		return nil
+	}
+
+	today := Date(localNow)
+	var kinds []string
+	if s.DailyEnabled && !sentOn(s.LastDailyOn, today) {
+		kinds = append(kinds, KindDaily)
+	}
+	if s.WeeklyEnabled && int(localNow.Weekday()) == s.WeeklyDay && !sentOn(s.LastWeeklyOn, today) {
+		kinds = append(kinds, KindWeekly)
+	}
+	return kinds
+}
+
+func sentOn(last *time.Time, today time.Time) bool {
+	return last != nil && !Date(*last).Before(today)
+}
+
+// Subscriber is a user together with the profile fields needed to build and send their digest
+type Subscriber struct {
+	Settings
+	Email    string
+	Name     string
+	Language string
+	Timezone string
+}
+
+// Location returns the subscriber's timezone, UTC if it is unknown
+func (s *Subscriber) Location() *time.Location {
+	if loc, err := time.LoadLocation(s.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Date truncates t to its calendar date, keeping the wall clock date rather than the instant
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Digest is one summary of a user's day or week, built for their local date
+type Digest struct {
+	Kind          string
+	Date          time.Time
+	Timezone      string
+	Tasks         TaskSummary
+	HabitsPending []PendingHabit
+	Deadlines     []CourseDeadline
+	Birthdays     []Birthday
+	Spending      Spending
+}
+
+type TaskSummary struct {
+	CompletedToday int
+	DueToday       int
+	DueTomorrow    int
+	Overdue        int
+}
+
+// PendingHabit is an active habit not yet completed or skipped today
+type PendingHabit struct {
+	HabitID       int
+	Name          string
+	CurrentStreak int
+}
+
+type CourseDeadline struct {
+	CourseID      int
+	CourseName    string
+	ComponentID   int
+	ComponentName string
+	ComponentType string
+	DueDate       time.Time
+	DaysRemaining int
+}
+
+// Birthday is a person's birth date; Next, DaysUntil and Age are filled by UpcomingBirthdays
+type Birthday struct {
+	PersonID  int
+	Name      string
+	Birthday  time.Time
+	Next      time.Time
+	DaysUntil int
+	Age       int
+}
+
+// Spending totals transactions between Start and End (inclusive dates)
+type Spending struct {
+	Start   time.Time
+	End     time.Time
+	Income  float64
+	Expense float64
+}
+
+// DeadlineWindow returns how many days ahead course deadlines are listed
+func DeadlineWindow(kind string) int {
+	if kind == KindWeekly {
+		return weeklyDeadlineDays
+	}
+	return dailyDeadlineDays
+}
+
+// BirthdayWindow returns how many days ahead birthdays are listed; 0 means today only
+func BirthdayWindow(kind string) int {
+	if kind == KindWeekly {
+		return weeklyBirthdayDays
+	}
+	return 0
+}
+
+// SpendingPeriod returns the dates whose spending is summarised: yesterday for a daily
+// digest, the seven days before today for a weekly one
+func SpendingPeriod(kind string, today time.Time) (time.Time, time.Time) {
+	end := today.AddDate(0, 0, -1)
+	if kind == KindWeekly {
+		return today.AddDate(0, 0, -weeklySpendingDays), end
+	}
+	return end, end
+}
+
+// UpcomingBirthdays returns the birthdays falling within days of today, soonest first.
+// A 29 February birthday is celebrated on 1 March in other years.
+func UpcomingBirthdays(all []Birthday, today time.Time, days int) []Birthday {
+	upcoming := []Birthday{}
+	for _, b := range all {
+		next := time.Date(today.Year(), b.Birthday.Month(), b.Birthday.Day(), 0, 0, 0, 0, time.UTC)
+		if next.Before(today) {
+			next = time.Date(today.Year()+1, b.Birthday.Month(), b.Birthday.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		daysUntil := int(next.Sub(today).Hours() / 24)
+		if daysUntil > days {
+			continue
+		}
+		b.Next = next
+		b.DaysUntil = daysUntil
+		b.Age = next.Year() - b.Birthday.Year()
+		upcoming = append(upcoming, b)
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].DaysUntil < upcoming[j].DaysUntil })
+	return upcoming
+}
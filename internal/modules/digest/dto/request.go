@@ -0,0 +1,11 @@
+package dto
+
+// UpdateSettingsRequest changes digest settings; omitted fields keep their current value.
+// SendTime is HH:MM and WeeklyDay 0 (Sunday) to 6, both in the user's profile timezone.
+type UpdateSettingsRequest struct {
+	DailyEnabled  *bool   `json:"daily_enabled,omitempty"`
+	WeeklyEnabled *bool   `json:"weekly_enabled,omitempty"`
+	SendTime      *string `json:"send_time,omitempty" validate:"omitempty,datetime=15:04"`
+	WeeklyDay     *int    `json:"weekly_day,omitempty" validate:"omitempty,min=0,max=6"`
+	EmailEnabled  *bool   `json:"email_enabled,omitempty"`
+}
@@ -0,0 +1,128 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+)
+
+const dateLayout = "2006-01-02"
+
+type SettingsResponse struct {
+	DailyEnabled  bool   `json:"daily_enabled"`
+	WeeklyEnabled bool   `json:"weekly_enabled"`
+	SendTime      string `json:"send_time"`
+	WeeklyDay     int    `json:"weekly_day"`
+	EmailEnabled  bool   `json:"email_enabled"`
+	Timezone      string `json:"timezone"`
+	LastDailyOn   string `json:"last_daily_on,omitempty"`
+	LastWeeklyOn  string `json:"last_weekly_on,omitempty"`
+}
+
+type DigestResponse struct {
+	Kind            string                   `json:"kind"`
+	Date            string                   `json:"date"`
+	Timezone        string                   `json:"timezone"`
+	Tasks           TaskSummaryResponse      `json:"tasks"`
+	HabitsPending   []PendingHabitResponse   `json:"habits_pending"`
+	CourseDeadlines []CourseDeadlineResponse `json:"course_deadlines"`
+	Birthdays       []BirthdayResponse       `json:"birthdays"`
+	Spending        SpendingResponse         `json:"spending"`
+}
+
+type TaskSummaryResponse struct {
+	CompletedToday int `json:"completed_today"`
+	DueToday       int `json:"due_today"`
+	DueTomorrow    int `json:"due_tomorrow"`
+	Overdue        int `json:"overdue"`
+}
+
+type PendingHabitResponse struct {
+	HabitID       int    `json:"habit_id"`
+	Name          string `json:"name"`
+	CurrentStreak int    `json:"current_streak"`
+}
+
+type CourseDeadlineResponse struct {
+	CourseID      int       `json:"course_id"`
+	CourseName    string    `json:"course_name"`
+	ComponentID   int       `json:"component_id"`
+	ComponentName string    `json:"component_name"`
+	ComponentType string    `json:"component_type"`
+	DueDate       time.Time `json:"due_date"`
+	DaysRemaining int       `json:"days_remaining"`
+}
+
+type BirthdayResponse struct {
+	PersonID  int    `json:"person_id"`
+	Name      string `json:"name"`
+	Date      string `json:"date"`
+	DaysUntil int    `json:"days_until"`
+	Age       int    `json:"age"`
+}
+
+type SpendingResponse struct {
+	Start   string  `json:"start"`
+	End     string  `json:"end"`
+	Income  float64 `json:"income"`
+	Expense float64 `json:"expense"`
+	Net     float64 `json:"net"`
+}
+
+func ToSettingsResponse(s *domain.Subscriber) *SettingsResponse {
+	if s == nil {
+		return nil
+	}
+	resp := &SettingsResponse{
+		DailyEnabled:  s.DailyEnabled,
+		WeeklyEnabled: s.WeeklyEnabled,
+		SendTime:      s.SendTime,
+		WeeklyDay:     s.WeeklyDay,
+		EmailEnabled:  s.EmailEnabled,
+		Timezone:      s.Location().String(),
+	}
+	if s.LastDailyOn != nil {
+		resp.LastDailyOn = s.LastDailyOn.Format(dateLayout)
+	}
+	if s.LastWeeklyOn != nil {
+		resp.LastWeeklyOn = s.LastWeeklyOn.Format(dateLayout)
+	}
+	return resp
+}
+
+func ToDigestResponse(d *domain.Digest) *DigestResponse {
+	if d == nil {
+		return nil
+	}
+	resp := &DigestResponse{
+		Kind:     d.Kind,
+		Date:     d.Date.Format(dateLayout),
+		Timezone: d.Timezone,
+		Tasks: TaskSummaryResponse{
+			CompletedToday: d.Tasks.CompletedToday,
+			DueToday:       d.Tasks.DueToday,
+			DueTomorrow:    d.Tasks.DueTomorrow,
+			Overdue:        d.Tasks.Overdue,
+		},
+		HabitsPending:   make([]PendingHabitResponse, len(d.HabitsPending)),
+		CourseDeadlines: make([]CourseDeadlineResponse, len(d.Deadlines)),
+		Birthdays:       make([]BirthdayResponse, len(d.Birthdays)),
+		Spending: SpendingResponse{
+			Start:   d.Spending.Start.Format(dateLayout),
+			End:     d.Spending.End.Format(dateLayout),
+			Income:  d.Spending.Income,
+			Expense: d.Spending.Expense,
+			Net:     d.Spending.Income - d.Spending.Expense,
+		},
+	}
+	for i, h := range d.HabitsPending {
+		resp.HabitsPending[i] = PendingHabitResponse{HabitID: h.HabitID, Name: h.Name, CurrentStreak: h.CurrentStreak}
+	}
+	for i, c := range d.Deadlines {
+		resp.CourseDeadlines[i] = CourseDeadlineResponse{CourseID: c.CourseID, CourseName: c.CourseName, ComponentID: c.ComponentID, ComponentName: c.ComponentName, ComponentType: c.ComponentType, DueDate: c.DueDate, DaysRemaining: c.DaysRemaining}
+	}
+	for i, b := range d.Birthdays {
+		resp.Birthdays[i] = BirthdayResponse{PersonID: b.PersonID, Name: b.Name, Date: b.Next.Format(dateLayout), DaysUntil: b.DaysUntil, Age: b.Age}
+	}
+	return resp
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/service"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct{ service service.DigestService }
+
+func NewHandler(service service.DigestService) *Handler { return &Handler{service: service} }
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/digests/settings", h.GetSettings).Methods("GET")
+	router.HandleFunc("/digests/settings", h.UpdateSettings).Methods("PUT", "PATCH")
+	router.HandleFunc("/digests/preview", h.Preview).Methods("GET")
+}
+
+func (h *Handler) getUserID(r *http.Request) int {
+	return utils.GetUserIDFromContext(r.Context())
+}
+
+// GetSettings returns the user's digest settings
+// GET /api/digests/settings
+func (h *Handler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.GetSettings(r.Context(), h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Özet ayarları getirilemedi")
+		return
+	}
+	utils.WriteJson(w, settings, http.StatusOK, "Özet ayarları getirildi")
+}
+
+// UpdateSettings opts in or out of digests and sets when they are sent
+// PUT /api/digests/settings
+func (h *Handler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+	settings, err := h.service.UpdateSettings(r.Context(), &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Özet ayarları güncellenemedi")
+		return
+	}
+	utils.WriteJson(w, settings, http.StatusOK, "Özet ayarları güncellendi")
+}
+
+// Preview builds the digest as it would be sent now, without sending it
+// GET /api/digests/preview?kind=daily|weekly
+func (h *Handler) Preview(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("kind")
+	if kind == "" {
+		kind = domain.KindDaily
+	}
+	digest, err := h.service.Preview(r.Context(), kind, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Özet oluşturulamadı")
+		return
+	}
+	utils.WriteJson(w, digest, http.StatusOK, "Özet oluşturuldu")
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string) {
+	switch err.Error() {
+	case "user not found":
+		utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+	case "invalid digest kind":
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz özet tipi", err.Error())
+	default:
+		utils.ReturnError(w, "INTERNAL_ERROR", message, err.Error())
+	}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+)
+
+type SubscriberModel struct {
+	UserID        int        `db:"user_id"`
+	Email         string     `db:"email"`
+	FullName      *string    `db:"full_name"`
+	Language      string     `db:"language"`
+	Timezone      *string    `db:"timezone"`
+	DailyEnabled  *bool      `db:"daily_enabled"`
+	WeeklyEnabled *bool      `db:"weekly_enabled"`
+	SendTime      *string    `db:"send_time"` // formatted as HH:MM in the query
+	WeeklyDay     *int       `db:"weekly_day"`
+	EmailEnabled  *bool      `db:"email_enabled"`
+	LastDailyOn   *time.Time `db:"last_daily_on"`
+	LastWeeklyOn  *time.Time `db:"last_weekly_on"`
+	UpdatedAt     *time.Time `db:"updated_at"`
+}
+
+// ToDomain fills in defaults for users who never saved digest settings (the columns come from a LEFT JOIN)
+func (m *SubscriberModel) ToDomain() *domain.Subscriber {
+	if m == nil {
+		return nil
+	}
+	s := &domain.Subscriber{Settings: *domain.DefaultSettings(m.UserID), Email: m.Email, Language: m.Language}
+	if m.FullName != nil {
+		s.Name = *m.FullName
+	}
+	if m.Timezone != nil {
+		s.Timezone = *m.Timezone
+	}
+	if m.DailyEnabled != nil {
+		s.DailyEnabled = *m.DailyEnabled
+	}
+	if m.WeeklyEnabled != nil {
+		s.WeeklyEnabled = *m.WeeklyEnabled
+	}
+	if m.SendTime != nil {
+		s.SendTime = *m.SendTime
+	}
+	if m.WeeklyDay != nil {
+		s.WeeklyDay = *m.WeeklyDay
+	}
+	if m.EmailEnabled != nil {
+		s.EmailEnabled = *m.EmailEnabled
+	}
+	s.LastDailyOn = m.LastDailyOn
+	s.LastWeeklyOn = m.LastWeeklyOn
+	if m.UpdatedAt != nil {
+		s.UpdatedAt = *m.UpdatedAt
+	}
+	return s
+}
+
+type PendingHabitModel struct {
+	HabitID       int    `db:"id"`
+	Name          string `db:"name"`
+	CurrentStreak *int   `db:"current_streak"`
+}
+
+func (m *PendingHabitModel) ToDomain() domain.PendingHabit {
+	h := domain.PendingHabit{HabitID: m.HabitID, Name: m.Name}
+	if m.CurrentStreak != nil {
+		h.CurrentStreak = *m.CurrentStreak
+	}
+	return h
+}
+
+type CourseDeadlineModel struct {
+	CourseID      int       `db:"course_id"`
+	CourseName    string    `db:"course_name"`
+	ComponentID   int       `db:"component_id"`
+	ComponentName string    `db:"component_name"`
+	ComponentType string    `db:"component_type"`
+	DueDate       time.Time `db:"due_date"`
+	DaysRemaining *float64  `db:"days_remaining"`
+}
+
+func (m *CourseDeadlineModel) ToDomain() domain.CourseDeadline {
+	d := domain.CourseDeadline{CourseID: m.CourseID, CourseName: m.CourseName, ComponentID: m.ComponentID, ComponentName: m.ComponentName, ComponentType: m.ComponentType, DueDate: m.DueDate}
+	if m.DaysRemaining != nil {
+		d.DaysRemaining = int(*m.DaysRemaining)
+	}
+	return d
+}
+
+type BirthdayModel struct {
+	PersonID int       `db:"id"`
+	Name     string    `db:"name"`
+	Birthday time.Time `db:"birthday"`
+}
+
+func (m *BirthdayModel) ToDomain() domain.Birthday {
+	return domain.Birthday{PersonID: m.PersonID, Name: m.Name, Birthday: m.Birthday}
+}
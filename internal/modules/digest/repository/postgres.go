@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxDeadlines caps the course deadlines listed in one digest
+const maxDeadlines = 20
+
+const subscriberColumns = `u.id AS user_id, u.email, u.full_name, u.language, u.timezone,
+	s.daily_enabled, s.weekly_enabled, to_char(s.send_time, 'HH24:MI') AS send_time, s.weekly_day,
+	s.email_enabled, s.last_daily_on, s.last_weekly_on, s.updated_at`
+
+type postgresRepository struct{ db *sqlx.DB }
+
+func NewPostgresRepository(db *sqlx.DB) DigestRepository { return &postgresRepository{db: db} }
+
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *postgresRepository) GetSubscriber(ctx context.Context, userID int) (*domain.Subscriber, error) {
+	query := `SELECT ` + subscriberColumns + `
+		FROM users u LEFT JOIN digest_settings s ON s.user_id = u.id WHERE u.id = $1`
+	var model SubscriberModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *postgresRepository) GetSubscribers(ctx context.Context) ([]*domain.Subscriber, error) {
+	query := `SELECT ` + subscriberColumns + `
+		FROM digest_settings s JOIN users u ON u.id = s.user_id
+		WHERE s.daily_enabled OR s.weekly_enabled ORDER BY u.id`
+	var models []SubscriberModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query); err != nil {
+		return nil, err
+	}
+	subscribers := make([]*domain.Subscriber, len(models))
+	for i := range models {
+		subscribers[i] = models[i].ToDomain()
+	}
+	return subscribers, nil
+}
+
+func (r *postgresRepository) UpsertSettings(ctx context.Context, s *domain.Settings) error {
+	query := `INSERT INTO digest_settings (user_id, daily_enabled, weekly_enabled, send_time, weekly_day, email_enabled, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET daily_enabled = EXCLUDED.daily_enabled, weekly_enabled = EXCLUDED.weekly_enabled,
+			send_time = EXCLUDED.send_time, weekly_day = EXCLUDED.weekly_day, email_enabled = EXCLUDED.email_enabled,
+			updated_at = EXCLUDED.updated_at`
+	_, err := r.conn(ctx).ExecContext(ctx, query, s.UserID, s.DailyEnabled, s.WeeklyEnabled, s.SendTime, s.WeeklyDay, s.EmailEnabled, time.Now())
+	return err
+}
+
+func (r *postgresRepository) MarkSent(ctx context.Context, userID int, kind string, date time.Time) (bool, error) {
+	column := "last_daily_on"
+	if kind == domain.KindWeekly {
+		column = "last_weekly_on"
+	}
+	query := fmt.Sprintf(`UPDATE digest_settings SET %[1]s = $2
+		WHERE user_id = $1 AND (%[1]s IS NULL OR %[1]s < $2)`, column)
+	result, err := r.conn(ctx).ExecContext(ctx, query, userID, date.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func (r *postgresRepository) GetPendingHabits(ctx context.Context, userID int, date time.Time) ([]domain.PendingHabit, error) {
+	query := `SELECT h.id, h.name, h.current_streak FROM habits h
+		WHERE h.user_id = $1 AND h.is_active = true AND h.deleted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM habit_logs l
+			WHERE l.habit_id = h.id AND l.log_date = $2 AND (l.is_completed = true OR l.skipped = true)
+		)
+		ORDER BY h.name`
+	var models []PendingHabitModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID, date.Format("2006-01-02")); err != nil {
+		return nil, err
+	}
+	habits := make([]domain.PendingHabit, len(models))
+	for i := range models {
+		habits[i] = models[i].ToDomain()
+	}
+	return habits, nil
+}
+
+func (r *postgresRepository) GetCourseDeadlines(ctx context.Context, userID int, until time.Time) ([]domain.CourseDeadline, error) {
+	query := `SELECT course_id, course_name, component_id, component_name, component_type, due_date, days_remaining
+		FROM upcoming_course_deadlines
+		WHERE user_id = $1 AND DATE(due_date) <= $2
+		ORDER BY due_date LIMIT $3`
+	var models []CourseDeadlineModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID, until.Format("2006-01-02"), maxDeadlines); err != nil {
+		return nil, err
+	}
+	deadlines := make([]domain.CourseDeadline, len(models))
+	for i := range models {
+		deadlines[i] = models[i].ToDomain()
+	}
+	return deadlines, nil
+}
+
+func (r *postgresRepository) GetBirthdays(ctx context.Context, userID int) ([]domain.Birthday, error) {
+	query := `SELECT id, name, birthday FROM people WHERE user_id = $1 AND birthday IS NOT NULL AND deleted_at IS NULL`
+	var models []BirthdayModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID); err != nil {
+		return nil, err
+	}
+	birthdays := make([]domain.Birthday, len(models))
+	for i := range models {
+		birthdays[i] = models[i].ToDomain()
+	}
+	return birthdays, nil
+}
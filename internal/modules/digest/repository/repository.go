@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+)
+
+type DigestRepository interface {
+	// GetSubscriber returns the user's profile and digest settings, with defaults if none were saved
+	GetSubscriber(ctx context.Context, userID int) (*domain.Subscriber, error)
+	// GetSubscribers returns every user with the daily or weekly digest enabled
+	GetSubscribers(ctx context.Context) ([]*domain.Subscriber, error)
+	UpsertSettings(ctx context.Context, s *domain.Settings) error
+	// MarkSent records that the digest of the given kind went out for the user's local date.
+	// It returns false if another run already sent it, so each digest is sent once. In a unit of
+	// work the claim is released if the transaction rolls back.
+	MarkSent(ctx context.Context, userID int, kind string, date time.Time) (bool, error)
+
+	GetPendingHabits(ctx context.Context, userID int, date time.Time) ([]domain.PendingHabit, error)
+	GetCourseDeadlines(ctx context.Context, userID int, until time.Time) ([]domain.CourseDeadline, error)
+	GetBirthdays(ctx context.Context, userID int) ([]domain.Birthday, error)
+}
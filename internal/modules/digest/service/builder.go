@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/repository"
+)
+
+// TaskStats counts a user's tasks relative to a calendar day in their timezone (the task repository)
+type TaskStats interface {
+	GetStatsOn(ctx context.Context, userID int, day time.Time, loc *time.Location) (completedToday, dueToday, dueTomorrow, overdue int, err error)
+}
+
+// SpendingSummary totals a user's transactions between two dates (the finance repository)
+type SpendingSummary interface {
+	GetSummary(ctx context.Context, userID int, start, end time.Time) (income float64, expense float64, err error)
+}
+
+// Builder assembles a digest from the other modules' data, using the subscriber's local date
+type Builder struct {
+	repo    repository.DigestRepository
+	tasks   TaskStats
+	finance SpendingSummary
+}
+
+func NewBuilder(repo repository.DigestRepository, tasks TaskStats, finance SpendingSummary) *Builder {
+	return &Builder{repo: repo, tasks: tasks, finance: finance}
+}
+
+// Build returns the subscriber's digest of the given kind as of now
+func (b *Builder) Build(ctx context.Context, sub *domain.Subscriber, kind string, now time.Time) (*domain.Digest, error) {
+	loc := sub.Location()
+	today := domain.Date(now.In(loc))
+	digest := &domain.Digest{Kind: kind, Date: today, Timezone: loc.String()}
+
+	var err error
+	digest.Tasks.CompletedToday, digest.Tasks.DueToday, digest.Tasks.DueTomorrow, digest.Tasks.Overdue, err = b.tasks.GetStatsOn(ctx, sub.UserID, today, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if digest.HabitsPending, err = b.repo.GetPendingHabits(ctx, sub.UserID, today); err != nil {
+		return nil, err
+	}
+
+	until := today.AddDate(0, 0, domain.DeadlineWindow(kind))
+	if digest.Deadlines, err = b.repo.GetCourseDeadlines(ctx, sub.UserID, until); err != nil {
+		return nil, err
+	}
+
+	birthdays, err := b.repo.GetBirthdays(ctx, sub.UserID)
+	if err != nil {
+		return nil, err
+	}
+	digest.Birthdays = domain.UpcomingBirthdays(birthdays, today, domain.BirthdayWindow(kind))
+
+	start, end := domain.SpendingPeriod(kind, today)
+	digest.Spending = domain.Spending{Start: start, End: end}
+	if digest.Spending.Income, digest.Spending.Expense, err = b.finance.GetSummary(ctx, sub.UserID, start, end); err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
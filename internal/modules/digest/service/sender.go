@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/mailer"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/repository"
+)
+
+// digestTemplate is the email template used for both digest kinds
+const digestTemplate = "digest"
+
+// Publisher hands an event to its subscribers (the event bus); the notification broadcaster
+// delivers it to the inbox and live connections
+type Publisher interface {
+	Publish(ctx context.Context, userID int, event events.Event) error
+}
+
+// Sender sends each subscriber's digests once their local send time has passed
+type Sender struct {
+	repo      repository.DigestRepository
+	uow       *database.UnitOfWork
+	builder   *Builder
+	publisher Publisher
+	outbox    *mailer.Outbox
+	renderer  *mailer.Renderer
+	logger    *logger.ZapLogger
+}
+
+func NewSender(repo repository.DigestRepository, uow *database.UnitOfWork, builder *Builder, publisher Publisher, outbox *mailer.Outbox, renderer *mailer.Renderer, logger *logger.ZapLogger) *Sender {
+	return &Sender{repo: repo, uow: uow, builder: builder, publisher: publisher, outbox: outbox, renderer: renderer, logger: logger}
+}
+
+// SendDue sends every digest that is due. Each digest is claimed, built, published and
+// queued for email in one transaction: concurrent runs never send it twice, and a digest
+// that fails is rolled back and tried again by the next run. A failure only skips that
+// subscriber. Returns the number of digests sent.
+func (s *Sender) SendDue(ctx context.Context) (int, error) {
+	subscribers, err := s.repo.GetSubscribers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, sub := range subscribers {
+		localNow := now.In(sub.Location())
+		for _, kind := range sub.DueKinds(localNow) {
+			claimed := false
+			err := s.uow.Do(ctx, func(ctx context.Context) error {
+				var err error
+				if claimed, err = s.repo.MarkSent(ctx, sub.UserID, kind, domain.Date(localNow)); err != nil || !claimed {
+					return err
+				}
+				return s.send(ctx, sub, kind, now)
+			})
+			if err != nil {
+				s.logger.Error("Failed to send digest", err, map[string]interface{}{
+					"user_id": sub.UserID,
+					"kind":    kind,
+					"action":  "DIGEST_SEND_FAILED",
+				})
+				continue
+			}
+			if claimed {
+				sent++
+			}
+		}
+	}
+	return sent, nil
+}
+
+func (s *Sender) send(ctx context.Context, sub *domain.Subscriber, kind string, now time.Time) error {
+	digest, err := s.builder.Build(ctx, sub, kind, now)
+	if err != nil {
+		return err
+	}
+	resp := dto.ToDigestResponse(digest)
+
+	var event events.Event = events.DigestDaily{Date: resp.Date, Digest: resp}
+	if kind == domain.KindWeekly {
+		event = events.DigestWeekly{Date: resp.Date, Digest: resp}
+	}
+	if err := s.publisher.Publish(ctx, sub.UserID, event); err != nil {
+		return err
+	}
+
+	if sub.EmailEnabled && s.outbox != nil && sub.Email != "" {
+		email, err := s.renderer.Render(digestTemplate, sub.Language, newDigestEmail(sub, digest))
+		if err != nil {
+			return err
+		}
+		email.To = sub.Email
+		if _, err := s.outbox.Enqueue(ctx, sub.UserID, email); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("Digest sent", map[string]interface{}{
+		"user_id": sub.UserID,
+		"kind":    kind,
+		"date":    resp.Date,
+		"email":   sub.EmailEnabled,
+		"action":  "DIGEST_SENT",
+	})
+	return nil
+}
+
+// digestEmail is the data passed to the digest template; dates and amounts are preformatted
+// for the recipient's language
+type digestEmail struct {
+	Name      string
+	Weekly    bool
+	Date      string
+	Tasks     domain.TaskSummary
+	Habits    []domain.PendingHabit
+	Deadlines []digestEmailDeadline
+	Birthdays []digestEmailBirthday
+	Income    string
+	Expense   string
+	Period    string
+}
+
+type digestEmailDeadline struct {
+	Course    string
+	Component string
+	Due       string
+}
+
+type digestEmailBirthday struct {
+	Name      string
+	Date      string
+	DaysUntil int
+	Age       int
+}
+
+func newDigestEmail(sub *domain.Subscriber, d *domain.Digest) digestEmail {
+	dateLayout, dateTimeLayout := "02.01.2006", "02.01.2006 15:04"
+	if sub.Language == mailer.LanguageEnglish {
+		dateLayout, dateTimeLayout = "Jan 2, 2006", "Jan 2, 2006 15:04"
+	}
+
+	data := digestEmail{
+		Name:    sub.Name,
+		Weekly:  d.Kind == domain.KindWeekly,
+		Date:    d.Date.Format(dateLayout),
+		Tasks:   d.Tasks,
+		Habits:  d.HabitsPending,
+		Income:  fmt.Sprintf("%.2f", d.Spending.Income),
+		Expense: fmt.Sprintf("%.2f", d.Spending.Expense),
+		Period:  d.Spending.Start.Format(dateLayout),
+	}
+	if !d.Spending.End.Equal(d.Spending.Start) {
+		data.Period += " - " + d.Spending.End.Format(dateLayout)
+	}
+	for _, c := range d.Deadlines {
+		data.Deadlines = append(data.Deadlines, digestEmailDeadline{Course: c.CourseName, Component: c.ComponentName, Due: c.DueDate.Format(dateTimeLayout)})
+	}
+	for _, b := range d.Birthdays {
+		data.Birthdays = append(data.Birthdays, digestEmailBirthday{Name: b.Name, Date: b.Next.Format(dateLayout), DaysUntil: b.DaysUntil, Age: b.Age})
+	}
+	return data
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/repository"
+)
+
+// fakeDigests returns the subscribers and fails the claim of brokenClaim
+type fakeDigests struct {
+	repository.DigestRepository
+	subscribers []*domain.Subscriber
+	brokenClaim int
+}
+
+func (r *fakeDigests) GetSubscribers(ctx context.Context) ([]*domain.Subscriber, error) {
+	return r.subscribers, nil
+}
+
+func (r *fakeDigests) MarkSent(ctx context.Context, userID int, kind string, date time.Time) (bool, error) {
+	if userID == r.brokenClaim {
+		return false, errors.New("connection reset")
+	}
+	return true, nil
+}
+
+func (r *fakeDigests) GetPendingHabits(ctx context.Context, userID int, date time.Time) ([]domain.PendingHabit, error) {
+	return nil, nil
+}
+
+func (r *fakeDigests) GetCourseDeadlines(ctx context.Context, userID int, until time.Time) ([]domain.CourseDeadline, error) {
+	return nil, nil
+}
+
+func (r *fakeDigests) GetBirthdays(ctx context.Context, userID int) ([]domain.Birthday, error) {
+	return nil, nil
+}
+
+// fakeTaskStats fails for brokenStats and remembers the timezone each user was counted in
+type fakeTaskStats struct {
+	brokenStats int
+	zones       map[int]string
+}
+
+func (f *fakeTaskStats) GetStatsOn(ctx context.Context, userID int, day time.Time, loc *time.Location) (int, int, int, int, error) {
+	if userID == f.brokenStats {
+		return 0, 0, 0, 0, errors.New("query failed")
+	}
+	f.zones[userID] = loc.String()
+	return 0, 1, 0, 0, nil
+}
+
+type fakeSpending struct{}
+
+func (fakeSpending) GetSummary(ctx context.Context, userID int, start, end time.Time) (float64, float64, error) {
+	return 0, 0, nil
+}
+
+type fakePublisher struct{ users []int }
+
+func (p *fakePublisher) Publish(ctx context.Context, userID int, event events.Event) error {
+	p.users = append(p.users, userID)
+	return nil
+}
+
+func TestSendDueSkipsOnlyFailingSubscribers(t *testing.T) {
+	subscriber := func(userID int, timezone string) *domain.Subscriber {
+		return &domain.Subscriber{
+			Settings: domain.Settings{UserID: userID, DailyEnabled: true, SendTime: "00:00"},
+			Timezone: timezone,
+		}
+	}
+	repo := &fakeDigests{
+		subscribers: []*domain.Subscriber{subscriber(1, "UTC"), subscriber(2, "UTC"), subscriber(3, "Europe/Istanbul")},
+		brokenClaim: 1,
+	}
+	tasks := &fakeTaskStats{brokenStats: 2, zones: map[int]string{}}
+	publisher := &fakePublisher{}
+	sender := NewSender(repo, nil, NewBuilder(repo, tasks, fakeSpending{}), publisher, nil, nil, logger.NewLogger(nil))
+
+	sent, err := sender.SendDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || len(publisher.users) != 1 || publisher.users[0] != 3 {
+		t.Fatalf("sent %d digests to %v, want 1 to user 3", sent, publisher.users)
+	}
+	if want := repo.subscribers[2].Location().String(); tasks.zones[3] != want {
+		t.Errorf("tasks were counted in %q, want %q", tasks.zones[3], want)
+	}
+}
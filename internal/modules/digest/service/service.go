@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/dto"
+)
+
+type DigestService interface {
+	GetSettings(ctx context.Context, userID int) (*dto.SettingsResponse, error)
+	UpdateSettings(ctx context.Context, req *dto.UpdateSettingsRequest, userID int) (*dto.SettingsResponse, error)
+	// Preview builds the user's digest of the given kind for now without sending it
+	Preview(ctx context.Context, kind string, userID int) (*dto.DigestResponse, error)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/repository"
+)
+
+type digestService struct {
+	repo    repository.DigestRepository
+	builder *Builder
+	logger  *logger.ZapLogger
+}
+
+func NewDigestService(repo repository.DigestRepository, builder *Builder, logger *logger.ZapLogger) DigestService {
+	return &digestService{repo: repo, builder: builder, logger: logger}
+}
+
+func (s *digestService) GetSettings(ctx context.Context, userID int) (*dto.SettingsResponse, error) {
+	sub, err := s.getSubscriber(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToSettingsResponse(sub), nil
+}
+
+func (s *digestService) UpdateSettings(ctx context.Context, req *dto.UpdateSettingsRequest, userID int) (*dto.SettingsResponse, error) {
+	sub, err := s.getSubscriber(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DailyEnabled != nil {
+		sub.DailyEnabled = *req.DailyEnabled
+	}
+	if req.WeeklyEnabled != nil {
+		sub.WeeklyEnabled = *req.WeeklyEnabled
+	}
+	if req.SendTime != nil {
+		sub.SendTime = *req.SendTime
+	}
+	if req.WeeklyDay != nil {
+		sub.WeeklyDay = *req.WeeklyDay
+	}
+	if req.EmailEnabled != nil {
+		sub.EmailEnabled = *req.EmailEnabled
+	}
+
+	if err := s.repo.UpsertSettings(ctx, &sub.Settings); err != nil {
+		s.logger.Error("Failed to update digest settings", err, map[string]interface{}{"user_id": userID, "action": "UPDATE_DIGEST_SETTINGS_FAILED"})
+		return nil, err
+	}
+
+	s.logger.Info("Digest settings updated", map[string]interface{}{
+		"user_id":   userID,
+		"daily":     sub.DailyEnabled,
+		"weekly":    sub.WeeklyEnabled,
+		"send_time": sub.SendTime,
+		"action":    "UPDATE_DIGEST_SETTINGS_SUCCESS",
+	})
+	return s.GetSettings(ctx, userID)
+}
+
+func (s *digestService) Preview(ctx context.Context, kind string, userID int) (*dto.DigestResponse, error) {
+	if kind != domain.KindDaily && kind != domain.KindWeekly {
+		return nil, errors.New("invalid digest kind")
+	}
+	sub, err := s.getSubscriber(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := s.builder.Build(ctx, sub, kind, time.Now())
+	if err != nil {
+		s.logger.Error("Failed to build digest preview", err, map[string]interface{}{"user_id": userID, "kind": kind, "action": "DIGEST_PREVIEW_FAILED"})
+		return nil, err
+	}
+	return dto.ToDigestResponse(digest), nil
+}
+
+func (s *digestService) getSubscriber(ctx context.Context, userID int) (*domain.Subscriber, error) {
+	sub, err := s.repo.GetSubscriber(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, errors.New("user not found")
+	}
+	return sub, nil
+}
@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/domain"
 )
 
@@ -23,6 +24,12 @@ type EventResponse struct {
 	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
+// EventListResponse is one page of GET /events
+type EventListResponse struct {
+	Items []*EventResponse `json:"items"`
+	query.PageInfo
+}
+
 func ToEventResponse(e *domain.Event) *EventResponse {
 	if e == nil {
 		return nil
@@ -9,6 +9,7 @@ import (
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/service"
 	"github.com/gorilla/mux"
 )
@@ -58,8 +59,15 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, event, http.StatusOK, "Etkinlik getirildi")
 }
 
+// GetAll returns one page of the caller's events
+// GET /api/events?starts_after=2026-01-01&is_all_day=false&sort=start_time&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	events, err := h.service.GetAll(r.Context(), h.getUserID(r))
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+	events, err := h.service.GetAll(r.Context(), q, h.getUserID(r))
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Etkinlikler getirilemedi", err.Error())
 		return
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/domain"
 	"github.com/jmoiron/sqlx"
 )
@@ -14,12 +16,17 @@ type postgresRepository struct{ db *sqlx.DB }
 
 func NewPostgresRepository(db *sqlx.DB) EventRepository { return &postgresRepository{db: db} }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, event *domain.Event) (*domain.Event, error) {
 	query := `INSERT INTO events (user_id, life_area_id, title, description, start_time, end_time, location, is_all_day, is_recurring, recurrence, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at`
 	now := time.Now()
 	model := FromDomain(event)
-	err := r.db.QueryRowxContext(ctx, query, model.UserID, model.LifeAreaID, model.Title, model.Description, model.StartTime, model.EndTime, model.Location, model.IsAllDay, model.IsRecurring, model.Recurrence, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
+	err := r.conn(ctx).QueryRowxContext(ctx, query, model.UserID, model.LifeAreaID, model.Title, model.Description, model.StartTime, model.EndTime, model.Location, model.IsAllDay, model.IsRecurring, model.Recurrence, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -27,9 +34,9 @@ func (r *postgresRepository) Create(ctx context.Context, event *domain.Event) (*
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Event, error) {
-	query := `SELECT id, user_id, life_area_id, title, description, start_time, end_time, location, is_all_day, is_recurring, recurrence, created_at, updated_at FROM events WHERE id = $1`
+	query := `SELECT id, user_id, life_area_id, title, description, start_time, end_time, location, is_all_day, is_recurring, recurrence, created_at, updated_at FROM events WHERE id = $1 AND deleted_at IS NULL`
 	var model EventModel
-	if err := r.db.GetContext(ctx, &model, query, id); err != nil {
+	if err := r.conn(ctx).GetContext(ctx, &model, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -38,23 +45,29 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Event
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Event, error) {
-	query := `SELECT id, user_id, life_area_id, title, description, start_time, end_time, location, is_all_day, is_recurring, recurrence, created_at, updated_at FROM events WHERE user_id = $1 ORDER BY start_time`
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.Event]) ([]*domain.Event, int, error) {
+	where, args := q.Where(`WHERE user_id = $1 AND deleted_at IS NULL`, userID)
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM events `+where, args...); err != nil {
+		return nil, 0, err
+	}
+	page, args := q.Paginate(where, args)
+	sqlQuery := `SELECT id, user_id, life_area_id, title, description, start_time, end_time, location, is_all_day, is_recurring, recurrence, created_at, updated_at FROM events ` + page
 	var models []EventModel
-	if err := r.db.SelectContext(ctx, &models, query, userID); err != nil {
-		return nil, err
+	if err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...); err != nil {
+		return nil, 0, err
 	}
 	events := make([]*domain.Event, len(models))
 	for i, m := range models {
 		events[i] = m.ToDomain()
 	}
-	return events, nil
+	return events, total, nil
 }
 
 func (r *postgresRepository) GetByDateRange(ctx context.Context, userID int, start, end time.Time) ([]*domain.Event, error) {
-	query := `SELECT id, user_id, life_area_id, title, description, start_time, end_time, location, is_all_day, is_recurring, recurrence, created_at, updated_at FROM events WHERE user_id = $1 AND start_time BETWEEN $2 AND $3 ORDER BY start_time`
+	query := `SELECT id, user_id, life_area_id, title, description, start_time, end_time, location, is_all_day, is_recurring, recurrence, created_at, updated_at FROM events WHERE user_id = $1 AND deleted_at IS NULL AND start_time BETWEEN $2 AND $3 ORDER BY start_time`
 	var models []EventModel
-	if err := r.db.SelectContext(ctx, &models, query, userID, start, end); err != nil {
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID, start, end); err != nil {
 		return nil, err
 	}
 	events := make([]*domain.Event, len(models))
@@ -67,11 +80,11 @@ func (r *postgresRepository) GetByDateRange(ctx context.Context, userID int, sta
 func (r *postgresRepository) Update(ctx context.Context, event *domain.Event) error {
 	query := `UPDATE events SET title = $1, description = $2, start_time = $3, end_time = $4, location = $5, is_all_day = $6, is_recurring = $7, recurrence = $8, life_area_id = $9, updated_at = $10 WHERE id = $11`
 	model := FromDomain(event)
-	_, err := r.db.ExecContext(ctx, query, model.Title, model.Description, model.StartTime, model.EndTime, model.Location, model.IsAllDay, model.IsRecurring, model.Recurrence, model.LifeAreaID, time.Now(), model.ID)
+	_, err := r.conn(ctx).ExecContext(ctx, query, model.Title, model.Description, model.StartTime, model.EndTime, model.Location, model.IsAllDay, model.IsRecurring, model.Recurrence, model.LifeAreaID, time.Now(), model.ID)
 	return err
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM events WHERE id = $1`, id)
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE events SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now(), id)
 	return err
 }
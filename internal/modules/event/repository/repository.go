@@ -4,13 +4,35 @@ import (
 	"context"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/domain"
 )
 
+// ListSpec whitelists the filters and sort keys of GET /events
+var ListSpec = &query.Spec[domain.Event]{
+	Filters: map[string]query.Filter{
+		"life_area_id":  {Column: "life_area_id", Op: query.Eq, Kind: query.Int},
+		"is_all_day":    {Column: "is_all_day", Op: query.Eq, Kind: query.Bool},
+		"is_recurring":  {Column: "is_recurring", Op: query.Eq, Kind: query.Bool},
+		"starts_after":  {Column: "start_time", Op: query.Gte, Kind: query.Time},
+		"starts_before": {Column: "start_time", Op: query.Lt, Kind: query.Time},
+		"q":             {Column: "title", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.Event]{
+		"start_time": {Column: "start_time", Kind: query.Time, Value: func(e *domain.Event) any { return e.StartTime }},
+		"end_time":   {Column: "end_time", Kind: query.Time, Nullable: true, Value: func(e *domain.Event) any { return e.EndTime }},
+		"created_at": {Column: "created_at", Kind: query.Time, Value: func(e *domain.Event) any { return e.CreatedAt }},
+		"title":      {Column: "title", Value: func(e *domain.Event) any { return e.Title }},
+	},
+	DefaultSort: "start_time",
+	ID:          func(e *domain.Event) int { return e.ID },
+}
+
 type EventRepository interface {
 	Create(ctx context.Context, event *domain.Event) (*domain.Event, error)
 	GetByID(ctx context.Context, id int) (*domain.Event, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.Event, error)
+	// List returns one page of the user's events together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.Event]) ([]*domain.Event, int, error)
 	GetByDateRange(ctx context.Context, userID int, start, end time.Time) ([]*domain.Event, error)
 	Update(ctx context.Context, event *domain.Event) error
 	Delete(ctx context.Context, id int) error
@@ -5,40 +5,53 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/event/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 )
 
 type eventService struct {
-	repo        repository.EventRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.EventRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
 }
 
-func NewEventService(repo repository.EventRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) EventService {
-	return &eventService{repo: repo, logger: logger, broadcaster: broadcaster}
+func NewEventService(repo repository.EventRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) EventService {
+	return &eventService{repo: repo, uow: uow, logger: logger, bus: bus}
 }
 
 func (s *eventService) Create(ctx context.Context, req *dto.CreateEventRequest, userID int) (*dto.EventResponse, error) {
 	s.logger.Info("Creating event", map[string]interface{}{"user_id": userID, "title": req.Title, "action": "CREATE_EVENT"})
 	now := time.Now()
 	event := &domain.Event{UserID: userID, LifeAreaID: req.LifeAreaID, Title: req.Title, Description: req.Description, StartTime: req.StartTime, EndTime: req.EndTime, Location: req.Location, IsAllDay: req.IsAllDay, IsRecurring: req.IsRecurring, Recurrence: req.Recurrence, CreatedAt: now, UpdatedAt: now}
-	created, err := s.repo.Create(ctx, event)
+	var (
+		created  *domain.Event
+		response *dto.EventResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, event); err != nil {
+			return err
+		}
+		response = dto.ToEventResponse(created)
+		return s.publish(ctx, userID, events.EventCreated{
+			EventID: created.ID,
+			Event:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create event", err, map[string]interface{}{"user_id": userID, "action": "CREATE_EVENT_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Event created", map[string]interface{}{"user_id": userID, "event_id": created.ID, "action": "CREATE_EVENT_SUCCESS"})
-	response := dto.ToEventResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventEventCreated, map[string]interface{}{
-			"event_id": created.ID,
-			"event":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventEventCreated,
 			"user_id":    userID,
@@ -63,12 +76,13 @@ func (s *eventService) GetByID(ctx context.Context, id, userID int) (*dto.EventR
 	return dto.ToEventResponse(event), nil
 }
 
-func (s *eventService) GetAll(ctx context.Context, userID int) ([]*dto.EventResponse, error) {
-	events, err := s.repo.GetByUserID(ctx, userID)
+func (s *eventService) GetAll(ctx context.Context, q *query.Query[domain.Event], userID int) (*dto.EventListResponse, error) {
+	events, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
-	return dto.ToEventResponseList(events), nil
+	events, page := q.Result(events, total)
+	return &dto.EventListResponse{Items: dto.ToEventResponseList(events), PageInfo: page}, nil
 }
 
 func (s *eventService) GetByDateRange(ctx context.Context, userID int, start, end time.Time) ([]*dto.EventResponse, error) {
@@ -81,49 +95,56 @@ func (s *eventService) GetByDateRange(ctx context.Context, userID int, start, en
 
 func (s *eventService) Update(ctx context.Context, id int, req *dto.UpdateEventRequest, userID int) (*dto.EventResponse, error) {
 	s.logger.Info("Updating event", map[string]interface{}{"user_id": userID, "event_id": id, "action": "UPDATE_EVENT"})
-	event, err := s.repo.GetByID(ctx, id)
+	var response *dto.EventResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		event, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return errors.New("event not found")
+		}
+		if event.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToEventResponse(event)
+		if req.Title != nil {
+			event.Title = *req.Title
+		}
+		if req.Description != nil {
+			event.Description = *req.Description
+		}
+		if req.StartTime != nil {
+			event.StartTime = *req.StartTime
+		}
+		if req.EndTime != nil {
+			event.EndTime = req.EndTime
+		}
+		if req.Location != nil {
+			event.Location = *req.Location
+		}
+		if req.IsAllDay != nil {
+			event.IsAllDay = *req.IsAllDay
+		}
+		if req.LifeAreaID != nil {
+			event.LifeAreaID = req.LifeAreaID
+		}
+		event.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, event); err != nil {
+			return err
+		}
+		response = dto.ToEventResponse(event)
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.EventUpdated{
+			EventID: id,
+			Event:   response,
+		})
+	})
 	if err != nil {
-		return nil, err
-	}
-	if event == nil {
-		return nil, errors.New("event not found")
-	}
-	if event.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
-	if req.Title != nil {
-		event.Title = *req.Title
-	}
-	if req.Description != nil {
-		event.Description = *req.Description
-	}
-	if req.StartTime != nil {
-		event.StartTime = *req.StartTime
-	}
-	if req.EndTime != nil {
-		event.EndTime = req.EndTime
-	}
-	if req.Location != nil {
-		event.Location = *req.Location
-	}
-	if req.IsAllDay != nil {
-		event.IsAllDay = *req.IsAllDay
-	}
-	if req.LifeAreaID != nil {
-		event.LifeAreaID = req.LifeAreaID
-	}
-	event.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, event); err != nil {
 		s.logger.Error("Failed to update event", err, map[string]interface{}{"user_id": userID, "event_id": id, "action": "UPDATE_EVENT_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Event updated", map[string]interface{}{"user_id": userID, "event_id": id, "action": "UPDATE_EVENT_SUCCESS"})
-	response := dto.ToEventResponse(event)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventEventUpdated, map[string]interface{}{
-			"event_id": id,
-			"event":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventEventUpdated,
 			"user_id":    userID,
@@ -136,26 +157,32 @@ func (s *eventService) Update(ctx context.Context, id int, req *dto.UpdateEventR
 
 func (s *eventService) Delete(ctx context.Context, id, userID int) error {
 	s.logger.Info("Deleting event", map[string]interface{}{"user_id": userID, "event_id": id, "action": "DELETE_EVENT"})
-	event, err := s.repo.GetByID(ctx, id)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		event, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return errors.New("event not found")
+		}
+		if event.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToEventResponse(event)
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.EventDeleted{
+			EventID: id,
+			Title:   event.Title,
+		})
+	})
 	if err != nil {
-		return err
-	}
-	if event == nil {
-		return errors.New("event not found")
-	}
-	if event.UserID != userID {
-		return errors.New("unauthorized")
-	}
-	if err := s.repo.Delete(ctx, id); err != nil {
 		s.logger.Error("Failed to delete event", err, map[string]interface{}{"user_id": userID, "event_id": id, "action": "DELETE_EVENT_FAILED"})
 		return err
 	}
 	s.logger.Info("Event deleted", map[string]interface{}{"user_id": userID, "event_id": id, "action": "DELETE_EVENT_SUCCESS"})
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventEventDeleted, map[string]interface{}{
-			"event_id": id,
-			"title":    event.Title,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventEventDeleted,
 			"user_id":    userID,
@@ -165,3 +192,12 @@ func (s *eventService) Delete(ctx context.Context, id, userID int) error {
 	}
 	return nil
 }
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *eventService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
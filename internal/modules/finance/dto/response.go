@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/finance/domain"
 )
 
@@ -18,6 +19,12 @@ type TransactionResponse struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// TransactionListResponse is one page of GET /finance
+type TransactionListResponse struct {
+	Items []*TransactionResponse `json:"items"`
+	query.PageInfo
+}
+
 type SummaryResponse struct {
 	Income  float64 `json:"income"`
 	Expense float64 `json:"expense"`
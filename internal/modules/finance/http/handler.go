@@ -9,6 +9,7 @@ import (
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/finance/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/finance/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/finance/service"
 	"github.com/gorilla/mux"
 )
@@ -58,8 +59,15 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, tx, http.StatusOK, "İşlem getirildi")
 }
 
+// GetAll returns one page of the caller's transactions
+// GET /api/finance?type=expense&category=food&date_after=2026-01-01&sort=-amount&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	txs, err := h.service.GetAll(r.Context(), h.getUserID(r))
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+	txs, err := h.service.GetAll(r.Context(), q, h.getUserID(r))
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "İşlemler getirilemedi", err.Error())
 		return
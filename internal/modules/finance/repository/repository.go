@@ -4,13 +4,35 @@ import (
 	"context"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/finance/domain"
 )
 
+// ListSpec whitelists the filters and sort keys of GET /finance
+var ListSpec = &query.Spec[domain.Transaction]{
+	Filters: map[string]query.Filter{
+		"type":        {Column: "type", Op: query.Eq, Values: []string{"income", "expense"}},
+		"category":    {Column: "category", Op: query.Eq},
+		"amount_min":  {Column: "amount", Op: query.Gte, Kind: query.Float},
+		"amount_max":  {Column: "amount", Op: query.Lte, Kind: query.Float},
+		"date_after":  {Column: "date", Op: query.Gte, Kind: query.Time},
+		"date_before": {Column: "date", Op: query.Lt, Kind: query.Time},
+		"q":           {Column: "description", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.Transaction]{
+		"date":       {Column: "date", Kind: query.Time, Value: func(t *domain.Transaction) any { return t.Date }},
+		"amount":     {Column: "amount", Kind: query.Float, Value: func(t *domain.Transaction) any { return t.Amount }},
+		"created_at": {Column: "created_at", Kind: query.Time, Value: func(t *domain.Transaction) any { return t.CreatedAt }},
+	},
+	DefaultSort: "-date",
+	ID:          func(t *domain.Transaction) int { return t.ID },
+}
+
 type TransactionRepository interface {
 	Create(ctx context.Context, tx *domain.Transaction) (*domain.Transaction, error)
 	GetByID(ctx context.Context, id int) (*domain.Transaction, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.Transaction, error)
+	// List returns one page of the user's transactions together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.Transaction]) ([]*domain.Transaction, int, error)
 	GetByDateRange(ctx context.Context, userID int, start, end time.Time) ([]*domain.Transaction, error)
 	GetSummary(ctx context.Context, userID int, start, end time.Time) (income float64, expense float64, err error)
 	Update(ctx context.Context, tx *domain.Transaction) error
@@ -5,40 +5,53 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/finance/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/finance/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/finance/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 )
 
 type financeService struct {
-	repo        repository.TransactionRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.TransactionRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
 }
 
-func NewFinanceService(repo repository.TransactionRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) FinanceService {
-	return &financeService{repo: repo, logger: logger, broadcaster: broadcaster}
+func NewFinanceService(repo repository.TransactionRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) FinanceService {
+	return &financeService{repo: repo, uow: uow, logger: logger, bus: bus}
 }
 
 func (s *financeService) Create(ctx context.Context, req *dto.CreateTransactionRequest, userID int) (*dto.TransactionResponse, error) {
 	s.logger.Info("Creating transaction", map[string]interface{}{"user_id": userID, "amount": req.Amount, "type": req.Type, "action": "CREATE_TRANSACTION"})
 	now := time.Now()
 	tx := &domain.Transaction{UserID: userID, Amount: req.Amount, Type: req.Type, Category: req.Category, Description: req.Description, Date: req.Date, CreatedAt: now, UpdatedAt: now}
-	created, err := s.repo.Create(ctx, tx)
+	var (
+		created  *domain.Transaction
+		response *dto.TransactionResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, tx); err != nil {
+			return err
+		}
+		response = dto.ToTransactionResponse(created)
+		return s.publish(ctx, userID, events.TransactionCreated{
+			TransactionID: created.ID,
+			Transaction:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create transaction", err, map[string]interface{}{"user_id": userID, "action": "CREATE_TRANSACTION_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Transaction created", map[string]interface{}{"user_id": userID, "transaction_id": created.ID, "action": "CREATE_TRANSACTION_SUCCESS"})
-	response := dto.ToTransactionResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventTransactionCreated, map[string]interface{}{
-			"transaction_id": created.ID,
-			"transaction":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventTransactionCreated,
 			"user_id":    userID,
@@ -63,12 +76,13 @@ func (s *financeService) GetByID(ctx context.Context, id, userID int) (*dto.Tran
 	return dto.ToTransactionResponse(tx), nil
 }
 
-func (s *financeService) GetAll(ctx context.Context, userID int) ([]*dto.TransactionResponse, error) {
-	txs, err := s.repo.GetByUserID(ctx, userID)
+func (s *financeService) GetAll(ctx context.Context, q *query.Query[domain.Transaction], userID int) (*dto.TransactionListResponse, error) {
+	txs, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
-	return dto.ToTransactionResponseList(txs), nil
+	txs, page := q.Result(txs, total)
+	return &dto.TransactionListResponse{Items: dto.ToTransactionResponseList(txs), PageInfo: page}, nil
 }
 
 func (s *financeService) GetSummary(ctx context.Context, userID int, start, end time.Time) (*dto.SummaryResponse, error) {
@@ -81,43 +95,50 @@ func (s *financeService) GetSummary(ctx context.Context, userID int, start, end
 
 func (s *financeService) Update(ctx context.Context, id int, req *dto.UpdateTransactionRequest, userID int) (*dto.TransactionResponse, error) {
 	s.logger.Info("Updating transaction", map[string]interface{}{"user_id": userID, "transaction_id": id, "action": "UPDATE_TRANSACTION"})
-	tx, err := s.repo.GetByID(ctx, id)
+	var response *dto.TransactionResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		tx, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if tx == nil {
+			return errors.New("transaction not found")
+		}
+		if tx.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToTransactionResponse(tx)
+		if req.Amount != nil {
+			tx.Amount = *req.Amount
+		}
+		if req.Type != nil {
+			tx.Type = *req.Type
+		}
+		if req.Category != nil {
+			tx.Category = *req.Category
+		}
+		if req.Description != nil {
+			tx.Description = *req.Description
+		}
+		if req.Date != nil {
+			tx.Date = *req.Date
+		}
+		tx.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, tx); err != nil {
+			return err
+		}
+		response = dto.ToTransactionResponse(tx)
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.TransactionUpdated{
+			TransactionID: id,
+			Transaction:   response,
+		})
+	})
 	if err != nil {
-		return nil, err
-	}
-	if tx == nil {
-		return nil, errors.New("transaction not found")
-	}
-	if tx.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
-	if req.Amount != nil {
-		tx.Amount = *req.Amount
-	}
-	if req.Type != nil {
-		tx.Type = *req.Type
-	}
-	if req.Category != nil {
-		tx.Category = *req.Category
-	}
-	if req.Description != nil {
-		tx.Description = *req.Description
-	}
-	if req.Date != nil {
-		tx.Date = *req.Date
-	}
-	tx.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, tx); err != nil {
 		s.logger.Error("Failed to update transaction", err, map[string]interface{}{"user_id": userID, "transaction_id": id, "action": "UPDATE_TRANSACTION_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Transaction updated", map[string]interface{}{"user_id": userID, "transaction_id": id, "action": "UPDATE_TRANSACTION_SUCCESS"})
-	response := dto.ToTransactionResponse(tx)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventTransactionUpdated, map[string]interface{}{
-			"transaction_id": id,
-			"transaction":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventTransactionUpdated,
 			"user_id":    userID,
@@ -130,25 +151,31 @@ func (s *financeService) Update(ctx context.Context, id int, req *dto.UpdateTran
 
 func (s *financeService) Delete(ctx context.Context, id, userID int) error {
 	s.logger.Info("Deleting transaction", map[string]interface{}{"user_id": userID, "transaction_id": id, "action": "DELETE_TRANSACTION"})
-	tx, err := s.repo.GetByID(ctx, id)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		tx, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if tx == nil {
+			return errors.New("transaction not found")
+		}
+		if tx.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToTransactionResponse(tx)
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.TransactionDeleted{
+			TransactionID: id,
+		})
+	})
 	if err != nil {
-		return err
-	}
-	if tx == nil {
-		return errors.New("transaction not found")
-	}
-	if tx.UserID != userID {
-		return errors.New("unauthorized")
-	}
-	if err := s.repo.Delete(ctx, id); err != nil {
 		s.logger.Error("Failed to delete transaction", err, map[string]interface{}{"user_id": userID, "transaction_id": id, "action": "DELETE_TRANSACTION_FAILED"})
 		return err
 	}
 	s.logger.Info("Transaction deleted", map[string]interface{}{"user_id": userID, "transaction_id": id, "action": "DELETE_TRANSACTION_SUCCESS"})
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventTransactionDeleted, map[string]interface{}{
-			"transaction_id": id,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventTransactionDeleted,
 			"user_id":    userID,
@@ -158,3 +185,12 @@ func (s *financeService) Delete(ctx context.Context, id, userID int) error {
 	}
 	return nil
 }
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *financeService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
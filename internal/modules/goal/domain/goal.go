@@ -5,6 +5,7 @@ import "time"
 type Goal struct {
 	ID          int
 	UserID      int
+	WorkspaceID *int
 	LifeAreaID  *int
 	Title       string
 	Description string
@@ -3,6 +3,7 @@ package dto
 import "time"
 
 type CreateGoalRequest struct {
+	WorkspaceID *int       `json:"workspace_id,omitempty" validate:"omitempty,min=1"`
 	LifeAreaID  *int       `json:"life_area_id,omitempty"`
 	Title       string     `json:"title" validate:"required,min=1,max=255"`
 	Description string     `json:"description,omitempty"`
@@ -17,4 +18,6 @@ type UpdateGoalRequest struct {
 	TargetDate  *time.Time `json:"target_date,omitempty"`
 	Priority    *string    `json:"priority,omitempty" validate:"omitempty,oneof=low medium high"`
 	IsCompleted *bool      `json:"is_completed,omitempty"`
+	// WorkspaceID moves the goal to a workspace, or back to personal with 0
+	WorkspaceID *int `json:"workspace_id,omitempty" validate:"omitempty,min=0"`
 }
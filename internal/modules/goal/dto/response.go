@@ -3,12 +3,14 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/domain"
 )
 
 type GoalResponse struct {
 	ID                  int        `json:"id"`
 	UserID              int        `json:"user_id"`
+	WorkspaceID         *int       `json:"workspace_id,omitempty"`
 	LifeAreaID          *int       `json:"life_area_id,omitempty"`
 	Title               string     `json:"title"`
 	Description         string     `json:"description,omitempty"`
@@ -19,10 +21,18 @@ type GoalResponse struct {
 	ProgressPercentage  float64    `json:"progress_percentage"`
 	TotalMilestones     int        `json:"total_milestones"`
 	CompletedMilestones int        `json:"completed_milestones"`
+	TotalTasks          int        `json:"total_tasks"`
+	CompletedTasks      int        `json:"completed_tasks"`
 	CreatedAt           time.Time  `json:"created_at"`
 	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
+// GoalListResponse is one page of GET /goals
+type GoalListResponse struct {
+	Items []*GoalResponse `json:"items"`
+	query.PageInfo
+}
+
 func ToGoalResponse(g *domain.Goal, total, completed int) *GoalResponse {
 	if g == nil {
 		return nil
@@ -34,5 +44,20 @@ func ToGoalResponse(g *domain.Goal, total, completed int) *GoalResponse {
 	if g.IsCompleted {
 		progress = 100
 	}
-	return &GoalResponse{ID: g.ID, UserID: g.UserID, LifeAreaID: g.LifeAreaID, Title: g.Title, Description: g.Description, TargetDate: g.TargetDate, IsCompleted: g.IsCompleted, CompletedAt: g.CompletedAt, Priority: g.Priority, ProgressPercentage: progress, TotalMilestones: total, CompletedMilestones: completed, CreatedAt: g.CreatedAt, UpdatedAt: g.UpdatedAt}
+	return &GoalResponse{ID: g.ID, UserID: g.UserID, WorkspaceID: g.WorkspaceID, LifeAreaID: g.LifeAreaID, Title: g.Title, Description: g.Description, TargetDate: g.TargetDate, IsCompleted: g.IsCompleted, CompletedAt: g.CompletedAt, Priority: g.Priority, ProgressPercentage: progress, TotalMilestones: total, CompletedMilestones: completed, CreatedAt: g.CreatedAt, UpdatedAt: g.UpdatedAt}
+}
+
+// WithTasks adds the goal's linked tasks to the counts and recomputes progress over
+// milestones and tasks together
+func (r *GoalResponse) WithTasks(total, completed int) *GoalResponse {
+	r.TotalTasks = total
+	r.CompletedTasks = completed
+	if r.IsCompleted {
+		return r
+	}
+	steps := r.TotalMilestones + total
+	if steps > 0 {
+		r.ProgressPercentage = float64(r.CompletedMilestones+completed) / float64(steps) * 100
+	}
+	return r
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/service"
 	"github.com/gorilla/mux"
 )
@@ -40,7 +41,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	goal, err := h.service.Create(r.Context(), &req, h.getUserID(r))
 	if err != nil {
-		utils.ReturnError(w, "INTERNAL_ERROR", "Hedef oluşturulamadı", err.Error())
+		h.handleError(w, err, "Hedef oluşturulamadı")
 		return
 	}
 	utils.WriteJson(w, goal, http.StatusCreated, "Hedef oluşturuldu")
@@ -50,14 +51,21 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(mux.Vars(r)["id"])
 	goal, err := h.service.GetByID(r.Context(), id, h.getUserID(r))
 	if err != nil {
-		utils.ReturnError(w, "NOT_FOUND", "Hedef bulunamadı", err.Error())
+		h.handleError(w, err, "Hedef getirilemedi")
 		return
 	}
 	utils.WriteJson(w, goal, http.StatusOK, "Hedef getirildi")
 }
 
+// GetAll returns one page of the caller's goals
+// GET /api/goals?priority=high&is_completed=false&target_before=2026-12-31&sort=target_date&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	goals, err := h.service.GetAll(r.Context(), h.getUserID(r))
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+	goals, err := h.service.GetAll(r.Context(), q, h.getUserID(r))
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Hedefler getirilemedi", err.Error())
 		return
@@ -72,9 +80,13 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
 		return
 	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
 	goal, err := h.service.Update(r.Context(), id, &req, h.getUserID(r))
 	if err != nil {
-		utils.ReturnError(w, "INTERNAL_ERROR", "Hedef güncellenemedi", err.Error())
+		h.handleError(w, err, "Hedef güncellenemedi")
 		return
 	}
 	utils.WriteJson(w, goal, http.StatusOK, "Hedef güncellendi")
@@ -83,8 +95,19 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(mux.Vars(r)["id"])
 	if err := h.service.Delete(r.Context(), id, h.getUserID(r)); err != nil {
-		utils.ReturnError(w, "INTERNAL_ERROR", "Hedef silinemedi", err.Error())
+		h.handleError(w, err, "Hedef silinemedi")
 		return
 	}
 	utils.WriteJson(w, nil, http.StatusOK, "Hedef silindi")
 }
+
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string) {
+	switch err.Error() {
+	case "goal not found":
+		utils.ReturnError(w, "NOT_FOUND", "Hedef bulunamadı", err.Error())
+	case "unauthorized":
+		utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
+	default:
+		utils.ReturnError(w, "INTERNAL_ERROR", message, err.Error())
+	}
+}
@@ -9,6 +9,7 @@ import (
 type GoalModel struct {
 	ID          int        `db:"id"`
 	UserID      int        `db:"user_id"`
+	WorkspaceID *int       `db:"workspace_id"`
 	LifeAreaID  *int       `db:"life_area_id"`
 	Title       string     `db:"title"`
 	Description *string    `db:"description"`
@@ -28,7 +29,7 @@ func (m *GoalModel) ToDomain() *domain.Goal {
 	if m.Description != nil {
 		desc = *m.Description
 	}
-	return &domain.Goal{ID: m.ID, UserID: m.UserID, LifeAreaID: m.LifeAreaID, Title: m.Title, Description: desc, TargetDate: m.TargetDate, IsCompleted: m.IsCompleted, CompletedAt: m.CompletedAt, Priority: m.Priority, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}
+	return &domain.Goal{ID: m.ID, UserID: m.UserID, WorkspaceID: m.WorkspaceID, LifeAreaID: m.LifeAreaID, Title: m.Title, Description: desc, TargetDate: m.TargetDate, IsCompleted: m.IsCompleted, CompletedAt: m.CompletedAt, Priority: m.Priority, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}
 }
 
 func FromDomain(g *domain.Goal) *GoalModel {
@@ -39,7 +40,7 @@ func FromDomain(g *domain.Goal) *GoalModel {
 	if g.Description != "" {
 		desc = &g.Description
 	}
-	return &GoalModel{ID: g.ID, UserID: g.UserID, LifeAreaID: g.LifeAreaID, Title: g.Title, Description: desc, TargetDate: g.TargetDate, IsCompleted: g.IsCompleted, CompletedAt: g.CompletedAt, Priority: g.Priority, CreatedAt: g.CreatedAt, UpdatedAt: g.UpdatedAt}
+	return &GoalModel{ID: g.ID, UserID: g.UserID, WorkspaceID: g.WorkspaceID, LifeAreaID: g.LifeAreaID, Title: g.Title, Description: desc, TargetDate: g.TargetDate, IsCompleted: g.IsCompleted, CompletedAt: g.CompletedAt, Priority: g.Priority, CreatedAt: g.CreatedAt, UpdatedAt: g.UpdatedAt}
 }
 
 type MilestoneModel struct {
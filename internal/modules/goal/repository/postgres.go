@@ -6,20 +6,30 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/domain"
 	"github.com/jmoiron/sqlx"
 )
 
+// visibleTo matches the personal goals of user $1 and every goal in the workspaces they belong to
+const visibleTo = `(workspace_id IS NULL AND user_id = $1 OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1))`
+
 type postgresRepository struct{ db *sqlx.DB }
 
 func NewPostgresRepository(db *sqlx.DB) GoalRepository { return &postgresRepository{db: db} }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, goal *domain.Goal) (*domain.Goal, error) {
-	query := `INSERT INTO goals (user_id, life_area_id, title, description, target_date, is_completed, priority, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at`
+	query := `INSERT INTO goals (user_id, workspace_id, life_area_id, title, description, target_date, is_completed, priority, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at`
 	now := time.Now()
 	model := FromDomain(goal)
-	err := r.db.QueryRowxContext(ctx, query, model.UserID, model.LifeAreaID, model.Title, model.Description, model.TargetDate, false, model.Priority, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
+	err := r.conn(ctx).QueryRowxContext(ctx, query, model.UserID, model.WorkspaceID, model.LifeAreaID, model.Title, model.Description, model.TargetDate, false, model.Priority, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -27,9 +37,9 @@ func (r *postgresRepository) Create(ctx context.Context, goal *domain.Goal) (*do
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Goal, error) {
-	query := `SELECT id, user_id, life_area_id, title, description, target_date, is_completed, completed_at, priority, created_at, updated_at FROM goals WHERE id = $1`
+	query := `SELECT id, user_id, workspace_id, life_area_id, title, description, target_date, is_completed, completed_at, priority, created_at, updated_at FROM goals WHERE id = $1 AND deleted_at IS NULL`
 	var model GoalModel
-	err := r.db.GetContext(ctx, &model, query, id)
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -39,33 +49,59 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Goal,
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Goal, error) {
-	query := `SELECT id, user_id, life_area_id, title, description, target_date, is_completed, completed_at, priority, created_at, updated_at FROM goals WHERE user_id = $1 ORDER BY created_at DESC`
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.Goal]) ([]*domain.Goal, int, error) {
+	where, args := q.Where(`WHERE `+visibleTo+` AND deleted_at IS NULL`, userID)
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM goals `+where, args...); err != nil {
+		return nil, 0, err
+	}
+	page, args := q.Paginate(where, args)
+	sqlQuery := `SELECT id, user_id, workspace_id, life_area_id, title, description, target_date, is_completed, completed_at, priority, created_at, updated_at FROM goals ` + page
 	var models []GoalModel
-	if err := r.db.SelectContext(ctx, &models, query, userID); err != nil {
-		return nil, err
+	if err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...); err != nil {
+		return nil, 0, err
 	}
 	goals := make([]*domain.Goal, len(models))
 	for i, m := range models {
 		goals[i] = m.ToDomain()
 	}
-	return goals, nil
+	return goals, total, nil
 }
 
 func (r *postgresRepository) Update(ctx context.Context, goal *domain.Goal) error {
-	query := `UPDATE goals SET title = $1, description = $2, target_date = $3, is_completed = $4, completed_at = $5, priority = $6, life_area_id = $7, updated_at = $8 WHERE id = $9`
+	query := `UPDATE goals SET title = $1, description = $2, target_date = $3, is_completed = $4, completed_at = $5, priority = $6, life_area_id = $7, workspace_id = $8, updated_at = $9 WHERE id = $10`
 	model := FromDomain(goal)
-	_, err := r.db.ExecContext(ctx, query, model.Title, model.Description, model.TargetDate, model.IsCompleted, model.CompletedAt, model.Priority, model.LifeAreaID, time.Now(), model.ID)
+	_, err := r.conn(ctx).ExecContext(ctx, query, model.Title, model.Description, model.TargetDate, model.IsCompleted, model.CompletedAt, model.Priority, model.LifeAreaID, model.WorkspaceID, time.Now(), model.ID)
 	return err
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM goals WHERE id = $1`, id)
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE goals SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now(), id)
 	return err
 }
 
 func (r *postgresRepository) CountMilestones(ctx context.Context, goalID int) (total int, completed int, err error) {
 	query := `SELECT COUNT(*) as total, COUNT(*) FILTER (WHERE is_completed = true) as completed FROM milestones WHERE goal_id = $1`
-	err = r.db.QueryRowxContext(ctx, query, goalID).Scan(&total, &completed)
+	err = r.conn(ctx).QueryRowxContext(ctx, query, goalID).Scan(&total, &completed)
+	return
+}
+
+func (r *postgresRepository) GetByTaskID(ctx context.Context, taskID int) (*domain.Goal, error) {
+	query := `SELECT g.id, g.user_id, g.workspace_id, g.life_area_id, g.title, g.description, g.target_date, g.is_completed, g.completed_at, g.priority, g.created_at, g.updated_at
+		FROM goals g JOIN tasks t ON t.goal_id = g.id WHERE t.id = $1 AND g.deleted_at IS NULL`
+	var model GoalModel
+	err := r.conn(ctx).GetContext(ctx, &model, query, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *postgresRepository) CountTasks(ctx context.Context, goalID int) (total int, completed int, err error) {
+	query := `SELECT COUNT(*) as total, COUNT(*) FILTER (WHERE is_completed = true) as completed FROM tasks WHERE goal_id = $1 AND deleted_at IS NULL`
+	err = r.conn(ctx).QueryRowxContext(ctx, query, goalID).Scan(&total, &completed)
 	return
 }
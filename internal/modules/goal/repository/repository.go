@@ -3,14 +3,56 @@ package repository
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/domain"
 )
 
+// priorityRank sorts priorities by weight rather than alphabetically
+const priorityRank = `CASE priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END`
+
+func priorityWeight(priority string) int {
+	switch priority {
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// ListSpec whitelists the filters and sort keys of GET /goals
+var ListSpec = &query.Spec[domain.Goal]{
+	Filters: map[string]query.Filter{
+		"priority":      {Column: "priority", Op: query.Eq, Values: []string{"low", "medium", "high"}},
+		"is_completed":  {Column: "is_completed", Op: query.Eq, Kind: query.Bool},
+		"life_area_id":  {Column: "life_area_id", Op: query.Eq, Kind: query.Int},
+		"is_shared":     {Column: "workspace_id", Op: query.Present},
+		"workspace_id":  {Column: "workspace_id", Op: query.Eq, Kind: query.Int},
+		"target_before": {Column: "target_date", Op: query.Lt, Kind: query.Time},
+		"target_after":  {Column: "target_date", Op: query.Gte, Kind: query.Time},
+		"q":             {Column: "title", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.Goal]{
+		"created_at":  {Column: "created_at", Kind: query.Time, Value: func(g *domain.Goal) any { return g.CreatedAt }},
+		"updated_at":  {Column: "updated_at", Kind: query.Time, Value: func(g *domain.Goal) any { return g.UpdatedAt }},
+		"target_date": {Column: "target_date", Kind: query.Time, Nullable: true, Value: func(g *domain.Goal) any { return g.TargetDate }},
+		"priority":    {Column: priorityRank, Kind: query.Int, Value: func(g *domain.Goal) any { return priorityWeight(g.Priority) }},
+		"title":       {Column: "title", Value: func(g *domain.Goal) any { return g.Title }},
+	},
+	DefaultSort: "-created_at",
+	ID:          func(g *domain.Goal) int { return g.ID },
+}
+
 type GoalRepository interface {
 	Create(ctx context.Context, goal *domain.Goal) (*domain.Goal, error)
 	GetByID(ctx context.Context, id int) (*domain.Goal, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.Goal, error)
+	// List returns one page of the user's personal goals and those of the workspaces they belong to,
+	// together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.Goal]) ([]*domain.Goal, int, error)
 	Update(ctx context.Context, goal *domain.Goal) error
 	Delete(ctx context.Context, id int) error
 	CountMilestones(ctx context.Context, goalID int) (total int, completed int, err error)
+	GetByTaskID(ctx context.Context, taskID int) (*domain.Goal, error)
+	CountTasks(ctx context.Context, goalID int) (total int, completed int, err error)
 }
@@ -3,13 +3,23 @@ package service
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/dto"
 )
 
 type GoalService interface {
 	Create(ctx context.Context, req *dto.CreateGoalRequest, userID int) (*dto.GoalResponse, error)
 	GetByID(ctx context.Context, id, userID int) (*dto.GoalResponse, error)
-	GetAll(ctx context.Context, userID int) ([]*dto.GoalResponse, error)
+	GetAll(ctx context.Context, q *query.Query[domain.Goal], userID int) (*dto.GoalListResponse, error)
 	Update(ctx context.Context, id int, req *dto.UpdateGoalRequest, userID int) (*dto.GoalResponse, error)
 	Delete(ctx context.Context, id, userID int) error
+	HandleTaskCompleted(ctx context.Context, userID int, event events.TaskCompleted) error
+}
+
+// Subscribe registers the goal module's reactions to other modules' events
+func Subscribe(bus *eventbus.Bus, svc GoalService) {
+	eventbus.SubscribeAsync(bus, "goal.task_completed", svc.HandleTaskCompleted, nil)
 }
@@ -5,22 +5,28 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/goal/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace"
 )
 
 type goalService struct {
-	repo        repository.GoalRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.GoalRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
+	access *workspace.Access
 }
 
-func NewGoalService(repo repository.GoalRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) GoalService {
-	return &goalService{repo: repo, logger: logger, broadcaster: broadcaster}
+func NewGoalService(repo repository.GoalRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus, access *workspace.Access) GoalService {
+	return &goalService{repo: repo, uow: uow, logger: logger, bus: bus, access: access}
 }
 
 func (s *goalService) Create(ctx context.Context, req *dto.CreateGoalRequest, userID int) (*dto.GoalResponse, error) {
@@ -29,21 +35,35 @@ func (s *goalService) Create(ctx context.Context, req *dto.CreateGoalRequest, us
 	if priority == "" {
 		priority = "medium"
 	}
+	if req.WorkspaceID != nil {
+		if err := s.access.CheckWorkspace(ctx, *req.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return nil, err
+		}
+	}
 	now := time.Now()
-	goal := &domain.Goal{UserID: userID, LifeAreaID: req.LifeAreaID, Title: req.Title, Description: req.Description, TargetDate: req.TargetDate, Priority: priority, CreatedAt: now, UpdatedAt: now}
-	created, err := s.repo.Create(ctx, goal)
+	goal := &domain.Goal{UserID: userID, WorkspaceID: req.WorkspaceID, LifeAreaID: req.LifeAreaID, Title: req.Title, Description: req.Description, TargetDate: req.TargetDate, Priority: priority, CreatedAt: now, UpdatedAt: now}
+	var (
+		created  *domain.Goal
+		response *dto.GoalResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, goal); err != nil {
+			return err
+		}
+		response = dto.ToGoalResponse(created, 0, 0)
+		return s.access.Publish(ctx, created.UserID, created.WorkspaceID, events.GoalCreated{
+			GoalID: created.ID,
+			Goal:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create goal", err, map[string]interface{}{"user_id": userID, "action": "CREATE_GOAL_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Goal created", map[string]interface{}{"user_id": userID, "goal_id": created.ID, "action": "CREATE_GOAL_SUCCESS"})
-	
-	response := dto.ToGoalResponse(created, 0, 0)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventGoalCreated, map[string]interface{}{
-			"goal_id": created.ID,
-			"goal":    response,
-		})
+
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventGoalCreated,
 			"user_id":    userID,
@@ -63,87 +83,106 @@ func (s *goalService) GetByID(ctx context.Context, id, userID int) (*dto.GoalRes
 	if goal == nil {
 		return nil, errors.New("goal not found")
 	}
-	if goal.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.access.Check(ctx, goal.UserID, goal.WorkspaceID, userID, workspace.RoleViewer); err != nil {
+		return nil, err
 	}
-	total, completed, _ := s.repo.CountMilestones(ctx, id)
-	return dto.ToGoalResponse(goal, total, completed), nil
+	return s.toResponse(ctx, goal), nil
 }
 
-func (s *goalService) GetAll(ctx context.Context, userID int) ([]*dto.GoalResponse, error) {
-	goals, err := s.repo.GetByUserID(ctx, userID)
+func (s *goalService) GetAll(ctx context.Context, q *query.Query[domain.Goal], userID int) (*dto.GoalListResponse, error) {
+	goals, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
+	goals, page := q.Result(goals, total)
 	result := make([]*dto.GoalResponse, len(goals))
 	for i, g := range goals {
-		total, completed, _ := s.repo.CountMilestones(ctx, g.ID)
-		result[i] = dto.ToGoalResponse(g, total, completed)
+		result[i] = s.toResponse(ctx, g)
 	}
-	return result, nil
+	return &dto.GoalListResponse{Items: result, PageInfo: page}, nil
 }
 
 func (s *goalService) Update(ctx context.Context, id int, req *dto.UpdateGoalRequest, userID int) (*dto.GoalResponse, error) {
 	s.logger.Info("Updating goal", map[string]interface{}{"user_id": userID, "goal_id": id, "action": "UPDATE_GOAL"})
-	goal, err := s.repo.GetByID(ctx, id)
+	var (
+		response  *dto.GoalResponse
+		completed bool
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		goal, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if goal == nil {
+			return errors.New("goal not found")
+		}
+		if err := s.access.Check(ctx, goal.UserID, goal.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := s.toResponse(ctx, goal)
+		if req.WorkspaceID != nil {
+			target, err := s.access.CheckMove(ctx, goal.UserID, *req.WorkspaceID, userID)
+			if err != nil {
+				return err
+			}
+			goal.WorkspaceID = target
+		}
+		if req.Title != nil {
+			goal.Title = *req.Title
+		}
+		if req.Description != nil {
+			goal.Description = *req.Description
+		}
+		if req.TargetDate != nil {
+			goal.TargetDate = req.TargetDate
+		}
+		if req.Priority != nil {
+			goal.Priority = *req.Priority
+		}
+		if req.LifeAreaID != nil {
+			goal.LifeAreaID = req.LifeAreaID
+		}
+		if req.IsCompleted != nil && *req.IsCompleted {
+			goal.MarkCompleted()
+		}
+		goal.UpdatedAt = time.Now()
+		completed = req.IsCompleted != nil && *req.IsCompleted && goal.IsCompleted
+		if err := s.repo.Update(ctx, goal); err != nil {
+			return err
+		}
+		response = s.toResponse(ctx, goal)
+
+		// Check if goal was completed
+		if completed {
+			if err := s.access.Publish(ctx, goal.UserID, goal.WorkspaceID, events.GoalCompleted{
+				GoalID: id,
+				Goal:   response,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return s.access.Publish(eventbus.WithBefore(ctx, before), goal.UserID, goal.WorkspaceID, events.GoalUpdated{
+			GoalID: id,
+			Goal:   response,
+		})
+	})
 	if err != nil {
-		return nil, err
-	}
-	if goal == nil {
-		return nil, errors.New("goal not found")
-	}
-	if goal.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
-	if req.Title != nil {
-		goal.Title = *req.Title
-	}
-	if req.Description != nil {
-		goal.Description = *req.Description
-	}
-	if req.TargetDate != nil {
-		goal.TargetDate = req.TargetDate
-	}
-	if req.Priority != nil {
-		goal.Priority = *req.Priority
-	}
-	if req.LifeAreaID != nil {
-		goal.LifeAreaID = req.LifeAreaID
-	}
-	if req.IsCompleted != nil && *req.IsCompleted {
-		goal.MarkCompleted()
-	}
-	goal.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, goal); err != nil {
 		s.logger.Error("Failed to update goal", err, map[string]interface{}{"user_id": userID, "goal_id": id, "action": "UPDATE_GOAL_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Goal updated", map[string]interface{}{"user_id": userID, "goal_id": id, "action": "UPDATE_GOAL_SUCCESS"})
-	
-	total, completed, _ := s.repo.CountMilestones(ctx, id)
-	response := dto.ToGoalResponse(goal, total, completed)
-	
-	// Check if goal was completed
-	if req.IsCompleted != nil && *req.IsCompleted && goal.IsCompleted {
-		if s.broadcaster != nil {
-			s.broadcaster.Publish(userID, notification.EventGoalCompleted, map[string]interface{}{
-				"goal_id": id,
-				"goal":    response,
-			})
-			s.logger.Info("WebSocket event published", map[string]interface{}{
-				"event_type": notification.EventGoalCompleted,
-				"user_id":    userID,
-				"entity_id":  id,
-				"action":     "WS_EVENT_PUBLISHED",
-			})
-		}
-	}
-	
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventGoalUpdated, map[string]interface{}{
-			"goal_id": id,
-			"goal":    response,
+
+	if completed && s.bus != nil {
+		s.logger.Info("WebSocket event published", map[string]interface{}{
+			"event_type": notification.EventGoalCompleted,
+			"user_id":    userID,
+			"entity_id":  id,
+			"action":     "WS_EVENT_PUBLISHED",
 		})
+	}
+
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventGoalUpdated,
 			"user_id":    userID,
@@ -157,27 +196,33 @@ func (s *goalService) Update(ctx context.Context, id int, req *dto.UpdateGoalReq
 
 func (s *goalService) Delete(ctx context.Context, id, userID int) error {
 	s.logger.Info("Deleting goal", map[string]interface{}{"user_id": userID, "goal_id": id, "action": "DELETE_GOAL"})
-	goal, err := s.repo.GetByID(ctx, id)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		goal, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if goal == nil {
+			return errors.New("goal not found")
+		}
+		if err := s.access.Check(ctx, goal.UserID, goal.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := s.toResponse(ctx, goal)
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.access.Publish(eventbus.WithBefore(ctx, before), goal.UserID, goal.WorkspaceID, events.GoalDeleted{
+			GoalID: id,
+			Title:  goal.Title,
+		})
+	})
 	if err != nil {
-		return err
-	}
-	if goal == nil {
-		return errors.New("goal not found")
-	}
-	if goal.UserID != userID {
-		return errors.New("unauthorized")
-	}
-	if err := s.repo.Delete(ctx, id); err != nil {
 		s.logger.Error("Failed to delete goal", err, map[string]interface{}{"user_id": userID, "goal_id": id, "action": "DELETE_GOAL_FAILED"})
 		return err
 	}
 	s.logger.Info("Goal deleted", map[string]interface{}{"user_id": userID, "goal_id": id, "action": "DELETE_GOAL_SUCCESS"})
-	
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventGoalDeleted, map[string]interface{}{
-			"goal_id": id,
-			"title":   goal.Title,
-		})
+
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventGoalDeleted,
 			"user_id":    userID,
@@ -185,6 +230,48 @@ func (s *goalService) Delete(ctx context.Context, id, userID int) error {
 			"action":     "WS_EVENT_PUBLISHED",
 		})
 	}
-	
+
 	return nil
 }
+
+// HandleTaskCompleted advances the goal a completed task is linked to and pushes the
+// recomputed progress to everyone who sees the goal. Events about shared tasks reach every
+// workspace member, so only the copy addressed to the goal's owner is handled. Tasks
+// without a goal are ignored.
+func (s *goalService) HandleTaskCompleted(ctx context.Context, userID int, event events.TaskCompleted) error {
+	goal, err := s.repo.GetByTaskID(ctx, event.TaskID)
+	if err != nil {
+		return err
+	}
+	if goal == nil {
+		return nil
+	}
+	// The event comes once, for the task's owner; the goal may belong to another member of the workspace
+	if err := s.access.Check(ctx, goal.UserID, goal.WorkspaceID, userID, workspace.RoleViewer); err != nil {
+		if err.Error() == "unauthorized" {
+			return nil
+		}
+		return err
+	}
+
+	response := s.toResponse(ctx, goal)
+	s.logger.Info("Goal progress advanced", map[string]interface{}{
+		"user_id":  userID,
+		"goal_id":  goal.ID,
+		"task_id":  event.TaskID,
+		"progress": response.ProgressPercentage,
+		"action":   "GOAL_PROGRESS_ADVANCED",
+	})
+
+	return s.access.Publish(ctx, goal.UserID, goal.WorkspaceID, events.GoalUpdated{
+		GoalID: goal.ID,
+		Goal:   response,
+	})
+}
+
+// toResponse builds the goal response with progress over its milestones and linked tasks
+func (s *goalService) toResponse(ctx context.Context, goal *domain.Goal) *dto.GoalResponse {
+	total, completed, _ := s.repo.CountMilestones(ctx, goal.ID)
+	tasksTotal, tasksCompleted, _ := s.repo.CountTasks(ctx, goal.ID)
+	return dto.ToGoalResponse(goal, total, completed).WithTasks(tasksTotal, tasksCompleted)
+}
@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/domain"
 )
 
@@ -29,6 +30,12 @@ type HabitResponse struct {
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
+// HabitListResponse is one page of GET /habits
+type HabitListResponse struct {
+	Items []*HabitResponse `json:"items"`
+	query.PageInfo
+}
+
 func ToHabitResponse(h *domain.Habit, completedToday bool, skippedToday bool) *HabitResponse {
 	if h == nil {
 		return nil
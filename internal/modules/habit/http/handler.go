@@ -7,8 +7,8 @@ import (
 
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/service"
@@ -16,18 +16,18 @@ import (
 )
 
 type Handler struct {
-	service     service.HabitService
-	repo        repository.HabitRepository
-	broadcaster *notifService.Broadcaster
-	logger      *logger.ZapLogger
+	service service.HabitService
+	repo    repository.HabitRepository
+	bus     *eventbus.Bus
+	logger  *logger.ZapLogger
 }
 
-func NewHandler(service service.HabitService, repo repository.HabitRepository, broadcaster *notifService.Broadcaster, logger *logger.ZapLogger) *Handler {
+func NewHandler(service service.HabitService, repo repository.HabitRepository, bus *eventbus.Bus, logger *logger.ZapLogger) *Handler {
 	return &Handler{
-		service:     service,
-		repo:        repo,
-		broadcaster: broadcaster,
-		logger:      logger,
+		service: service,
+		repo:    repo,
+		bus:     bus,
+		logger:  logger,
 	}
 }
 
@@ -79,8 +79,15 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, habit, http.StatusOK, "Alışkanlık getirildi")
 }
 
+// GetAll returns one page of the caller's habits
+// GET /api/habits?frequency=daily&is_active=true&sort=-current_streak&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	habits, err := h.service.GetAll(r.Context(), h.getUserID(r))
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+	habits, err := h.service.GetAll(r.Context(), q, h.getUserID(r))
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Alışkanlıklar getirilemedi", err.Error())
 		return
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/domain"
 	"github.com/jmoiron/sqlx"
 )
@@ -18,6 +20,11 @@ func NewPostgresRepository(db *sqlx.DB) HabitRepository {
 	return &postgresRepository{db: db}
 }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, habit *domain.Habit) (*domain.Habit, error) {
 	query := `
 		INSERT INTO habits (user_id, life_area_id, name, icon, description, frequency, frequency_config, target_count, time_of_day, reminder_time, current_streak, longest_streak, is_active, created_at, updated_at)
@@ -26,7 +33,7 @@ func (r *postgresRepository) Create(ctx context.Context, habit *domain.Habit) (*
 	`
 	now := time.Now()
 	model := FromDomain(habit)
-	err := r.db.QueryRowxContext(ctx, query,
+	err := r.conn(ctx).QueryRowxContext(ctx, query,
 		model.UserID, model.LifeAreaID, model.Name, model.Icon, model.Description,
 		model.Frequency, model.FrequencyConfig, model.TargetCount, model.TimeOfDay, model.ReminderTime,
 		0, 0, true, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
@@ -37,9 +44,9 @@ func (r *postgresRepository) Create(ctx context.Context, habit *domain.Habit) (*
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Habit, error) {
-	query := `SELECT id, user_id, life_area_id, name, icon, description, frequency, frequency_config, target_count, time_of_day, reminder_time, current_streak, longest_streak, is_active, created_at, updated_at FROM habits WHERE id = $1`
+	query := `SELECT id, user_id, life_area_id, name, icon, description, frequency, frequency_config, target_count, time_of_day, reminder_time, current_streak, longest_streak, is_active, created_at, updated_at FROM habits WHERE id = $1 AND deleted_at IS NULL`
 	var model HabitModel
-	err := r.db.GetContext(ctx, &model, query, id)
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -49,24 +56,29 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Habit
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Habit, error) {
-	query := `SELECT id, user_id, life_area_id, name, icon, description, frequency, frequency_config, target_count, time_of_day, reminder_time, current_streak, longest_streak, is_active, created_at, updated_at FROM habits WHERE user_id = $1 ORDER BY created_at DESC`
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.Habit]) ([]*domain.Habit, int, error) {
+	where, args := q.Where(`WHERE user_id = $1 AND deleted_at IS NULL`, userID)
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM habits `+where, args...); err != nil {
+		return nil, 0, err
+	}
+	page, args := q.Paginate(where, args)
+	sqlQuery := `SELECT id, user_id, life_area_id, name, icon, description, frequency, frequency_config, target_count, time_of_day, reminder_time, current_streak, longest_streak, is_active, created_at, updated_at FROM habits ` + page
 	var models []HabitModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
-	if err != nil {
-		return nil, err
+	if err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...); err != nil {
+		return nil, 0, err
 	}
 	habits := make([]*domain.Habit, len(models))
 	for i, m := range models {
 		habits[i] = m.ToDomain()
 	}
-	return habits, nil
+	return habits, total, nil
 }
 
 func (r *postgresRepository) GetActiveHabits(ctx context.Context, userID int) ([]*domain.Habit, error) {
-	query := `SELECT id, user_id, life_area_id, name, icon, description, frequency, frequency_config, target_count, time_of_day, reminder_time, current_streak, longest_streak, is_active, created_at, updated_at FROM habits WHERE user_id = $1 AND is_active = true ORDER BY name`
+	query := `SELECT id, user_id, life_area_id, name, icon, description, frequency, frequency_config, target_count, time_of_day, reminder_time, current_streak, longest_streak, is_active, created_at, updated_at FROM habits WHERE user_id = $1 AND deleted_at IS NULL AND is_active = true ORDER BY name`
 	var models []HabitModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +92,7 @@ func (r *postgresRepository) GetActiveHabits(ctx context.Context, userID int) ([
 func (r *postgresRepository) Update(ctx context.Context, habit *domain.Habit) error {
 	query := `UPDATE habits SET name = $1, icon = $2, description = $3, frequency = $4, frequency_config = $5, target_count = $6, time_of_day = $7, reminder_time = $8, current_streak = $9, longest_streak = $10, is_active = $11, life_area_id = $12, updated_at = $13 WHERE id = $14`
 	model := FromDomain(habit)
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.conn(ctx).ExecContext(ctx, query,
 		model.Name, model.Icon, model.Description, model.Frequency, model.FrequencyConfig,
 		model.TargetCount, model.TimeOfDay, model.ReminderTime,
 		model.CurrentStreak, model.LongestStreak, model.IsActive, model.LifeAreaID, time.Now(), model.ID)
@@ -88,8 +100,8 @@ func (r *postgresRepository) Update(ctx context.Context, habit *domain.Habit) er
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM habits WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	query := `UPDATE habits SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.conn(ctx).ExecContext(ctx, query, time.Now(), id)
 	return err
 }
 
@@ -119,7 +131,7 @@ func (r *postgresRepository) LogHabit(ctx context.Context, habitID int, logDate
 	if notes != "" {
 		notesPtr = &notes
 	}
-	_, err = r.db.ExecContext(ctx, query, habitID, logDate.Format("2006-01-02"), count, notesPtr, count > 0, time.Now())
+	_, err = r.conn(ctx).ExecContext(ctx, query, habitID, logDate.Format("2006-01-02"), count, notesPtr, count > 0, time.Now())
 	return err
 }
 
@@ -149,14 +161,14 @@ func (r *postgresRepository) SkipHabit(ctx context.Context, habitID int, logDate
 	if notes != "" {
 		notesPtr = &notes
 	}
-	_, err = r.db.ExecContext(ctx, query, habitID, logDate.Format("2006-01-02"), notesPtr, time.Now())
+	_, err = r.conn(ctx).ExecContext(ctx, query, habitID, logDate.Format("2006-01-02"), notesPtr, time.Now())
 	return err
 }
 
 func (r *postgresRepository) GetLogsForDate(ctx context.Context, habitID int, date time.Time) (*HabitLogModel, error) {
 	query := `SELECT id, habit_id, log_date, count, notes, is_completed, skipped, created_at FROM habit_logs WHERE habit_id = $1 AND log_date = $2`
 	var model HabitLogModel
-	err := r.db.GetContext(ctx, &model, query, habitID, date.Format("2006-01-02"))
+	err := r.conn(ctx).GetContext(ctx, &model, query, habitID, date.Format("2006-01-02"))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -169,7 +181,7 @@ func (r *postgresRepository) GetLogsForDate(ctx context.Context, habitID int, da
 func (r *postgresRepository) GetLogsByDateRange(ctx context.Context, habitID int, start, end time.Time) ([]*HabitLogModel, error) {
 	query := `SELECT id, habit_id, log_date, count, notes, is_completed, created_at FROM habit_logs WHERE habit_id = $1 AND log_date BETWEEN $2 AND $3 ORDER BY log_date`
 	var models []*HabitLogModel
-	err := r.db.SelectContext(ctx, &models, query, habitID, start, end)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, habitID, start, end)
 	if err != nil {
 		return nil, err
 	}
@@ -179,13 +191,13 @@ func (r *postgresRepository) GetLogsByDateRange(ctx context.Context, habitID int
 func (r *postgresRepository) HasLogForToday(ctx context.Context, habitID int) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM habit_logs WHERE habit_id = $1 AND log_date = CURRENT_DATE AND is_completed = true)`
 	var exists bool
-	err := r.db.GetContext(ctx, &exists, query, habitID)
+	err := r.conn(ctx).GetContext(ctx, &exists, query, habitID)
 	return exists, err
 }
 
 func (r *postgresRepository) HasSkippedToday(ctx context.Context, habitID int) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM habit_logs WHERE habit_id = $1 AND log_date = CURRENT_DATE AND skipped = true)`
 	var exists bool
-	err := r.db.GetContext(ctx, &exists, query, habitID)
+	err := r.conn(ctx).GetContext(ctx, &exists, query, habitID)
 	return exists, err
 }
@@ -4,13 +4,34 @@ import (
 	"context"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/domain"
 )
 
+// ListSpec whitelists the filters and sort keys of GET /habits
+var ListSpec = &query.Spec[domain.Habit]{
+	Filters: map[string]query.Filter{
+		"frequency":    {Column: "frequency", Op: query.Eq, Values: []string{"daily", "weekly", "custom"}},
+		"is_active":    {Column: "is_active", Op: query.Eq, Kind: query.Bool},
+		"life_area_id": {Column: "life_area_id", Op: query.Eq, Kind: query.Int},
+		"q":            {Column: "name", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.Habit]{
+		"created_at":     {Column: "created_at", Kind: query.Time, Value: func(h *domain.Habit) any { return h.CreatedAt }},
+		"updated_at":     {Column: "updated_at", Kind: query.Time, Value: func(h *domain.Habit) any { return h.UpdatedAt }},
+		"name":           {Column: "name", Value: func(h *domain.Habit) any { return h.Name }},
+		"current_streak": {Column: "current_streak", Kind: query.Int, Value: func(h *domain.Habit) any { return h.CurrentStreak }},
+		"longest_streak": {Column: "longest_streak", Kind: query.Int, Value: func(h *domain.Habit) any { return h.LongestStreak }},
+	},
+	DefaultSort: "-created_at",
+	ID:          func(h *domain.Habit) int { return h.ID },
+}
+
 type HabitRepository interface {
 	Create(ctx context.Context, habit *domain.Habit) (*domain.Habit, error)
 	GetByID(ctx context.Context, id int) (*domain.Habit, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.Habit, error)
+	// List returns one page of the user's habits together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.Habit]) ([]*domain.Habit, int, error)
 	GetActiveHabits(ctx context.Context, userID int) ([]*domain.Habit, error)
 	Update(ctx context.Context, habit *domain.Habit) error
 	Delete(ctx context.Context, id int) error
@@ -5,22 +5,26 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 )
 
 type habitService struct {
-	repo        repository.HabitRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.HabitRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
 }
 
-func NewHabitService(repo repository.HabitRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) HabitService {
-	return &habitService{repo: repo, logger: logger, broadcaster: broadcaster}
+func NewHabitService(repo repository.HabitRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) HabitService {
+	return &habitService{repo: repo, uow: uow, logger: logger, bus: bus}
 }
 
 func (s *habitService) Create(ctx context.Context, req *dto.CreateHabitRequest, userID int) (*dto.HabitResponse, error) {
@@ -54,19 +58,31 @@ func (s *habitService) Create(ctx context.Context, req *dto.CreateHabitRequest,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
-	created, err := s.repo.Create(ctx, habit)
+	var (
+		created  *domain.Habit
+		response *dto.HabitResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, habit); err != nil {
+			return err
+		}
+		response = dto.ToHabitResponse(created, false, false)
+		if s.bus == nil {
+			return nil
+		}
+		return s.bus.Publish(ctx, userID, events.HabitCreated{
+			HabitID: created.ID,
+			Habit:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create habit", err, map[string]interface{}{"user_id": userID, "action": "CREATE_HABIT_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Habit created", map[string]interface{}{"user_id": userID, "habit_id": created.ID, "action": "CREATE_HABIT_SUCCESS"})
-	
-	response := dto.ToHabitResponse(created, false, false)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventHabitCreated, map[string]interface{}{
-			"habit_id": created.ID,
-			"habit":    response,
-		})
+
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventHabitCreated,
 			"user_id":    userID,
@@ -94,18 +110,19 @@ func (s *habitService) GetByID(ctx context.Context, id, userID int) (*dto.HabitR
 	return dto.ToHabitResponse(habit, completedToday, skippedToday), nil
 }
 
-func (s *habitService) GetAll(ctx context.Context, userID int) ([]*dto.HabitResponse, error) {
-	habits, err := s.repo.GetByUserID(ctx, userID)
+func (s *habitService) GetAll(ctx context.Context, q *query.Query[domain.Habit], userID int) (*dto.HabitListResponse, error) {
+	habits, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
+	habits, page := q.Result(habits, total)
 	result := make([]*dto.HabitResponse, len(habits))
 	for i, h := range habits {
 		completedToday, _ := s.repo.HasLogForToday(ctx, h.ID)
 		skippedToday, _ := s.repo.HasSkippedToday(ctx, h.ID)
 		result[i] = dto.ToHabitResponse(h, completedToday, skippedToday)
 	}
-	return result, nil
+	return &dto.HabitListResponse{Items: result, PageInfo: page}, nil
 }
 
 func (s *habitService) GetActive(ctx context.Context, userID int) ([]*dto.HabitResponse, error) {
@@ -124,74 +141,83 @@ func (s *habitService) GetActive(ctx context.Context, userID int) ([]*dto.HabitR
 
 func (s *habitService) Update(ctx context.Context, id int, req *dto.UpdateHabitRequest, userID int) (*dto.HabitResponse, error) {
 	s.logger.Info("Updating habit", map[string]interface{}{"user_id": userID, "habit_id": id, "action": "UPDATE_HABIT"})
-	habit, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if habit == nil {
-		return nil, errors.New("habit not found")
-	}
-	if habit.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
-	if req.Name != nil {
-		habit.Name = *req.Name
-	}
-	if req.Description != nil {
-		habit.Description = *req.Description
-	}
-	if req.Frequency != nil {
-		habit.Frequency = *req.Frequency
-	}
-	if req.TargetCount != nil {
-		habit.TargetCount = *req.TargetCount
-	}
-	if req.IsActive != nil {
-		habit.IsActive = *req.IsActive
-	}
-	if req.LifeAreaID != nil {
-		habit.LifeAreaID = req.LifeAreaID
-	}
-	if req.Icon != nil {
-		habit.Icon = *req.Icon
-	}
-	if req.TimeOfDay != nil {
-		habit.TimeOfDay = *req.TimeOfDay
-	}
-	if req.ReminderTime != nil {
-		habit.ReminderTime = *req.ReminderTime
-	}
-	if req.TargetDays != nil {
-		habit.TargetCount = *req.TargetDays
-	}
+	var response *dto.HabitResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		habit, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if habit == nil {
+			return errors.New("habit not found")
+		}
+		if habit.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		completedToday, _ := s.repo.HasLogForToday(ctx, id)
+		skippedToday, _ := s.repo.HasSkippedToday(ctx, id)
+		before := dto.ToHabitResponse(habit, completedToday, skippedToday)
+		if req.Name != nil {
+			habit.Name = *req.Name
+		}
+		if req.Description != nil {
+			habit.Description = *req.Description
+		}
+		if req.Frequency != nil {
+			habit.Frequency = *req.Frequency
+		}
+		if req.TargetCount != nil {
+			habit.TargetCount = *req.TargetCount
+		}
+		if req.IsActive != nil {
+			habit.IsActive = *req.IsActive
+		}
+		if req.LifeAreaID != nil {
+			habit.LifeAreaID = req.LifeAreaID
+		}
+		if req.Icon != nil {
+			habit.Icon = *req.Icon
+		}
+		if req.TimeOfDay != nil {
+			habit.TimeOfDay = *req.TimeOfDay
+		}
+		if req.ReminderTime != nil {
+			habit.ReminderTime = *req.ReminderTime
+		}
+		if req.TargetDays != nil {
+			habit.TargetCount = *req.TargetDays
+		}
 
-	if req.FrequencyDays != nil || req.IntervalDays != nil {
-		if habit.FrequencyConfig == nil {
-			habit.FrequencyConfig = make(map[string]interface{})
+		if req.FrequencyDays != nil || req.IntervalDays != nil {
+			if habit.FrequencyConfig == nil {
+				habit.FrequencyConfig = make(map[string]interface{})
+			}
+			if req.FrequencyDays != nil {
+				habit.FrequencyConfig["days"] = req.FrequencyDays
+			}
+			if req.IntervalDays != nil {
+				habit.FrequencyConfig["interval"] = *req.IntervalDays
+			}
 		}
-		if req.FrequencyDays != nil {
-			habit.FrequencyConfig["days"] = req.FrequencyDays
+		habit.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, habit); err != nil {
+			return err
 		}
-		if req.IntervalDays != nil {
-			habit.FrequencyConfig["interval"] = *req.IntervalDays
+		response = dto.ToHabitResponse(habit, completedToday, skippedToday)
+		if s.bus == nil {
+			return nil
 		}
-	}
-	habit.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, habit); err != nil {
+		return s.bus.Publish(eventbus.WithBefore(ctx, before), userID, events.HabitUpdated{
+			HabitID: id,
+			Habit:   response,
+		})
+	})
+	if err != nil {
 		s.logger.Error("Failed to update habit", err, map[string]interface{}{"user_id": userID, "habit_id": id, "action": "UPDATE_HABIT_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Habit updated", map[string]interface{}{"user_id": userID, "habit_id": id, "action": "UPDATE_HABIT_SUCCESS"})
-	
-	completedToday, _ := s.repo.HasLogForToday(ctx, id)
-	skippedToday, _ := s.repo.HasSkippedToday(ctx, id)
-	response := dto.ToHabitResponse(habit, completedToday, skippedToday)
-	
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventHabitUpdated, map[string]interface{}{
-			"habit_id": id,
-			"habit":    response,
-		})
+
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventHabitUpdated,
 			"user_id":    userID,
@@ -205,27 +231,38 @@ func (s *habitService) Update(ctx context.Context, id int, req *dto.UpdateHabitR
 
 func (s *habitService) Delete(ctx context.Context, id, userID int) error {
 	s.logger.Info("Deleting habit", map[string]interface{}{"user_id": userID, "habit_id": id, "action": "DELETE_HABIT"})
-	habit, err := s.repo.GetByID(ctx, id)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		habit, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if habit == nil {
+			return errors.New("habit not found")
+		}
+		if habit.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		completedToday, _ := s.repo.HasLogForToday(ctx, id)
+		skippedToday, _ := s.repo.HasSkippedToday(ctx, id)
+		before := dto.ToHabitResponse(habit, completedToday, skippedToday)
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if s.bus == nil {
+			return nil
+		}
+		return s.bus.Publish(eventbus.WithBefore(ctx, before), userID, events.HabitDeleted{
+			HabitID: id,
+			Title:   habit.Name,
+		})
+	})
 	if err != nil {
-		return err
-	}
-	if habit == nil {
-		return errors.New("habit not found")
-	}
-	if habit.UserID != userID {
-		return errors.New("unauthorized")
-	}
-	if err := s.repo.Delete(ctx, id); err != nil {
 		s.logger.Error("Failed to delete habit", err, map[string]interface{}{"user_id": userID, "habit_id": id, "action": "DELETE_HABIT_FAILED"})
 		return err
 	}
 	s.logger.Info("Habit deleted", map[string]interface{}{"user_id": userID, "habit_id": id, "action": "DELETE_HABIT_SUCCESS"})
-	
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventHabitDeleted, map[string]interface{}{
-			"habit_id": id,
-			"title":    habit.Name,
-		})
+
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventHabitDeleted,
 			"user_id":    userID,
@@ -239,77 +276,131 @@ func (s *habitService) Delete(ctx context.Context, id, userID int) error {
 
 func (s *habitService) LogHabit(ctx context.Context, id int, req *dto.LogHabitRequest, userID int) error {
 	s.logger.Info("Logging habit", map[string]interface{}{"user_id": userID, "habit_id": id, "count": req.Count, "action": "LOG_HABIT"})
-	habit, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if habit == nil {
-		return errors.New("habit not found")
-	}
-	if habit.UserID != userID {
-		return errors.New("unauthorized")
-	}
-	
-	// Check if habit is already completed or skipped today - prevent multiple actions in the same day
-	alreadyCompleted, err := s.repo.HasLogForToday(ctx, id)
-	if err != nil {
-		return err
-	}
-	if alreadyCompleted {
-		return errors.New("habit already completed today")
-	}
-	
-	alreadySkipped, err := s.repo.HasSkippedToday(ctx, id)
-	if err != nil {
-		return err
-	}
-	if alreadySkipped {
-		return errors.New("habit already skipped today")
-	}
-	
 	today := time.Now().Truncate(24 * time.Hour)
-	if err := s.repo.LogHabit(ctx, id, today, req.Count, req.Notes); err != nil {
+	var (
+		habit           *domain.Habit
+		wasCompleted    bool
+		streakIncreased bool
+		milestone       bool
+	)
+
+	// The checks, the log, the streak and their events commit together
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		habit, err = s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if habit == nil {
+			return errors.New("habit not found")
+		}
+		if habit.UserID != userID {
+			return errors.New("unauthorized")
+		}
+
+		// Check if habit is already completed or skipped today - prevent multiple actions in the same day
+		alreadyCompleted, err := s.repo.HasLogForToday(ctx, id)
+		if err != nil {
+			return err
+		}
+		if alreadyCompleted {
+			return errors.New("habit already completed today")
+		}
+
+		alreadySkipped, err := s.repo.HasSkippedToday(ctx, id)
+		if err != nil {
+			return err
+		}
+		if alreadySkipped {
+			return errors.New("habit already skipped today")
+		}
+		before := dto.ToHabitResponse(habit, false, false)
+
+		// Check if habit was completed (count >= target)
+		wasCompleted = req.Count >= habit.TargetCount
+		oldStreak := habit.CurrentStreak
+
+		if err := s.repo.LogHabit(ctx, id, today, req.Count, req.Notes); err != nil {
+			return err
+		}
+
+		if wasCompleted {
+			habit.IncrementStreak()
+			if err := s.repo.Update(ctx, habit); err != nil {
+				return err
+			}
+		}
+		streakIncreased = wasCompleted && habit.CurrentStreak > oldStreak
+		milestone = wasCompleted && habit.CurrentStreak%10 == 0 && habit.CurrentStreak > 0
+
+		if s.bus == nil {
+			return nil
+		}
+
+		completedToday, _ := s.repo.HasLogForToday(ctx, id)
+		skippedToday, _ := s.repo.HasSkippedToday(ctx, id)
+		habitResponse := dto.ToHabitResponse(habit, completedToday, skippedToday)
+
+		// Always broadcast habit completion event
+		published := []events.Event{
+			events.HabitCompleted{
+				HabitID: id,
+				Habit:   habitResponse,
+				Streak:  habit.CurrentStreak,
+			},
+			events.HabitLogged{
+				HabitID:   id,
+				Date:      today,
+				Completed: wasCompleted,
+				Streak:    habit.CurrentStreak,
+			},
+		}
+		// Broadcast streak increased event if streak changed
+		if streakIncreased {
+			published = append(published, events.HabitStreakIncreased{
+				HabitID: id,
+				Habit:   habitResponse,
+				Streak:  habit.CurrentStreak,
+			})
+		}
+		// Broadcast milestone event if reached milestone
+		if milestone {
+			published = append(published, events.HabitMilestone{
+				HabitID:       id,
+				Habit:         habitResponse,
+				Streak:        habit.CurrentStreak,
+				Milestone:     habit.CurrentStreak,
+				MilestoneName: notification.GetMilestoneName(habit.CurrentStreak),
+			})
+		}
+		for i, event := range published {
+			eventCtx := ctx
+			if i == 0 {
+				eventCtx = eventbus.WithBefore(ctx, before)
+			}
+			if err := s.bus.Publish(eventCtx, userID, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		s.logger.Error("Failed to log habit", err, map[string]interface{}{"user_id": userID, "habit_id": id, "action": "LOG_HABIT_FAILED"})
 		return err
 	}
-	
-	// Check if habit was completed (count >= target)
-	wasCompleted := req.Count >= habit.TargetCount
-	oldStreak := habit.CurrentStreak
-	
+
 	if wasCompleted {
-		habit.IncrementStreak()
-		if err := s.repo.Update(ctx, habit); err != nil {
-			return err
-		}
 		s.logger.Info("Habit streak incremented", map[string]interface{}{"habit_id": id, "streak": habit.CurrentStreak, "action": "STREAK_INCREMENT"})
 	}
 
-	// Always broadcast habit completion event
-	if s.broadcaster != nil {
-		completedToday, _ := s.repo.HasLogForToday(ctx, id)
-		skippedToday, _ := s.repo.HasSkippedToday(ctx, id)
-		habitResponse := dto.ToHabitResponse(habit, completedToday, skippedToday)
-		
-		s.broadcaster.Publish(userID, notification.EventHabitCompleted, map[string]interface{}{
-			"habit_id": id,
-			"habit":    habitResponse,
-			"streak":   habit.CurrentStreak,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventHabitCompleted,
 			"user_id":    userID,
 			"entity_id":  id,
 			"action":     "WS_EVENT_PUBLISHED",
 		})
-
-		// Broadcast streak increased event if streak changed
-		if wasCompleted && habit.CurrentStreak > oldStreak {
-			s.broadcaster.Publish(userID, notification.EventStreakIncreased, map[string]interface{}{
-				"habit_id": id,
-				"habit":    habitResponse,
-				"streak":   habit.CurrentStreak,
-			})
+		if streakIncreased {
 			s.logger.Info("WebSocket event published", map[string]interface{}{
 				"event_type": notification.EventStreakIncreased,
 				"user_id":    userID,
@@ -317,15 +408,7 @@ func (s *habitService) LogHabit(ctx context.Context, id int, req *dto.LogHabitRe
 				"action":     "WS_EVENT_PUBLISHED",
 			})
 		}
-
-		// Broadcast milestone event if reached milestone
-		if wasCompleted && habit.CurrentStreak%10 == 0 && habit.CurrentStreak > 0 {
-			s.broadcaster.Publish(userID, notification.EventStreakMilestone, map[string]interface{}{
-				"habit_id":  id,
-				"habit":     habitResponse,
-				"streak":    habit.CurrentStreak,
-				"milestone": notification.GetMilestoneName(habit.CurrentStreak),
-			})
+		if milestone {
 			s.logger.Info("WebSocket event published", map[string]interface{}{
 				"event_type": notification.EventStreakMilestone,
 				"user_id":    userID,
@@ -334,7 +417,7 @@ func (s *habitService) LogHabit(ctx context.Context, id int, req *dto.LogHabitRe
 			})
 		}
 	}
-	
+
 	s.logger.Info("Habit logged", map[string]interface{}{"user_id": userID, "habit_id": id, "action": "LOG_HABIT_SUCCESS"})
 	return nil
 }
@@ -381,52 +464,70 @@ func (s *habitService) Complete(ctx context.Context, id int, req *dto.LogHabitRe
 
 func (s *habitService) SkipHabit(ctx context.Context, id int, userID int) error {
 	s.logger.Info("Skipping habit", map[string]interface{}{"user_id": userID, "habit_id": id, "action": "SKIP_HABIT"})
-	habit, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if habit == nil {
-		return errors.New("habit not found")
-	}
-	if habit.UserID != userID {
-		return errors.New("unauthorized")
-	}
-	
-	// Check if habit is already completed or skipped today - prevent multiple actions in the same day
-	alreadyCompleted, err := s.repo.HasLogForToday(ctx, id)
-	if err != nil {
-		return err
-	}
-	if alreadyCompleted {
-		return errors.New("habit already completed today - cannot skip")
-	}
-	
-	alreadySkipped, err := s.repo.HasSkippedToday(ctx, id)
-	if err != nil {
-		return err
-	}
-	if alreadySkipped {
-		return errors.New("habit already skipped today")
-	}
-	
 	today := time.Now().Truncate(24 * time.Hour)
-	if err := s.repo.SkipHabit(ctx, id, today, ""); err != nil {
-		s.logger.Error("Failed to skip habit", err, map[string]interface{}{"user_id": userID, "habit_id": id, "action": "SKIP_HABIT_FAILED"})
-		return err
-	}
-	
-	s.logger.Info("Habit skipped", map[string]interface{}{"user_id": userID, "habit_id": id, "action": "SKIP_HABIT_SUCCESS"})
-	
-	// Broadcast skip event
-	if s.broadcaster != nil {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		habit, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if habit == nil {
+			return errors.New("habit not found")
+		}
+		if habit.UserID != userID {
+			return errors.New("unauthorized")
+		}
+
+		// Check if habit is already completed or skipped today - prevent multiple actions in the same day
+		alreadyCompleted, err := s.repo.HasLogForToday(ctx, id)
+		if err != nil {
+			return err
+		}
+		if alreadyCompleted {
+			return errors.New("habit already completed today - cannot skip")
+		}
+
+		alreadySkipped, err := s.repo.HasSkippedToday(ctx, id)
+		if err != nil {
+			return err
+		}
+		if alreadySkipped {
+			return errors.New("habit already skipped today")
+		}
+		before := dto.ToHabitResponse(habit, false, false)
+
+		if err := s.repo.SkipHabit(ctx, id, today, ""); err != nil {
+			return err
+		}
+		if s.bus == nil {
+			return nil
+		}
+
 		completedToday, _ := s.repo.HasLogForToday(ctx, id)
 		skippedToday, _ := s.repo.HasSkippedToday(ctx, id)
 		habitResponse := dto.ToHabitResponse(habit, completedToday, skippedToday)
-		
-		s.broadcaster.Publish(userID, notification.EventHabitSkipped, map[string]interface{}{
-			"habit_id": id,
-			"habit":    habitResponse,
+
+		// Broadcast skip event
+		if err := s.bus.Publish(eventbus.WithBefore(ctx, before), userID, events.HabitSkipped{
+			HabitID: id,
+			Habit:   habitResponse,
+		}); err != nil {
+			return err
+		}
+		return s.bus.Publish(ctx, userID, events.HabitLogged{
+			HabitID: id,
+			Date:    today,
+			Skipped: true,
+			Streak:  habit.CurrentStreak,
 		})
+	})
+	if err != nil {
+		s.logger.Error("Failed to skip habit", err, map[string]interface{}{"user_id": userID, "habit_id": id, "action": "SKIP_HABIT_FAILED"})
+		return err
+	}
+
+	s.logger.Info("Habit skipped", map[string]interface{}{"user_id": userID, "habit_id": id, "action": "SKIP_HABIT_SUCCESS"})
+
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventHabitSkipped,
 			"user_id":    userID,
@@ -434,6 +535,6 @@ func (s *habitService) SkipHabit(ctx context.Context, id int, userID int) error
 			"action":     "WS_EVENT_PUBLISHED",
 		})
 	}
-	
+
 	return nil
 }
@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/middleware"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/job/service"
 	"github.com/gorilla/mux"
 )
@@ -19,12 +21,12 @@ func NewHandler(service service.JobService) *Handler {
 	return &Handler{service: service}
 }
 
-// RegisterRoutes registers job routes
+// RegisterRoutes registers job routes; all of them are admin-only
 func (h *Handler) RegisterRoutes(r *mux.Router) {
-	r.HandleFunc("/jobs", h.ListJobs).Methods("GET")
-	r.HandleFunc("/jobs/{job_name}/trigger", h.TriggerJob).Methods("POST")
-	r.HandleFunc("/jobs/{job_name}/status", h.GetJobStatus).Methods("GET")
-	r.HandleFunc("/jobs/{job_name}/history", h.GetJobHistory).Methods("GET")
+	r.HandleFunc("/jobs", middleware.Require(authz.JobsRead, h.ListJobs)).Methods("GET")
+	r.HandleFunc("/jobs/{job_name}/trigger", middleware.Require(authz.JobsRun, h.TriggerJob)).Methods("POST")
+	r.HandleFunc("/jobs/{job_name}/status", middleware.Require(authz.JobsRead, h.GetJobStatus)).Methods("GET")
+	r.HandleFunc("/jobs/{job_name}/history", middleware.Require(authz.JobsRead, h.GetJobHistory)).Methods("GET")
 }
 
 // ListJobs lists all registered jobs
@@ -0,0 +1,66 @@
+package jobimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	authService "github.com/M1ralai/go-modular-monolith-template/internal/modules/auth/service"
+)
+
+// AuthTokenCleanupJob deletes expired refresh tokens, token revocation entries, password reset
+// tokens, abandoned OIDC logins and long inactive personal access tokens
+type AuthTokenCleanupJob struct {
+	jobs.BaseJob
+	logger       *logger.ZapLogger
+	service      authService.AuthService
+	passwords    authService.PasswordService
+	accessTokens authService.AccessTokenService
+	oidc         authService.OIDCService
+}
+
+// NewAuthTokenCleanupJob creates a job that runs daily at 3:30 AM
+func NewAuthTokenCleanupJob(logger *logger.ZapLogger, service authService.AuthService, passwords authService.PasswordService, accessTokens authService.AccessTokenService, oidc authService.OIDCService) *AuthTokenCleanupJob {
+	return &AuthTokenCleanupJob{
+		BaseJob:      jobs.NewBaseJob("auth_token_cleanup", "0 30 3 * * *", 5*time.Minute, nil),
+		logger:       logger,
+		service:      service,
+		passwords:    passwords,
+		accessTokens: accessTokens,
+		oidc:         oidc,
+	}
+}
+
+func (j *AuthTokenCleanupJob) Execute(ctx context.Context) error {
+	deleted, err := j.service.PurgeExpiredTokens(ctx)
+	if err == nil {
+		var resets int64
+		resets, err = j.passwords.PurgeExpired(ctx)
+		deleted += resets
+	}
+	if err == nil {
+		var accessTokens int64
+		accessTokens, err = j.accessTokens.PurgeInactive(ctx)
+		deleted += accessTokens
+	}
+	if err == nil {
+		var states int64
+		states, err = j.oidc.PurgeExpiredStates(ctx)
+		deleted += states
+	}
+	if err != nil {
+		j.logger.Error("Auth token cleanup failed", err, map[string]interface{}{
+			"job":    j.Name(),
+			"action": "AUTH_TOKEN_CLEANUP_FAILED",
+		})
+		return err
+	}
+
+	j.logger.Info("Expired auth tokens deleted", map[string]interface{}{
+		"job":     j.Name(),
+		"deleted": deleted,
+		"action":  "AUTH_TOKEN_CLEANUP_COMPLETED",
+	})
+	return nil
+}
@@ -0,0 +1,46 @@
+package jobimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
+)
+
+// DeferredNotificationJob delivers notifications held back during quiet hours once the period ends
+type DeferredNotificationJob struct {
+	jobs.BaseJob
+	logger      *logger.ZapLogger
+	broadcaster *notifService.Broadcaster
+}
+
+// NewDeferredNotificationJob creates a job that runs every minute
+func NewDeferredNotificationJob(logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) *DeferredNotificationJob {
+	return &DeferredNotificationJob{
+		BaseJob:     jobs.NewBaseJob("deferred_notification_flush", "0 * * * * *", 30*time.Second, nil),
+		logger:      logger,
+		broadcaster: broadcaster,
+	}
+}
+
+func (j *DeferredNotificationJob) Execute(ctx context.Context) error {
+	delivered, err := j.broadcaster.FlushDeferred(ctx)
+	if err != nil {
+		j.logger.Error("Deferred notification flush failed", err, map[string]interface{}{
+			"job":    j.Name(),
+			"action": "DEFERRED_NOTIFICATION_FLUSH_FAILED",
+		})
+		return err
+	}
+
+	if delivered > 0 {
+		j.logger.Info("Deferred notifications flushed", map[string]interface{}{
+			"job":       j.Name(),
+			"delivered": delivered,
+			"action":    "DEFERRED_NOTIFICATION_FLUSH_COMPLETED",
+		})
+	}
+	return nil
+}
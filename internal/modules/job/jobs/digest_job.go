@@ -0,0 +1,46 @@
+package jobimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	digestService "github.com/M1ralai/go-modular-monolith-template/internal/modules/digest/service"
+)
+
+// DigestJob sends daily and weekly digests whose local send time has passed
+type DigestJob struct {
+	jobs.BaseJob
+	logger *logger.ZapLogger
+	sender *digestService.Sender
+}
+
+// NewDigestJob creates a job that runs every 5 minutes
+func NewDigestJob(logger *logger.ZapLogger, sender *digestService.Sender) *DigestJob {
+	return &DigestJob{
+		BaseJob: jobs.NewBaseJob("digest_dispatch", "0 */5 * * * *", 4*time.Minute, nil),
+		logger:  logger,
+		sender:  sender,
+	}
+}
+
+func (j *DigestJob) Execute(ctx context.Context) error {
+	sent, err := j.sender.SendDue(ctx)
+	if err != nil {
+		j.logger.Error("Digest run failed", err, map[string]interface{}{
+			"job":    j.Name(),
+			"action": "DIGEST_RUN_FAILED",
+		})
+		return err
+	}
+
+	if sent > 0 {
+		j.logger.Info("Digests sent", map[string]interface{}{
+			"job":    j.Name(),
+			"sent":   sent,
+			"action": "DIGEST_RUN_COMPLETED",
+		})
+	}
+	return nil
+}
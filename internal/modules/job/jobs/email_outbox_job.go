@@ -0,0 +1,46 @@
+package jobimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/mailer"
+)
+
+// EmailOutboxJob sends queued emails from the outbox, retrying failed ones
+type EmailOutboxJob struct {
+	jobs.BaseJob
+	logger *logger.ZapLogger
+	outbox *mailer.Outbox
+}
+
+// NewEmailOutboxJob creates a job that runs every 30 seconds
+func NewEmailOutboxJob(logger *logger.ZapLogger, outbox *mailer.Outbox) *EmailOutboxJob {
+	return &EmailOutboxJob{
+		BaseJob: jobs.NewBaseJob("email_outbox_dispatch", "*/30 * * * * *", 2*time.Minute, nil),
+		logger:  logger,
+		outbox:  outbox,
+	}
+}
+
+func (j *EmailOutboxJob) Execute(ctx context.Context) error {
+	sent, err := j.outbox.Dispatch(ctx)
+	if err != nil {
+		j.logger.Error("Email outbox dispatch failed", err, map[string]interface{}{
+			"job":    j.Name(),
+			"action": "EMAIL_OUTBOX_DISPATCH_FAILED",
+		})
+		return err
+	}
+
+	if sent > 0 {
+		j.logger.Info("Email outbox dispatched", map[string]interface{}{
+			"job":    j.Name(),
+			"sent":   sent,
+			"action": "EMAIL_OUTBOX_DISPATCH_COMPLETED",
+		})
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package jobimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+)
+
+// eventOutboxRetention is how long published events stay in the outbox
+const eventOutboxRetention = 7 * 24 * time.Hour
+
+// EventOutboxPurgeJob deletes relayed events from the outbox once they are past retention
+type EventOutboxPurgeJob struct {
+	jobs.BaseJob
+	logger *logger.ZapLogger
+	outbox *eventbus.Outbox
+}
+
+// NewEventOutboxPurgeJob creates a job that runs daily at 3:30 AM
+func NewEventOutboxPurgeJob(logger *logger.ZapLogger, outbox *eventbus.Outbox) *EventOutboxPurgeJob {
+	return &EventOutboxPurgeJob{
+		BaseJob: jobs.NewBaseJob("event_outbox_purge", "0 30 3 * * *", 5*time.Minute, nil),
+		logger:  logger,
+		outbox:  outbox,
+	}
+}
+
+func (j *EventOutboxPurgeJob) Execute(ctx context.Context) error {
+	purged, err := j.outbox.Purge(ctx, time.Now().Add(-eventOutboxRetention))
+	if err != nil {
+		j.logger.Error("Event outbox purge failed", err, map[string]interface{}{
+			"job":    j.Name(),
+			"action": "EVENT_OUTBOX_PURGE_FAILED",
+		})
+		return err
+	}
+
+	j.logger.Info("Event outbox purged", map[string]interface{}{
+		"job":    j.Name(),
+		"purged": purged,
+		"action": "EVENT_OUTBOX_PURGE_COMPLETED",
+	})
+	return nil
+}
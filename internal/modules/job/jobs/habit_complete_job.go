@@ -5,22 +5,26 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/repository"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
 )
 
 // HabitCompleteJob completes a habit asynchronously
 type HabitCompleteJob struct {
 	jobs.BaseJob
-	logger      *logger.ZapLogger
-	repo        repository.HabitRepository
-	broadcaster *notifService.Broadcaster
-	habitID     int
-	userID      int
-	request     *dto.LogHabitRequest
+	logger  *logger.ZapLogger
+	repo    repository.HabitRepository
+	uow     *database.UnitOfWork
+	bus     *eventbus.Bus
+	habitID int
+	userID  int
+	request *dto.LogHabitRequest
 }
 
 // LockKey returns a unique lock key for this job instance
@@ -32,18 +36,20 @@ func (j *HabitCompleteJob) LockKey() int64 {
 func NewHabitCompleteJob(
 	logger *logger.ZapLogger,
 	repo repository.HabitRepository,
-	broadcaster *notifService.Broadcaster,
+	uow *database.UnitOfWork,
+	bus *eventbus.Bus,
 	habitID, userID int,
 	request *dto.LogHabitRequest,
 ) *HabitCompleteJob {
 	return &HabitCompleteJob{
-		BaseJob:     jobs.NewBaseJob("habit_complete", "", 30*time.Second, nil),
-		logger:      logger,
-		repo:        repo,
-		broadcaster: broadcaster,
-		habitID:     habitID,
-		userID:      userID,
-		request:     request,
+		BaseJob: jobs.NewBaseJob("habit_complete", "", 30*time.Second, nil),
+		logger:  logger,
+		repo:    repo,
+		uow:     uow,
+		bus:     bus,
+		habitID: habitID,
+		userID:  userID,
+		request: request,
 	}
 }
 
@@ -117,56 +123,70 @@ func (j *HabitCompleteJob) Execute(ctx context.Context) error {
 		count = habit.TargetCount
 	}
 
-	// Log habit for today
+	// Log habit for today; the streak update and events commit or roll back together
 	today := time.Now().Truncate(24 * time.Hour)
-	if err := j.repo.LogHabit(dbCtx, j.habitID, today, count, j.request.Notes); err != nil {
-		j.logger.Error("Failed to log habit in job", err, map[string]interface{}{
-			"habit_id": j.habitID,
-			"user_id":  j.userID,
-			"action":   "HABIT_COMPLETE_JOB_LOG_FAILED",
-		})
-		return err
-	}
-
-	// If count >= target, increment streak
 	oldStreak := habit.CurrentStreak
-	if count >= habit.TargetCount {
-		habit.IncrementStreak()
-		if err := j.repo.Update(dbCtx, habit); err != nil {
-			j.logger.Error("Failed to update habit streak in job", err, map[string]interface{}{
-				"habit_id": j.habitID,
-				"user_id":  j.userID,
-				"action":   "HABIT_COMPLETE_JOB_UPDATE_FAILED",
-			})
+	err = j.uow.Do(dbCtx, func(ctx context.Context) error {
+		if err := j.repo.LogHabit(ctx, j.habitID, today, count, j.request.Notes); err != nil {
 			return err
 		}
-	}
 
-	// Broadcast WebSocket message
-	if j.broadcaster != nil {
-		j.broadcaster.Publish(j.userID, "habit.completed", map[string]interface{}{
-			"habit_id": j.habitID,
-			"title":    habit.Name,
-			"streak":   habit.CurrentStreak,
-		})
+		// If count >= target, increment streak
+		if count >= habit.TargetCount {
+			habit.IncrementStreak()
+			if err := j.repo.Update(ctx, habit); err != nil {
+				return err
+			}
+		}
 
+		if j.bus == nil {
+			return nil
+		}
+		published := []events.Event{
+			events.HabitCompleted{
+				HabitID: j.habitID,
+				Title:   habit.Name,
+				Streak:  habit.CurrentStreak,
+			},
+			events.HabitLogged{
+				HabitID:   j.habitID,
+				Date:      today,
+				Completed: count >= habit.TargetCount,
+				Streak:    habit.CurrentStreak,
+			},
+		}
 		// If streak increased, notify
 		if habit.CurrentStreak > oldStreak {
-			j.broadcaster.Publish(j.userID, "habit.streak_increased", map[string]interface{}{
-				"habit_id": j.habitID,
-				"streak":   habit.CurrentStreak,
+			published = append(published, events.HabitStreakIncreased{
+				HabitID: j.habitID,
+				Streak:  habit.CurrentStreak,
 			})
 
 			// Check for milestone (every 10 days)
 			if habit.CurrentStreak%10 == 0 && habit.CurrentStreak > 0 {
-				j.broadcaster.Publish(j.userID, "habit.milestone", map[string]interface{}{
-					"habit_id":  j.habitID,
-					"title":     habit.Name,
-					"streak":    habit.CurrentStreak,
-					"milestone": habit.CurrentStreak,
+				published = append(published, events.HabitMilestone{
+					HabitID:       j.habitID,
+					Title:         habit.Name,
+					Streak:        habit.CurrentStreak,
+					Milestone:     habit.CurrentStreak,
+					MilestoneName: notification.GetMilestoneName(habit.CurrentStreak),
 				})
 			}
 		}
+		for _, event := range published {
+			if err := j.bus.Publish(ctx, j.userID, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		j.logger.Error("Failed to log habit in job", err, map[string]interface{}{
+			"habit_id": j.habitID,
+			"user_id":  j.userID,
+			"action":   "HABIT_COMPLETE_JOB_LOG_FAILED",
+		})
+		return err
 	}
 
 	j.logger.Info("Habit complete job completed", map[string]interface{}{
@@ -5,20 +5,23 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/habit/repository"
 )
 
 // HabitSkipJob skips a habit asynchronously
 type HabitSkipJob struct {
 	jobs.BaseJob
-	logger      *logger.ZapLogger
-	repo        repository.HabitRepository
-	broadcaster *notifService.Broadcaster
-	habitID     int
-	userID      int
+	logger  *logger.ZapLogger
+	repo    repository.HabitRepository
+	uow     *database.UnitOfWork
+	bus     *eventbus.Bus
+	habitID int
+	userID  int
 }
 
 // LockKey returns a unique lock key for this job instance
@@ -30,16 +33,18 @@ func (j *HabitSkipJob) LockKey() int64 {
 func NewHabitSkipJob(
 	logger *logger.ZapLogger,
 	repo repository.HabitRepository,
-	broadcaster *notifService.Broadcaster,
+	uow *database.UnitOfWork,
+	bus *eventbus.Bus,
 	habitID, userID int,
 ) *HabitSkipJob {
 	return &HabitSkipJob{
-		BaseJob:     jobs.NewBaseJob("habit_skip", "", 30*time.Second, nil),
-		logger:      logger,
-		repo:        repo,
-		broadcaster: broadcaster,
-		habitID:     habitID,
-		userID:      userID,
+		BaseJob: jobs.NewBaseJob("habit_skip", "", 30*time.Second, nil),
+		logger:  logger,
+		repo:    repo,
+		uow:     uow,
+		bus:     bus,
+		habitID: habitID,
+		userID:  userID,
 	}
 }
 
@@ -86,9 +91,29 @@ func (j *HabitSkipJob) Execute(ctx context.Context) error {
 		return err
 	}
 
-	// Skip habit for today
+	// Skip habit for today; the events are recorded with the skip so they are sent only if it commits
 	today := time.Now().Truncate(24 * time.Hour)
-	if err := j.repo.SkipHabit(dbCtx, j.habitID, today, ""); err != nil {
+	err = j.uow.Do(dbCtx, func(ctx context.Context) error {
+		if err := j.repo.SkipHabit(ctx, j.habitID, today, ""); err != nil {
+			return err
+		}
+		if j.bus == nil {
+			return nil
+		}
+		if err := j.bus.Publish(ctx, j.userID, events.HabitSkipped{
+			HabitID: j.habitID,
+			Title:   habit.Name,
+		}); err != nil {
+			return err
+		}
+		return j.bus.Publish(ctx, j.userID, events.HabitLogged{
+			HabitID: j.habitID,
+			Date:    today,
+			Skipped: true,
+			Streak:  habit.CurrentStreak,
+		})
+	})
+	if err != nil {
 		j.logger.Error("Failed to skip habit in job", err, map[string]interface{}{
 			"habit_id": j.habitID,
 			"user_id":  j.userID,
@@ -97,14 +122,6 @@ func (j *HabitSkipJob) Execute(ctx context.Context) error {
 		return err
 	}
 
-	// Broadcast WebSocket message
-	if j.broadcaster != nil {
-		j.broadcaster.Publish(j.userID, "habit.skipped", map[string]interface{}{
-			"habit_id": j.habitID,
-			"title":    habit.Name,
-		})
-	}
-
 	j.logger.Info("Habit skip job completed", map[string]interface{}{
 		"habit_id": j.habitID,
 		"user_id":  j.userID,
@@ -0,0 +1,44 @@
+package jobimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	trashService "github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/service"
+)
+
+// TrashPurgeJob deletes items for good once they have been in the trash past the retention period
+type TrashPurgeJob struct {
+	jobs.BaseJob
+	logger  *logger.ZapLogger
+	service trashService.TrashService
+}
+
+// NewTrashPurgeJob creates a job that runs daily at 4:00 AM
+func NewTrashPurgeJob(logger *logger.ZapLogger, service trashService.TrashService) *TrashPurgeJob {
+	return &TrashPurgeJob{
+		BaseJob: jobs.NewBaseJob("trash_purge", "0 0 4 * * *", 10*time.Minute, nil),
+		logger:  logger,
+		service: service,
+	}
+}
+
+func (j *TrashPurgeJob) Execute(ctx context.Context) error {
+	purged, err := j.service.PurgeExpired(ctx)
+	if err != nil {
+		j.logger.Error("Trash purge failed", err, map[string]interface{}{
+			"job":    j.Name(),
+			"action": "TRASH_PURGE_FAILED",
+		})
+		return err
+	}
+
+	j.logger.Info("Trash purged", map[string]interface{}{
+		"job":    j.Name(),
+		"purged": purged,
+		"action": "TRASH_PURGE_COMPLETED",
+	})
+	return nil
+}
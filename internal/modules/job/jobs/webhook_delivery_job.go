@@ -0,0 +1,46 @@
+package jobimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	webhookService "github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/service"
+)
+
+// WebhookDeliveryJob sends due webhook deliveries, including retries and redeliveries
+type WebhookDeliveryJob struct {
+	jobs.BaseJob
+	logger     *logger.ZapLogger
+	dispatcher *webhookService.Dispatcher
+}
+
+// NewWebhookDeliveryJob creates a job that runs every 5 seconds
+func NewWebhookDeliveryJob(logger *logger.ZapLogger, dispatcher *webhookService.Dispatcher) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{
+		BaseJob:    jobs.NewBaseJob("webhook_delivery", "*/5 * * * * *", time.Minute, nil),
+		logger:     logger,
+		dispatcher: dispatcher,
+	}
+}
+
+func (j *WebhookDeliveryJob) Execute(ctx context.Context) error {
+	delivered, err := j.dispatcher.DeliverDue(ctx)
+	if err != nil {
+		j.logger.Error("Webhook delivery run failed", err, map[string]interface{}{
+			"job":    j.Name(),
+			"action": "WEBHOOK_DELIVERY_RUN_FAILED",
+		})
+		return err
+	}
+
+	if delivered > 0 {
+		j.logger.Info("Webhook deliveries sent", map[string]interface{}{
+			"job":       j.Name(),
+			"delivered": delivered,
+			"action":    "WEBHOOK_DELIVERY_RUN_COMPLETED",
+		})
+	}
+	return nil
+}
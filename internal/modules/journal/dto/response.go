@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/domain"
 )
 
@@ -17,6 +18,12 @@ type JournalResponse struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// JournalListResponse is one page of GET /journal
+type JournalListResponse struct {
+	Items []*JournalResponse `json:"items"`
+	query.PageInfo
+}
+
 func ToJournalResponse(j *domain.JournalEntry) *JournalResponse {
 	if j == nil {
 		return nil
@@ -8,6 +8,7 @@ import (
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/service"
 	"github.com/gorilla/mux"
 )
@@ -56,8 +57,15 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, entry, http.StatusOK, "Günlük getirildi")
 }
 
+// GetAll returns one page of the caller's journal entries
+// GET /api/journal?date_after=2026-01-01&q=trip&sort=-entry_date&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	entries, err := h.service.GetAll(r.Context(), h.getUserID(r))
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+	entries, err := h.service.GetAll(r.Context(), q, h.getUserID(r))
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Günlükler getirilemedi", err.Error())
 		return
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/domain"
 	"github.com/jmoiron/sqlx"
 )
@@ -14,12 +16,17 @@ type postgresRepository struct{ db *sqlx.DB }
 
 func NewPostgresRepository(db *sqlx.DB) JournalRepository { return &postgresRepository{db: db} }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, entry *domain.JournalEntry) (*domain.JournalEntry, error) {
 	query := `INSERT INTO journal_entries (user_id, entry_date, content, mood, energy_level, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at, updated_at`
 	now := time.Now()
 	model := FromDomain(entry)
-	err := r.db.QueryRowxContext(ctx, query, model.UserID, model.EntryDate, model.Content, model.Mood, model.EnergyLevel, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
+	err := r.conn(ctx).QueryRowxContext(ctx, query, model.UserID, model.EntryDate, model.Content, model.Mood, model.EnergyLevel, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -27,9 +34,9 @@ func (r *postgresRepository) Create(ctx context.Context, entry *domain.JournalEn
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.JournalEntry, error) {
-	query := `SELECT id, user_id, entry_date, content, mood, energy_level, created_at, updated_at FROM journal_entries WHERE id = $1`
+	query := `SELECT id, user_id, entry_date, content, mood, energy_level, created_at, updated_at FROM journal_entries WHERE id = $1 AND deleted_at IS NULL`
 	var model JournalModel
-	if err := r.db.GetContext(ctx, &model, query, id); err != nil {
+	if err := r.conn(ctx).GetContext(ctx, &model, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -38,23 +45,29 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Journ
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.JournalEntry, error) {
-	query := `SELECT id, user_id, entry_date, content, mood, energy_level, created_at, updated_at FROM journal_entries WHERE user_id = $1 ORDER BY entry_date DESC`
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.JournalEntry]) ([]*domain.JournalEntry, int, error) {
+	where, args := q.Where(`WHERE user_id = $1 AND deleted_at IS NULL`, userID)
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM journal_entries `+where, args...); err != nil {
+		return nil, 0, err
+	}
+	page, args := q.Paginate(where, args)
+	sqlQuery := `SELECT id, user_id, entry_date, content, mood, energy_level, created_at, updated_at FROM journal_entries ` + page
 	var models []JournalModel
-	if err := r.db.SelectContext(ctx, &models, query, userID); err != nil {
-		return nil, err
+	if err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...); err != nil {
+		return nil, 0, err
 	}
 	entries := make([]*domain.JournalEntry, len(models))
 	for i, m := range models {
 		entries[i] = m.ToDomain()
 	}
-	return entries, nil
+	return entries, total, nil
 }
 
 func (r *postgresRepository) GetByDate(ctx context.Context, userID int, date time.Time) (*domain.JournalEntry, error) {
-	query := `SELECT id, user_id, entry_date, content, mood, energy_level, created_at, updated_at FROM journal_entries WHERE user_id = $1 AND entry_date = $2`
+	query := `SELECT id, user_id, entry_date, content, mood, energy_level, created_at, updated_at FROM journal_entries WHERE user_id = $1 AND deleted_at IS NULL AND entry_date = $2`
 	var model JournalModel
-	if err := r.db.GetContext(ctx, &model, query, userID, date); err != nil {
+	if err := r.conn(ctx).GetContext(ctx, &model, query, userID, date); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -64,9 +77,9 @@ func (r *postgresRepository) GetByDate(ctx context.Context, userID int, date tim
 }
 
 func (r *postgresRepository) GetByDateRange(ctx context.Context, userID int, start, end time.Time) ([]*domain.JournalEntry, error) {
-	query := `SELECT id, user_id, entry_date, content, mood, energy_level, created_at, updated_at FROM journal_entries WHERE user_id = $1 AND entry_date BETWEEN $2 AND $3 ORDER BY entry_date DESC`
+	query := `SELECT id, user_id, entry_date, content, mood, energy_level, created_at, updated_at FROM journal_entries WHERE user_id = $1 AND deleted_at IS NULL AND entry_date BETWEEN $2 AND $3 ORDER BY entry_date DESC`
 	var models []JournalModel
-	if err := r.db.SelectContext(ctx, &models, query, userID, start, end); err != nil {
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID, start, end); err != nil {
 		return nil, err
 	}
 	entries := make([]*domain.JournalEntry, len(models))
@@ -79,11 +92,11 @@ func (r *postgresRepository) GetByDateRange(ctx context.Context, userID int, sta
 func (r *postgresRepository) Update(ctx context.Context, entry *domain.JournalEntry) error {
 	query := `UPDATE journal_entries SET content = $1, mood = $2, energy_level = $3, updated_at = $4 WHERE id = $5`
 	model := FromDomain(entry)
-	_, err := r.db.ExecContext(ctx, query, model.Content, model.Mood, model.EnergyLevel, time.Now(), model.ID)
+	_, err := r.conn(ctx).ExecContext(ctx, query, model.Content, model.Mood, model.EnergyLevel, time.Now(), model.ID)
 	return err
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM journal_entries WHERE id = $1`, id)
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE journal_entries SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now(), id)
 	return err
 }
@@ -4,13 +4,32 @@ import (
 	"context"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/domain"
 )
 
+// ListSpec whitelists the filters and sort keys of GET /journal
+var ListSpec = &query.Spec[domain.JournalEntry]{
+	Filters: map[string]query.Filter{
+		"mood":        {Column: "mood", Op: query.Eq},
+		"date_after":  {Column: "entry_date", Op: query.Gte, Kind: query.Time},
+		"date_before": {Column: "entry_date", Op: query.Lt, Kind: query.Time},
+		"q":           {Column: "content", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.JournalEntry]{
+		"entry_date": {Column: "entry_date", Kind: query.Time, Value: func(e *domain.JournalEntry) any { return e.EntryDate }},
+		"created_at": {Column: "created_at", Kind: query.Time, Value: func(e *domain.JournalEntry) any { return e.CreatedAt }},
+		"updated_at": {Column: "updated_at", Kind: query.Time, Value: func(e *domain.JournalEntry) any { return e.UpdatedAt }},
+	},
+	DefaultSort: "-entry_date",
+	ID:          func(e *domain.JournalEntry) int { return e.ID },
+}
+
 type JournalRepository interface {
 	Create(ctx context.Context, entry *domain.JournalEntry) (*domain.JournalEntry, error)
 	GetByID(ctx context.Context, id int) (*domain.JournalEntry, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.JournalEntry, error)
+	// List returns one page of the user's journal entries together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.JournalEntry]) ([]*domain.JournalEntry, int, error)
 	GetByDate(ctx context.Context, userID int, date time.Time) (*domain.JournalEntry, error)
 	GetByDateRange(ctx context.Context, userID int, start, end time.Time) ([]*domain.JournalEntry, error)
 	Update(ctx context.Context, entry *domain.JournalEntry) error
@@ -5,40 +5,53 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/journal/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 )
 
 type journalService struct {
-	repo        repository.JournalRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.JournalRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
 }
 
-func NewJournalService(repo repository.JournalRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) JournalService {
-	return &journalService{repo: repo, logger: logger, broadcaster: broadcaster}
+func NewJournalService(repo repository.JournalRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) JournalService {
+	return &journalService{repo: repo, uow: uow, logger: logger, bus: bus}
 }
 
 func (s *journalService) Create(ctx context.Context, req *dto.CreateJournalRequest, userID int) (*dto.JournalResponse, error) {
 	s.logger.Info("Creating journal entry", map[string]interface{}{"user_id": userID, "date": req.EntryDate, "action": "CREATE_JOURNAL"})
 	now := time.Now()
 	entry := &domain.JournalEntry{UserID: userID, EntryDate: req.EntryDate, Content: req.Content, Mood: req.Mood, EnergyLevel: req.EnergyLevel, CreatedAt: now, UpdatedAt: now}
-	created, err := s.repo.Create(ctx, entry)
+	var (
+		created  *domain.JournalEntry
+		response *dto.JournalResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, entry); err != nil {
+			return err
+		}
+		response = dto.ToJournalResponse(created)
+		return s.publish(ctx, userID, events.JournalCreated{
+			JournalID: created.ID,
+			Journal:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create journal", err, map[string]interface{}{"user_id": userID, "action": "CREATE_JOURNAL_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Journal created", map[string]interface{}{"user_id": userID, "journal_id": created.ID, "action": "CREATE_JOURNAL_SUCCESS"})
-	response := dto.ToJournalResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventJournalCreated, map[string]interface{}{
-			"journal_id": created.ID,
-			"journal":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventJournalCreated,
 			"user_id":    userID,
@@ -63,47 +76,55 @@ func (s *journalService) GetByID(ctx context.Context, id, userID int) (*dto.Jour
 	return dto.ToJournalResponse(entry), nil
 }
 
-func (s *journalService) GetAll(ctx context.Context, userID int) ([]*dto.JournalResponse, error) {
-	entries, err := s.repo.GetByUserID(ctx, userID)
+func (s *journalService) GetAll(ctx context.Context, q *query.Query[domain.JournalEntry], userID int) (*dto.JournalListResponse, error) {
+	entries, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
-	return dto.ToJournalResponseList(entries), nil
+	entries, page := q.Result(entries, total)
+	return &dto.JournalListResponse{Items: dto.ToJournalResponseList(entries), PageInfo: page}, nil
 }
 
 func (s *journalService) Update(ctx context.Context, id int, req *dto.UpdateJournalRequest, userID int) (*dto.JournalResponse, error) {
 	s.logger.Info("Updating journal", map[string]interface{}{"user_id": userID, "journal_id": id, "action": "UPDATE_JOURNAL"})
-	entry, err := s.repo.GetByID(ctx, id)
+	var response *dto.JournalResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		entry, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return errors.New("journal entry not found")
+		}
+		if entry.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToJournalResponse(entry)
+		if req.Content != nil {
+			entry.Content = *req.Content
+		}
+		if req.Mood != nil {
+			entry.Mood = *req.Mood
+		}
+		if req.EnergyLevel != nil {
+			entry.EnergyLevel = *req.EnergyLevel
+		}
+		entry.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, entry); err != nil {
+			return err
+		}
+		response = dto.ToJournalResponse(entry)
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.JournalUpdated{
+			JournalID: id,
+			Journal:   response,
+		})
+	})
 	if err != nil {
-		return nil, err
-	}
-	if entry == nil {
-		return nil, errors.New("journal entry not found")
-	}
-	if entry.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
-	if req.Content != nil {
-		entry.Content = *req.Content
-	}
-	if req.Mood != nil {
-		entry.Mood = *req.Mood
-	}
-	if req.EnergyLevel != nil {
-		entry.EnergyLevel = *req.EnergyLevel
-	}
-	entry.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, entry); err != nil {
 		s.logger.Error("Failed to update journal", err, map[string]interface{}{"user_id": userID, "journal_id": id, "action": "UPDATE_JOURNAL_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Journal updated", map[string]interface{}{"user_id": userID, "journal_id": id, "action": "UPDATE_JOURNAL_SUCCESS"})
-	response := dto.ToJournalResponse(entry)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventJournalUpdated, map[string]interface{}{
-			"journal_id": id,
-			"journal":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventJournalUpdated,
 			"user_id":    userID,
@@ -116,25 +137,31 @@ func (s *journalService) Update(ctx context.Context, id int, req *dto.UpdateJour
 
 func (s *journalService) Delete(ctx context.Context, id, userID int) error {
 	s.logger.Info("Deleting journal", map[string]interface{}{"user_id": userID, "journal_id": id, "action": "DELETE_JOURNAL"})
-	entry, err := s.repo.GetByID(ctx, id)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		entry, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return errors.New("journal entry not found")
+		}
+		if entry.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToJournalResponse(entry)
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.JournalDeleted{
+			JournalID: id,
+		})
+	})
 	if err != nil {
-		return err
-	}
-	if entry == nil {
-		return errors.New("journal entry not found")
-	}
-	if entry.UserID != userID {
-		return errors.New("unauthorized")
-	}
-	if err := s.repo.Delete(ctx, id); err != nil {
 		s.logger.Error("Failed to delete journal", err, map[string]interface{}{"user_id": userID, "journal_id": id, "action": "DELETE_JOURNAL_FAILED"})
 		return err
 	}
 	s.logger.Info("Journal deleted", map[string]interface{}{"user_id": userID, "journal_id": id, "action": "DELETE_JOURNAL_SUCCESS"})
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventJournalDeleted, map[string]interface{}{
-			"journal_id": id,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventJournalDeleted,
 			"user_id":    userID,
@@ -144,3 +171,12 @@ func (s *journalService) Delete(ctx context.Context, id, userID int) error {
 	}
 	return nil
 }
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *journalService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
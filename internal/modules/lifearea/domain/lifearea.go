@@ -9,5 +9,7 @@ type LifeArea struct {
 	Icon         string
 	Color        string
 	DisplayOrder int
-	CreatedAt    time.Time
+	// LastActivityAt is the last day a habit in the area was completed
+	LastActivityAt *time.Time
+	CreatedAt      time.Time
 }
@@ -3,28 +3,38 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/domain"
 )
 
 type LifeAreaResponse struct {
-	ID           int       `json:"id"`
-	UserID       int       `json:"user_id"`
-	Name         string    `json:"name"`
-	Icon         string    `json:"icon,omitempty"`
-	Color        string    `json:"color,omitempty"`
-	DisplayOrder int       `json:"display_order"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           int    `json:"id"`
+	UserID       int    `json:"user_id"`
+	Name         string `json:"name"`
+	Icon         string `json:"icon,omitempty"`
+	Color        string `json:"color,omitempty"`
+	DisplayOrder int    `json:"display_order"`
+	// LastActivityAt is the last day a habit in the area was completed
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// LifeAreaListResponse is one page of GET /life-areas
+type LifeAreaListResponse struct {
+	Items []*LifeAreaResponse `json:"items"`
+	query.PageInfo
 }
 
 func ToLifeAreaResponse(la *domain.LifeArea) *LifeAreaResponse {
 	return &LifeAreaResponse{
-		ID:           la.ID,
-		UserID:       la.UserID,
-		Name:         la.Name,
-		Icon:         la.Icon,
-		Color:        la.Color,
-		DisplayOrder: la.DisplayOrder,
-		CreatedAt:    la.CreatedAt,
+		ID:             la.ID,
+		UserID:         la.UserID,
+		Name:           la.Name,
+		Icon:           la.Icon,
+		Color:          la.Color,
+		DisplayOrder:   la.DisplayOrder,
+		LastActivityAt: la.LastActivityAt,
+		CreatedAt:      la.CreatedAt,
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/service"
 	"github.com/gorilla/mux"
 )
@@ -80,9 +81,17 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, lifeArea, http.StatusOK, "Hayat alanı getirildi")
 }
 
+// GetAll returns one page of the caller's life areas
+// GET /api/life-areas?q=health&sort=-last_activity_at&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+
 	userID := h.getUserID(r)
-	areas, err := h.service.GetByUserID(r.Context(), userID)
+	areas, err := h.service.GetByUserID(r.Context(), q, userID)
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Hayat alanları getirilemedi", err.Error())
 		return
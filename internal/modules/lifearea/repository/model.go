@@ -7,13 +7,14 @@ import (
 )
 
 type LifeAreaModel struct {
-	ID           int       `db:"id"`
-	UserID       int       `db:"user_id"`
-	Name         string    `db:"name"`
-	Icon         *string   `db:"icon"`
-	Color        *string   `db:"color"`
-	DisplayOrder int       `db:"display_order"`
-	CreatedAt    time.Time `db:"created_at"`
+	ID             int        `db:"id"`
+	UserID         int        `db:"user_id"`
+	Name           string     `db:"name"`
+	Icon           *string    `db:"icon"`
+	Color          *string    `db:"color"`
+	DisplayOrder   int        `db:"display_order"`
+	LastActivityAt *time.Time `db:"last_activity_at"`
+	CreatedAt      time.Time  `db:"created_at"`
 }
 
 func (m *LifeAreaModel) ToDomain() *domain.LifeArea {
@@ -27,13 +28,14 @@ func (m *LifeAreaModel) ToDomain() *domain.LifeArea {
 	}
 
 	return &domain.LifeArea{
-		ID:           m.ID,
-		UserID:       m.UserID,
-		Name:         m.Name,
-		Icon:         icon,
-		Color:        color,
-		DisplayOrder: m.DisplayOrder,
-		CreatedAt:    m.CreatedAt,
+		ID:             m.ID,
+		UserID:         m.UserID,
+		Name:           m.Name,
+		Icon:           icon,
+		Color:          color,
+		DisplayOrder:   m.DisplayOrder,
+		LastActivityAt: m.LastActivityAt,
+		CreatedAt:      m.CreatedAt,
 	}
 }
 
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/domain"
 	"github.com/jmoiron/sqlx"
 )
@@ -18,6 +20,11 @@ func NewPostgresRepository(db *sqlx.DB) LifeAreaRepository {
 	return &postgresRepository{db: db}
 }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, lifeArea *domain.LifeArea) (*domain.LifeArea, error) {
 	query := `
 		INSERT INTO life_areas (user_id, name, icon, color, display_order, created_at)
@@ -28,7 +35,7 @@ func (r *postgresRepository) Create(ctx context.Context, lifeArea *domain.LifeAr
 	now := time.Now()
 	model := FromDomain(lifeArea)
 
-	err := r.db.QueryRowxContext(
+	err := r.conn(ctx).QueryRowxContext(
 		ctx, query,
 		model.UserID,
 		model.Name,
@@ -47,13 +54,13 @@ func (r *postgresRepository) Create(ctx context.Context, lifeArea *domain.LifeAr
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.LifeArea, error) {
 	query := `
-		SELECT id, user_id, name, icon, color, display_order, created_at
+		SELECT id, user_id, name, icon, color, display_order, last_activity_at, created_at
 		FROM life_areas
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var model LifeAreaModel
-	err := r.db.GetContext(ctx, &model, query, id)
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -64,18 +71,24 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.LifeA
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.LifeArea, error) {
-	query := `
-		SELECT id, user_id, name, icon, color, display_order, created_at
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.LifeArea]) ([]*domain.LifeArea, int, error) {
+	where, args := q.Where(`WHERE user_id = $1 AND deleted_at IS NULL`, userID)
+
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM life_areas `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	page, args := q.Paginate(where, args)
+	sqlQuery := `
+		SELECT id, user_id, name, icon, color, display_order, last_activity_at, created_at
 		FROM life_areas
-		WHERE user_id = $1
-		ORDER BY display_order ASC, created_at ASC
-	`
+		` + page
 
 	var models []LifeAreaModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	areas := make([]*domain.LifeArea, len(models))
@@ -83,7 +96,7 @@ func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*do
 		areas[i] = m.ToDomain()
 	}
 
-	return areas, nil
+	return areas, total, nil
 }
 
 func (r *postgresRepository) Update(ctx context.Context, lifeArea *domain.LifeArea) error {
@@ -94,7 +107,7 @@ func (r *postgresRepository) Update(ctx context.Context, lifeArea *domain.LifeAr
 	`
 
 	model := FromDomain(lifeArea)
-	_, err := r.db.ExecContext(
+	_, err := r.conn(ctx).ExecContext(
 		ctx, query,
 		model.Name,
 		model.Icon,
@@ -106,8 +119,21 @@ func (r *postgresRepository) Update(ctx context.Context, lifeArea *domain.LifeAr
 	return err
 }
 
+// RecordHabitActivity moves the last activity of the habit's life area forward to the day;
+// an earlier day, or a habit outside any life area, changes nothing
+func (r *postgresRepository) RecordHabitActivity(ctx context.Context, habitID int, day time.Time) error {
+	query := `
+		UPDATE life_areas
+		SET last_activity_at = $2
+		WHERE id = (SELECT life_area_id FROM habits WHERE id = $1)
+		AND (last_activity_at IS NULL OR last_activity_at < $2)
+	`
+	_, err := r.conn(ctx).ExecContext(ctx, query, habitID, day)
+	return err
+}
+
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM life_areas WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	query := `UPDATE life_areas SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.conn(ctx).ExecContext(ctx, query, time.Now(), id)
 	return err
 }
@@ -2,14 +2,34 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/domain"
 )
 
+// ListSpec whitelists the filters and sort keys of GET /life-areas
+var ListSpec = &query.Spec[domain.LifeArea]{
+	Filters: map[string]query.Filter{
+		"q": {Column: "name", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.LifeArea]{
+		"display_order":    {Column: "display_order", Kind: query.Int, Value: func(la *domain.LifeArea) any { return la.DisplayOrder }},
+		"name":             {Column: "name", Value: func(la *domain.LifeArea) any { return la.Name }},
+		"created_at":       {Column: "created_at", Kind: query.Time, Value: func(la *domain.LifeArea) any { return la.CreatedAt }},
+		"last_activity_at": {Column: "last_activity_at", Kind: query.Time, Nullable: true, Value: func(la *domain.LifeArea) any { return la.LastActivityAt }},
+	},
+	DefaultSort: "display_order,created_at",
+	ID:          func(la *domain.LifeArea) int { return la.ID },
+}
+
 type LifeAreaRepository interface {
 	Create(ctx context.Context, lifeArea *domain.LifeArea) (*domain.LifeArea, error)
 	GetByID(ctx context.Context, id int) (*domain.LifeArea, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.LifeArea, error)
+	// List returns one page of the user's life areas together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.LifeArea]) ([]*domain.LifeArea, int, error)
 	Update(ctx context.Context, lifeArea *domain.LifeArea) error
 	Delete(ctx context.Context, id int) error
+	// RecordHabitActivity notes that a habit of the life area was completed on the day
+	RecordHabitActivity(ctx context.Context, habitID int, day time.Time) error
 }
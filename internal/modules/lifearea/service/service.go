@@ -3,13 +3,24 @@ package service
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/dto"
 )
 
 type LifeAreaService interface {
 	Create(ctx context.Context, req *dto.CreateLifeAreaRequest, userID int) (*dto.LifeAreaResponse, error)
 	GetByID(ctx context.Context, id, userID int) (*dto.LifeAreaResponse, error)
-	GetByUserID(ctx context.Context, userID int) ([]*dto.LifeAreaResponse, error)
+	GetByUserID(ctx context.Context, q *query.Query[domain.LifeArea], userID int) (*dto.LifeAreaListResponse, error)
 	Update(ctx context.Context, id int, req *dto.UpdateLifeAreaRequest, userID int) (*dto.LifeAreaResponse, error)
 	Delete(ctx context.Context, id, userID int) error
+	HandleHabitLogged(ctx context.Context, userID int, event events.HabitLogged) error
+}
+
+// Subscribe registers the life area module's reactions to other modules' events. Recording
+// habit activity is a single update, so it runs as the event is dispatched.
+func Subscribe(bus *eventbus.Bus, svc LifeAreaService) {
+	eventbus.Subscribe(bus, "lifearea.habit_logged", svc.HandleHabitLogged)
 }
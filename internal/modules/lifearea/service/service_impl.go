@@ -5,25 +5,30 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/lifearea/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 )
 
 type lifeAreaService struct {
-	repo        repository.LifeAreaRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.LifeAreaRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
 }
 
-func NewLifeAreaService(repo repository.LifeAreaRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) LifeAreaService {
+func NewLifeAreaService(repo repository.LifeAreaRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) LifeAreaService {
 	return &lifeAreaService{
-		repo:        repo,
-		logger:      logger,
-		broadcaster: broadcaster,
+		repo:   repo,
+		uow:    uow,
+		logger: logger,
+		bus:    bus,
 	}
 }
 
@@ -43,7 +48,21 @@ func (s *lifeAreaService) Create(ctx context.Context, req *dto.CreateLifeAreaReq
 		CreatedAt:    time.Now(),
 	}
 
-	created, err := s.repo.Create(ctx, lifeArea)
+	var (
+		created  *domain.LifeArea
+		response *dto.LifeAreaResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, lifeArea); err != nil {
+			return err
+		}
+		response = dto.ToLifeAreaResponse(created)
+		return s.publish(ctx, userID, events.LifeAreaCreated{
+			LifeAreaID: created.ID,
+			LifeArea:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("failed to create life area", err, map[string]interface{}{
 			"user_id": userID,
@@ -59,12 +78,7 @@ func (s *lifeAreaService) Create(ctx context.Context, req *dto.CreateLifeAreaReq
 		"action":       "CREATE_LIFE_AREA",
 	})
 
-	response := dto.ToLifeAreaResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventLifeAreaCreated, map[string]interface{}{
-			"lifearea_id": created.ID,
-			"lifearea":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventLifeAreaCreated,
 			"user_id":    userID,
@@ -91,13 +105,13 @@ func (s *lifeAreaService) GetByID(ctx context.Context, id, userID int) (*dto.Lif
 	return dto.ToLifeAreaResponse(lifeArea), nil
 }
 
-func (s *lifeAreaService) GetByUserID(ctx context.Context, userID int) ([]*dto.LifeAreaResponse, error) {
-	areas, err := s.repo.GetByUserID(ctx, userID)
+func (s *lifeAreaService) GetByUserID(ctx context.Context, q *query.Query[domain.LifeArea], userID int) (*dto.LifeAreaListResponse, error) {
+	areas, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
-
-	return dto.ToLifeAreaResponseList(areas), nil
+	areas, page := q.Result(areas, total)
+	return &dto.LifeAreaListResponse{Items: dto.ToLifeAreaResponseList(areas), PageInfo: page}, nil
 }
 
 func (s *lifeAreaService) Update(ctx context.Context, id int, req *dto.UpdateLifeAreaRequest, userID int) (*dto.LifeAreaResponse, error) {
@@ -107,31 +121,43 @@ func (s *lifeAreaService) Update(ctx context.Context, id int, req *dto.UpdateLif
 		"action":       "UPDATE_LIFE_AREA",
 	})
 
-	lifeArea, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if lifeArea == nil {
-		return nil, errors.New("life area not found")
-	}
-	if lifeArea.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
+	var response *dto.LifeAreaResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		lifeArea, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if lifeArea == nil {
+			return errors.New("life area not found")
+		}
+		if lifeArea.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToLifeAreaResponse(lifeArea)
 
-	if req.Name != nil {
-		lifeArea.Name = *req.Name
-	}
-	if req.Icon != nil {
-		lifeArea.Icon = *req.Icon
-	}
-	if req.Color != nil {
-		lifeArea.Color = *req.Color
-	}
-	if req.DisplayOrder != nil {
-		lifeArea.DisplayOrder = *req.DisplayOrder
-	}
+		if req.Name != nil {
+			lifeArea.Name = *req.Name
+		}
+		if req.Icon != nil {
+			lifeArea.Icon = *req.Icon
+		}
+		if req.Color != nil {
+			lifeArea.Color = *req.Color
+		}
+		if req.DisplayOrder != nil {
+			lifeArea.DisplayOrder = *req.DisplayOrder
+		}
 
-	if err := s.repo.Update(ctx, lifeArea); err != nil {
+		if err := s.repo.Update(ctx, lifeArea); err != nil {
+			return err
+		}
+		response = dto.ToLifeAreaResponse(lifeArea)
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.LifeAreaUpdated{
+			LifeAreaID: id,
+			LifeArea:   response,
+		})
+	})
+	if err != nil {
 		s.logger.Error("failed to update life area", err, map[string]interface{}{
 			"life_area_id": id,
 			"user_id":      userID,
@@ -146,12 +172,7 @@ func (s *lifeAreaService) Update(ctx context.Context, id int, req *dto.UpdateLif
 		"action":       "UPDATE_LIFE_AREA",
 	})
 
-	response := dto.ToLifeAreaResponse(lifeArea)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventLifeAreaUpdated, map[string]interface{}{
-			"lifearea_id": id,
-			"lifearea":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventLifeAreaUpdated,
 			"user_id":    userID,
@@ -170,18 +191,28 @@ func (s *lifeAreaService) Delete(ctx context.Context, id, userID int) error {
 		"action":       "DELETE_LIFE_AREA",
 	})
 
-	lifeArea, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if lifeArea == nil {
-		return errors.New("life area not found")
-	}
-	if lifeArea.UserID != userID {
-		return errors.New("unauthorized")
-	}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		lifeArea, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if lifeArea == nil {
+			return errors.New("life area not found")
+		}
+		if lifeArea.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToLifeAreaResponse(lifeArea)
 
-	if err := s.repo.Delete(ctx, id); err != nil {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.LifeAreaDeleted{
+			LifeAreaID: id,
+			Name:       lifeArea.Name,
+		})
+	})
+	if err != nil {
 		s.logger.Error("failed to delete life area", err, map[string]interface{}{
 			"life_area_id": id,
 			"user_id":      userID,
@@ -196,11 +227,7 @@ func (s *lifeAreaService) Delete(ctx context.Context, id, userID int) error {
 		"action":       "DELETE_LIFE_AREA",
 	})
 
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventLifeAreaDeleted, map[string]interface{}{
-			"lifearea_id": id,
-			"name":        lifeArea.Name,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventLifeAreaDeleted,
 			"user_id":    userID,
@@ -211,3 +238,21 @@ func (s *lifeAreaService) Delete(ctx context.Context, id, userID int) error {
 
 	return nil
 }
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *lifeAreaService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
+
+// HandleHabitLogged moves the last activity of a completed habit's life area forward. Skips
+// are not activity, and a repeated event changes nothing.
+func (s *lifeAreaService) HandleHabitLogged(ctx context.Context, userID int, event events.HabitLogged) error {
+	if !event.Completed {
+		return nil
+	}
+	return s.repo.RecordHabitActivity(ctx, event.HabitID, event.Date)
+}
@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/domain"
 )
 
@@ -22,6 +23,12 @@ type NoteResponse struct {
 	UpdatedAt     time.Time       `json:"updated_at"`
 }
 
+// NoteListResponse is one page of GET /notes
+type NoteListResponse struct {
+	Items []*NoteResponse `json:"items"`
+	query.PageInfo
+}
+
 type NoteLinkInfo struct {
 	ID       int    `json:"id"`
 	NoteID   int    `json:"note_id"`
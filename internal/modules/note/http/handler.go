@@ -8,6 +8,7 @@ import (
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/service"
 	"github.com/gorilla/mux"
 )
@@ -85,9 +86,17 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, note, http.StatusOK, "Not getirildi")
 }
 
+// GetAll returns one page of the caller's notes
+// GET /api/notes?is_favorite=true&course_id=3&sort=title&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+
 	userID := h.getUserID(r)
-	notes, err := h.service.GetAll(r.Context(), userID)
+	notes, err := h.service.GetAll(r.Context(), q, userID)
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Notlar getirilemedi", err.Error())
 		return
@@ -251,6 +260,14 @@ func (h *Handler) DeleteLink(w http.ResponseWriter, r *http.Request) {
 
 	userID := h.getUserID(r)
 	if err := h.service.DeleteLink(r.Context(), linkID, userID); err != nil {
+		if err.Error() == "note link not found" {
+			utils.ReturnError(w, "NOT_FOUND", "Link bulunamadı", err.Error())
+			return
+		}
+		if err.Error() == "unauthorized" {
+			utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
+			return
+		}
 		utils.ReturnError(w, "INTERNAL_ERROR", "Link silinemedi", err.Error())
 		return
 	}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/domain"
 	"github.com/jmoiron/sqlx"
 )
@@ -18,6 +20,11 @@ func NewPostgresRepository(db *sqlx.DB) NoteRepository {
 	return &postgresRepository{db: db}
 }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, note *domain.Note) (*domain.Note, error) {
 	query := `
 		INSERT INTO notes (user_id, course_id, component_id, life_area_id, title, content, is_favorite, created_at, updated_at)
@@ -28,7 +35,7 @@ func (r *postgresRepository) Create(ctx context.Context, note *domain.Note) (*do
 	now := time.Now()
 	model := FromDomain(note)
 
-	err := r.db.QueryRowxContext(
+	err := r.conn(ctx).QueryRowxContext(
 		ctx, query,
 		model.UserID,
 		model.CourseID,
@@ -52,11 +59,11 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Note,
 	query := `
 		SELECT id, user_id, course_id, component_id, life_area_id, title, content, is_favorite, created_at, updated_at
 		FROM notes
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var model NoteModel
-	err := r.db.GetContext(ctx, &model, query, id)
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -67,18 +74,24 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Note,
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Note, error) {
-	query := `
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.Note]) ([]*domain.Note, int, error) {
+	where, args := q.Where(`WHERE user_id = $1 AND deleted_at IS NULL`, userID)
+
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM notes `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	page, args := q.Paginate(where, args)
+	sqlQuery := `
 		SELECT id, user_id, course_id, component_id, life_area_id, title, content, is_favorite, created_at, updated_at
 		FROM notes
-		WHERE user_id = $1
-		ORDER BY updated_at DESC
-	`
+		` + page
 
 	var models []NoteModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	notes := make([]*domain.Note, len(models))
@@ -86,19 +99,19 @@ func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*do
 		notes[i] = m.ToDomain()
 	}
 
-	return notes, nil
+	return notes, total, nil
 }
 
 func (r *postgresRepository) GetByCourseID(ctx context.Context, courseID int) ([]*domain.Note, error) {
 	query := `
 		SELECT id, user_id, course_id, component_id, life_area_id, title, content, is_favorite, created_at, updated_at
 		FROM notes
-		WHERE course_id = $1
+		WHERE course_id = $1 AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 	`
 
 	var models []NoteModel
-	err := r.db.SelectContext(ctx, &models, query, courseID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, courseID)
 	if err != nil {
 		return nil, err
 	}
@@ -115,12 +128,12 @@ func (r *postgresRepository) GetByLifeAreaID(ctx context.Context, lifeAreaID int
 	query := `
 		SELECT id, user_id, course_id, component_id, life_area_id, title, content, is_favorite, created_at, updated_at
 		FROM notes
-		WHERE life_area_id = $1
+		WHERE life_area_id = $1 AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 	`
 
 	var models []NoteModel
-	err := r.db.SelectContext(ctx, &models, query, lifeAreaID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, lifeAreaID)
 	if err != nil {
 		return nil, err
 	}
@@ -137,12 +150,12 @@ func (r *postgresRepository) GetFavorites(ctx context.Context, userID int) ([]*d
 	query := `
 		SELECT id, user_id, course_id, component_id, life_area_id, title, content, is_favorite, created_at, updated_at
 		FROM notes
-		WHERE user_id = $1 AND is_favorite = true
+		WHERE user_id = $1 AND is_favorite = true AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 	`
 
 	var models []NoteModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -159,13 +172,13 @@ func (r *postgresRepository) Search(ctx context.Context, userID int, query strin
 	sqlQuery := `
 		SELECT id, user_id, course_id, component_id, life_area_id, title, content, is_favorite, created_at, updated_at
 		FROM notes
-		WHERE user_id = $1 AND (title ILIKE $2 OR content ILIKE $2)
+		WHERE user_id = $1 AND deleted_at IS NULL AND (title ILIKE $2 OR content ILIKE $2)
 		ORDER BY updated_at DESC
 	`
 
 	searchPattern := "%" + query + "%"
 	var models []NoteModel
-	err := r.db.SelectContext(ctx, &models, sqlQuery, userID, searchPattern)
+	err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, userID, searchPattern)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +199,7 @@ func (r *postgresRepository) Update(ctx context.Context, note *domain.Note) erro
 	`
 
 	model := FromDomain(note)
-	_, err := r.db.ExecContext(
+	_, err := r.conn(ctx).ExecContext(
 		ctx, query,
 		model.CourseID,
 		model.ComponentID,
@@ -202,8 +215,8 @@ func (r *postgresRepository) Update(ctx context.Context, note *domain.Note) erro
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM notes WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	query := `UPDATE notes SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.conn(ctx).ExecContext(ctx, query, time.Now(), id)
 	return err
 }
 
@@ -217,7 +230,7 @@ func (r *postgresRepository) CreateLink(ctx context.Context, link *domain.NoteLi
 	now := time.Now()
 	model := NoteLinkFromDomain(link)
 
-	err := r.db.QueryRowxContext(
+	err := r.conn(ctx).QueryRowxContext(
 		ctx, query,
 		model.SourceNoteID,
 		model.TargetNoteID,
@@ -232,16 +245,36 @@ func (r *postgresRepository) CreateLink(ctx context.Context, link *domain.NoteLi
 	return model.ToDomain(), nil
 }
 
+func (r *postgresRepository) GetLinkByID(ctx context.Context, id int) (*domain.NoteLink, error) {
+	query := `
+		SELECT id, source_note_id, target_note_id, link_text, created_at
+		FROM note_links
+		WHERE id = $1
+	`
+
+	var model NoteLinkModel
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return model.ToDomain(), nil
+}
+
 func (r *postgresRepository) GetOutgoingLinks(ctx context.Context, noteID int) ([]*domain.NoteLink, error) {
 	query := `
 		SELECT id, source_note_id, target_note_id, link_text, created_at
 		FROM note_links
 		WHERE source_note_id = $1
+		AND EXISTS (SELECT 1 FROM notes n WHERE n.id = target_note_id AND n.deleted_at IS NULL)
 		ORDER BY created_at
 	`
 
 	var models []NoteLinkModel
-	err := r.db.SelectContext(ctx, &models, query, noteID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, noteID)
 	if err != nil {
 		return nil, err
 	}
@@ -259,11 +292,12 @@ func (r *postgresRepository) GetBacklinks(ctx context.Context, noteID int) ([]*d
 		SELECT id, source_note_id, target_note_id, link_text, created_at
 		FROM note_links
 		WHERE target_note_id = $1
+		AND EXISTS (SELECT 1 FROM notes n WHERE n.id = source_note_id AND n.deleted_at IS NULL)
 		ORDER BY created_at
 	`
 
 	var models []NoteLinkModel
-	err := r.db.SelectContext(ctx, &models, query, noteID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, noteID)
 	if err != nil {
 		return nil, err
 	}
@@ -278,12 +312,12 @@ func (r *postgresRepository) GetBacklinks(ctx context.Context, noteID int) ([]*d
 
 func (r *postgresRepository) DeleteLink(ctx context.Context, id int) error {
 	query := `DELETE FROM note_links WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := r.conn(ctx).ExecContext(ctx, query, id)
 	return err
 }
 
 func (r *postgresRepository) DeleteLinksByNote(ctx context.Context, noteID int) error {
 	query := `DELETE FROM note_links WHERE source_note_id = $1 OR target_note_id = $1`
-	_, err := r.db.ExecContext(ctx, query, noteID)
+	_, err := r.conn(ctx).ExecContext(ctx, query, noteID)
 	return err
 }
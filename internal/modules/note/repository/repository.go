@@ -3,13 +3,35 @@ package repository
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/domain"
 )
 
+// ListSpec whitelists the filters and sort keys of GET /notes
+var ListSpec = &query.Spec[domain.Note]{
+	Filters: map[string]query.Filter{
+		"is_favorite":    {Column: "is_favorite", Op: query.Eq, Kind: query.Bool},
+		"course_id":      {Column: "course_id", Op: query.Eq, Kind: query.Int},
+		"component_id":   {Column: "component_id", Op: query.Eq, Kind: query.Int},
+		"life_area_id":   {Column: "life_area_id", Op: query.Eq, Kind: query.Int},
+		"updated_after":  {Column: "updated_at", Op: query.Gte, Kind: query.Time},
+		"updated_before": {Column: "updated_at", Op: query.Lt, Kind: query.Time},
+		"q":              {Column: "title", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.Note]{
+		"updated_at": {Column: "updated_at", Kind: query.Time, Value: func(n *domain.Note) any { return n.UpdatedAt }},
+		"created_at": {Column: "created_at", Kind: query.Time, Value: func(n *domain.Note) any { return n.CreatedAt }},
+		"title":      {Column: "title", Value: func(n *domain.Note) any { return n.Title }},
+	},
+	DefaultSort: "-updated_at",
+	ID:          func(n *domain.Note) int { return n.ID },
+}
+
 type NoteRepository interface {
 	Create(ctx context.Context, note *domain.Note) (*domain.Note, error)
 	GetByID(ctx context.Context, id int) (*domain.Note, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.Note, error)
+	// List returns one page of the user's notes together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.Note]) ([]*domain.Note, int, error)
 	GetByCourseID(ctx context.Context, courseID int) ([]*domain.Note, error)
 	GetByLifeAreaID(ctx context.Context, lifeAreaID int) ([]*domain.Note, error)
 	GetFavorites(ctx context.Context, userID int) ([]*domain.Note, error)
@@ -18,6 +40,7 @@ type NoteRepository interface {
 	Delete(ctx context.Context, id int) error
 
 	CreateLink(ctx context.Context, link *domain.NoteLink) (*domain.NoteLink, error)
+	GetLinkByID(ctx context.Context, id int) (*domain.NoteLink, error)
 	GetOutgoingLinks(ctx context.Context, noteID int) ([]*domain.NoteLink, error)
 	GetBacklinks(ctx context.Context, noteID int) ([]*domain.NoteLink, error)
 	DeleteLink(ctx context.Context, id int) error
@@ -3,13 +3,15 @@ package service
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/dto"
 )
 
 type NoteService interface {
 	Create(ctx context.Context, req *dto.CreateNoteRequest, userID int) (*dto.NoteResponse, error)
 	GetByID(ctx context.Context, id, userID int) (*dto.NoteResponse, error)
-	GetAll(ctx context.Context, userID int) ([]*dto.NoteResponse, error)
+	GetAll(ctx context.Context, q *query.Query[domain.Note], userID int) (*dto.NoteListResponse, error)
 	GetByCourse(ctx context.Context, courseID, userID int) ([]*dto.NoteResponse, error)
 	GetByLifeArea(ctx context.Context, lifeAreaID, userID int) ([]*dto.NoteResponse, error)
 	GetFavorites(ctx context.Context, userID int) ([]*dto.NoteResponse, error)
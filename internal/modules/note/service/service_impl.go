@@ -5,25 +5,30 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/note/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 )
 
 type noteService struct {
-	repo        repository.NoteRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.NoteRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
 }
 
-func NewNoteService(repo repository.NoteRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) NoteService {
+func NewNoteService(repo repository.NoteRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) NoteService {
 	return &noteService{
-		repo:        repo,
-		logger:      logger,
-		broadcaster: broadcaster,
+		repo:   repo,
+		uow:    uow,
+		logger: logger,
+		bus:    bus,
 	}
 }
 
@@ -47,7 +52,21 @@ func (s *noteService) Create(ctx context.Context, req *dto.CreateNoteRequest, us
 		UpdatedAt:   now,
 	}
 
-	created, err := s.repo.Create(ctx, note)
+	var (
+		created  *domain.Note
+		response *dto.NoteResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, note); err != nil {
+			return err
+		}
+		response = dto.ToNoteResponse(created)
+		return s.publish(ctx, userID, events.NoteCreated{
+			NoteID: created.ID,
+			Note:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create note", err, map[string]interface{}{
 			"user_id": userID,
@@ -63,12 +82,7 @@ func (s *noteService) Create(ctx context.Context, req *dto.CreateNoteRequest, us
 		"action":  "CREATE_NOTE_SUCCESS",
 	})
 
-	response := dto.ToNoteResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventNoteCreated, map[string]interface{}{
-			"note_id": created.ID,
-			"note":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventNoteCreated,
 			"user_id":    userID,
@@ -129,13 +143,14 @@ func (s *noteService) GetByID(ctx context.Context, id, userID int) (*dto.NoteRes
 	return response, nil
 }
 
-func (s *noteService) GetAll(ctx context.Context, userID int) ([]*dto.NoteResponse, error) {
-	notes, err := s.repo.GetByUserID(ctx, userID)
+func (s *noteService) GetAll(ctx context.Context, q *query.Query[domain.Note], userID int) (*dto.NoteListResponse, error) {
+	notes, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
 
-	return dto.ToNoteResponseList(notes), nil
+	notes, page := q.Result(notes, total)
+	return &dto.NoteListResponse{Items: dto.ToNoteResponseList(notes), PageInfo: page}, nil
 }
 
 func (s *noteService) GetByCourse(ctx context.Context, courseID, userID int) ([]*dto.NoteResponse, error) {
@@ -195,39 +210,51 @@ func (s *noteService) Update(ctx context.Context, id int, req *dto.UpdateNoteReq
 		"action":  "UPDATE_NOTE",
 	})
 
-	note, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if note == nil {
-		return nil, errors.New("note not found")
-	}
-	if note.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
+	var response *dto.NoteResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		note, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if note == nil {
+			return errors.New("note not found")
+		}
+		if note.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToNoteResponse(note)
 
-	if req.CourseID != nil {
-		note.CourseID = req.CourseID
-	}
-	if req.ComponentID != nil {
-		note.ComponentID = req.ComponentID
-	}
-	if req.LifeAreaID != nil {
-		note.LifeAreaID = req.LifeAreaID
-	}
-	if req.Title != nil {
-		note.Title = *req.Title
-	}
-	if req.Content != nil {
-		note.Content = *req.Content
-	}
-	if req.IsFavorite != nil {
-		note.IsFavorite = *req.IsFavorite
-	}
+		if req.CourseID != nil {
+			note.CourseID = req.CourseID
+		}
+		if req.ComponentID != nil {
+			note.ComponentID = req.ComponentID
+		}
+		if req.LifeAreaID != nil {
+			note.LifeAreaID = req.LifeAreaID
+		}
+		if req.Title != nil {
+			note.Title = *req.Title
+		}
+		if req.Content != nil {
+			note.Content = *req.Content
+		}
+		if req.IsFavorite != nil {
+			note.IsFavorite = *req.IsFavorite
+		}
 
-	note.UpdatedAt = time.Now()
+		note.UpdatedAt = time.Now()
 
-	if err := s.repo.Update(ctx, note); err != nil {
+		if err := s.repo.Update(ctx, note); err != nil {
+			return err
+		}
+		response = dto.ToNoteResponse(note)
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.NoteUpdated{
+			NoteID: id,
+			Note:   response,
+		})
+	})
+	if err != nil {
 		s.logger.Error("Failed to update note", err, map[string]interface{}{
 			"user_id": userID,
 			"note_id": id,
@@ -242,12 +269,7 @@ func (s *noteService) Update(ctx context.Context, id int, req *dto.UpdateNoteReq
 		"action":  "UPDATE_NOTE_SUCCESS",
 	})
 
-	response := dto.ToNoteResponse(note)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventNoteUpdated, map[string]interface{}{
-			"note_id": id,
-			"note":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventNoteUpdated,
 			"user_id":    userID,
@@ -266,24 +288,28 @@ func (s *noteService) Delete(ctx context.Context, id, userID int) error {
 		"action":  "DELETE_NOTE",
 	})
 
-	note, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if note == nil {
-		return errors.New("note not found")
-	}
-	if note.UserID != userID {
-		return errors.New("unauthorized")
-	}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		note, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if note == nil {
+			return errors.New("note not found")
+		}
+		if note.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToNoteResponse(note)
 
-	if err := s.repo.DeleteLinksByNote(ctx, id); err != nil {
-		s.logger.Error("Failed to delete note links", err, map[string]interface{}{
-			"note_id": id,
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.NoteDeleted{
+			NoteID: id,
+			Title:  note.Title,
 		})
-	}
-
-	if err := s.repo.Delete(ctx, id); err != nil {
+	})
+	if err != nil {
 		s.logger.Error("Failed to delete note", err, map[string]interface{}{
 			"user_id": userID,
 			"note_id": id,
@@ -298,11 +324,7 @@ func (s *noteService) Delete(ctx context.Context, id, userID int) error {
 		"action":  "DELETE_NOTE_SUCCESS",
 	})
 
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventNoteDeleted, map[string]interface{}{
-			"note_id": id,
-			"title":   note.Title,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventNoteDeleted,
 			"user_id":    userID,
@@ -345,7 +367,18 @@ func (s *noteService) CreateLink(ctx context.Context, sourceNoteID int, req *dto
 		CreatedAt:    time.Now(),
 	}
 
-	created, err := s.repo.CreateLink(ctx, link)
+	var response *dto.NoteLinkResponse
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		created, err := s.repo.CreateLink(ctx, link)
+		if err != nil {
+			return err
+		}
+		response = dto.ToNoteLinkResponse(created)
+		return s.publish(ctx, userID, events.NoteLinkCreated{
+			NoteLinkID: created.ID,
+			NoteLink:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create note link", err, map[string]interface{}{
 			"source_note_id": sourceNoteID,
@@ -356,13 +389,13 @@ func (s *noteService) CreateLink(ctx context.Context, sourceNoteID int, req *dto
 	}
 
 	s.logger.Info("Note link created", map[string]interface{}{
-		"link_id":        created.ID,
+		"link_id":        response.ID,
 		"source_note_id": sourceNoteID,
 		"target_note_id": req.TargetNoteID,
 		"action":         "CREATE_NOTE_LINK_SUCCESS",
 	})
 
-	return dto.ToNoteLinkResponse(created), nil
+	return response, nil
 }
 
 func (s *noteService) GetBacklinks(ctx context.Context, noteID, userID int) ([]*dto.NoteLinkInfo, error) {
@@ -405,7 +438,34 @@ func (s *noteService) DeleteLink(ctx context.Context, linkID, userID int) error
 		"action":  "DELETE_NOTE_LINK",
 	})
 
-	if err := s.repo.DeleteLink(ctx, linkID); err != nil {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		link, err := s.repo.GetLinkByID(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if link == nil {
+			return errors.New("note link not found")
+		}
+		// A link belongs to whoever owns the note it starts from
+		source, err := s.repo.GetByID(ctx, link.SourceNoteID)
+		if err != nil {
+			return err
+		}
+		if source == nil || source.UserID != userID {
+			return errors.New("unauthorized")
+		}
+
+		before := dto.ToNoteLinkResponse(link)
+		if err := s.repo.DeleteLink(ctx, linkID); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.NoteLinkDeleted{
+			NoteLinkID:   linkID,
+			SourceNoteID: link.SourceNoteID,
+			TargetNoteID: link.TargetNoteID,
+		})
+	})
+	if err != nil {
 		s.logger.Error("Failed to delete note link", err, map[string]interface{}{
 			"link_id": linkID,
 			"action":  "DELETE_NOTE_LINK_FAILED",
@@ -420,3 +480,12 @@ func (s *noteService) DeleteLink(ctx context.Context, linkID, userID int) error
 
 	return nil
 }
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *noteService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Notification is a published event stored in the user's inbox
+type Notification struct {
+	ID        int
+	UserID    int
+	Type      string
+	Version   int
+	Payload   json.RawMessage
+	MessageID string
+	IsRead    bool
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NotificationFilter narrows an inbox listing
+type NotificationFilter struct {
+	Type       string
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Delivery channels a preference can switch on or off
+const (
+	ChannelInApp     = "in_app"
+	ChannelWebSocket = "websocket"
+	ChannelEmail     = "email"
+	ChannelWebhook   = "webhook"
+)
+
+// AllEvents is the event_type of a user's default preference
+const AllEvents = "*"
+
+// Channels is the set of channels an event is delivered on
+type Channels struct {
+	InApp     bool
+	WebSocket bool
+	Email     bool
+	Webhook   bool
+}
+
+// DefaultChannels applies when the user has no matching preference. Webhooks are on by
+// default since they only reach endpoints the user subscribed to the event.
+func DefaultChannels() Channels {
+	return Channels{InApp: true, WebSocket: true, Webhook: true}
+}
+
+// Names returns the enabled channels as API names
+func (c Channels) Names() []string {
+	names := []string{}
+	if c.InApp {
+		names = append(names, ChannelInApp)
+	}
+	if c.WebSocket {
+		names = append(names, ChannelWebSocket)
+	}
+	if c.Email {
+		names = append(names, ChannelEmail)
+	}
+	if c.Webhook {
+		names = append(names, ChannelWebhook)
+	}
+	return names
+}
+
+// ChannelsFromNames builds a channel set from API names
+func ChannelsFromNames(names []string) Channels {
+	var c Channels
+	for _, name := range names {
+		switch name {
+		case ChannelInApp:
+			c.InApp = true
+		case ChannelWebSocket:
+			c.WebSocket = true
+		case ChannelEmail:
+			c.Email = true
+		case ChannelWebhook:
+			c.Webhook = true
+		}
+	}
+	return c
+}
+
+// NotificationPreference controls delivery of one event type (or all, see AllEvents) for a user
+type NotificationPreference struct {
+	ID        int
+	UserID    int
+	EventType string
+	Enabled   bool
+	Channels  Channels
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Delivery is the resolved outcome of a user's preferences for one event
+type Delivery struct {
+	Enabled  bool
+	Channels Channels
+}
+
+// QuietHours holds back non-urgent live notifications between Start and End ("HH:MM") in Timezone
+type QuietHours struct {
+	UserID    int
+	Enabled   bool
+	Start     string
+	End       string
+	Timezone  string
+	UpdatedAt time.Time
+}
+
+// QuietUntil reports whether now falls inside the quiet period and, if so, when it ends.
+// A period whose end is before its start spans midnight (e.g. 22:00-07:00).
+func (q *QuietHours) QuietUntil(now time.Time) (time.Time, bool) {
+	if q == nil || !q.Enabled || q.Start == q.End {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, errStart := time.Parse("15:04", q.Start)
+	end, errEnd := time.Parse("15:04", q.End)
+	if errStart != nil || errEnd != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	var quiet bool
+	if startMinute < endMinute {
+		quiet = minute >= startMinute && minute < endMinute
+	} else {
+		quiet = minute >= startMinute || minute < endMinute
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+	if minute >= endMinute {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
+
+// DeferredNotification is a live notification waiting for the user's quiet hours to end
+type DeferredNotification struct {
+	ID           int
+	UserID       int
+	Type         string
+	Version      int
+	Payload      json.RawMessage
+	MessageID    string
+	DeliverAfter time.Time
+	CreatedAt    time.Time
+}
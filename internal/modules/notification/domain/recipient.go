@@ -0,0 +1,10 @@
+package domain
+
+// Recipient is the contact information needed to deliver a notification outside the app
+type Recipient struct {
+	UserID   int
+	Email    string
+	Name     string
+	Language string
+	Timezone string
+}
@@ -0,0 +1,22 @@
+package dto
+
+// ListNotificationsRequest is built from the query string of GET /notifications
+type ListNotificationsRequest struct {
+	Type   string `json:"type,omitempty"`
+	Unread bool   `json:"unread,omitempty"`
+	Page   int    `json:"page" validate:"min=1"`
+	Limit  int    `json:"limit" validate:"min=1,max=100"`
+}
+
+// UpdatePreferenceRequest sets delivery for one event type ({type} in the URL, "*" for all events)
+type UpdatePreferenceRequest struct {
+	Enabled  *bool    `json:"enabled" validate:"required"`
+	Channels []string `json:"channels" validate:"dive,oneof=in_app websocket email webhook"`
+}
+
+// UpdateQuietHoursRequest sets the quiet period; times are HH:MM in the user's profile timezone
+type UpdateQuietHoursRequest struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start" validate:"required,datetime=15:04"`
+	End     string `json:"end" validate:"required,datetime=15:04"`
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+)
+
+type NotificationResponse struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	Version   int             `json:"version"`
+	Payload   json.RawMessage `json:"payload"`
+	MessageID string          `json:"message_id,omitempty"`
+	IsRead    bool            `json:"is_read"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	Items       []*NotificationResponse `json:"items"`
+	Total       int                     `json:"total"`
+	Page        int                     `json:"page"`
+	Limit       int                     `json:"limit"`
+	UnreadCount int                     `json:"unread_count"`
+}
+
+type UnreadCountResponse struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+type MarkAllReadResponse struct {
+	Updated int `json:"updated"`
+}
+
+func ToNotificationResponse(n *domain.Notification) *NotificationResponse {
+	if n == nil {
+		return nil
+	}
+	return &NotificationResponse{ID: n.ID, Type: n.Type, Version: n.Version, Payload: n.Payload, MessageID: n.MessageID, IsRead: n.IsRead, ReadAt: n.ReadAt, CreatedAt: n.CreatedAt}
+}
+
+func ToNotificationResponseList(notifications []*domain.Notification) []*NotificationResponse {
+	result := make([]*NotificationResponse, len(notifications))
+	for i, n := range notifications {
+		result[i] = ToNotificationResponse(n)
+	}
+	return result
+}
+
+type PreferenceResponse struct {
+	EventType string    `json:"event_type"`
+	Enabled   bool      `json:"enabled"`
+	Channels  []string  `json:"channels"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PreferencesResponse struct {
+	// DefaultChannels apply to event types without a preference (and without a "*" preference)
+	DefaultChannels []string              `json:"default_channels"`
+	Preferences     []*PreferenceResponse `json:"preferences"`
+}
+
+type QuietHoursResponse struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+	// QuietUntil is set while the quiet period is active
+	QuietUntil *time.Time `json:"quiet_until,omitempty"`
+}
+
+func ToPreferenceResponse(p *domain.NotificationPreference) *PreferenceResponse {
+	if p == nil {
+		return nil
+	}
+	return &PreferenceResponse{EventType: p.EventType, Enabled: p.Enabled, Channels: p.Channels.Names(), UpdatedAt: p.UpdatedAt}
+}
+
+func ToPreferenceResponseList(prefs []*domain.NotificationPreference) []*PreferenceResponse {
+	result := make([]*PreferenceResponse, len(prefs))
+	for i, p := range prefs {
+		result[i] = ToPreferenceResponse(p)
+	}
+	return result
+}
+
+func ToQuietHoursResponse(q *domain.QuietHours, now time.Time) *QuietHoursResponse {
+	if q == nil {
+		return nil
+	}
+	resp := &QuietHoursResponse{Enabled: q.Enabled, Start: q.Start, End: q.End, Timezone: q.Timezone}
+	if until, quiet := q.QuietUntil(now); quiet {
+		resp.QuietUntil = &until
+	}
+	return resp
+}
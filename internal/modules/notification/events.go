@@ -1,85 +1,89 @@
 package notification
 
+import "github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+
+// Event names used by module log lines. They alias the shared catalog in
+// internal/common/events, which is what Broadcaster.Publish enforces.
 const (
 	// Task events
-	EventTaskCreated   = "task.created"
-	EventTaskUpdated   = "task.updated"
-	EventTaskCompleted = "task.completed"
-	EventTaskDeleted   = "task.deleted"
+	EventTaskCreated   = events.TypeTaskCreated
+	EventTaskUpdated   = events.TypeTaskUpdated
+	EventTaskCompleted = events.TypeTaskCompleted
+	EventTaskDeleted   = events.TypeTaskDeleted
 
 	// Habit events
-	EventHabitCreated   = "habit.created"
-	EventHabitUpdated   = "habit.updated"
-	EventHabitDeleted   = "habit.deleted"
-	EventHabitReminder   = "habit.reminder"
-	EventHabitCompleted  = "habit.completed"
-	EventHabitSkipped    = "habit.skipped"
-	EventStreakIncreased = "streak.increased"
-	EventStreakMilestone = "habit.milestone"
-	EventStreakBroken    = "streak.broken"
+	EventHabitCreated    = events.TypeHabitCreated
+	EventHabitUpdated    = events.TypeHabitUpdated
+	EventHabitDeleted    = events.TypeHabitDeleted
+	EventHabitReminder   = events.TypeHabitReminder
+	EventHabitCompleted  = events.TypeHabitCompleted
+	EventHabitSkipped    = events.TypeHabitSkipped
+	EventStreakIncreased = events.TypeHabitStreakIncreased
+	EventStreakMilestone = events.TypeHabitMilestone
+	EventStreakBroken    = events.TypeHabitStreakBroken
 
 	// Course events
-	EventCourseCreated     = "course.created"
-	EventCourseUpdated     = "course.updated"
-	EventCourseDeleted     = "course.deleted"
-	EventComponentCreated  = "component.created"
-	EventComponentUpdated  = "component.updated"
-	EventComponentDeleted  = "component.deleted"
-	EventComponentGraded   = "component.graded"
-	EventScheduleCreated   = "schedule.created"
-	EventScheduleUpdated   = "schedule.updated"
-	EventScheduleDeleted   = "schedule.deleted"
+	EventCourseCreated    = events.TypeCourseCreated
+	EventCourseUpdated    = events.TypeCourseUpdated
+	EventCourseDeleted    = events.TypeCourseDeleted
+	EventComponentCreated = events.TypeComponentCreated
+	EventComponentUpdated = events.TypeComponentUpdated
+	EventComponentDeleted = events.TypeComponentDeleted
+	EventComponentGraded  = events.TypeComponentGraded
+	EventScheduleCreated  = events.TypeScheduleCreated
+	EventScheduleUpdated  = events.TypeScheduleUpdated
+	EventScheduleDeleted  = events.TypeScheduleDeleted
 
 	// Calendar events
-	EventSyncStarted   = "sync.started"
-	EventSyncProgress  = "sync.progress"
-	EventSyncCompleted = "sync.completed"
-	EventSyncFailed    = "sync.failed"
+	EventSyncStarted   = events.TypeCalendarSyncStarted
+	EventSyncProgress  = events.TypeCalendarSyncProgress
+	EventSyncCompleted = events.TypeCalendarSyncCompleted
+	EventSyncFailed    = events.TypeCalendarSyncFailed
 
 	// Event module events
-	EventEventCreated = "event.created"
-	EventEventUpdated = "event.updated"
-	EventEventDeleted = "event.deleted"
+	EventEventCreated = events.TypeEventCreated
+	EventEventUpdated = events.TypeEventUpdated
+	EventEventDeleted = events.TypeEventDeleted
 
 	// Schedule events
-	EventConflictDetected = "conflict.detected"
-	EventEventsGenerated  = "events.generated"
+	EventConflictDetected = events.TypeCalendarConflict
+	EventEventsGenerated  = events.TypeCalendarEventsGenerated
 
 	// Goal events
-	EventGoalCreated      = "goal.created"
-	EventGoalUpdated      = "goal.updated"
-	EventGoalDeleted      = "goal.deleted"
-	EventGoalCompleted    = "goal.completed"
-	EventMilestoneReached = "milestone.reached"
+	EventGoalCreated      = events.TypeGoalCreated
+	EventGoalUpdated      = events.TypeGoalUpdated
+	EventGoalDeleted      = events.TypeGoalDeleted
+	EventGoalCompleted    = events.TypeGoalCompleted
+	EventMilestoneReached = events.TypeGoalMilestoneReached
 
 	// Note events
-	EventNoteCreated = "note.created"
-	EventNoteUpdated = "note.updated"
-	EventNoteDeleted = "note.deleted"
+	EventNoteCreated = events.TypeNoteCreated
+	EventNoteUpdated = events.TypeNoteUpdated
+	EventNoteDeleted = events.TypeNoteDeleted
 
 	// LifeArea events
-	EventLifeAreaCreated = "lifearea.created"
-	EventLifeAreaUpdated = "lifearea.updated"
-	EventLifeAreaDeleted = "lifearea.deleted"
+	EventLifeAreaCreated = events.TypeLifeAreaCreated
+	EventLifeAreaUpdated = events.TypeLifeAreaUpdated
+	EventLifeAreaDeleted = events.TypeLifeAreaDeleted
 
 	// People events
-	EventPersonCreated = "person.created"
-	EventPersonUpdated = "person.updated"
-	EventPersonDeleted = "person.deleted"
+	EventPersonCreated = events.TypePersonCreated
+	EventPersonUpdated = events.TypePersonUpdated
+	EventPersonDeleted = events.TypePersonDeleted
 
 	// Journal events
-	EventJournalCreated = "journal.created"
-	EventJournalUpdated = "journal.updated"
-	EventJournalDeleted = "journal.deleted"
+	EventJournalCreated = events.TypeJournalCreated
+	EventJournalUpdated = events.TypeJournalUpdated
+	EventJournalDeleted = events.TypeJournalDeleted
 
 	// Finance events
-	EventTransactionCreated = "transaction.created"
-	EventTransactionUpdated = "transaction.updated"
-	EventTransactionDeleted = "transaction.deleted"
+	EventTransactionCreated = events.TypeTransactionCreated
+	EventTransactionUpdated = events.TypeTransactionUpdated
+	EventTransactionDeleted = events.TypeTransactionDeleted
 
 	// System events
-	EventConnected = "connected"
-	EventError     = "error"
+	EventConnected = events.TypeConnected
+	EventError     = events.TypeError
 )
 
 func GetMilestoneName(streak int) string {
@@ -0,0 +1,242 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/dto"
+	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
+	"github.com/gorilla/mux"
+)
+
+const defaultPageSize = 20
+
+type Handler struct {
+	service notifService.NotificationService
+	prefs   notifService.PreferenceService
+}
+
+func NewHandler(service notifService.NotificationService, prefs notifService.PreferenceService) *Handler {
+	return &Handler{service: service, prefs: prefs}
+}
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/notifications", h.List).Methods("GET")
+	router.HandleFunc("/notifications/unread-count", h.UnreadCount).Methods("GET")
+	router.HandleFunc("/notifications/read-all", h.MarkAllRead).Methods("POST")
+	router.HandleFunc("/notifications/preferences", h.GetPreferences).Methods("GET")
+	router.HandleFunc("/notifications/preferences/{type}", h.UpdatePreference).Methods("PUT")
+	router.HandleFunc("/notifications/preferences/{type}", h.ResetPreference).Methods("DELETE")
+	router.HandleFunc("/notifications/quiet-hours", h.GetQuietHours).Methods("GET")
+	router.HandleFunc("/notifications/quiet-hours", h.UpdateQuietHours).Methods("PUT")
+	router.HandleFunc("/notifications/{id}/read", h.MarkRead).Methods("POST", "PATCH")
+	router.HandleFunc("/notifications/{id}", h.Delete).Methods("DELETE")
+}
+
+func (h *Handler) getUserID(r *http.Request) int {
+	return utils.GetUserIDFromContext(r.Context())
+}
+
+// List returns the user's inbox, newest first
+// GET /api/notifications?page=1&limit=20&type=habit.streak_broken&unread=true
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.ListNotificationsRequest{
+		Type:  query.Get("type"),
+		Page:  1,
+		Limit: defaultPageSize,
+	}
+
+	if pageStr := query.Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sayfa", err.Error())
+			return
+		}
+		req.Page = page
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz limit", err.Error())
+			return
+		}
+		req.Limit = limit
+	}
+	if unreadStr := query.Get("unread"); unreadStr != "" {
+		unread, err := strconv.ParseBool(unreadStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz unread değeri", err.Error())
+			return
+		}
+		req.Unread = unread
+	}
+	if req.Type != "" {
+		if _, ok := events.Lookup(req.Type); !ok {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz bildirim tipi", "unknown event type: "+req.Type)
+			return
+		}
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	notifications, err := h.service.List(r.Context(), &req, h.getUserID(r))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Bildirimler getirilemedi", err.Error())
+		return
+	}
+	utils.WriteJson(w, notifications, http.StatusOK, "Bildirimler getirildi")
+}
+
+// UnreadCount returns the number of unread notifications
+// GET /api/notifications/unread-count
+func (h *Handler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	count, err := h.service.UnreadCount(r.Context(), h.getUserID(r))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Okunmamış bildirim sayısı getirilemedi", err.Error())
+		return
+	}
+	utils.WriteJson(w, count, http.StatusOK, "Okunmamış bildirim sayısı getirildi")
+}
+
+// MarkRead marks a single notification as read
+// POST /api/notifications/{id}/read
+func (h *Handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+
+	notification, err := h.service.MarkRead(r.Context(), id, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Bildirim okundu olarak işaretlenemedi")
+		return
+	}
+	utils.WriteJson(w, notification, http.StatusOK, "Bildirim okundu olarak işaretlendi")
+}
+
+// MarkAllRead marks every unread notification of the user as read
+// POST /api/notifications/read-all
+func (h *Handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.MarkAllRead(r.Context(), h.getUserID(r))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Bildirimler okundu olarak işaretlenemedi", err.Error())
+		return
+	}
+	utils.WriteJson(w, result, http.StatusOK, "Tüm bildirimler okundu olarak işaretlendi")
+}
+
+// Delete removes a notification from the inbox
+// DELETE /api/notifications/{id}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, h.getUserID(r)); err != nil {
+		h.handleError(w, err, "Bildirim silinemedi")
+		return
+	}
+	utils.WriteJson(w, nil, http.StatusOK, "Bildirim silindi")
+}
+
+// GetPreferences returns the user's delivery overrides and the default channels
+// GET /api/notifications/preferences
+func (h *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := h.prefs.GetPreferences(r.Context(), h.getUserID(r))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Bildirim tercihleri getirilemedi", err.Error())
+		return
+	}
+	utils.WriteJson(w, prefs, http.StatusOK, "Bildirim tercihleri getirildi")
+}
+
+// UpdatePreference sets delivery for one event type, or for all events with "*"
+// PUT /api/notifications/preferences/{type}
+func (h *Handler) UpdatePreference(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdatePreferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	pref, err := h.prefs.UpdatePreference(r.Context(), mux.Vars(r)["type"], &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Bildirim tercihi güncellenemedi")
+		return
+	}
+	utils.WriteJson(w, pref, http.StatusOK, "Bildirim tercihi güncellendi")
+}
+
+// ResetPreference removes an override so the event falls back to the default
+// DELETE /api/notifications/preferences/{type}
+func (h *Handler) ResetPreference(w http.ResponseWriter, r *http.Request) {
+	if err := h.prefs.ResetPreference(r.Context(), mux.Vars(r)["type"], h.getUserID(r)); err != nil {
+		h.handleError(w, err, "Bildirim tercihi sıfırlanamadı")
+		return
+	}
+	utils.WriteJson(w, nil, http.StatusOK, "Bildirim tercihi varsayılana döndü")
+}
+
+// GetQuietHours returns the user's quiet period
+// GET /api/notifications/quiet-hours
+func (h *Handler) GetQuietHours(w http.ResponseWriter, r *http.Request) {
+	quietHours, err := h.prefs.GetQuietHours(r.Context(), h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Sessiz saatler getirilemedi")
+		return
+	}
+	utils.WriteJson(w, quietHours, http.StatusOK, "Sessiz saatler getirildi")
+}
+
+// UpdateQuietHours sets the user's quiet period
+// PUT /api/notifications/quiet-hours
+func (h *Handler) UpdateQuietHours(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateQuietHoursRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	quietHours, err := h.prefs.UpdateQuietHours(r.Context(), &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Sessiz saatler güncellenemedi")
+		return
+	}
+	utils.WriteJson(w, quietHours, http.StatusOK, "Sessiz saatler güncellendi")
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string) {
+	switch err.Error() {
+	case "notification not found":
+		utils.ReturnError(w, "NOT_FOUND", "Bildirim bulunamadı", err.Error())
+	case "unknown event type":
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz bildirim tipi", err.Error())
+	case "user not found":
+		utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+	case "unauthorized":
+		utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
+	default:
+		utils.ReturnError(w, "INTERNAL_ERROR", message, err.Error())
+	}
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+)
+
+type NotificationModel struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	Type      string     `db:"type"`
+	Version   int        `db:"version"`
+	Payload   []byte     `db:"payload"`
+	MessageID *string    `db:"message_id"`
+	IsRead    bool       `db:"is_read"`
+	ReadAt    *time.Time `db:"read_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+func (m *NotificationModel) ToDomain() *domain.Notification {
+	if m == nil {
+		return nil
+	}
+	messageID := ""
+	if m.MessageID != nil {
+		messageID = *m.MessageID
+	}
+	return &domain.Notification{ID: m.ID, UserID: m.UserID, Type: m.Type, Version: m.Version, Payload: m.Payload, MessageID: messageID, IsRead: m.IsRead, ReadAt: m.ReadAt, CreatedAt: m.CreatedAt}
+}
+
+func FromDomain(n *domain.Notification) *NotificationModel {
+	if n == nil {
+		return nil
+	}
+	var messageID *string
+	if n.MessageID != "" {
+		messageID = &n.MessageID
+	}
+	payload := []byte(n.Payload)
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	return &NotificationModel{ID: n.ID, UserID: n.UserID, Type: n.Type, Version: n.Version, Payload: payload, MessageID: messageID, IsRead: n.IsRead, ReadAt: n.ReadAt, CreatedAt: n.CreatedAt}
+}
+
+type PreferenceModel struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	EventType string    `db:"event_type"`
+	Enabled   bool      `db:"enabled"`
+	InApp     bool      `db:"in_app"`
+	WebSocket bool      `db:"websocket"`
+	Email     bool      `db:"email"`
+	Webhook   bool      `db:"webhook"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (m *PreferenceModel) ToDomain() *domain.NotificationPreference {
+	if m == nil {
+		return nil
+	}
+	return &domain.NotificationPreference{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		EventType: m.EventType,
+		Enabled:   m.Enabled,
+		Channels:  domain.Channels{InApp: m.InApp, WebSocket: m.WebSocket, Email: m.Email, Webhook: m.Webhook},
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func PreferenceFromDomain(p *domain.NotificationPreference) *PreferenceModel {
+	if p == nil {
+		return nil
+	}
+	return &PreferenceModel{ID: p.ID, UserID: p.UserID, EventType: p.EventType, Enabled: p.Enabled, InApp: p.Channels.InApp, WebSocket: p.Channels.WebSocket, Email: p.Channels.Email, Webhook: p.Channels.Webhook, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt}
+}
+
+type QuietHoursModel struct {
+	UserID    int        `db:"user_id"`
+	Enabled   *bool      `db:"enabled"`
+	Start     *string    `db:"start_time"` // formatted as HH:MM in the query
+	End       *string    `db:"end_time"`
+	Timezone  *string    `db:"timezone"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+// ToDomain fills in defaults for users who never saved quiet hours (the columns come from a LEFT JOIN)
+func (m *QuietHoursModel) ToDomain() *domain.QuietHours {
+	if m == nil {
+		return nil
+	}
+	q := &domain.QuietHours{UserID: m.UserID, Start: "22:00", End: "07:00", Timezone: "UTC"}
+	if m.Enabled != nil {
+		q.Enabled = *m.Enabled
+	}
+	if m.Start != nil {
+		q.Start = *m.Start
+	}
+	if m.End != nil {
+		q.End = *m.End
+	}
+	if m.Timezone != nil && *m.Timezone != "" {
+		q.Timezone = *m.Timezone
+	}
+	if m.UpdatedAt != nil {
+		q.UpdatedAt = *m.UpdatedAt
+	}
+	return q
+}
+
+type RecipientModel struct {
+	UserID   int     `db:"id"`
+	Email    string  `db:"email"`
+	FullName *string `db:"full_name"`
+	Language string  `db:"language"`
+	Timezone *string `db:"timezone"`
+}
+
+func (m *RecipientModel) ToDomain() *domain.Recipient {
+	r := &domain.Recipient{UserID: m.UserID, Email: m.Email, Language: m.Language, Timezone: "UTC"}
+	if m.FullName != nil {
+		r.Name = *m.FullName
+	}
+	if m.Timezone != nil && *m.Timezone != "" {
+		r.Timezone = *m.Timezone
+	}
+	return r
+}
+
+type DeferredModel struct {
+	ID           int       `db:"id"`
+	UserID       int       `db:"user_id"`
+	Type         string    `db:"type"`
+	Version      int       `db:"version"`
+	Payload      []byte    `db:"payload"`
+	MessageID    *string   `db:"message_id"`
+	DeliverAfter time.Time `db:"deliver_after"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func (m *DeferredModel) ToDomain() *domain.DeferredNotification {
+	if m == nil {
+		return nil
+	}
+	messageID := ""
+	if m.MessageID != nil {
+		messageID = *m.MessageID
+	}
+	return &domain.DeferredNotification{ID: m.ID, UserID: m.UserID, Type: m.Type, Version: m.Version, Payload: m.Payload, MessageID: messageID, DeliverAfter: m.DeliverAfter, CreatedAt: m.CreatedAt}
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresRepository struct{ db *sqlx.DB }
+
+func NewPostgresRepository(db *sqlx.DB) NotificationRepository { return &postgresRepository{db: db} }
+
+const notificationColumns = `id, user_id, type, version, payload, message_id, is_read, read_at, created_at`
+
+func (r *postgresRepository) Create(ctx context.Context, n *domain.Notification) (*domain.Notification, error) {
+	query := `INSERT INTO notifications (user_id, type, version, payload, message_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	model := FromDomain(n)
+	err := r.db.QueryRowxContext(ctx, query, model.UserID, model.Type, model.Version, model.Payload, model.MessageID, time.Now()).Scan(&model.ID, &model.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE id = $1`
+	var model NotificationModel
+	if err := r.db.GetContext(ctx, &model, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// List returns one page of the user's inbox, newest first, together with the total matching the filter
+func (r *postgresRepository) List(ctx context.Context, userID int, filter domain.NotificationFilter) ([]*domain.Notification, int, error) {
+	where := `WHERE user_id = $1`
+	args := []interface{}{userID}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		where += fmt.Sprintf(` AND type = $%d`, len(args))
+	}
+	if filter.UnreadOnly {
+		where += ` AND is_read = FALSE`
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM notifications `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM notifications %s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		notificationColumns, where, len(args)-1, len(args))
+	var models []NotificationModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, 0, err
+	}
+	notifications := make([]*domain.Notification, len(models))
+	for i, m := range models {
+		notifications[i] = m.ToDomain()
+	}
+	return notifications, total, nil
+}
+
+func (r *postgresRepository) MarkRead(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notifications SET is_read = TRUE, read_at = $1 WHERE id = $2 AND is_read = FALSE`, time.Now(), id)
+	return err
+}
+
+func (r *postgresRepository) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE notifications SET is_read = TRUE, read_at = $1 WHERE user_id = $2 AND is_read = FALSE`, time.Now(), userID)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM notifications WHERE id = $1`, id)
+	return err
+}
+
+func (r *postgresRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = FALSE`, userID)
+	return count, err
+}
+
+type preferenceRepository struct{ db *sqlx.DB }
+
+func NewPreferenceRepository(db *sqlx.DB) PreferenceRepository { return &preferenceRepository{db: db} }
+
+func (r *preferenceRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.NotificationPreference, error) {
+	query := `SELECT id, user_id, event_type, enabled, in_app, websocket, email, webhook, created_at, updated_at
+		FROM notification_preferences WHERE user_id = $1 ORDER BY event_type`
+	var models []PreferenceModel
+	if err := r.db.SelectContext(ctx, &models, query, userID); err != nil {
+		return nil, err
+	}
+	prefs := make([]*domain.NotificationPreference, len(models))
+	for i, m := range models {
+		prefs[i] = m.ToDomain()
+	}
+	return prefs, nil
+}
+
+func (r *preferenceRepository) Upsert(ctx context.Context, pref *domain.NotificationPreference) (*domain.NotificationPreference, error) {
+	query := `INSERT INTO notification_preferences (user_id, event_type, enabled, in_app, websocket, email, webhook, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (user_id, event_type) DO UPDATE SET enabled = EXCLUDED.enabled, in_app = EXCLUDED.in_app,
+			websocket = EXCLUDED.websocket, email = EXCLUDED.email, webhook = EXCLUDED.webhook, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, updated_at`
+	model := PreferenceFromDomain(pref)
+	err := r.db.QueryRowxContext(ctx, query, model.UserID, model.EventType, model.Enabled, model.InApp, model.WebSocket, model.Email, model.Webhook, time.Now()).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *preferenceRepository) Delete(ctx context.Context, userID int, eventType string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM notification_preferences WHERE user_id = $1 AND event_type = $2`, userID, eventType)
+	return err
+}
+
+func (r *preferenceRepository) GetQuietHours(ctx context.Context, userID int) (*domain.QuietHours, error) {
+	query := `SELECT u.id AS user_id, q.enabled, to_char(q.start_time, 'HH24:MI') AS start_time,
+			to_char(q.end_time, 'HH24:MI') AS end_time, u.timezone, q.updated_at
+		FROM users u LEFT JOIN notification_quiet_hours q ON q.user_id = u.id WHERE u.id = $1`
+	var model QuietHoursModel
+	if err := r.db.GetContext(ctx, &model, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *preferenceRepository) GetRecipient(ctx context.Context, userID int) (*domain.Recipient, error) {
+	query := `SELECT id, email, full_name, language, timezone FROM users WHERE id = $1`
+	var model RecipientModel
+	if err := r.db.GetContext(ctx, &model, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *preferenceRepository) UpsertQuietHours(ctx context.Context, q *domain.QuietHours) error {
+	query := `INSERT INTO notification_quiet_hours (user_id, enabled, start_time, end_time, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET enabled = EXCLUDED.enabled, start_time = EXCLUDED.start_time,
+			end_time = EXCLUDED.end_time, updated_at = EXCLUDED.updated_at`
+	_, err := r.db.ExecContext(ctx, query, q.UserID, q.Enabled, q.Start, q.End, time.Now())
+	return err
+}
+
+type deferredRepository struct{ db *sqlx.DB }
+
+func NewDeferredRepository(db *sqlx.DB) DeferredRepository { return &deferredRepository{db: db} }
+
+func (r *deferredRepository) Create(ctx context.Context, n *domain.DeferredNotification) error {
+	query := `INSERT INTO deferred_notifications (user_id, type, version, payload, message_id, deliver_after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	var messageID *string
+	if n.MessageID != "" {
+		messageID = &n.MessageID
+	}
+	payload := []byte(n.Payload)
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	_, err := r.db.ExecContext(ctx, query, n.UserID, n.Type, n.Version, payload, messageID, n.DeliverAfter, time.Now())
+	return err
+}
+
+// ClaimDue deletes and returns due rows in one statement so concurrent runners never deliver a row twice
+func (r *deferredRepository) ClaimDue(ctx context.Context, now time.Time) ([]*domain.DeferredNotification, error) {
+	query := `DELETE FROM deferred_notifications WHERE deliver_after <= $1
+		RETURNING id, user_id, type, version, payload, message_id, deliver_after, created_at`
+	var models []DeferredModel
+	if err := r.db.SelectContext(ctx, &models, query, now); err != nil {
+		return nil, err
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	deferred := make([]*domain.DeferredNotification, len(models))
+	for i, m := range models {
+		deferred[i] = m.ToDomain()
+	}
+	return deferred, nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n *domain.Notification) (*domain.Notification, error)
+	GetByID(ctx context.Context, id int) (*domain.Notification, error)
+	List(ctx context.Context, userID int, filter domain.NotificationFilter) ([]*domain.Notification, int, error)
+	MarkRead(ctx context.Context, id int) error
+	MarkAllRead(ctx context.Context, userID int) (int, error)
+	Delete(ctx context.Context, id int) error
+	CountUnread(ctx context.Context, userID int) (int, error)
+}
+
+type PreferenceRepository interface {
+	GetByUserID(ctx context.Context, userID int) ([]*domain.NotificationPreference, error)
+	Upsert(ctx context.Context, pref *domain.NotificationPreference) (*domain.NotificationPreference, error)
+	Delete(ctx context.Context, userID int, eventType string) error
+	GetQuietHours(ctx context.Context, userID int) (*domain.QuietHours, error)
+	UpsertQuietHours(ctx context.Context, q *domain.QuietHours) error
+	GetRecipient(ctx context.Context, userID int) (*domain.Recipient, error)
+}
+
+type DeferredRepository interface {
+	Create(ctx context.Context, n *domain.DeferredNotification) error
+	// ClaimDue removes and returns every notification due at or before now, oldest first
+	ClaimDue(ctx context.Context, now time.Time) ([]*domain.DeferredNotification, error)
+}
@@ -1,77 +1,351 @@
 package notification
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/websocket"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/repository"
 )
 
+// inboxWriteTimeout bounds the preference lookup and inbox writes done on the publisher's goroutine
+const inboxWriteTimeout = 5 * time.Second
+
+// quietHoursEndedReason marks batches flushed when a user's quiet period ends
+const quietHoursEndedReason = "quiet_hours_ended"
+
+// WebhookDispatcher queues a message for the user's webhook endpoints
+type WebhookDispatcher interface {
+	Dispatch(ctx context.Context, userID int, message *websocket.Message) error
+}
+
 type Broadcaster struct {
-	hub    *websocket.Hub
-	logger *logger.ZapLogger
+	hub      *websocket.Hub
+	repo     repository.NotificationRepository
+	deferred repository.DeferredRepository
+	prefs    PreferenceService
+	email    *EmailChannel
+	webhooks WebhookDispatcher
+	logger   *logger.ZapLogger
+}
+
+func NewBroadcaster(hub *websocket.Hub, repo repository.NotificationRepository, deferred repository.DeferredRepository, prefs PreferenceService, logger *logger.ZapLogger) *Broadcaster {
+	return &Broadcaster{hub: hub, repo: repo, deferred: deferred, prefs: prefs, logger: logger}
 }
 
-func NewBroadcaster(hub *websocket.Hub, logger *logger.ZapLogger) *Broadcaster {
-	return &Broadcaster{hub: hub, logger: logger}
+// SetEmailChannel enables the email channel. Without it email preferences are ignored.
+func (b *Broadcaster) SetEmailChannel(email *EmailChannel) {
+	b.email = email
 }
 
-func (b *Broadcaster) Publish(userID int, eventType string, data map[string]interface{}) {
+// SetWebhookDispatcher enables the webhook channel
+func (b *Broadcaster) SetWebhookDispatcher(webhooks WebhookDispatcher) {
+	b.webhooks = webhooks
+}
+
+// Publish delivers a catalog event according to the user's preferences: stored in the inbox
+// (in-app), sent to the user's connections (websocket), queued as an email and posted to
+// subscribed webhook endpoints. During quiet hours non-urgent live delivery is deferred and
+// flushed as one batch when the period ends, and emails are held until the period ends.
+// Webhooks are for automations and ignore quiet hours. Digests send their own email.
+// Events missing from the catalog are rejected so clients never receive an undocumented type.
+func (b *Broadcaster) Publish(userID int, event events.Event) error {
+	eventType := event.EventType()
+
+	def, ok := events.Lookup(eventType)
+	if !ok {
+		err := fmt.Errorf("unregistered event type: %s", eventType)
+		b.logger.Error("Rejected unregistered event", err, map[string]interface{}{
+			"user_id": userID,
+			"type":    eventType,
+			"action":  "BROADCAST_REJECTED",
+		})
+		return err
+	}
+
+	// Protocol and inbox bookkeeping events bypass preferences
+	if events.IsTransient(eventType) {
+		b.hub.PublishToUser(userID, websocket.NewMessage(userID, event))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), inboxWriteTimeout)
+	defer cancel()
+
+	delivery := b.resolve(ctx, userID, eventType)
+	if !delivery.Enabled {
+		b.logger.Info("Notification suppressed by preference", map[string]interface{}{
+			"user_id": userID,
+			"type":    eventType,
+			"action":  "BROADCAST_SUPPRESSED",
+		})
+		return nil
+	}
+
+	quietUntil, quiet := b.quietUntil(ctx, userID, eventType)
+
 	b.logger.Info("Broadcasting notification", map[string]interface{}{
-		"user_id": userID,
-		"type":    eventType,
-		"action":  "BROADCAST_NOTIFICATION",
+		"user_id":  userID,
+		"type":     eventType,
+		"version":  def.Version,
+		"channels": delivery.Channels.Names(),
+		"deferred": quiet,
+		"action":   "BROADCAST_NOTIFICATION",
 	})
 
-	message := websocket.NewMessage(eventType, userID, data)
-	b.hub.PublishToUser(userID, message)
+	message := websocket.NewMessage(userID, event)
+	stored := false
+	if delivery.Channels.InApp {
+		stored = b.store(ctx, userID, message)
+	}
+
+	if delivery.Channels.WebSocket {
+		if quiet {
+			b.deferLive(ctx, userID, message, quietUntil)
+		} else {
+			b.hub.PublishToUser(userID, message)
+		}
+	}
+
+	if delivery.Channels.Email && b.email != nil && !events.IsSelfMailed(eventType) {
+		notBefore := time.Now()
+		if quiet {
+			notBefore = quietUntil
+		}
+		if err := b.email.Deliver(ctx, userID, message, notBefore); err != nil {
+			b.logger.Error("Failed to queue notification email", err, map[string]interface{}{
+				"user_id": userID,
+				"type":    eventType,
+				"action":  "NOTIFICATION_EMAIL_FAILED",
+			})
+		}
+	}
+
+	if delivery.Channels.Webhook && b.webhooks != nil {
+		if err := b.webhooks.Dispatch(ctx, userID, message); err != nil {
+			b.logger.Error("Failed to queue webhook deliveries", err, map[string]interface{}{
+				"user_id": userID,
+				"type":    eventType,
+				"action":  "NOTIFICATION_WEBHOOK_FAILED",
+			})
+		}
+	}
+
+	if stored && !quiet {
+		b.PublishUnreadCount(userID)
+	}
+	return nil
 }
 
-func (b *Broadcaster) TaskCreated(userID int, taskID int, title string) {
-	b.Publish(userID, websocket.TypeTaskCreated, map[string]interface{}{
-		"task_id": taskID,
-		"title":   title,
+// HandleEvent is the broadcaster's event bus subscription: every catalog event published on
+// the bus is delivered to each of its recipients. Domain-only events (not in the catalog) stay
+// in the process.
+func (b *Broadcaster) HandleEvent(ctx context.Context, env eventbus.Envelope) error {
+	if _, ok := events.Lookup(env.Event.EventType()); !ok {
+		return nil
+	}
+	var firstErr error
+	for _, userID := range env.Recipients() {
+		if err := b.Publish(userID, env.Event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// AllowLive reports whether an event sent to every connection (e.g. job progress) should reach
+// this user. Used as the hub's delivery filter; during quiet hours non-urgent events are dropped.
+func (b *Broadcaster) AllowLive(userID int, eventType string) bool {
+	if events.IsTransient(eventType) {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), inboxWriteTimeout)
+	defer cancel()
+
+	delivery := b.resolve(ctx, userID, eventType)
+	if !delivery.Enabled || !delivery.Channels.WebSocket {
+		return false
+	}
+	_, quiet := b.quietUntil(ctx, userID, eventType)
+	return !quiet
+}
+
+// FlushDeferred sends every deferred notification whose quiet period has ended,
+// one notification.batch message per user. Returns the number of notifications delivered.
+func (b *Broadcaster) FlushDeferred(ctx context.Context) (int, error) {
+	if b.deferred == nil {
+		return 0, nil
+	}
+
+	due, err := b.deferred.ClaimDue(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	batches := make(map[int][]events.NotificationBatchItem)
+	var order []int
+	for _, d := range due {
+		if _, exists := batches[d.UserID]; !exists {
+			order = append(order, d.UserID)
+		}
+		batches[d.UserID] = append(batches[d.UserID], events.NotificationBatchItem{
+			Type:      d.Type,
+			Version:   d.Version,
+			Payload:   d.Payload,
+			Timestamp: d.CreatedAt.UTC(),
+			MessageID: d.MessageID,
+		})
+	}
+
+	for _, userID := range order {
+		b.hub.PublishToUser(userID, websocket.NewMessage(userID, events.NotificationBatch{
+			Reason: quietHoursEndedReason,
+			Items:  batches[userID],
+		}))
+		b.PublishUnreadCount(userID)
+
+		b.logger.Info("Deferred notifications delivered", map[string]interface{}{
+			"user_id": userID,
+			"count":   len(batches[userID]),
+			"action":  "DEFERRED_NOTIFICATIONS_FLUSHED",
+		})
+	}
+
+	return len(due), nil
+}
+
+func (b *Broadcaster) resolve(ctx context.Context, userID int, eventType string) domain.Delivery {
+	if b.prefs == nil {
+		return domain.Delivery{Enabled: true, Channels: domain.DefaultChannels()}
+	}
+	return b.prefs.Resolve(ctx, userID, eventType)
+}
+
+func (b *Broadcaster) quietUntil(ctx context.Context, userID int, eventType string) (time.Time, bool) {
+	if b.prefs == nil || b.deferred == nil || events.IsUrgent(eventType) {
+		return time.Time{}, false
+	}
+	return b.prefs.QuietUntil(ctx, userID, time.Now())
+}
+
+// deferLive holds a live message until the quiet period ends. If it cannot be saved
+// it is sent right away rather than lost.
+func (b *Broadcaster) deferLive(ctx context.Context, userID int, message *websocket.Message, until time.Time) {
+	payload, err := json.Marshal(message.Payload)
+	if err == nil {
+		err = b.deferred.Create(ctx, &domain.DeferredNotification{
+			UserID:       userID,
+			Type:         message.Type,
+			Version:      message.Version,
+			Payload:      payload,
+			MessageID:    message.MessageID,
+			DeliverAfter: until,
+		})
+	}
+	if err != nil {
+		b.logger.Error("Failed to defer notification, sending now", err, map[string]interface{}{
+			"user_id": userID,
+			"type":    message.Type,
+			"action":  "NOTIFICATION_DEFER_FAILED",
+		})
+		b.hub.PublishToUser(userID, message)
+	}
+}
+
+// store writes a message to the inbox so offline users see it later.
+// A failed insert is logged and does not block live delivery.
+func (b *Broadcaster) store(ctx context.Context, userID int, message *websocket.Message) bool {
+	if b.repo == nil {
+		return false
+	}
+
+	payload, err := json.Marshal(message.Payload)
+	if err != nil {
+		b.logger.Error("Failed to encode notification payload", err, map[string]interface{}{
+			"user_id": userID,
+			"type":    message.Type,
+			"action":  "NOTIFICATION_STORE_FAILED",
+		})
+		return false
+	}
+
+	_, err = b.repo.Create(ctx, &domain.Notification{
+		UserID:    userID,
+		Type:      message.Type,
+		Version:   message.Version,
+		Payload:   payload,
+		MessageID: message.MessageID,
 	})
+	if err != nil {
+		b.logger.Error("Failed to store notification", err, map[string]interface{}{
+			"user_id": userID,
+			"type":    message.Type,
+			"action":  "NOTIFICATION_STORE_FAILED",
+		})
+		return false
+	}
+	return true
+}
+
+// PublishUnreadCount pushes the user's current unread inbox count to their connections
+func (b *Broadcaster) PublishUnreadCount(userID int) {
+	if b.repo == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), inboxWriteTimeout)
+	defer cancel()
+
+	count, err := b.repo.CountUnread(ctx, userID)
+	if err != nil {
+		b.logger.Error("Failed to count unread notifications", err, map[string]interface{}{
+			"user_id": userID,
+			"action":  "NOTIFICATION_UNREAD_COUNT_FAILED",
+		})
+		return
+	}
+
+	b.hub.PublishToUser(userID, websocket.NewMessage(userID, events.NotificationUnreadCount{UnreadCount: count}))
+}
+
+func (b *Broadcaster) TaskCreated(userID int, taskID int, title string) {
+	b.Publish(userID, events.TaskCreated{TaskID: taskID, Title: title})
 }
 
 func (b *Broadcaster) TaskCompleted(userID int, taskID int, title string) {
-	b.Publish(userID, websocket.TypeTaskCompleted, map[string]interface{}{
-		"task_id": taskID,
-		"title":   title,
-	})
+	b.Publish(userID, events.TaskCompleted{TaskID: taskID, Title: title})
 }
 
 func (b *Broadcaster) HabitCompleted(userID int, habitID int, title string, streak int) {
-	b.Publish(userID, websocket.TypeHabitCompleted, map[string]interface{}{
-		"habit_id": habitID,
-		"title":    title,
-		"streak":   streak,
-	})
+	b.Publish(userID, events.HabitCompleted{HabitID: habitID, Title: title, Streak: streak})
 }
 
 func (b *Broadcaster) StreakMilestone(userID int, habitID int, title string, streak int) {
-	b.Publish(userID, websocket.TypeHabitMilestone, map[string]interface{}{
-		"habit_id": habitID,
-		"title":    title,
-		"streak":   streak,
+	b.Publish(userID, events.HabitMilestone{
+		HabitID:       habitID,
+		Title:         title,
+		Streak:        streak,
+		Milestone:     streak,
+		MilestoneName: notification.GetMilestoneName(streak),
 	})
 }
 
 func (b *Broadcaster) SyncProgress(userID int, provider string, progress int) {
-	b.Publish(userID, websocket.TypeJobProgress, map[string]interface{}{
-		"provider": provider,
-		"progress": progress,
-	})
+	b.Publish(userID, events.CalendarSyncProgress{Provider: provider, Progress: progress})
 }
 
-func (b *Broadcaster) SyncCompleted(userID int, provider string) {
-	b.Publish(userID, websocket.TypeJobCompleted, map[string]interface{}{
-		"provider": provider,
-	})
+func (b *Broadcaster) SyncCompleted(userID int, provider string, eventsSynced int) {
+	b.Publish(userID, events.CalendarSyncCompleted{Provider: provider, EventsSynced: eventsSynced})
 }
 
-func (b *Broadcaster) ConflictDetected(userID int, reason string, start, end string) {
-	b.Publish(userID, websocket.TypeCalendarConflict, map[string]interface{}{
-		"reason": reason,
-		"start":  start,
-		"end":    end,
-	})
+func (b *Broadcaster) ConflictDetected(userID int, reason string, start, end time.Time) {
+	b.Publish(userID, events.CalendarConflict{Reason: reason, Start: start, End: end})
 }
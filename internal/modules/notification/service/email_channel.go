@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/mailer"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/websocket"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/repository"
+)
+
+// notificationTemplate is the email template used for every catalog event
+const notificationTemplate = "notification"
+
+// notificationEmail is the data passed to the notification template
+type notificationEmail struct {
+	Name  string
+	Type  string
+	Title string
+	Time  string
+}
+
+// EmailChannel renders notifications in the recipient's language and queues them in the email outbox
+type EmailChannel struct {
+	outbox     *mailer.Outbox
+	renderer   *mailer.Renderer
+	recipients repository.PreferenceRepository
+	logger     *logger.ZapLogger
+}
+
+func NewEmailChannel(outbox *mailer.Outbox, renderer *mailer.Renderer, recipients repository.PreferenceRepository, logger *logger.ZapLogger) *EmailChannel {
+	return &EmailChannel{outbox: outbox, renderer: renderer, recipients: recipients, logger: logger}
+}
+
+// Deliver queues the message for the user's address; the outbox sends it no earlier than notBefore
+func (c *EmailChannel) Deliver(ctx context.Context, userID int, message *websocket.Message, notBefore time.Time) error {
+	recipient, err := c.recipients.GetRecipient(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if recipient == nil {
+		return errors.New("user not found")
+	}
+
+	data := notificationEmail{
+		Name:  recipient.Name,
+		Type:  message.Type,
+		Title: payloadTitle(message.Payload),
+		Time:  formatTime(message.Timestamp, recipient),
+	}
+
+	email, err := c.renderer.Render(notificationTemplate, recipient.Language, data)
+	if err != nil {
+		return err
+	}
+	email.To = recipient.Email
+
+	_, err = c.outbox.EnqueueAt(ctx, userID, email, notBefore)
+	return err
+}
+
+// payloadTitle picks a human readable label from the payload: its title or name,
+// or that of the entity snapshot it carries
+func payloadTitle(payload interface{}) string {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ""
+	}
+
+	if title := stringField(fields); title != "" {
+		return title
+	}
+	for _, value := range fields {
+		if nested, ok := value.(map[string]interface{}); ok {
+			if title := stringField(nested); title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
+
+func stringField(fields map[string]interface{}) string {
+	for _, key := range []string{"title", "name", "full_name"} {
+		if s, ok := fields[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func formatTime(t time.Time, recipient *domain.Recipient) string {
+	if loc, err := time.LoadLocation(recipient.Timezone); err == nil {
+		t = t.In(loc)
+	}
+	if recipient.Language == mailer.LanguageEnglish {
+		return t.Format("Jan 2, 2006 15:04")
+	}
+	return t.Format("02.01.2006 15:04")
+}
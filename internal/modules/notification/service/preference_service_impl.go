@@ -0,0 +1,176 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/repository"
+)
+
+// preferenceCacheTTL bounds how stale another instance's view of a user's settings can be
+const preferenceCacheTTL = time.Minute
+
+// userSettings is the cached, per-user view used on the publish path
+type userSettings struct {
+	prefs    map[string]*domain.NotificationPreference
+	quiet    *domain.QuietHours
+	loadedAt time.Time
+}
+
+type preferenceService struct {
+	repo   repository.PreferenceRepository
+	logger *logger.ZapLogger
+
+	cache map[int]*userSettings
+	mu    sync.Mutex
+}
+
+func NewPreferenceService(repo repository.PreferenceRepository, logger *logger.ZapLogger) PreferenceService {
+	return &preferenceService{repo: repo, logger: logger, cache: make(map[int]*userSettings)}
+}
+
+func (s *preferenceService) GetPreferences(ctx context.Context, userID int) (*dto.PreferencesResponse, error) {
+	prefs, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PreferencesResponse{
+		DefaultChannels: domain.DefaultChannels().Names(),
+		Preferences:     dto.ToPreferenceResponseList(prefs),
+	}, nil
+}
+
+func (s *preferenceService) UpdatePreference(ctx context.Context, eventType string, req *dto.UpdatePreferenceRequest, userID int) (*dto.PreferenceResponse, error) {
+	if err := validateEventType(eventType); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Updating notification preference", map[string]interface{}{"user_id": userID, "event_type": eventType, "action": "UPDATE_NOTIFICATION_PREFERENCE"})
+
+	channels := domain.DefaultChannels()
+	if req.Channels != nil {
+		channels = domain.ChannelsFromNames(req.Channels)
+	}
+	pref, err := s.repo.Upsert(ctx, &domain.NotificationPreference{
+		UserID:    userID,
+		EventType: eventType,
+		Enabled:   *req.Enabled,
+		Channels:  channels,
+	})
+	if err != nil {
+		s.logger.Error("Failed to update notification preference", err, map[string]interface{}{"user_id": userID, "event_type": eventType, "action": "UPDATE_NOTIFICATION_PREFERENCE_FAILED"})
+		return nil, err
+	}
+	s.invalidate(userID)
+	s.logger.Info("Notification preference updated", map[string]interface{}{"user_id": userID, "event_type": eventType, "action": "UPDATE_NOTIFICATION_PREFERENCE_SUCCESS"})
+	return dto.ToPreferenceResponse(pref), nil
+}
+
+func (s *preferenceService) ResetPreference(ctx context.Context, eventType string, userID int) error {
+	if err := validateEventType(eventType); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, userID, eventType); err != nil {
+		s.logger.Error("Failed to reset notification preference", err, map[string]interface{}{"user_id": userID, "event_type": eventType, "action": "RESET_NOTIFICATION_PREFERENCE_FAILED"})
+		return err
+	}
+	s.invalidate(userID)
+	s.logger.Info("Notification preference reset", map[string]interface{}{"user_id": userID, "event_type": eventType, "action": "RESET_NOTIFICATION_PREFERENCE_SUCCESS"})
+	return nil
+}
+
+func (s *preferenceService) GetQuietHours(ctx context.Context, userID int) (*dto.QuietHoursResponse, error) {
+	q, err := s.repo.GetQuietHours(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		return nil, errors.New("user not found")
+	}
+	return dto.ToQuietHoursResponse(q, time.Now()), nil
+}
+
+func (s *preferenceService) UpdateQuietHours(ctx context.Context, req *dto.UpdateQuietHoursRequest, userID int) (*dto.QuietHoursResponse, error) {
+	s.logger.Info("Updating quiet hours", map[string]interface{}{"user_id": userID, "enabled": req.Enabled, "start": req.Start, "end": req.End, "action": "UPDATE_QUIET_HOURS"})
+	if err := s.repo.UpsertQuietHours(ctx, &domain.QuietHours{UserID: userID, Enabled: req.Enabled, Start: req.Start, End: req.End}); err != nil {
+		s.logger.Error("Failed to update quiet hours", err, map[string]interface{}{"user_id": userID, "action": "UPDATE_QUIET_HOURS_FAILED"})
+		return nil, err
+	}
+	s.invalidate(userID)
+	return s.GetQuietHours(ctx, userID)
+}
+
+// Resolve applies the user's preference for the exact event type, then their "*" default,
+// then the system default. Lookup failures fall back to the system default so delivery is not lost.
+func (s *preferenceService) Resolve(ctx context.Context, userID int, eventType string) domain.Delivery {
+	settings := s.load(ctx, userID)
+	if settings != nil {
+		if pref, ok := settings.prefs[eventType]; ok {
+			return domain.Delivery{Enabled: pref.Enabled, Channels: pref.Channels}
+		}
+		if pref, ok := settings.prefs[domain.AllEvents]; ok {
+			return domain.Delivery{Enabled: pref.Enabled, Channels: pref.Channels}
+		}
+	}
+	return domain.Delivery{Enabled: true, Channels: domain.DefaultChannels()}
+}
+
+func (s *preferenceService) QuietUntil(ctx context.Context, userID int, now time.Time) (time.Time, bool) {
+	settings := s.load(ctx, userID)
+	if settings == nil {
+		return time.Time{}, false
+	}
+	return settings.quiet.QuietUntil(now)
+}
+
+// load returns the user's cached settings, refreshing them when stale
+func (s *preferenceService) load(ctx context.Context, userID int) *userSettings {
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < preferenceCacheTTL {
+		return cached
+	}
+
+	prefs, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to load notification preferences", err, map[string]interface{}{"user_id": userID, "action": "LOAD_NOTIFICATION_PREFERENCES_FAILED"})
+		return cached
+	}
+	quiet, err := s.repo.GetQuietHours(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to load quiet hours", err, map[string]interface{}{"user_id": userID, "action": "LOAD_QUIET_HOURS_FAILED"})
+		return cached
+	}
+
+	settings := &userSettings{prefs: make(map[string]*domain.NotificationPreference, len(prefs)), quiet: quiet, loadedAt: time.Now()}
+	for _, p := range prefs {
+		settings.prefs[p.EventType] = p
+	}
+
+	s.mu.Lock()
+	s.cache[userID] = settings
+	s.mu.Unlock()
+	return settings
+}
+
+func (s *preferenceService) invalidate(userID int) {
+	s.mu.Lock()
+	delete(s.cache, userID)
+	s.mu.Unlock()
+}
+
+func validateEventType(eventType string) error {
+	if eventType == domain.AllEvents {
+		return nil
+	}
+	if _, ok := events.Lookup(eventType); !ok || events.IsTransient(eventType) {
+		return errors.New("unknown event type")
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/dto"
+)
+
+type NotificationService interface {
+	List(ctx context.Context, req *dto.ListNotificationsRequest, userID int) (*dto.NotificationListResponse, error)
+	UnreadCount(ctx context.Context, userID int) (*dto.UnreadCountResponse, error)
+	MarkRead(ctx context.Context, id, userID int) (*dto.NotificationResponse, error)
+	MarkAllRead(ctx context.Context, userID int) (*dto.MarkAllReadResponse, error)
+	Delete(ctx context.Context, id, userID int) error
+}
+
+type PreferenceService interface {
+	GetPreferences(ctx context.Context, userID int) (*dto.PreferencesResponse, error)
+	UpdatePreference(ctx context.Context, eventType string, req *dto.UpdatePreferenceRequest, userID int) (*dto.PreferenceResponse, error)
+	ResetPreference(ctx context.Context, eventType string, userID int) error
+	GetQuietHours(ctx context.Context, userID int) (*dto.QuietHoursResponse, error)
+	UpdateQuietHours(ctx context.Context, req *dto.UpdateQuietHoursRequest, userID int) (*dto.QuietHoursResponse, error)
+
+	// Resolve returns how an event should be delivered to the user
+	Resolve(ctx context.Context, userID int, eventType string) domain.Delivery
+	// QuietUntil reports whether the user is in quiet hours and when they end
+	QuietUntil(ctx context.Context, userID int, now time.Time) (time.Time, bool)
+}
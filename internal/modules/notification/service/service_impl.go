@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/repository"
+)
+
+type notificationService struct {
+	repo        repository.NotificationRepository
+	logger      *logger.ZapLogger
+	broadcaster *Broadcaster
+}
+
+func NewNotificationService(repo repository.NotificationRepository, logger *logger.ZapLogger, broadcaster *Broadcaster) NotificationService {
+	return &notificationService{repo: repo, logger: logger, broadcaster: broadcaster}
+}
+
+func (s *notificationService) List(ctx context.Context, req *dto.ListNotificationsRequest, userID int) (*dto.NotificationListResponse, error) {
+	filter := domain.NotificationFilter{
+		Type:       req.Type,
+		UnreadOnly: req.Unread,
+		Limit:      req.Limit,
+		Offset:     (req.Page - 1) * req.Limit,
+	}
+	notifications, total, err := s.repo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.NotificationListResponse{
+		Items:       dto.ToNotificationResponseList(notifications),
+		Total:       total,
+		Page:        req.Page,
+		Limit:       req.Limit,
+		UnreadCount: unread,
+	}, nil
+}
+
+func (s *notificationService) UnreadCount(ctx context.Context, userID int) (*dto.UnreadCountResponse, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnreadCountResponse{UnreadCount: count}, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, id, userID int) (*dto.NotificationResponse, error) {
+	n, err := s.getOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if n.IsRead {
+		return dto.ToNotificationResponse(n), nil
+	}
+	if err := s.repo.MarkRead(ctx, id); err != nil {
+		s.logger.Error("Failed to mark notification read", err, map[string]interface{}{"user_id": userID, "notification_id": id, "action": "MARK_NOTIFICATION_READ_FAILED"})
+		return nil, err
+	}
+	s.logger.Info("Notification marked read", map[string]interface{}{"user_id": userID, "notification_id": id, "action": "MARK_NOTIFICATION_READ"})
+	s.pushUnreadCount(userID)
+
+	updated, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToNotificationResponse(updated), nil
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID int) (*dto.MarkAllReadResponse, error) {
+	updated, err := s.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to mark all notifications read", err, map[string]interface{}{"user_id": userID, "action": "MARK_ALL_NOTIFICATIONS_READ_FAILED"})
+		return nil, err
+	}
+	s.logger.Info("All notifications marked read", map[string]interface{}{"user_id": userID, "updated": updated, "action": "MARK_ALL_NOTIFICATIONS_READ"})
+	if updated > 0 {
+		s.pushUnreadCount(userID)
+	}
+	return &dto.MarkAllReadResponse{Updated: updated}, nil
+}
+
+func (s *notificationService) Delete(ctx context.Context, id, userID int) error {
+	n, err := s.getOwned(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.Error("Failed to delete notification", err, map[string]interface{}{"user_id": userID, "notification_id": id, "action": "DELETE_NOTIFICATION_FAILED"})
+		return err
+	}
+	s.logger.Info("Notification deleted", map[string]interface{}{"user_id": userID, "notification_id": id, "action": "DELETE_NOTIFICATION_SUCCESS"})
+	if !n.IsRead {
+		s.pushUnreadCount(userID)
+	}
+	return nil
+}
+
+func (s *notificationService) getOwned(ctx context.Context, id, userID int) (*domain.Notification, error) {
+	n, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return nil, errors.New("notification not found")
+	}
+	if n.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+	return n, nil
+}
+
+func (s *notificationService) pushUnreadCount(userID int) {
+	if s.broadcaster != nil {
+		s.broadcaster.PublishUnreadCount(userID)
+	}
+}
@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/domain"
 )
 
@@ -20,6 +21,12 @@ type PersonResponse struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// PersonListResponse is one page of GET /people
+type PersonListResponse struct {
+	Items []*PersonResponse `json:"items"`
+	query.PageInfo
+}
+
 func ToPersonResponse(p *domain.Person) *PersonResponse {
 	if p == nil {
 		return nil
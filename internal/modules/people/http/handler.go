@@ -8,6 +8,7 @@ import (
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/service"
 	"github.com/gorilla/mux"
 )
@@ -58,8 +59,15 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, person, http.StatusOK, "Kişi getirildi")
 }
 
+// GetAll returns one page of the caller's people
+// GET /api/people?relationship=friend&tag=work&sort=-updated_at&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	people, err := h.service.GetAll(r.Context(), h.getUserID(r))
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+	people, err := h.service.GetAll(r.Context(), q, h.getUserID(r))
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Kişiler getirilemedi", err.Error())
 		return
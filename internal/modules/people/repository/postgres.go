@@ -6,6 +6,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/domain"
 	"github.com/jmoiron/sqlx"
 )
@@ -14,12 +16,17 @@ type postgresRepository struct{ db *sqlx.DB }
 
 func NewPostgresRepository(db *sqlx.DB) PersonRepository { return &postgresRepository{db: db} }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, person *domain.Person) (*domain.Person, error) {
 	query := `INSERT INTO people (user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at`
 	now := time.Now()
 	model := FromDomain(person)
-	err := r.db.QueryRowxContext(ctx, query, model.UserID, model.Name, model.Email, model.Phone, model.Company, model.Relationship, model.Tags, model.Notes, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
+	err := r.conn(ctx).QueryRowxContext(ctx, query, model.UserID, model.Name, model.Email, model.Phone, model.Company, model.Relationship, model.Tags, model.Notes, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -27,9 +34,9 @@ func (r *postgresRepository) Create(ctx context.Context, person *domain.Person)
 }
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Person, error) {
-	query := `SELECT id, user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at FROM people WHERE id = $1`
+	query := `SELECT id, user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at FROM people WHERE id = $1 AND deleted_at IS NULL`
 	var model PersonModel
-	if err := r.db.GetContext(ctx, &model, query, id); err != nil {
+	if err := r.conn(ctx).GetContext(ctx, &model, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -38,23 +45,29 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Perso
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Person, error) {
-	query := `SELECT id, user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at FROM people WHERE user_id = $1 ORDER BY name`
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.Person]) ([]*domain.Person, int, error) {
+	where, args := q.Where(`WHERE user_id = $1 AND deleted_at IS NULL`, userID)
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM people `+where, args...); err != nil {
+		return nil, 0, err
+	}
+	page, args := q.Paginate(where, args)
+	sqlQuery := `SELECT id, user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at FROM people ` + page
 	var models []PersonModel
-	if err := r.db.SelectContext(ctx, &models, query, userID); err != nil {
-		return nil, err
+	if err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...); err != nil {
+		return nil, 0, err
 	}
 	people := make([]*domain.Person, len(models))
 	for i, m := range models {
 		people[i] = m.ToDomain()
 	}
-	return people, nil
+	return people, total, nil
 }
 
 func (r *postgresRepository) SearchByTag(ctx context.Context, userID int, tag string) ([]*domain.Person, error) {
-	query := `SELECT id, user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at FROM people WHERE user_id = $1 AND $2 = ANY(tags) ORDER BY name`
+	query := `SELECT id, user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at FROM people WHERE user_id = $1 AND deleted_at IS NULL AND $2 = ANY(tags) ORDER BY name`
 	var models []PersonModel
-	if err := r.db.SelectContext(ctx, &models, query, userID, tag); err != nil {
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID, tag); err != nil {
 		return nil, err
 	}
 	people := make([]*domain.Person, len(models))
@@ -65,9 +78,9 @@ func (r *postgresRepository) SearchByTag(ctx context.Context, userID int, tag st
 }
 
 func (r *postgresRepository) Search(ctx context.Context, userID int, query string) ([]*domain.Person, error) {
-	sqlQuery := `SELECT id, user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at FROM people WHERE user_id = $1 AND (name ILIKE $2 OR company ILIKE $2 OR email ILIKE $2) ORDER BY name`
+	sqlQuery := `SELECT id, user_id, name, email, phone, company, relationship, tags, notes, created_at, updated_at FROM people WHERE user_id = $1 AND deleted_at IS NULL AND (name ILIKE $2 OR company ILIKE $2 OR email ILIKE $2) ORDER BY name`
 	var models []PersonModel
-	if err := r.db.SelectContext(ctx, &models, sqlQuery, userID, "%"+query+"%"); err != nil {
+	if err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, userID, "%"+query+"%"); err != nil {
 		return nil, err
 	}
 	people := make([]*domain.Person, len(models))
@@ -80,11 +93,11 @@ func (r *postgresRepository) Search(ctx context.Context, userID int, query strin
 func (r *postgresRepository) Update(ctx context.Context, person *domain.Person) error {
 	query := `UPDATE people SET name = $1, email = $2, phone = $3, company = $4, relationship = $5, tags = $6, notes = $7, updated_at = $8 WHERE id = $9`
 	model := FromDomain(person)
-	_, err := r.db.ExecContext(ctx, query, model.Name, model.Email, model.Phone, model.Company, model.Relationship, model.Tags, model.Notes, time.Now(), model.ID)
+	_, err := r.conn(ctx).ExecContext(ctx, query, model.Name, model.Email, model.Phone, model.Company, model.Relationship, model.Tags, model.Notes, time.Now(), model.ID)
 	return err
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM people WHERE id = $1`, id)
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE people SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now(), id)
 	return err
 }
@@ -3,13 +3,32 @@ package repository
 import (
 	"context"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/domain"
 )
 
+// ListSpec whitelists the filters and sort keys of GET /people
+var ListSpec = &query.Spec[domain.Person]{
+	Filters: map[string]query.Filter{
+		"relationship": {Column: "relationship", Op: query.Eq},
+		"company":      {Column: "company", Op: query.Eq},
+		"tag":          {Column: "tags", Op: query.Has},
+		"q":            {Column: "name", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.Person]{
+		"name":       {Column: "name", Value: func(p *domain.Person) any { return p.Name }},
+		"created_at": {Column: "created_at", Kind: query.Time, Value: func(p *domain.Person) any { return p.CreatedAt }},
+		"updated_at": {Column: "updated_at", Kind: query.Time, Value: func(p *domain.Person) any { return p.UpdatedAt }},
+	},
+	DefaultSort: "name",
+	ID:          func(p *domain.Person) int { return p.ID },
+}
+
 type PersonRepository interface {
 	Create(ctx context.Context, person *domain.Person) (*domain.Person, error)
 	GetByID(ctx context.Context, id int) (*domain.Person, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.Person, error)
+	// List returns one page of the user's people together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.Person]) ([]*domain.Person, int, error)
 	SearchByTag(ctx context.Context, userID int, tag string) ([]*domain.Person, error)
 	Search(ctx context.Context, userID int, query string) ([]*domain.Person, error)
 	Update(ctx context.Context, person *domain.Person) error
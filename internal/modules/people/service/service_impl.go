@@ -5,40 +5,53 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/people/repository"
-	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 )
 
 type personService struct {
-	repo        repository.PersonRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.PersonRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
 }
 
-func NewPersonService(repo repository.PersonRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) PersonService {
-	return &personService{repo: repo, logger: logger, broadcaster: broadcaster}
+func NewPersonService(repo repository.PersonRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) PersonService {
+	return &personService{repo: repo, uow: uow, logger: logger, bus: bus}
 }
 
 func (s *personService) Create(ctx context.Context, req *dto.CreatePersonRequest, userID int) (*dto.PersonResponse, error) {
 	s.logger.Info("Creating person", map[string]interface{}{"user_id": userID, "name": req.Name, "action": "CREATE_PERSON"})
 	now := time.Now()
 	person := &domain.Person{UserID: userID, Name: req.Name, Email: req.Email, Phone: req.Phone, Company: req.Company, Relationship: req.Relationship, Tags: req.Tags, Notes: req.Notes, CreatedAt: now, UpdatedAt: now}
-	created, err := s.repo.Create(ctx, person)
+	var (
+		created  *domain.Person
+		response *dto.PersonResponse
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, person); err != nil {
+			return err
+		}
+		response = dto.ToPersonResponse(created)
+		return s.publish(ctx, userID, events.PersonCreated{
+			PersonID: created.ID,
+			Person:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create person", err, map[string]interface{}{"user_id": userID, "action": "CREATE_PERSON_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Person created", map[string]interface{}{"user_id": userID, "person_id": created.ID, "action": "CREATE_PERSON_SUCCESS"})
-	response := dto.ToPersonResponse(created)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventPersonCreated, map[string]interface{}{
-			"person_id": created.ID,
-			"person":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventPersonCreated,
 			"user_id":    userID,
@@ -63,12 +76,13 @@ func (s *personService) GetByID(ctx context.Context, id, userID int) (*dto.Perso
 	return dto.ToPersonResponse(person), nil
 }
 
-func (s *personService) GetAll(ctx context.Context, userID int) ([]*dto.PersonResponse, error) {
-	people, err := s.repo.GetByUserID(ctx, userID)
+func (s *personService) GetAll(ctx context.Context, q *query.Query[domain.Person], userID int) (*dto.PersonListResponse, error) {
+	people, total, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
-	return dto.ToPersonResponseList(people), nil
+	people, page := q.Result(people, total)
+	return &dto.PersonListResponse{Items: dto.ToPersonResponseList(people), PageInfo: page}, nil
 }
 
 func (s *personService) SearchByTag(ctx context.Context, userID int, tag string) ([]*dto.PersonResponse, error) {
@@ -89,49 +103,56 @@ func (s *personService) Search(ctx context.Context, userID int, query string) ([
 
 func (s *personService) Update(ctx context.Context, id int, req *dto.UpdatePersonRequest, userID int) (*dto.PersonResponse, error) {
 	s.logger.Info("Updating person", map[string]interface{}{"user_id": userID, "person_id": id, "action": "UPDATE_PERSON"})
-	person, err := s.repo.GetByID(ctx, id)
+	var response *dto.PersonResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		person, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if person == nil {
+			return errors.New("person not found")
+		}
+		if person.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToPersonResponse(person)
+		if req.Name != nil {
+			person.Name = *req.Name
+		}
+		if req.Email != nil {
+			person.Email = *req.Email
+		}
+		if req.Phone != nil {
+			person.Phone = *req.Phone
+		}
+		if req.Company != nil {
+			person.Company = *req.Company
+		}
+		if req.Relationship != nil {
+			person.Relationship = *req.Relationship
+		}
+		if req.Tags != nil {
+			person.Tags = req.Tags
+		}
+		if req.Notes != nil {
+			person.Notes = *req.Notes
+		}
+		person.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, person); err != nil {
+			return err
+		}
+		response = dto.ToPersonResponse(person)
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.PersonUpdated{
+			PersonID: id,
+			Person:   response,
+		})
+	})
 	if err != nil {
-		return nil, err
-	}
-	if person == nil {
-		return nil, errors.New("person not found")
-	}
-	if person.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
-	if req.Name != nil {
-		person.Name = *req.Name
-	}
-	if req.Email != nil {
-		person.Email = *req.Email
-	}
-	if req.Phone != nil {
-		person.Phone = *req.Phone
-	}
-	if req.Company != nil {
-		person.Company = *req.Company
-	}
-	if req.Relationship != nil {
-		person.Relationship = *req.Relationship
-	}
-	if req.Tags != nil {
-		person.Tags = req.Tags
-	}
-	if req.Notes != nil {
-		person.Notes = *req.Notes
-	}
-	person.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, person); err != nil {
 		s.logger.Error("Failed to update person", err, map[string]interface{}{"user_id": userID, "person_id": id, "action": "UPDATE_PERSON_FAILED"})
 		return nil, err
 	}
 	s.logger.Info("Person updated", map[string]interface{}{"user_id": userID, "person_id": id, "action": "UPDATE_PERSON_SUCCESS"})
-	response := dto.ToPersonResponse(person)
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventPersonUpdated, map[string]interface{}{
-			"person_id": id,
-			"person":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventPersonUpdated,
 			"user_id":    userID,
@@ -144,26 +165,32 @@ func (s *personService) Update(ctx context.Context, id int, req *dto.UpdatePerso
 
 func (s *personService) Delete(ctx context.Context, id, userID int) error {
 	s.logger.Info("Deleting person", map[string]interface{}{"user_id": userID, "person_id": id, "action": "DELETE_PERSON"})
-	person, err := s.repo.GetByID(ctx, id)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		person, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if person == nil {
+			return errors.New("person not found")
+		}
+		if person.UserID != userID {
+			return errors.New("unauthorized")
+		}
+		before := dto.ToPersonResponse(person)
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.PersonDeleted{
+			PersonID: id,
+			Name:     person.Name,
+		})
+	})
 	if err != nil {
-		return err
-	}
-	if person == nil {
-		return errors.New("person not found")
-	}
-	if person.UserID != userID {
-		return errors.New("unauthorized")
-	}
-	if err := s.repo.Delete(ctx, id); err != nil {
 		s.logger.Error("Failed to delete person", err, map[string]interface{}{"user_id": userID, "person_id": id, "action": "DELETE_PERSON_FAILED"})
 		return err
 	}
 	s.logger.Info("Person deleted", map[string]interface{}{"user_id": userID, "person_id": id, "action": "DELETE_PERSON_SUCCESS"})
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventPersonDeleted, map[string]interface{}{
-			"person_id": id,
-			"name":      person.Name,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventPersonDeleted,
 			"user_id":    userID,
@@ -173,3 +200,12 @@ func (s *personService) Delete(ctx context.Context, id, userID int) error {
 	}
 	return nil
 }
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *personService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
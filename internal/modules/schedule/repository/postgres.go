@@ -6,6 +6,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/schedule/domain"
 	"github.com/jmoiron/sqlx"
 )
@@ -16,11 +17,16 @@ func NewBlockedTimeSlotRepository(db *sqlx.DB) BlockedTimeSlotRepository {
 	return &postgresBlockedSlotRepo{db: db}
 }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresBlockedSlotRepo) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresBlockedSlotRepo) Create(ctx context.Context, s *domain.BlockedTimeSlot) (*domain.BlockedTimeSlot, error) {
 	query := `INSERT INTO blocked_time_slots (user_id, source_type, source_id, start_datetime, end_datetime, reason, is_flexible, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
 	now := time.Now()
 	model := BlockedTimeSlotFromDomain(s)
-	err := r.db.QueryRowxContext(ctx, query, model.UserID, model.SourceType, model.SourceID, model.StartDatetime, model.EndDatetime, model.Reason, model.IsFlexible, now).Scan(&model.ID, &model.CreatedAt)
+	err := r.conn(ctx).QueryRowxContext(ctx, query, model.UserID, model.SourceType, model.SourceID, model.StartDatetime, model.EndDatetime, model.Reason, model.IsFlexible, now).Scan(&model.ID, &model.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +35,7 @@ func (r *postgresBlockedSlotRepo) Create(ctx context.Context, s *domain.BlockedT
 
 func (r *postgresBlockedSlotRepo) GetByID(ctx context.Context, id int) (*domain.BlockedTimeSlot, error) {
 	var model BlockedTimeSlotModel
-	if err := r.db.GetContext(ctx, &model, `SELECT * FROM blocked_time_slots WHERE id = $1`, id); err != nil {
+	if err := r.conn(ctx).GetContext(ctx, &model, `SELECT * FROM blocked_time_slots WHERE id = $1`, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -40,7 +46,7 @@ func (r *postgresBlockedSlotRepo) GetByID(ctx context.Context, id int) (*domain.
 
 func (r *postgresBlockedSlotRepo) GetByUserAndTimeRange(ctx context.Context, userID int, start, end time.Time) ([]*domain.BlockedTimeSlot, error) {
 	var models []BlockedTimeSlotModel
-	if err := r.db.SelectContext(ctx, &models, `SELECT * FROM blocked_time_slots WHERE user_id = $1 AND start_datetime < $3 AND end_datetime > $2 ORDER BY start_datetime`, userID, start, end); err != nil {
+	if err := r.conn(ctx).SelectContext(ctx, &models, `SELECT * FROM blocked_time_slots WHERE user_id = $1 AND start_datetime < $3 AND end_datetime > $2 ORDER BY start_datetime`, userID, start, end); err != nil {
 		return nil, err
 	}
 	result := make([]*domain.BlockedTimeSlot, len(models))
@@ -58,7 +64,7 @@ func (r *postgresBlockedSlotRepo) GetByUserAndDate(ctx context.Context, userID i
 
 func (r *postgresBlockedSlotRepo) GetBySource(ctx context.Context, sourceType string, sourceID int) ([]*domain.BlockedTimeSlot, error) {
 	var models []BlockedTimeSlotModel
-	if err := r.db.SelectContext(ctx, &models, `SELECT * FROM blocked_time_slots WHERE source_type = $1 AND source_id = $2`, sourceType, sourceID); err != nil {
+	if err := r.conn(ctx).SelectContext(ctx, &models, `SELECT * FROM blocked_time_slots WHERE source_type = $1 AND source_id = $2`, sourceType, sourceID); err != nil {
 		return nil, err
 	}
 	result := make([]*domain.BlockedTimeSlot, len(models))
@@ -70,22 +76,22 @@ func (r *postgresBlockedSlotRepo) GetBySource(ctx context.Context, sourceType st
 
 func (r *postgresBlockedSlotRepo) Update(ctx context.Context, s *domain.BlockedTimeSlot) error {
 	model := BlockedTimeSlotFromDomain(s)
-	_, err := r.db.ExecContext(ctx, `UPDATE blocked_time_slots SET start_datetime = $1, end_datetime = $2, reason = $3, is_flexible = $4 WHERE id = $5`, model.StartDatetime, model.EndDatetime, model.Reason, model.IsFlexible, model.ID)
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE blocked_time_slots SET start_datetime = $1, end_datetime = $2, reason = $3, is_flexible = $4 WHERE id = $5`, model.StartDatetime, model.EndDatetime, model.Reason, model.IsFlexible, model.ID)
 	return err
 }
 
 func (r *postgresBlockedSlotRepo) Delete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM blocked_time_slots WHERE id = $1`, id)
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM blocked_time_slots WHERE id = $1`, id)
 	return err
 }
 
 func (r *postgresBlockedSlotRepo) DeleteBySource(ctx context.Context, sourceType string, sourceID int) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM blocked_time_slots WHERE source_type = $1 AND source_id = $2`, sourceType, sourceID)
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM blocked_time_slots WHERE source_type = $1 AND source_id = $2`, sourceType, sourceID)
 	return err
 }
 
 func (r *postgresBlockedSlotRepo) DeleteOld(ctx context.Context, before time.Time) (int, error) {
-	result, err := r.db.ExecContext(ctx, `DELETE FROM blocked_time_slots WHERE end_datetime < $1`, before)
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM blocked_time_slots WHERE end_datetime < $1`, before)
 	if err != nil {
 		return 0, err
 	}
@@ -5,9 +5,10 @@ import (
 	"sort"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
-	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/schedule/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/schedule/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/schedule/repository"
@@ -15,12 +16,13 @@ import (
 
 type scheduleService struct {
 	blockedSlotRepo repository.BlockedTimeSlotRepository
+	uow             *database.UnitOfWork
 	logger          *logger.ZapLogger
-	broadcaster     *notifService.Broadcaster
+	bus             *eventbus.Bus
 }
 
-func NewScheduleService(blockedSlotRepo repository.BlockedTimeSlotRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) ScheduleService {
-	return &scheduleService{blockedSlotRepo: blockedSlotRepo, logger: logger, broadcaster: broadcaster}
+func NewScheduleService(blockedSlotRepo repository.BlockedTimeSlotRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus) ScheduleService {
+	return &scheduleService{blockedSlotRepo: blockedSlotRepo, uow: uow, logger: logger, bus: bus}
 }
 
 // CheckConflict implements the conflict detection algorithm
@@ -41,12 +43,12 @@ func (s *scheduleService) CheckConflict(ctx context.Context, userID int, start,
 			suggestionDTOs := make([]*dto.TimeSlotResponse, len(suggestions))
 			copy(suggestionDTOs, suggestions)
 
-			if s.broadcaster != nil {
-				s.broadcaster.Publish(userID, notification.EventConflictDetected, map[string]interface{}{
-					"reason": slot.Reason,
-					"start":  start,
-					"end":    end,
-				})
+			if err := s.publish(ctx, userID, events.CalendarConflict{
+				Reason: slot.Reason,
+				Start:  start,
+				End:    end,
+			}); err != nil {
+				return nil, err
 			}
 
 			return &dto.ConflictResponse{HasConflict: true, Reason: slot.Reason, Suggestions: suggestionDTOs}, nil
@@ -185,39 +187,54 @@ func (s *scheduleService) GenerateEventsForSchedule(ctx context.Context, userID
 	eventsGenerated := 0
 	blockedSlotsCreated := 0
 
-	// Loop weekly until semester end
-	for !current.After(req.SemesterEndDate) {
-		// Skip excluded dates
-		excluded := false
-		for _, excl := range req.ExcludeDates {
-			if current.Year() == excl.Year() && current.Month() == excl.Month() && current.Day() == excl.Day() {
-				excluded = true
-				break
-			}
-		}
-
-		if !excluded {
-			// Create blocked time slot for each occurrence
-			eventStart := time.Date(current.Year(), current.Month(), current.Day(), startTime.Hour(), startTime.Minute(), 0, 0, current.Location())
-			eventEnd := time.Date(current.Year(), current.Month(), current.Day(), endTime.Hour(), endTime.Minute(), 0, 0, current.Location())
-
-			slot := &domain.BlockedTimeSlot{
-				UserID:        userID,
-				SourceType:    "course",
-				SourceID:      req.CourseScheduleID,
-				StartDatetime: eventStart,
-				EndDatetime:   eventEnd,
-				Reason:        req.Title + " (" + req.Location + ")",
-				IsFlexible:    false,
+	// The semester is generated as a whole: a failed week rolls back the others
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		// Loop weekly until semester end
+		for !current.After(req.SemesterEndDate) {
+			// Skip excluded dates
+			excluded := false
+			for _, excl := range req.ExcludeDates {
+				if current.Year() == excl.Year() && current.Month() == excl.Month() && current.Day() == excl.Day() {
+					excluded = true
+					break
+				}
 			}
 
-			if _, err := s.blockedSlotRepo.Create(ctx, slot); err == nil {
+			if !excluded {
+				// Create blocked time slot for each occurrence
+				eventStart := time.Date(current.Year(), current.Month(), current.Day(), startTime.Hour(), startTime.Minute(), 0, 0, current.Location())
+				eventEnd := time.Date(current.Year(), current.Month(), current.Day(), endTime.Hour(), endTime.Minute(), 0, 0, current.Location())
+
+				slot := &domain.BlockedTimeSlot{
+					UserID:        userID,
+					SourceType:    "course",
+					SourceID:      req.CourseScheduleID,
+					StartDatetime: eventStart,
+					EndDatetime:   eventEnd,
+					Reason:        req.Title + " (" + req.Location + ")",
+					IsFlexible:    false,
+				}
+
+				if _, err := s.blockedSlotRepo.Create(ctx, slot); err != nil {
+					return err
+				}
 				blockedSlotsCreated++
 				eventsGenerated++
 			}
+
+			current = current.AddDate(0, 0, 7) // Next week
 		}
 
-		current = current.AddDate(0, 0, 7) // Next week
+		return s.publish(ctx, userID, events.CalendarEventsGenerated{
+			Title:         req.Title,
+			EventsCreated: eventsGenerated,
+			SemesterStart: req.SemesterStartDate,
+			SemesterEnd:   req.SemesterEndDate,
+		})
+	})
+	if err != nil {
+		s.logger.Error("Failed to generate semester events", err, map[string]interface{}{"user_id": userID, "action": "GENERATE_EVENTS_FAILED"})
+		return nil, err
 	}
 
 	s.logger.Info("Semester events generated", map[string]interface{}{
@@ -227,14 +244,14 @@ func (s *scheduleService) GenerateEventsForSchedule(ctx context.Context, userID
 		"action":                "GENERATE_EVENTS_SUCCESS",
 	})
 
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventEventsGenerated, map[string]interface{}{
-			"title":          req.Title,
-			"events_created": eventsGenerated,
-			"semester_start": req.SemesterStartDate,
-			"semester_end":   req.SemesterEndDate,
-		})
-	}
-
 	return &dto.GenerateEventsResponse{EventsGenerated: eventsGenerated, BlockedSlotsCreated: blockedSlotsCreated}, nil
 }
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *scheduleService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Log levels written by the application logger
+const (
+	LevelInfo  = "INFO"
+	LevelError = "ERROR"
+)
+
+// IsLevel reports whether level is one the logger writes
+func IsLevel(level string) bool {
+	return level == LevelInfo || level == LevelError
+}
+
+// Entry is one row of system_logs
+type Entry struct {
+	ID          int
+	Level       string
+	Message     string
+	Details     json.RawMessage
+	IsPermanent bool
+	CreatedAt   time.Time
+}
+
+// Filter narrows a log listing. Zero values match everything.
+type Filter struct {
+	Level  string
+	Action string
+	UserID int
+	Search string
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
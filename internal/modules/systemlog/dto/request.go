@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// ListLogsRequest is built from the query string of GET /logs
+type ListLogsRequest struct {
+	Level  string     `json:"level,omitempty"`
+	Action string     `json:"action,omitempty"`
+	UserID int        `json:"user_id,omitempty" validate:"min=0"`
+	Query  string     `json:"q,omitempty"`
+	From   *time.Time `json:"from,omitempty"`
+	To     *time.Time `json:"to,omitempty"`
+	Page   int        `json:"page" validate:"min=1"`
+	Limit  int        `json:"limit" validate:"min=1,max=200"`
+}
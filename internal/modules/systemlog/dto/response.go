@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/domain"
+)
+
+type LogEntryResponse struct {
+	ID          int             `json:"id"`
+	Level       string          `json:"level"`
+	Message     string          `json:"message"`
+	Details     json.RawMessage `json:"details,omitempty"`
+	IsPermanent bool            `json:"is_permanent"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type LogListResponse struct {
+	Entries []*LogEntryResponse `json:"entries"`
+	Total   int                 `json:"total"`
+	Page    int                 `json:"page"`
+	Limit   int                 `json:"limit"`
+}
+
+func ToLogEntryResponse(e *domain.Entry) *LogEntryResponse {
+	if e == nil {
+		return nil
+	}
+	return &LogEntryResponse{ID: e.ID, Level: e.Level, Message: e.Message, Details: e.Details, IsPermanent: e.IsPermanent, CreatedAt: e.CreatedAt}
+}
+
+func ToLogEntryResponseList(entries []*domain.Entry) []*LogEntryResponse {
+	result := make([]*LogEntryResponse, len(entries))
+	for i, e := range entries {
+		result[i] = ToLogEntryResponse(e)
+	}
+	return result
+}
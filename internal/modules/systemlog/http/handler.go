@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/middleware"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/dto"
+	logService "github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/service"
+	"github.com/gorilla/mux"
+)
+
+const defaultPageSize = 50
+
+type Handler struct {
+	service logService.LogService
+}
+
+func NewHandler(service logService.LogService) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/logs", middleware.Require(authz.LogsRead, h.List)).Methods("GET")
+}
+
+// List returns system logs, newest first
+// GET /api/logs?level=ERROR&action=LOGIN_FAILED&user_id=1&q=timeout&from=...&to=...&page=1&limit=50
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.ListLogsRequest{
+		Level:  query.Get("level"),
+		Action: query.Get("action"),
+		Query:  query.Get("q"),
+		Page:   1,
+		Limit:  defaultPageSize,
+	}
+
+	for name, target := range map[string]*int{"page": &req.Page, "limit": &req.Limit, "user_id": &req.UserID} {
+		if value := query.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi: "+name, err.Error())
+				return
+			}
+			*target = n
+		}
+	}
+	for name, target := range map[string]**time.Time{"from": &req.From, "to": &req.To} {
+		if value := query.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				utils.ReturnError(w, "BAD_REQUEST", "Geçersiz tarih: "+name, err.Error())
+				return
+			}
+			*target = &t
+		}
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	logs, err := h.service.List(r.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid log level":
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz log seviyesi", err.Error())
+		case "invalid time range":
+			utils.ReturnError(w, "BAD_REQUEST", "Başlangıç zamanı bitişten önce olmalı", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Loglar getirilemedi", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, logs, http.StatusOK, "Loglar getirildi")
+}
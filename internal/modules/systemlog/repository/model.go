@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/domain"
+)
+
+type EntryModel struct {
+	ID          int       `db:"id"`
+	Level       string    `db:"level"`
+	Message     string    `db:"message"`
+	Details     []byte    `db:"details"`
+	IsPermanent *bool     `db:"is_permanent"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (m *EntryModel) ToDomain() *domain.Entry {
+	entry := &domain.Entry{ID: m.ID, Level: m.Level, Message: m.Message, Details: m.Details, CreatedAt: m.CreatedAt}
+	if m.IsPermanent != nil {
+		entry.IsPermanent = *m.IsPermanent
+	}
+	return entry
+}
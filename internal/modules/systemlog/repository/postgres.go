@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type postgresRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresRepository(db *sqlx.DB) LogRepository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) List(ctx context.Context, filter domain.Filter) ([]*domain.Entry, int, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Level != "" {
+		add("level = $%d", filter.Level)
+	}
+	if filter.Action != "" {
+		add("details->>'action' = $%d", filter.Action)
+	}
+	if filter.UserID != 0 {
+		add("details->>'user_id' = $%d", fmt.Sprint(filter.UserID))
+	}
+	if filter.Search != "" {
+		add("message ILIKE '%%' || $%d || '%%'", filter.Search)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM system_logs `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, level, message, details, is_permanent, created_at
+		FROM system_logs
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+
+	var models []EntryModel
+	if err := r.db.SelectContext(ctx, &models, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]*domain.Entry, len(models))
+	for i := range models {
+		entries[i] = models[i].ToDomain()
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/domain"
+)
+
+type LogRepository interface {
+	// List returns matching entries newest first, with the total number of matches
+	List(ctx context.Context, filter domain.Filter) ([]*domain.Entry, int, error)
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/dto"
+)
+
+type LogService interface {
+	List(ctx context.Context, req *dto.ListLogsRequest) (*dto.LogListResponse, error)
+}
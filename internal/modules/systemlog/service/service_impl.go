@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/systemlog/repository"
+)
+
+type logService struct {
+	repo   repository.LogRepository
+	logger *logger.ZapLogger
+}
+
+func NewLogService(repo repository.LogRepository, logger *logger.ZapLogger) LogService {
+	return &logService{repo: repo, logger: logger}
+}
+
+func (s *logService) List(ctx context.Context, req *dto.ListLogsRequest) (*dto.LogListResponse, error) {
+	level := strings.ToUpper(req.Level)
+	if level != "" && !domain.IsLevel(level) {
+		return nil, errors.New("invalid log level")
+	}
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, errors.New("invalid time range")
+	}
+
+	filter := domain.Filter{
+		Level:  level,
+		Action: strings.ToUpper(req.Action),
+		UserID: req.UserID,
+		Search: req.Query,
+		From:   req.From,
+		To:     req.To,
+		Limit:  req.Limit,
+		Offset: (req.Page - 1) * req.Limit,
+	}
+	entries, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LogListResponse{
+		Entries: dto.ToLogEntryResponseList(entries),
+		Total:   total,
+		Page:    req.Page,
+		Limit:   req.Limit,
+	}, nil
+}
@@ -5,6 +5,7 @@ import "time"
 type Task struct {
 	ID                 int
 	UserID             int
+	WorkspaceID        *int
 	ParentTaskID       *int
 	Title              string
 	Description        string
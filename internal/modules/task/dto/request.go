@@ -3,6 +3,8 @@ package dto
 import "time"
 
 type CreateTaskRequest struct {
+	// WorkspaceID shares the task with a workspace; subtasks always follow their parent
+	WorkspaceID    *int       `json:"workspace_id,omitempty" validate:"omitempty,min=1"`
 	ParentTaskID   *int       `json:"parent_task_id,omitempty"`
 	Title          string     `json:"title" validate:"required,min=1,max=255"`
 	Description    string     `json:"description,omitempty"`
@@ -23,4 +25,6 @@ type UpdateTaskRequest struct {
 	Priority       *string    `json:"priority,omitempty" validate:"omitempty,oneof=low medium high"`
 	IsCompleted    *bool      `json:"is_completed,omitempty"`
 	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	// WorkspaceID moves the task and its subtasks to a workspace, or back to personal with 0
+	WorkspaceID *int `json:"workspace_id,omitempty" validate:"omitempty,min=0"`
 }
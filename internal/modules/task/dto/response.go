@@ -3,12 +3,14 @@ package dto
 import (
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/domain"
 )
 
 type TaskResponse struct {
 	ID                   int        `json:"id"`
 	UserID               int        `json:"user_id"`
+	WorkspaceID          *int       `json:"workspace_id,omitempty"`
 	ParentTaskID         *int       `json:"parent_task_id,omitempty"`
 	Title                string     `json:"title"`
 	Description          string     `json:"description,omitempty"`
@@ -45,6 +47,7 @@ func ToTaskResponse(t *domain.Task, totalSubtasks, completedSubtasks int) *TaskR
 	return &TaskResponse{
 		ID:                   t.ID,
 		UserID:               t.UserID,
+		WorkspaceID:          t.WorkspaceID,
 		ParentTaskID:         t.ParentTaskID,
 		Title:                t.Title,
 		Description:          t.Description,
@@ -79,6 +82,12 @@ func ToTaskResponseList(tasks []*domain.Task) []*TaskResponse {
 	return result
 }
 
+// TaskListResponse is one page of GET /tasks
+type TaskListResponse struct {
+	Items []*TaskResponse `json:"items"`
+	query.PageInfo
+}
+
 type TaskStatsResponse struct {
 	CompletedToday int `json:"completed_today"`
 	DueToday       int `json:"due_today"`
@@ -7,31 +7,34 @@ import (
 
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/jobs"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	jobimpl "github.com/M1ralai/go-modular-monolith-template/internal/modules/job/jobs"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/repository"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/service"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace"
 	"github.com/gorilla/mux"
 )
 
 type Handler struct {
-	service     service.TaskService
-	jobPool     *jobs.WorkerPool
-	repo        repository.TaskRepository
-	broadcaster *notifService.Broadcaster
-	logger      *logger.ZapLogger
+	service service.TaskService
+	jobPool *jobs.WorkerPool
+	repo    repository.TaskRepository
+	uow     *database.UnitOfWork
+	access  *workspace.Access
+	logger  *logger.ZapLogger
 }
 
-func NewHandler(service service.TaskService, jobPool *jobs.WorkerPool, repo repository.TaskRepository, broadcaster *notifService.Broadcaster, logger *logger.ZapLogger) *Handler {
+func NewHandler(service service.TaskService, jobPool *jobs.WorkerPool, repo repository.TaskRepository, uow *database.UnitOfWork, access *workspace.Access, logger *logger.ZapLogger) *Handler {
 	return &Handler{
-		service:     service,
-		jobPool:     jobPool,
-		repo:        repo,
-		broadcaster: broadcaster,
-		logger:      logger,
+		service: service,
+		jobPool: jobPool,
+		repo:    repo,
+		uow:     uow,
+		access:  access,
+		logger:  logger,
 	}
 }
 
@@ -71,6 +74,14 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	userID := h.getUserID(r)
 	task, err := h.service.Create(r.Context(), &req, userID)
 	if err != nil {
+		if err.Error() == "parent task not found" {
+			utils.ReturnError(w, "NOT_FOUND", "Ana görev bulunamadı", err.Error())
+			return
+		}
+		if err.Error() == "unauthorized" {
+			utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
+			return
+		}
 		utils.ReturnError(w, "INTERNAL_ERROR", "Görev oluşturulamadı", err.Error())
 		return
 	}
@@ -104,9 +115,17 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, task, http.StatusOK, "Görev getirildi")
 }
 
+// GetAll returns one page of the caller's tasks
+// GET /api/tasks?priority=high&is_completed=false&due_before=2026-01-01&sort=due_date,-priority&limit=50&cursor=...
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q, err := repository.ListSpec.Parse(r.URL.Query())
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sorgu parametresi", err.Error())
+		return
+	}
+
 	userID := h.getUserID(r)
-	tasks, err := h.service.GetAll(r.Context(), userID)
+	tasks, err := h.service.GetAll(r.Context(), q, userID)
 	if err != nil {
 		utils.ReturnError(w, "INTERNAL_ERROR", "Görevler getirilemedi", err.Error())
 		return
@@ -173,9 +192,9 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 
 	userID := h.getUserID(r)
 
-	// Submit job to pool asynchronously
-	if h.jobPool != nil {
-		updateJob := jobimpl.NewTaskUpdateJob(h.logger, h.repo, h.broadcaster, id, userID, &req)
+	// Submit job to pool asynchronously; moves between workspaces are checked synchronously
+	if h.jobPool != nil && req.WorkspaceID == nil {
+		updateJob := jobimpl.NewTaskUpdateJob(h.logger, h.repo, h.uow, h.access, id, userID, &req)
 		if err := h.jobPool.SubmitAsync(updateJob); err != nil {
 			h.logger.Error("Failed to submit task update job", err, map[string]interface{}{
 				"task_id": id,
@@ -211,6 +230,10 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 			utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
 			return
 		}
+		if err.Error() == "subtasks follow their parent task" {
+			utils.ReturnError(w, "BAD_REQUEST", "Alt görevler ana görevin çalışma alanında kalır", err.Error())
+			return
+		}
 		utils.ReturnError(w, "INTERNAL_ERROR", "Görev güncellenemedi", err.Error())
 		return
 	}
@@ -9,6 +9,7 @@ import (
 type TaskModel struct {
 	ID                 int        `db:"id"`
 	UserID             int        `db:"user_id"`
+	WorkspaceID        *int       `db:"workspace_id"`
 	ParentTaskID       *int       `db:"parent_task_id"`
 	Title              string     `db:"title"`
 	Description        *string    `db:"description"`
@@ -38,6 +39,7 @@ func (m *TaskModel) ToDomain() *domain.Task {
 	return &domain.Task{
 		ID:                 m.ID,
 		UserID:             m.UserID,
+		WorkspaceID:        m.WorkspaceID,
 		ParentTaskID:       m.ParentTaskID,
 		Title:              m.Title,
 		Description:        description,
@@ -68,6 +70,7 @@ func FromDomain(t *domain.Task) *TaskModel {
 	return &TaskModel{
 		ID:                 t.ID,
 		UserID:             t.UserID,
+		WorkspaceID:        t.WorkspaceID,
 		ParentTaskID:       t.ParentTaskID,
 		Title:              t.Title,
 		Description:        description,
@@ -4,12 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/domain"
 	"github.com/jmoiron/sqlx"
 )
 
+// visibleTo matches the personal tasks of user $1 and every task in the workspaces they belong to
+const visibleTo = `(workspace_id IS NULL AND user_id = $1 OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1))`
+
+// localDate is the calendar day of a task timestamp in the timezone named by $3, or in the
+// database session's timezone when $3 is empty
+const localDate = `DATE(%s::timestamptz AT TIME ZONE COALESCE(NULLIF($3, ''), current_setting('TimeZone')))`
+
 type postgresRepository struct {
 	db *sqlx.DB
 }
@@ -18,21 +28,27 @@ func NewPostgresRepository(db *sqlx.DB) TaskRepository {
 	return &postgresRepository{db: db}
 }
 
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
 func (r *postgresRepository) Create(ctx context.Context, task *domain.Task) (*domain.Task, error) {
 	query := `
-		INSERT INTO tasks (user_id, parent_task_id, title, description, due_date,
+		INSERT INTO tasks (user_id, workspace_id, parent_task_id, title, description, due_date,
 						   estimated_start, estimated_end, priority, is_completed,
 						   progress_percentage, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id, created_at, updated_at
 	`
 
 	now := time.Now()
 	model := FromDomain(task)
 
-	err := r.db.QueryRowxContext(
+	err := r.conn(ctx).QueryRowxContext(
 		ctx, query,
 		model.UserID,
+		model.WorkspaceID,
 		model.ParentTaskID,
 		model.Title,
 		model.Description,
@@ -55,16 +71,16 @@ func (r *postgresRepository) Create(ctx context.Context, task *domain.Task) (*do
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Task, error) {
 	query := `
-		SELECT id, user_id, parent_task_id, title, description, due_date,
+		SELECT id, user_id, workspace_id, parent_task_id, title, description, due_date,
 			   estimated_start, estimated_end, actual_start, actual_end,
 			   priority, is_completed, completed_at, progress_percentage,
 			   created_at, updated_at
 		FROM tasks
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var model TaskModel
-	err := r.db.GetContext(ctx, &model, query, id)
+	err := r.conn(ctx).GetContext(ctx, &model, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -75,21 +91,27 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.Task,
 	return model.ToDomain(), nil
 }
 
-func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Task, error) {
-	query := `
-		SELECT id, user_id, parent_task_id, title, description, due_date,
+func (r *postgresRepository) List(ctx context.Context, userID int, q *query.Query[domain.Task]) ([]*domain.Task, int, error) {
+	where, args := q.Where(`WHERE `+visibleTo+` AND deleted_at IS NULL`, userID)
+
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM tasks `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	page, args := q.Paginate(where, args)
+	sqlQuery := `
+		SELECT id, user_id, workspace_id, parent_task_id, title, description, due_date,
 			   estimated_start, estimated_end, actual_start, actual_end,
 			   priority, is_completed, completed_at, progress_percentage,
 			   created_at, updated_at
 		FROM tasks
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-	`
+		` + page
 
 	var models []TaskModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &models, sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	tasks := make([]*domain.Task, len(models))
@@ -97,22 +119,22 @@ func (r *postgresRepository) GetByUserID(ctx context.Context, userID int) ([]*do
 		tasks[i] = m.ToDomain()
 	}
 
-	return tasks, nil
+	return tasks, total, nil
 }
 
 func (r *postgresRepository) GetSubtasks(ctx context.Context, parentID int) ([]*domain.Task, error) {
 	query := `
-		SELECT id, user_id, parent_task_id, title, description, due_date,
+		SELECT id, user_id, workspace_id, parent_task_id, title, description, due_date,
 			   estimated_start, estimated_end, actual_start, actual_end,
 			   priority, is_completed, completed_at, progress_percentage,
 			   created_at, updated_at
 		FROM tasks
-		WHERE parent_task_id = $1
+		WHERE parent_task_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at ASC
 	`
 
 	var models []TaskModel
-	err := r.db.SelectContext(ctx, &models, query, parentID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, parentID)
 	if err != nil {
 		return nil, err
 	}
@@ -127,17 +149,17 @@ func (r *postgresRepository) GetSubtasks(ctx context.Context, parentID int) ([]*
 
 func (r *postgresRepository) GetParentTasks(ctx context.Context, userID int) ([]*domain.Task, error) {
 	query := `
-		SELECT id, user_id, parent_task_id, title, description, due_date,
+		SELECT id, user_id, workspace_id, parent_task_id, title, description, due_date,
 			   estimated_start, estimated_end, actual_start, actual_end,
 			   priority, is_completed, completed_at, progress_percentage,
 			   created_at, updated_at
 		FROM tasks
-		WHERE user_id = $1 AND parent_task_id IS NULL
+		WHERE ` + visibleTo + ` AND parent_task_id IS NULL AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
 	var models []TaskModel
-	err := r.db.SelectContext(ctx, &models, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &models, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -155,12 +177,12 @@ func (r *postgresRepository) Update(ctx context.Context, task *domain.Task) erro
 		UPDATE tasks
 		SET title = $1, description = $2, due_date = $3, estimated_start = $4,
 			estimated_end = $5, actual_start = $6, actual_end = $7, priority = $8,
-			is_completed = $9, completed_at = $10, progress_percentage = $11, updated_at = $12
-		WHERE id = $13
+			is_completed = $9, completed_at = $10, progress_percentage = $11, workspace_id = $12, updated_at = $13
+		WHERE id = $14
 	`
 
 	model := FromDomain(task)
-	_, err := r.db.ExecContext(
+	_, err := r.conn(ctx).ExecContext(
 		ctx, query,
 		model.Title,
 		model.Description,
@@ -173,6 +195,7 @@ func (r *postgresRepository) Update(ctx context.Context, task *domain.Task) erro
 		model.IsCompleted,
 		model.CompletedAt,
 		model.ProgressPercentage,
+		model.WorkspaceID,
 		time.Now(),
 		model.ID,
 	)
@@ -180,9 +203,34 @@ func (r *postgresRepository) Update(ctx context.Context, task *domain.Task) erro
 	return err
 }
 
+// MoveSubtasks puts every subtask below the task, trashed ones included, into its workspace
+func (r *postgresRepository) MoveSubtasks(ctx context.Context, parentID int, workspaceID *int) error {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id FROM tasks WHERE parent_task_id = $1
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN tree ON t.parent_task_id = tree.id
+		)
+		UPDATE tasks SET workspace_id = $2, updated_at = $3 WHERE id IN (SELECT id FROM tree)
+	`
+	_, err := r.conn(ctx).ExecContext(ctx, query, parentID, workspaceID, time.Now())
+	return err
+}
+
+// Delete moves the task to the trash together with its live subtasks, which are marked
+// deleted_by_parent so restoring the task brings them back
 func (r *postgresRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM tasks WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id FROM tasks WHERE id = $1
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN tree ON t.parent_task_id = tree.id WHERE t.deleted_at IS NULL
+		)
+		UPDATE tasks
+		SET deleted_at = $2, deleted_by_parent = (id <> $1)
+		WHERE id IN (SELECT id FROM tree) AND deleted_at IS NULL
+	`
+	_, err := r.conn(ctx).ExecContext(ctx, query, id, time.Now())
 	return err
 }
 
@@ -192,24 +240,38 @@ func (r *postgresRepository) CountSubtasks(ctx context.Context, parentID int) (t
 			COUNT(*) as total,
 			COUNT(*) FILTER (WHERE is_completed = true) as completed
 		FROM tasks
-		WHERE parent_task_id = $1
+		WHERE parent_task_id = $1 AND deleted_at IS NULL
 	`
 
-	err = r.db.QueryRowxContext(ctx, query, parentID).Scan(&total, &completed)
+	err = r.conn(ctx).QueryRowxContext(ctx, query, parentID).Scan(&total, &completed)
 	return total, completed, err
 }
 
 func (r *postgresRepository) GetStats(ctx context.Context, userID int) (completedToday, dueToday, dueTomorrow, overdue int, err error) {
+	return r.GetStatsOn(ctx, userID, time.Now(), nil)
+}
+
+// GetStatsOn counts tasks relative to the given calendar day, e.g. "today" in the user's timezone.
+// Task timestamps are converted to loc before comparing days; a nil loc keeps the session's timezone.
+func (r *postgresRepository) GetStatsOn(ctx context.Context, userID int, day time.Time, loc *time.Location) (completedToday, dueToday, dueTomorrow, overdue int, err error) {
+	date := day.Format("2006-01-02")
+	zone := ""
+	if loc != nil {
+		zone = loc.String()
+	}
+	completedOn, dueOn := fmt.Sprintf(localDate, "completed_at"), fmt.Sprintf(localDate, "due_date")
+
 	// Completed today
 	completedTodayQuery := `
 		SELECT COUNT(*) 
 		FROM tasks 
 		WHERE user_id = $1 
+		AND deleted_at IS NULL
 		AND is_completed = true 
 		AND completed_at IS NOT NULL
-		AND DATE(completed_at) = CURRENT_DATE
+		AND ` + completedOn + ` = $2::date
 	`
-	err = r.db.QueryRowxContext(ctx, completedTodayQuery, userID).Scan(&completedToday)
+	err = r.conn(ctx).QueryRowxContext(ctx, completedTodayQuery, userID, date, zone).Scan(&completedToday)
 	if err != nil {
 		return
 	}
@@ -219,11 +281,12 @@ func (r *postgresRepository) GetStats(ctx context.Context, userID int) (complete
 		SELECT COUNT(*) 
 		FROM tasks 
 		WHERE user_id = $1 
+		AND deleted_at IS NULL
 		AND is_completed = false 
 		AND due_date IS NOT NULL
-		AND DATE(due_date) = CURRENT_DATE
+		AND ` + dueOn + ` = $2::date
 	`
-	err = r.db.QueryRowxContext(ctx, dueTodayQuery, userID).Scan(&dueToday)
+	err = r.conn(ctx).QueryRowxContext(ctx, dueTodayQuery, userID, date, zone).Scan(&dueToday)
 	if err != nil {
 		return
 	}
@@ -233,11 +296,12 @@ func (r *postgresRepository) GetStats(ctx context.Context, userID int) (complete
 		SELECT COUNT(*) 
 		FROM tasks 
 		WHERE user_id = $1 
+		AND deleted_at IS NULL
 		AND is_completed = false 
 		AND due_date IS NOT NULL
-		AND DATE(due_date) = CURRENT_DATE + INTERVAL '1 day'
+		AND ` + dueOn + ` = $2::date + 1
 	`
-	err = r.db.QueryRowxContext(ctx, dueTomorrowQuery, userID).Scan(&dueTomorrow)
+	err = r.conn(ctx).QueryRowxContext(ctx, dueTomorrowQuery, userID, date, zone).Scan(&dueTomorrow)
 	if err != nil {
 		return
 	}
@@ -247,10 +311,11 @@ func (r *postgresRepository) GetStats(ctx context.Context, userID int) (complete
 		SELECT COUNT(*) 
 		FROM tasks 
 		WHERE user_id = $1 
+		AND deleted_at IS NULL
 		AND is_completed = false 
 		AND due_date IS NOT NULL
-		AND DATE(due_date) < CURRENT_DATE
+		AND ` + dueOn + ` < $2::date
 	`
-	err = r.db.QueryRowxContext(ctx, overdueQuery, userID).Scan(&overdue)
+	err = r.conn(ctx).QueryRowxContext(ctx, overdueQuery, userID, date, zone).Scan(&overdue)
 	return
 }
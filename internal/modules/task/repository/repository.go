@@ -2,18 +2,65 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/domain"
 )
 
+// priorityRank sorts priorities by weight rather than alphabetically
+const priorityRank = `CASE priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END`
+
+func priorityWeight(priority string) int {
+	switch priority {
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// ListSpec whitelists the filters and sort keys of GET /tasks
+var ListSpec = &query.Spec[domain.Task]{
+	Filters: map[string]query.Filter{
+		"priority":       {Column: "priority", Op: query.Eq, Values: []string{"low", "medium", "high"}},
+		"is_completed":   {Column: "is_completed", Op: query.Eq, Kind: query.Bool},
+		"is_subtask":     {Column: "parent_task_id", Op: query.Present},
+		"parent_task_id": {Column: "parent_task_id", Op: query.Eq, Kind: query.Int},
+		"is_shared":      {Column: "workspace_id", Op: query.Present},
+		"workspace_id":   {Column: "workspace_id", Op: query.Eq, Kind: query.Int},
+		"goal_id":        {Column: "goal_id", Op: query.Eq, Kind: query.Int},
+		"due_before":     {Column: "due_date", Op: query.Lt, Kind: query.Time},
+		"due_after":      {Column: "due_date", Op: query.Gte, Kind: query.Time},
+		"created_before": {Column: "created_at", Op: query.Lt, Kind: query.Time},
+		"created_after":  {Column: "created_at", Op: query.Gte, Kind: query.Time},
+		"q":              {Column: "title", Op: query.Contains},
+	},
+	Sorts: map[string]query.Sort[domain.Task]{
+		"created_at": {Column: "created_at", Kind: query.Time, Value: func(t *domain.Task) any { return t.CreatedAt }},
+		"updated_at": {Column: "updated_at", Kind: query.Time, Value: func(t *domain.Task) any { return t.UpdatedAt }},
+		"due_date":   {Column: "due_date", Kind: query.Time, Nullable: true, Value: func(t *domain.Task) any { return t.DueDate }},
+		"priority":   {Column: priorityRank, Kind: query.Int, Value: func(t *domain.Task) any { return priorityWeight(t.Priority) }},
+		"title":      {Column: "title", Value: func(t *domain.Task) any { return t.Title }},
+	},
+	DefaultSort: "-created_at",
+	ID:          func(t *domain.Task) int { return t.ID },
+}
+
 type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) (*domain.Task, error)
 	GetByID(ctx context.Context, id int) (*domain.Task, error)
-	GetByUserID(ctx context.Context, userID int) ([]*domain.Task, error)
+	// List returns one page of the user's personal tasks and those of the workspaces they belong to,
+	// together with the total matching the filters
+	List(ctx context.Context, userID int, q *query.Query[domain.Task]) ([]*domain.Task, int, error)
 	GetSubtasks(ctx context.Context, parentID int) ([]*domain.Task, error)
 	GetParentTasks(ctx context.Context, userID int) ([]*domain.Task, error)
 	Update(ctx context.Context, task *domain.Task) error
+	MoveSubtasks(ctx context.Context, parentID int, workspaceID *int) error
 	Delete(ctx context.Context, id int) error
 	CountSubtasks(ctx context.Context, parentID int) (total int, completed int, err error)
 	GetStats(ctx context.Context, userID int) (completedToday, dueToday, dueTomorrow, overdue int, err error)
+	GetStatsOn(ctx context.Context, userID int, day time.Time, loc *time.Location) (completedToday, dueToday, dueTomorrow, overdue int, err error)
 }
@@ -5,25 +5,36 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/query"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/notification"
-	notifService "github.com/M1ralai/go-modular-monolith-template/internal/modules/notification/service"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/task/repository"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace"
 )
 
 type taskService struct {
-	repo        repository.TaskRepository
-	logger      *logger.ZapLogger
-	broadcaster *notifService.Broadcaster
+	repo   repository.TaskRepository
+	uow    *database.UnitOfWork
+	logger *logger.ZapLogger
+	bus    *eventbus.Bus
+	access *workspace.Access
 }
 
-func NewTaskService(repo repository.TaskRepository, logger *logger.ZapLogger, broadcaster *notifService.Broadcaster) TaskService {
+// NewTaskService creates the task service. Writes and the events they raise share one
+// transaction through uow, so events are only delivered for committed changes. Access
+// decides who may read and change shared tasks and who their events go to.
+func NewTaskService(repo repository.TaskRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, bus *eventbus.Bus, access *workspace.Access) TaskService {
 	return &taskService{
-		repo:        repo,
-		logger:      logger,
-		broadcaster: broadcaster,
+		repo:   repo,
+		uow:    uow,
+		logger: logger,
+		bus:    bus,
+		access: access,
 	}
 }
 
@@ -39,9 +50,30 @@ func (s *taskService) Create(ctx context.Context, req *dto.CreateTaskRequest, us
 		priority = "medium"
 	}
 
+	workspaceID := req.WorkspaceID
+	if req.ParentTaskID != nil {
+		parent, err := s.repo.GetByID(ctx, *req.ParentTaskID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, errors.New("parent task not found")
+		}
+		if err := s.access.Check(ctx, parent.UserID, parent.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return nil, err
+		}
+		// Subtasks live wherever their parent does
+		workspaceID = parent.WorkspaceID
+	} else if workspaceID != nil {
+		if err := s.access.CheckWorkspace(ctx, *workspaceID, userID, workspace.RoleEditor); err != nil {
+			return nil, err
+		}
+	}
+
 	now := time.Now()
 	task := &domain.Task{
 		UserID:             userID,
+		WorkspaceID:        workspaceID,
 		ParentTaskID:       req.ParentTaskID,
 		Title:              req.Title,
 		Description:        req.Description,
@@ -55,7 +87,19 @@ func (s *taskService) Create(ctx context.Context, req *dto.CreateTaskRequest, us
 		UpdatedAt:          now,
 	}
 
-	created, err := s.repo.Create(ctx, task)
+	var response *dto.TaskResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		created, err := s.repo.Create(ctx, task)
+		if err != nil {
+			return err
+		}
+		task = created
+		response = dto.ToTaskResponse(created, 0, 0)
+		return s.access.Publish(ctx, created.UserID, created.WorkspaceID, events.TaskCreated{
+			TaskID: created.ID,
+			Task:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("Failed to create task", err, map[string]interface{}{
 			"user_id": userID,
@@ -67,21 +111,15 @@ func (s *taskService) Create(ctx context.Context, req *dto.CreateTaskRequest, us
 
 	s.logger.Info("Task created successfully", map[string]interface{}{
 		"user_id": userID,
-		"task_id": created.ID,
+		"task_id": task.ID,
 		"action":  "CREATE_TASK_SUCCESS",
 	})
 
-	response := dto.ToTaskResponse(created, 0, 0)
-	
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventTaskCreated, map[string]interface{}{
-			"task_id": created.ID,
-			"task":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventTaskCreated,
 			"user_id":    userID,
-			"entity_id":  created.ID,
+			"entity_id":  task.ID,
 			"action":     "WS_EVENT_PUBLISHED",
 		})
 	}
@@ -97,19 +135,20 @@ func (s *taskService) GetByID(ctx context.Context, id, userID int) (*dto.TaskRes
 	if task == nil {
 		return nil, errors.New("task not found")
 	}
-	if task.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.access.Check(ctx, task.UserID, task.WorkspaceID, userID, workspace.RoleViewer); err != nil {
+		return nil, err
 	}
 
 	total, completed, _ := s.repo.CountSubtasks(ctx, id)
 	return dto.ToTaskResponse(task, total, completed), nil
 }
 
-func (s *taskService) GetAll(ctx context.Context, userID int) ([]*dto.TaskResponse, error) {
-	tasks, err := s.repo.GetByUserID(ctx, userID)
+func (s *taskService) GetAll(ctx context.Context, q *query.Query[domain.Task], userID int) (*dto.TaskListResponse, error) {
+	tasks, count, err := s.repo.List(ctx, userID, q)
 	if err != nil {
 		return nil, err
 	}
+	tasks, page := q.Result(tasks, count)
 
 	result := make([]*dto.TaskResponse, len(tasks))
 	for i, task := range tasks {
@@ -117,7 +156,7 @@ func (s *taskService) GetAll(ctx context.Context, userID int) ([]*dto.TaskRespon
 		result[i] = dto.ToTaskResponse(task, total, completed)
 	}
 
-	return result, nil
+	return &dto.TaskListResponse{Items: result, PageInfo: page}, nil
 }
 
 func (s *taskService) GetParentTasks(ctx context.Context, userID int) ([]*dto.TaskResponse, error) {
@@ -143,8 +182,8 @@ func (s *taskService) GetSubtasks(ctx context.Context, parentID, userID int) ([]
 	if parent == nil {
 		return nil, errors.New("parent task not found")
 	}
-	if parent.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.access.Check(ctx, parent.UserID, parent.WorkspaceID, userID, workspace.RoleViewer); err != nil {
+		return nil, err
 	}
 
 	subtasks, err := s.repo.GetSubtasks(ctx, parentID)
@@ -162,59 +201,107 @@ func (s *taskService) Update(ctx context.Context, id int, req *dto.UpdateTaskReq
 		"action":  "UPDATE_TASK",
 	})
 
-	task, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if task == nil {
-		return nil, errors.New("task not found")
-	}
-	if task.UserID != userID {
-		return nil, errors.New("unauthorized")
-	}
+	var (
+		response      *dto.TaskResponse
+		justCompleted bool
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		task, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			return errors.New("task not found")
+		}
+		if err := s.access.Check(ctx, task.UserID, task.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		total, completed, _ := s.repo.CountSubtasks(ctx, id)
+		before := dto.ToTaskResponse(task, total, completed)
+
+		moved := false
+		if req.WorkspaceID != nil {
+			if task.ParentTaskID != nil {
+				return errors.New("subtasks follow their parent task")
+			}
+			target, err := s.access.CheckMove(ctx, task.UserID, *req.WorkspaceID, userID)
+			if err != nil {
+				return err
+			}
+			moved = !sameWorkspace(task.WorkspaceID, target)
+			task.WorkspaceID = target
+		}
 
-	if req.Title != nil {
-		task.Title = *req.Title
-	}
-	if req.Description != nil {
-		task.Description = *req.Description
-	}
-	if req.DueDate != nil {
-		task.DueDate = req.DueDate
-	}
-	if req.EstimatedStart != nil {
-		task.EstimatedStart = req.EstimatedStart
-	}
-	if req.EstimatedEnd != nil {
-		task.EstimatedEnd = req.EstimatedEnd
-	}
-	if req.ActualStart != nil {
-		task.ActualStart = req.ActualStart
-	}
-	if req.ActualEnd != nil {
-		task.ActualEnd = req.ActualEnd
-	}
-	if req.Priority != nil {
-		task.Priority = *req.Priority
-	}
-	wasCompleted := task.IsCompleted
-	if req.IsCompleted != nil {
-		task.IsCompleted = *req.IsCompleted
-		if *req.IsCompleted && !wasCompleted {
-			// Mark as completed if transitioning from incomplete to complete
-			task.MarkCompleted()
-		} else if !*req.IsCompleted && wasCompleted {
-			// Unmark completion
-			task.CompletedAt = nil
+		if req.Title != nil {
+			task.Title = *req.Title
 		}
-	}
-	if req.CompletedAt != nil {
-		task.CompletedAt = req.CompletedAt
-	}
+		if req.Description != nil {
+			task.Description = *req.Description
+		}
+		if req.DueDate != nil {
+			task.DueDate = req.DueDate
+		}
+		if req.EstimatedStart != nil {
+			task.EstimatedStart = req.EstimatedStart
+		}
+		if req.EstimatedEnd != nil {
+			task.EstimatedEnd = req.EstimatedEnd
+		}
+		if req.ActualStart != nil {
+			task.ActualStart = req.ActualStart
+		}
+		if req.ActualEnd != nil {
+			task.ActualEnd = req.ActualEnd
+		}
+		if req.Priority != nil {
+			task.Priority = *req.Priority
+		}
+		wasCompleted := task.IsCompleted
+		if req.IsCompleted != nil {
+			task.IsCompleted = *req.IsCompleted
+			if *req.IsCompleted && !wasCompleted {
+				// Mark as completed if transitioning from incomplete to complete
+				task.MarkCompleted()
+			} else if !*req.IsCompleted && wasCompleted {
+				// Unmark completion
+				task.CompletedAt = nil
+			}
+		}
+		if req.CompletedAt != nil {
+			task.CompletedAt = req.CompletedAt
+		}
+
+		task.UpdatedAt = time.Now()
+		justCompleted = req.IsCompleted != nil && *req.IsCompleted && !wasCompleted
 
-	task.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, task); err != nil {
+			return err
+		}
+		if moved {
+			if err := s.repo.MoveSubtasks(ctx, id, task.WorkspaceID); err != nil {
+				return err
+			}
+		}
 
-	if err := s.repo.Update(ctx, task); err != nil {
+		response = dto.ToTaskResponse(task, total, completed)
+
+		// If task was just completed, send completion event
+		if justCompleted {
+			if err := s.access.Publish(ctx, task.UserID, task.WorkspaceID, events.TaskCompleted{
+				TaskID: id,
+				Task:   response,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Always send update event; it carries the before-state for the audit log
+		return s.access.Publish(eventbus.WithBefore(ctx, before), task.UserID, task.WorkspaceID, events.TaskUpdated{
+			TaskID: id,
+			Task:   response,
+		})
+	})
+	if err != nil {
 		s.logger.Error("Failed to update task", err, map[string]interface{}{
 			"user_id": userID,
 			"task_id": id,
@@ -229,16 +316,8 @@ func (s *taskService) Update(ctx context.Context, id int, req *dto.UpdateTaskReq
 		"action":  "UPDATE_TASK_SUCCESS",
 	})
 
-	total, completed, _ := s.repo.CountSubtasks(ctx, id)
-	response := dto.ToTaskResponse(task, total, completed)
-	
-	if s.broadcaster != nil {
-		// If task was just completed, send completion event
-		if req.IsCompleted != nil && *req.IsCompleted && !wasCompleted {
-			s.broadcaster.Publish(userID, notification.EventTaskCompleted, map[string]interface{}{
-				"task_id": id,
-				"task":    response,
-			})
+	if s.bus != nil {
+		if justCompleted {
 			s.logger.Info("WebSocket event published", map[string]interface{}{
 				"event_type": notification.EventTaskCompleted,
 				"user_id":    userID,
@@ -246,12 +325,6 @@ func (s *taskService) Update(ctx context.Context, id int, req *dto.UpdateTaskReq
 				"action":     "WS_EVENT_PUBLISHED",
 			})
 		}
-		
-		// Always send update event
-		s.broadcaster.Publish(userID, notification.EventTaskUpdated, map[string]interface{}{
-			"task_id": id,
-			"task":    response,
-		})
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventTaskUpdated,
 			"user_id":    userID,
@@ -270,18 +343,29 @@ func (s *taskService) Delete(ctx context.Context, id, userID int) error {
 		"action":  "DELETE_TASK",
 	})
 
-	task, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if task == nil {
-		return errors.New("task not found")
-	}
-	if task.UserID != userID {
-		return errors.New("unauthorized")
-	}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		task, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			return errors.New("task not found")
+		}
+		if err := s.access.Check(ctx, task.UserID, task.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		total, completed, _ := s.repo.CountSubtasks(ctx, id)
+		before := dto.ToTaskResponse(task, total, completed)
 
-	if err := s.repo.Delete(ctx, id); err != nil {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.access.Publish(eventbus.WithBefore(ctx, before), task.UserID, task.WorkspaceID, events.TaskDeleted{
+			TaskID: id,
+			Title:  task.Title,
+		})
+	})
+	if err != nil {
 		s.logger.Error("Failed to delete task", err, map[string]interface{}{
 			"user_id": userID,
 			"task_id": id,
@@ -296,11 +380,7 @@ func (s *taskService) Delete(ctx context.Context, id, userID int) error {
 		"action":  "DELETE_TASK_SUCCESS",
 	})
 
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventTaskDeleted, map[string]interface{}{
-			"task_id": id,
-			"title":   task.Title,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventTaskDeleted,
 			"user_id":    userID,
@@ -319,24 +399,45 @@ func (s *taskService) CompleteSubtask(ctx context.Context, subtaskID, userID int
 		"action":     "COMPLETE_SUBTASK",
 	})
 
-	subtask, err := s.repo.GetByID(ctx, subtaskID)
-	if err != nil {
-		return err
-	}
-	if subtask == nil {
-		return errors.New("subtask not found")
-	}
-	if subtask.UserID != userID {
-		return errors.New("unauthorized")
-	}
+	// The checks, the subtask, its parent's progress and their events commit together
+	alreadyCompleted := false
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		subtask, err := s.repo.GetByID(ctx, subtaskID)
+		if err != nil {
+			return err
+		}
+		if subtask == nil {
+			return errors.New("subtask not found")
+		}
+		if err := s.access.Check(ctx, subtask.UserID, subtask.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+			return err
+		}
 
-	if subtask.IsCompleted {
-		return nil
-	}
+		if subtask.IsCompleted {
+			alreadyCompleted = true
+			return nil
+		}
 
-	subtask.MarkCompleted()
+		total, completed, _ := s.repo.CountSubtasks(ctx, subtaskID)
+		before := dto.ToTaskResponse(subtask, total, completed)
+		subtask.MarkCompleted()
+		if err := s.repo.Update(ctx, subtask); err != nil {
+			return err
+		}
+
+		if err := s.access.Publish(eventbus.WithBefore(ctx, before), subtask.UserID, subtask.WorkspaceID, events.TaskCompleted{
+			TaskID: subtaskID,
+			Task:   dto.ToTaskResponse(subtask, total, completed),
+		}); err != nil {
+			return err
+		}
 
-	if err := s.repo.Update(ctx, subtask); err != nil {
+		if subtask.ParentTaskID != nil {
+			return s.checkAndCompleteParent(ctx, *subtask.ParentTaskID, userID)
+		}
+		return nil
+	})
+	if err != nil {
 		s.logger.Error("Failed to complete subtask", err, map[string]interface{}{
 			"user_id":    userID,
 			"subtask_id": subtaskID,
@@ -345,20 +446,17 @@ func (s *taskService) CompleteSubtask(ctx context.Context, subtaskID, userID int
 		return err
 	}
 
+	if alreadyCompleted {
+		return nil
+	}
+
 	s.logger.Info("Subtask completed successfully", map[string]interface{}{
 		"user_id":    userID,
 		"subtask_id": subtaskID,
 		"action":     "COMPLETE_SUBTASK_SUCCESS",
 	})
 
-	total, completed, _ := s.repo.CountSubtasks(ctx, subtaskID)
-	response := dto.ToTaskResponse(subtask, total, completed)
-	
-	if s.broadcaster != nil {
-		s.broadcaster.Publish(userID, notification.EventTaskCompleted, map[string]interface{}{
-			"task_id": subtaskID,
-			"task":    response,
-		})
+	if s.bus != nil {
 		s.logger.Info("WebSocket event published", map[string]interface{}{
 			"event_type": notification.EventTaskCompleted,
 			"user_id":    userID,
@@ -367,10 +465,6 @@ func (s *taskService) CompleteSubtask(ctx context.Context, subtaskID, userID int
 		})
 	}
 
-	if subtask.ParentTaskID != nil {
-		return s.checkAndCompleteParent(ctx, *subtask.ParentTaskID, userID)
-	}
-
 	return nil
 }
 
@@ -388,38 +482,47 @@ func (s *taskService) checkAndCompleteParent(ctx context.Context, parentID, user
 	if err != nil || parent == nil {
 		return err
 	}
+	before := dto.ToTaskResponse(parent, total, completed)
 
 	parent.ProgressPercentage = float64(completed) / float64(total) * 100
 	parent.UpdatedAt = time.Now()
 
-	if completed == total {
+	autoCompleted := completed == total
+	if autoCompleted {
 		parent.MarkCompleted()
+	}
 
-		s.logger.Info("Parent task auto-completed", map[string]interface{}{
-			"user_id":   userID,
-			"parent_id": parentID,
-			"action":    "AUTO_COMPLETE_PARENT",
-		})
+	if err := s.repo.Update(ctx, parent); err != nil {
+		return err
+	}
 
-		if s.broadcaster != nil {
-			parentTotal, parentCompleted, _ := s.repo.CountSubtasks(ctx, parentID)
-			parentResponse := dto.ToTaskResponse(parent, parentTotal, parentCompleted)
-			
-			s.broadcaster.Publish(userID, notification.EventTaskCompleted, map[string]interface{}{
-				"task_id":        parentID,
-				"task":           parentResponse,
-				"auto_completed": true,
-			})
-			s.logger.Info("WebSocket event published", map[string]interface{}{
-				"event_type": notification.EventTaskCompleted,
-				"user_id":    userID,
-				"entity_id":  parentID,
-				"action":     "WS_EVENT_PUBLISHED",
-			})
+	if !autoCompleted {
+		return nil
+	}
+
+	s.logger.Info("Parent task auto-completed", map[string]interface{}{
+		"user_id":   userID,
+		"parent_id": parentID,
+		"action":    "AUTO_COMPLETE_PARENT",
+	})
+
+	if s.bus != nil {
+		if err := s.access.Publish(eventbus.WithBefore(ctx, before), parent.UserID, parent.WorkspaceID, events.TaskCompleted{
+			TaskID:        parentID,
+			Task:          dto.ToTaskResponse(parent, total, completed),
+			AutoCompleted: true,
+		}); err != nil {
+			return err
 		}
+		s.logger.Info("WebSocket event published", map[string]interface{}{
+			"event_type": notification.EventTaskCompleted,
+			"user_id":    userID,
+			"entity_id":  parentID,
+			"action":     "WS_EVENT_PUBLISHED",
+		})
 	}
 
-	return s.repo.Update(ctx, parent)
+	return nil
 }
 
 func (s *taskService) GetStats(ctx context.Context, userID int) (*dto.TaskStatsResponse, error) {
@@ -439,3 +542,11 @@ func (s *taskService) GetStats(ctx context.Context, userID int) (*dto.TaskStatsR
 		Overdue:        overdue,
 	}, nil
 }
+
+// sameWorkspace reports whether two workspace IDs point to the same place, nil being personal
+func sameWorkspace(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Entities lists the entity types that go to the trash when deleted
+var Entities = []string{
+	"course", "event", "goal", "habit", "journal", "lifearea", "note", "person", "task", "transaction",
+}
+
+// IsEntity reports whether an entity type goes to the trash
+func IsEntity(entityType string) bool {
+	for _, e := range Entities {
+		if e == entityType {
+			return true
+		}
+	}
+	return false
+}
+
+// Item is a deleted entity waiting in the trash of its owner or, for a shared course, goal
+// or task, of its workspace's editors. Children deleted with it (subtasks, habit logs,
+// components, milestones, note links, ...) are not listed; they come back when the item
+// is restored and are removed when it is purged.
+type Item struct {
+	EntityType  string
+	EntityID    int
+	UserID      int
+	WorkspaceID *int
+	Title       string
+	DeletedAt   time.Time
+}
+
+// Filter narrows a trash listing
+type Filter struct {
+	EntityType string
+	Limit      int
+	Offset     int
+}
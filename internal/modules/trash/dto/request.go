@@ -0,0 +1,8 @@
+package dto
+
+// ListTrashRequest is built from the query string of GET /trash
+type ListTrashRequest struct {
+	Entity string `json:"entity,omitempty"`
+	Page   int    `json:"page" validate:"min=1"`
+	Limit  int    `json:"limit" validate:"min=1,max=100"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/domain"
+)
+
+type TrashItemResponse struct {
+	Entity   string `json:"entity"`
+	EntityID int    `json:"entity_id"`
+	// WorkspaceID is set when the item was shared in a workspace
+	WorkspaceID *int      `json:"workspace_id,omitempty"`
+	Title       string    `json:"title"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	// PurgeAt is when the item is deleted for good unless restored
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+type TrashListResponse struct {
+	Items         []*TrashItemResponse `json:"items"`
+	Total         int                  `json:"total"`
+	Page          int                  `json:"page"`
+	Limit         int                  `json:"limit"`
+	RetentionDays int                  `json:"retention_days"`
+}
+
+type EmptyTrashResponse struct {
+	Purged int `json:"purged"`
+}
+
+func ToTrashItemResponse(item *domain.Item, retention time.Duration) *TrashItemResponse {
+	if item == nil {
+		return nil
+	}
+	return &TrashItemResponse{Entity: item.EntityType, EntityID: item.EntityID, WorkspaceID: item.WorkspaceID, Title: item.Title, DeletedAt: item.DeletedAt, PurgeAt: item.DeletedAt.Add(retention)}
+}
+
+func ToTrashItemResponseList(items []*domain.Item, retention time.Duration) []*TrashItemResponse {
+	result := make([]*TrashItemResponse, len(items))
+	for i, item := range items {
+		result[i] = ToTrashItemResponse(item, retention)
+	}
+	return result
+}
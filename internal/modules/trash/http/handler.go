@@ -0,0 +1,134 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/dto"
+	trashService "github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/service"
+	"github.com/gorilla/mux"
+)
+
+const defaultPageSize = 20
+
+type Handler struct {
+	service trashService.TrashService
+}
+
+func NewHandler(service trashService.TrashService) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/trash", h.List).Methods("GET")
+	router.HandleFunc("/trash", h.Empty).Methods("DELETE")
+	router.HandleFunc("/trash/{entity}/{id}/restore", h.Restore).Methods("POST")
+	router.HandleFunc("/trash/{entity}/{id}", h.Purge).Methods("DELETE")
+}
+
+func (h *Handler) getUserID(r *http.Request) int {
+	return utils.GetUserIDFromContext(r.Context())
+}
+
+// List returns the user's trash, most recently deleted first
+// GET /api/trash?entity=task&page=1&limit=20
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.ListTrashRequest{
+		Entity: query.Get("entity"),
+		Page:   1,
+		Limit:  defaultPageSize,
+	}
+
+	if pageStr := query.Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sayfa", err.Error())
+			return
+		}
+		req.Page = page
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz limit", err.Error())
+			return
+		}
+		req.Limit = limit
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	items, err := h.service.List(r.Context(), &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Çöp kutusu getirilemedi")
+		return
+	}
+	utils.WriteJson(w, items, http.StatusOK, "Çöp kutusu getirildi")
+}
+
+// Restore brings a deleted item back together with the children deleted with it
+// POST /api/trash/{entity}/{id}/restore
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+
+	item, err := h.service.Restore(r.Context(), vars["entity"], id, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Kayıt geri yüklenemedi")
+		return
+	}
+	utils.WriteJson(w, item, http.StatusOK, "Kayıt geri yüklendi")
+}
+
+// Purge deletes an item in the trash for good
+// DELETE /api/trash/{entity}/{id}
+func (h *Handler) Purge(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+
+	if err := h.service.Purge(r.Context(), vars["entity"], id, h.getUserID(r)); err != nil {
+		h.handleError(w, err, "Kayıt kalıcı olarak silinemedi")
+		return
+	}
+	utils.WriteJson(w, nil, http.StatusOK, "Kayıt kalıcı olarak silindi")
+}
+
+// Empty deletes everything in the user's trash for good
+// DELETE /api/trash
+func (h *Handler) Empty(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.Empty(r.Context(), h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Çöp kutusu boşaltılamadı")
+		return
+	}
+	utils.WriteJson(w, result, http.StatusOK, "Çöp kutusu boşaltıldı")
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string) {
+	switch err.Error() {
+	case "trash item not found":
+		utils.ReturnError(w, "NOT_FOUND", "Kayıt çöp kutusunda bulunamadı", err.Error())
+	case "invalid entity type":
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz varlık tipi", err.Error())
+	case "parent is in trash":
+		utils.ReturnError(w, "BAD_REQUEST", "Önce üst görevi geri yükleyin", err.Error())
+	case "unauthorized":
+		utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
+	default:
+		utils.ReturnError(w, "INTERNAL_ERROR", message, err.Error())
+	}
+}
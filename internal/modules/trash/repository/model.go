@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/domain"
+)
+
+type ItemModel struct {
+	EntityType  string    `db:"entity_type"`
+	EntityID    int       `db:"entity_id"`
+	UserID      int       `db:"user_id"`
+	WorkspaceID *int      `db:"workspace_id"`
+	Title       *string   `db:"title"`
+	DeletedAt   time.Time `db:"deleted_at"`
+}
+
+func (m *ItemModel) ToDomain() *domain.Item {
+	if m == nil {
+		return nil
+	}
+	item := &domain.Item{EntityType: m.EntityType, EntityID: m.EntityID, UserID: m.UserID, WorkspaceID: m.WorkspaceID, DeletedAt: m.DeletedAt}
+	if m.Title != nil {
+		item.Title = *m.Title
+	}
+	return item
+}
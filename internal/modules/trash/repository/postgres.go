@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+// source describes where an entity type lives: its table, the expression shown as the
+// item's title, the condition that leaves out rows trashed along with a parent, and whether
+// its rows can belong to a workspace
+type source struct {
+	table  string
+	title  string
+	root   string
+	shared bool
+}
+
+var sources = map[string]source{
+	"course":      {table: "courses", title: "name", shared: true},
+	"event":       {table: "events", title: "title"},
+	"goal":        {table: "goals", title: "title", shared: true},
+	"habit":       {table: "habits", title: "name"},
+	"journal":     {table: "journal_entries", title: "to_char(entry_date, 'YYYY-MM-DD')"},
+	"lifearea":    {table: "life_areas", title: "name"},
+	"note":        {table: "notes", title: "title"},
+	"person":      {table: "people", title: "name"},
+	"task":        {table: "tasks", title: "title", root: "NOT deleted_by_parent", shared: true},
+	"transaction": {table: "finance_transactions", title: "COALESCE(NULLIF(description, ''), category)"},
+}
+
+func (s source) where() string {
+	where := "deleted_at IS NOT NULL"
+	if s.root != "" {
+		where += " AND " + s.root
+	}
+	return where
+}
+
+func (s source) columns(entityType string) string {
+	workspaceID := "NULL::integer"
+	if s.shared {
+		workspaceID = "workspace_id"
+	}
+	return fmt.Sprintf(`'%s' AS entity_type, id AS entity_id, user_id, %s AS workspace_id, %s::text AS title, deleted_at`,
+		entityType, workspaceID, s.title)
+}
+
+// editableBy matches the rows the user given by param may restore and purge: their own
+// personal rows, and shared rows of the workspaces where they are at least an editor
+func (s source) editableBy(param string) string {
+	if !s.shared {
+		return "user_id = " + param
+	}
+	return fmt.Sprintf(`(workspace_id IS NULL AND user_id = %[1]s OR workspace_id IN (
+		SELECT workspace_id FROM workspace_members WHERE user_id = %[1]s AND role IN ('editor', 'owner')))`, param)
+}
+
+type postgresRepository struct{ db *sqlx.DB }
+
+func NewPostgresRepository(db *sqlx.DB) TrashRepository { return &postgresRepository{db: db} }
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *postgresRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func lookup(entityType string) (source, error) {
+	src, ok := sources[entityType]
+	if !ok {
+		return source{}, errors.New("invalid entity type")
+	}
+	return src, nil
+}
+
+// List returns one page of the trash the user can edit, most recently deleted first, together with the total
+func (r *postgresRepository) List(ctx context.Context, userID int, filter domain.Filter) ([]*domain.Item, int, error) {
+	entityTypes := domain.Entities
+	if filter.EntityType != "" {
+		entityTypes = []string{filter.EntityType}
+	}
+
+	parts := make([]string, 0, len(entityTypes))
+	for _, entityType := range entityTypes {
+		src, err := lookup(entityType)
+		if err != nil {
+			return nil, 0, err
+		}
+		parts = append(parts, fmt.Sprintf(`SELECT %s FROM %s WHERE %s AND %s`, src.columns(entityType), src.table, src.editableBy("$1"), src.where()))
+	}
+	union := strings.Join(parts, " UNION ALL ")
+
+	var total int
+	if err := r.conn(ctx).GetContext(ctx, &total, `SELECT COUNT(*) FROM (`+union+`) trash`, userID); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT * FROM (` + union + `) trash ORDER BY deleted_at DESC, entity_type, entity_id LIMIT $2 OFFSET $3`
+	var models []ItemModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID, filter.Limit, filter.Offset); err != nil {
+		return nil, 0, err
+	}
+	items := make([]*domain.Item, len(models))
+	for i := range models {
+		items[i] = models[i].ToDomain()
+	}
+	return items, total, nil
+}
+
+func (r *postgresRepository) Get(ctx context.Context, entityType string, entityID int) (*domain.Item, error) {
+	src, err := lookup(entityType)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 AND %s`, src.columns(entityType), src.table, src.where())
+	var model ItemModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, entityID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// ParentTrashed only applies to subtasks; other entities in the trash have no trashable parent
+func (r *postgresRepository) ParentTrashed(ctx context.Context, entityType string, entityID int) (bool, error) {
+	if entityType != "task" {
+		return false, nil
+	}
+	var trashed bool
+	err := r.conn(ctx).GetContext(ctx, &trashed, `
+		SELECT EXISTS(
+			SELECT 1 FROM tasks t JOIN tasks p ON p.id = t.parent_task_id
+			WHERE t.id = $1 AND p.deleted_at IS NOT NULL
+		)`, entityID)
+	return trashed, err
+}
+
+func (r *postgresRepository) Restore(ctx context.Context, entityType string, entityID int) error {
+	src, err := lookup(entityType)
+	if err != nil {
+		return err
+	}
+	if entityType == "task" {
+		// Subtasks trashed with the task come back with it; ones deleted on their own stay in the trash
+		_, err = r.conn(ctx).ExecContext(ctx, `
+			WITH RECURSIVE tree AS (
+				SELECT id FROM tasks WHERE id = $1
+				UNION ALL
+				SELECT t.id FROM tasks t JOIN tree ON t.parent_task_id = tree.id WHERE t.deleted_by_parent
+			)
+			UPDATE tasks SET deleted_at = NULL, deleted_by_parent = FALSE WHERE id IN (SELECT id FROM tree)`, entityID)
+		return err
+	}
+	_, err = r.conn(ctx).ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET deleted_at = NULL WHERE id = $1`, src.table), entityID)
+	return err
+}
+
+// Purge deletes the row for good; ON DELETE CASCADE removes its children
+func (r *postgresRepository) Purge(ctx context.Context, entityType string, entityID int) error {
+	src, err := lookup(entityType)
+	if err != nil {
+		return err
+	}
+	_, err = r.conn(ctx).ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND deleted_at IS NOT NULL`, src.table), entityID)
+	return err
+}
+
+func (r *postgresRepository) PurgeBefore(ctx context.Context, userID int, before time.Time) ([]*domain.Item, error) {
+	var items []*domain.Item
+	for _, entityType := range domain.Entities {
+		src := sources[entityType]
+		query := fmt.Sprintf(`DELETE FROM %s WHERE %s AND deleted_at < $1 AND ($2 = 0 OR %s) RETURNING %s`,
+			src.table, src.where(), src.editableBy("$2"), src.columns(entityType))
+		var models []ItemModel
+		if err := r.conn(ctx).SelectContext(ctx, &models, query, before, userID); err != nil {
+			return items, err
+		}
+		for i := range models {
+			items = append(items, models[i].ToDomain())
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/domain"
+)
+
+type TrashRepository interface {
+	// List returns the items the user may restore: their own, and those of workspaces where they are an editor
+	List(ctx context.Context, userID int, filter domain.Filter) ([]*domain.Item, int, error)
+	// Get returns a trashed entity, nil if it is not in the trash
+	Get(ctx context.Context, entityType string, entityID int) (*domain.Item, error)
+	// ParentTrashed reports whether the entity belongs to a parent that is in the trash
+	ParentTrashed(ctx context.Context, entityType string, entityID int) (bool, error)
+	Restore(ctx context.Context, entityType string, entityID int) error
+	Purge(ctx context.Context, entityType string, entityID int) error
+	// PurgeBefore permanently deletes the items trashed before the cutoff, those the user may
+	// edit or every item when userID is 0, and returns them
+	PurgeBefore(ctx context.Context, userID int, before time.Time) ([]*domain.Item, error)
+}
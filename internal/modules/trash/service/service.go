@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/dto"
+)
+
+// DefaultRetention is how long deleted items stay in the trash when TRASH_RETENTION_DAYS is not set
+const DefaultRetention = 30 * 24 * time.Hour
+
+// RetentionFromEnv reads the trash retention period from TRASH_RETENTION_DAYS
+func RetentionFromEnv() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && v > 0 {
+		return time.Duration(v) * 24 * time.Hour
+	}
+	return DefaultRetention
+}
+
+type TrashService interface {
+	List(ctx context.Context, req *dto.ListTrashRequest, userID int) (*dto.TrashListResponse, error)
+	Restore(ctx context.Context, entityType string, entityID, userID int) (*dto.TrashItemResponse, error)
+	Purge(ctx context.Context, entityType string, entityID, userID int) error
+	Empty(ctx context.Context, userID int) (*dto.EmptyTrashResponse, error)
+
+	// PurgeExpired deletes for good every item that has been in the trash longer than the retention period
+	PurgeExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/repository"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace"
+)
+
+type trashService struct {
+	repo      repository.TrashRepository
+	uow       *database.UnitOfWork
+	logger    *logger.ZapLogger
+	access    *workspace.Access
+	retention time.Duration
+}
+
+func NewTrashService(repo repository.TrashRepository, uow *database.UnitOfWork, logger *logger.ZapLogger, access *workspace.Access, retention time.Duration) TrashService {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &trashService{repo: repo, uow: uow, logger: logger, access: access, retention: retention}
+}
+
+func (s *trashService) List(ctx context.Context, req *dto.ListTrashRequest, userID int) (*dto.TrashListResponse, error) {
+	if req.Entity != "" && !domain.IsEntity(req.Entity) {
+		return nil, errors.New("invalid entity type")
+	}
+	filter := domain.Filter{
+		EntityType: req.Entity,
+		Limit:      req.Limit,
+		Offset:     (req.Page - 1) * req.Limit,
+	}
+	items, total, err := s.repo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TrashListResponse{
+		Items:         dto.ToTrashItemResponseList(items, s.retention),
+		Total:         total,
+		Page:          req.Page,
+		Limit:         req.Limit,
+		RetentionDays: int(s.retention / (24 * time.Hour)),
+	}, nil
+}
+
+func (s *trashService) Restore(ctx context.Context, entityType string, entityID, userID int) (*dto.TrashItemResponse, error) {
+	s.logger.Info("Restoring from trash", map[string]interface{}{
+		"user_id":   userID,
+		"entity":    entityType,
+		"entity_id": entityID,
+		"action":    "RESTORE_TRASH_ITEM",
+	})
+
+	item, err := s.getEditable(ctx, entityType, entityID, userID)
+	if err != nil {
+		return nil, err
+	}
+	parentTrashed, err := s.repo.ParentTrashed(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if parentTrashed {
+		return nil, errors.New("parent is in trash")
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Restore(ctx, entityType, entityID); err != nil {
+			return err
+		}
+		return s.access.Publish(ctx, item.UserID, item.WorkspaceID, events.TrashRestored{Entity: entityType, EntityID: entityID})
+	})
+	if err != nil {
+		s.logger.Error("Failed to restore from trash", err, map[string]interface{}{
+			"user_id":   userID,
+			"entity":    entityType,
+			"entity_id": entityID,
+			"action":    "RESTORE_TRASH_ITEM_FAILED",
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Restored from trash", map[string]interface{}{
+		"user_id":   userID,
+		"entity":    entityType,
+		"entity_id": entityID,
+		"action":    "RESTORE_TRASH_ITEM_SUCCESS",
+	})
+	return dto.ToTrashItemResponse(item, s.retention), nil
+}
+
+func (s *trashService) Purge(ctx context.Context, entityType string, entityID, userID int) error {
+	s.logger.Info("Purging from trash", map[string]interface{}{
+		"user_id":   userID,
+		"entity":    entityType,
+		"entity_id": entityID,
+		"action":    "PURGE_TRASH_ITEM",
+	})
+
+	item, err := s.getEditable(ctx, entityType, entityID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Purge(ctx, entityType, entityID); err != nil {
+			return err
+		}
+		return s.publishPurged(ctx, item)
+	})
+	if err != nil {
+		s.logger.Error("Failed to purge from trash", err, map[string]interface{}{
+			"user_id":   userID,
+			"entity":    entityType,
+			"entity_id": entityID,
+			"action":    "PURGE_TRASH_ITEM_FAILED",
+		})
+		return err
+	}
+
+	s.logger.Info("Purged from trash", map[string]interface{}{
+		"user_id":   userID,
+		"entity":    entityType,
+		"entity_id": entityID,
+		"action":    "PURGE_TRASH_ITEM_SUCCESS",
+	})
+	return nil
+}
+
+func (s *trashService) Empty(ctx context.Context, userID int) (*dto.EmptyTrashResponse, error) {
+	purged, err := s.purgeBefore(ctx, userID, time.Now())
+	if err != nil {
+		s.logger.Error("Failed to empty trash", err, map[string]interface{}{
+			"user_id": userID,
+			"action":  "EMPTY_TRASH_FAILED",
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Trash emptied", map[string]interface{}{
+		"user_id": userID,
+		"purged":  purged,
+		"action":  "EMPTY_TRASH",
+	})
+	return &dto.EmptyTrashResponse{Purged: purged}, nil
+}
+
+func (s *trashService) PurgeExpired(ctx context.Context) (int, error) {
+	return s.purgeBefore(ctx, 0, time.Now().Add(-s.retention))
+}
+
+func (s *trashService) purgeBefore(ctx context.Context, userID int, before time.Time) (int, error) {
+	var purged int
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		items, err := s.repo.PurgeBefore(ctx, userID, before)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := s.publishPurged(ctx, item); err != nil {
+				return err
+			}
+		}
+		purged = len(items)
+		return nil
+	})
+	return purged, err
+}
+
+func (s *trashService) publishPurged(ctx context.Context, item *domain.Item) error {
+	return s.access.Publish(ctx, item.UserID, item.WorkspaceID, events.TrashPurged{Entity: item.EntityType, EntityID: item.EntityID, Title: item.Title})
+}
+
+// getEditable loads a trashed item the user may restore or purge: a personal one of their
+// own, or a shared one of a workspace where they are at least an editor
+func (s *trashService) getEditable(ctx context.Context, entityType string, entityID, userID int) (*domain.Item, error) {
+	if !domain.IsEntity(entityType) {
+		return nil, errors.New("invalid entity type")
+	}
+	item, err := s.repo.Get(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, errors.New("trash item not found")
+	}
+	if err := s.access.Check(ctx, item.UserID, item.WorkspaceID, userID, workspace.RoleEditor); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
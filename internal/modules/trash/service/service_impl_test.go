@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/trash/repository"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace"
+	wsDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/domain"
+	wsRepository "github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/repository"
+)
+
+// fakeTrash holds a single trashed item and remembers what was done to it
+type fakeTrash struct {
+	repository.TrashRepository
+	item     *domain.Item
+	restored bool
+	purged   bool
+}
+
+func (r *fakeTrash) Get(ctx context.Context, entityType string, entityID int) (*domain.Item, error) {
+	if r.item == nil || r.item.EntityType != entityType || r.item.EntityID != entityID {
+		return nil, nil
+	}
+	return r.item, nil
+}
+
+func (r *fakeTrash) ParentTrashed(ctx context.Context, entityType string, entityID int) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeTrash) Restore(ctx context.Context, entityType string, entityID int) error {
+	r.restored = true
+	return nil
+}
+
+func (r *fakeTrash) Purge(ctx context.Context, entityType string, entityID int) error {
+	r.purged = true
+	return nil
+}
+
+// fakeMembers holds the roles of workspace 1
+type fakeMembers struct {
+	wsRepository.MemberRepository
+	roles map[int]string
+}
+
+func (f fakeMembers) Get(ctx context.Context, workspaceID, userID int) (*wsDomain.Member, error) {
+	role, ok := f.roles[userID]
+	if workspaceID != 1 || !ok {
+		return nil, nil
+	}
+	return &wsDomain.Member{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil
+}
+
+func TestTrashFollowsWorkspaceAccess(t *testing.T) {
+	workspaceID := 1
+	access := workspace.NewAccess(fakeMembers{roles: map[int]string{1: workspace.RoleOwner, 2: workspace.RoleEditor, 3: workspace.RoleViewer}}, nil)
+
+	tests := []struct {
+		name    string
+		item    domain.Item
+		userID  int
+		wantErr string
+	}{
+		{"owner of a personal item", domain.Item{EntityType: "habit", EntityID: 5, UserID: 1}, 1, ""},
+		{"someone else's personal item", domain.Item{EntityType: "habit", EntityID: 5, UserID: 1}, 2, "unauthorized"},
+		{"editor restores a shared item created by another member", domain.Item{EntityType: "task", EntityID: 5, UserID: 1, WorkspaceID: &workspaceID}, 2, ""},
+		{"creator who is only a viewer now", domain.Item{EntityType: "task", EntityID: 5, UserID: 3, WorkspaceID: &workspaceID}, 3, "unauthorized"},
+		{"non-member", domain.Item{EntityType: "course", EntityID: 5, UserID: 1, WorkspaceID: &workspaceID}, 9, "unauthorized"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, purge := range []bool{false, true} {
+				item := tt.item
+				repo := &fakeTrash{item: &item}
+				svc := NewTrashService(repo, nil, logger.NewLogger(nil), access, 0)
+
+				var err error
+				if purge {
+					err = svc.Purge(context.Background(), item.EntityType, item.EntityID, tt.userID)
+				} else {
+					_, err = svc.Restore(context.Background(), item.EntityType, item.EntityID, tt.userID)
+				}
+
+				if tt.wantErr != "" {
+					if err == nil || err.Error() != tt.wantErr {
+						t.Fatalf("purge=%v: error = %v, want %q", purge, err, tt.wantErr)
+					}
+					if repo.restored || repo.purged {
+						t.Fatalf("purge=%v: item was changed without access", purge)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("purge=%v: %v", purge, err)
+				}
+				if repo.restored == purge || repo.purged != purge {
+					t.Errorf("purge=%v: restored=%v purged=%v", purge, repo.restored, repo.purged)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package domain
+
+// Role is a named set of permissions assigned to users
+type Role struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// Access is what a user may do: their roles and the permissions those roles grant
+type Access struct {
+	Roles       []string
+	Permissions []string
+}
@@ -9,8 +9,16 @@ type User struct {
 	FullName     string
 	AvatarURL    string
 	Timezone     string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	Language     string
+	// EmailVerifiedAt is when the user proved they own Email; nil until then
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// EmailVerified reports whether the user has verified their current email address
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 func (u *User) IsValid() bool {
@@ -5,15 +5,21 @@ type CreateUserRequest struct {
 	Password string `json:"password" validate:"required,min=6"`
 	FullName string `json:"full_name,omitempty"`
 	Timezone string `json:"timezone,omitempty"`
+	Language string `json:"language,omitempty" validate:"omitempty,oneof=tr en"`
 }
 
 type UpdateUserRequest struct {
 	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
 	FullName *string `json:"full_name,omitempty"`
 	Timezone *string `json:"timezone,omitempty"`
+	Language *string `json:"language,omitempty" validate:"omitempty,oneof=tr en"`
 }
 
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required"`
 	NewPassword string `json:"new_password" validate:"required,min=6"`
 }
+
+type SetRolesRequest struct {
+	Roles []string `json:"roles" validate:"required,min=1,dive,required"`
+}
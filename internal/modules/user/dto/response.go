@@ -7,24 +7,30 @@ import (
 )
 
 type UserResponse struct {
-	ID        int       `json:"id"`
-	Email     string    `json:"email"`
-	FullName  string    `json:"full_name,omitempty"`
-	AvatarURL string    `json:"avatar_url,omitempty"`
-	Timezone  string    `json:"timezone"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	FullName        string     `json:"full_name,omitempty"`
+	AvatarURL       string     `json:"avatar_url,omitempty"`
+	Timezone        string     `json:"timezone"`
+	Language        string     `json:"language"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 func ToUserResponse(u *domain.User) *UserResponse {
 	return &UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		FullName:  u.FullName,
-		AvatarURL: u.AvatarURL,
-		Timezone:  u.Timezone,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:              u.ID,
+		Email:           u.Email,
+		FullName:        u.FullName,
+		AvatarURL:       u.AvatarURL,
+		Timezone:        u.Timezone,
+		Language:        u.Language,
+		EmailVerified:   u.EmailVerified(),
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
 	}
 }
 
@@ -35,3 +41,38 @@ func ToUserResponseList(users []*domain.User) []*UserResponse {
 	}
 	return result
 }
+
+// MeResponse is the caller's own profile together with what they are allowed to do
+type MeResponse struct {
+	*UserResponse
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+type UserAccessResponse struct {
+	UserID      int      `json:"user_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+func ToMeResponse(u *domain.User, access *domain.Access) *MeResponse {
+	return &MeResponse{UserResponse: ToUserResponse(u), Roles: access.Roles, Permissions: access.Permissions}
+}
+
+func ToRoleResponseList(roles []*domain.Role) []*RoleResponse {
+	result := make([]*RoleResponse, len(roles))
+	for i, r := range roles {
+		result[i] = &RoleResponse{Name: r.Name, Description: r.Description, Permissions: r.Permissions}
+	}
+	return result
+}
+
+func ToUserAccessResponse(userID int, access *domain.Access) *UserAccessResponse {
+	return &UserAccessResponse{UserID: userID, Roles: access.Roles, Permissions: access.Permissions}
+}
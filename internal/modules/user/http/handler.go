@@ -2,11 +2,14 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
 	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/middleware"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/service"
 	"github.com/gorilla/mux"
@@ -21,11 +24,18 @@ func NewHandler(service service.UserService) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/users", h.GetAllUsers).Methods("GET")
-	router.HandleFunc("/users", h.CreateUser).Methods("POST")
-	router.HandleFunc("/users/{id}", h.GetUser).Methods("GET")
-	router.HandleFunc("/users/{id}", h.UpdateUser).Methods("PUT")
-	router.HandleFunc("/users/{id}", h.DeleteUser).Methods("DELETE")
+	// Self-service profile
+	router.HandleFunc("/me", h.GetMe).Methods("GET")
+	router.HandleFunc("/me", h.UpdateMe).Methods("PUT")
+
+	// User management (admin)
+	router.HandleFunc("/users", middleware.Require(authz.UsersRead, h.GetAllUsers)).Methods("GET")
+	router.HandleFunc("/users", middleware.Require(authz.UsersWrite, h.CreateUser)).Methods("POST")
+	router.HandleFunc("/users/{id}", middleware.Require(authz.UsersRead, h.GetUser)).Methods("GET")
+	router.HandleFunc("/users/{id}", middleware.Require(authz.UsersWrite, h.UpdateUser)).Methods("PUT")
+	router.HandleFunc("/users/{id}", middleware.Require(authz.UsersWrite, h.DeleteUser)).Methods("DELETE")
+	router.HandleFunc("/users/{id}/roles", middleware.Require(authz.RolesManage, h.SetRoles)).Methods("PUT")
+	router.HandleFunc("/roles", middleware.Require(authz.RolesManage, h.ListRoles)).Methods("GET")
 }
 
 func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
@@ -133,9 +143,108 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 			utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
 			return
 		}
+		if errors.Is(err, service.ErrLastAdmin) {
+			utils.ReturnError(w, "BAD_REQUEST", "Son yönetici silinemez", err.Error())
+			return
+		}
 		utils.ReturnError(w, "INTERNAL_ERROR", "Kullanıcı silinemedi", err.Error())
 		return
 	}
 
 	utils.WriteJson(w, nil, http.StatusOK, "Kullanıcı silindi")
 }
+
+func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+
+	me, err := h.service.GetMe(r.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+			return
+		}
+		utils.ReturnError(w, "INTERNAL_ERROR", "Profil getirilemedi", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, me, http.StatusOK, "Profil getirildi")
+}
+
+func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID := utils.GetUserIDFromContext(r.Context())
+
+	var req dto.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	user, err := h.service.UpdateUser(r.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "user not found" {
+			utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+			return
+		}
+		if err.Error() == "email already exists" {
+			utils.ReturnError(w, "BAD_REQUEST", "Bu e-posta adresi zaten kullanımda", err.Error())
+			return
+		}
+		utils.ReturnError(w, "INTERNAL_ERROR", "Profil güncellenemedi", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, user, http.StatusOK, "Profil güncellendi")
+}
+
+func (h *Handler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.ListRoles(r.Context())
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Roller getirilemedi", err.Error())
+		return
+	}
+
+	utils.WriteJson(w, roles, http.StatusOK, "Roller getirildi")
+}
+
+func (h *Handler) SetRoles(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz kullanıcı ID", err.Error())
+		return
+	}
+
+	var req dto.SetRolesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek formatı", err.Error())
+		return
+	}
+
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	actorID := utils.GetUserIDFromContext(r.Context())
+	access, err := h.service.SetRoles(r.Context(), actorID, id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLastAdmin):
+			utils.ReturnError(w, "BAD_REQUEST", "Son yöneticinin yönetici rolü kaldırılamaz", err.Error())
+		case err.Error() == "user not found":
+			utils.ReturnError(w, "NOT_FOUND", "Kullanıcı bulunamadı", err.Error())
+		case err.Error() == "invalid role":
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz rol", err.Error())
+		default:
+			utils.ReturnError(w, "INTERNAL_ERROR", "Roller güncellenemedi", err.Error())
+		}
+		return
+	}
+
+	utils.WriteJson(w, access, http.StatusOK, "Roller güncellendi")
+}
@@ -3,18 +3,22 @@ package repository
 import (
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
 )
 
 type UserModel struct {
-	ID           int       `db:"id"`
-	Email        string    `db:"email"`
-	PasswordHash string    `db:"password_hash"`
-	FullName     *string   `db:"full_name"`
-	AvatarURL    *string   `db:"avatar_url"`
-	Timezone     string    `db:"timezone"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
+	ID              int        `db:"id"`
+	Email           string     `db:"email"`
+	PasswordHash    string     `db:"password_hash"`
+	FullName        *string    `db:"full_name"`
+	AvatarURL       *string    `db:"avatar_url"`
+	Timezone        string     `db:"timezone"`
+	Language        string     `db:"language"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
 }
 
 func (m *UserModel) ToDomain() *domain.User {
@@ -28,14 +32,16 @@ func (m *UserModel) ToDomain() *domain.User {
 	}
 
 	return &domain.User{
-		ID:           m.ID,
-		Email:        m.Email,
-		PasswordHash: m.PasswordHash,
-		FullName:     fullName,
-		AvatarURL:    avatarURL,
-		Timezone:     m.Timezone,
-		CreatedAt:    m.CreatedAt,
-		UpdatedAt:    m.UpdatedAt,
+		ID:              m.ID,
+		Email:           m.Email,
+		PasswordHash:    m.PasswordHash,
+		FullName:        fullName,
+		AvatarURL:       avatarURL,
+		Timezone:        m.Timezone,
+		Language:        m.Language,
+		EmailVerifiedAt: m.EmailVerifiedAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
 	}
 }
 
@@ -49,13 +55,34 @@ func FromDomain(u *domain.User) *UserModel {
 	}
 
 	return &UserModel{
-		ID:           u.ID,
-		Email:        u.Email,
-		PasswordHash: u.PasswordHash,
-		FullName:     fullName,
-		AvatarURL:    avatarURL,
-		Timezone:     u.Timezone,
-		CreatedAt:    u.CreatedAt,
-		UpdatedAt:    u.UpdatedAt,
+		ID:              u.ID,
+		Email:           u.Email,
+		PasswordHash:    u.PasswordHash,
+		FullName:        fullName,
+		AvatarURL:       avatarURL,
+		Timezone:        u.Timezone,
+		Language:        u.Language,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
+
+type RoleModel struct {
+	Name        string         `db:"name"`
+	Description *string        `db:"description"`
+	Permissions pq.StringArray `db:"permissions"`
+}
+
+func (m *RoleModel) ToDomain() *domain.Role {
+	role := &domain.Role{Name: m.Name, Permissions: []string(m.Permissions)}
+	if m.Description != nil {
+		role.Description = *m.Description
 	}
+	return role
+}
+
+type AccessModel struct {
+	Roles       pq.StringArray `db:"roles"`
+	Permissions pq.StringArray `db:"permissions"`
 }
@@ -6,8 +6,10 @@ import (
 	"errors"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type postgresRepository struct {
@@ -19,10 +21,16 @@ func NewPostgresRepository(db *sqlx.DB) UserRepository {
 }
 
 func (r *postgresRepository) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	// Every new account starts with the "user" role
 	query := `
-		INSERT INTO users (email, password_hash, full_name, avatar_url, timezone, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at, updated_at
+		WITH created AS (
+			INSERT INTO users (email, password_hash, full_name, avatar_url, timezone, language, email_verified_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id, created_at, updated_at
+		), granted AS (
+			INSERT INTO user_roles (user_id, role) SELECT id, 'user' FROM created
+		)
+		SELECT id, created_at, updated_at FROM created
 	`
 
 	now := time.Now()
@@ -35,6 +43,8 @@ func (r *postgresRepository) Create(ctx context.Context, user *domain.User) (*do
 		model.FullName,
 		model.AvatarURL,
 		model.Timezone,
+		model.Language,
+		model.EmailVerifiedAt,
 		now,
 		now,
 	).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
@@ -48,7 +58,7 @@ func (r *postgresRepository) Create(ctx context.Context, user *domain.User) (*do
 
 func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, full_name, avatar_url, timezone, created_at, updated_at
+		SELECT id, email, password_hash, full_name, avatar_url, timezone, language, email_verified_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -67,9 +77,9 @@ func (r *postgresRepository) GetByID(ctx context.Context, id int) (*domain.User,
 
 func (r *postgresRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, full_name, avatar_url, timezone, created_at, updated_at
+		SELECT id, email, password_hash, full_name, avatar_url, timezone, language, email_verified_at, created_at, updated_at
 		FROM users
-		WHERE email = $1
+		WHERE LOWER(email) = LOWER($1)
 	`
 
 	var model UserModel
@@ -86,7 +96,7 @@ func (r *postgresRepository) GetByEmail(ctx context.Context, email string) (*dom
 
 func (r *postgresRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, full_name, avatar_url, timezone, created_at, updated_at
+		SELECT id, email, password_hash, full_name, avatar_url, timezone, language, email_verified_at, created_at, updated_at
 		FROM users
 		ORDER BY created_at DESC
 	`
@@ -108,8 +118,8 @@ func (r *postgresRepository) GetAll(ctx context.Context) ([]*domain.User, error)
 func (r *postgresRepository) Update(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
-		SET email = $1, password_hash = $2, full_name = $3, avatar_url = $4, timezone = $5, updated_at = $6
-		WHERE id = $7
+		SET email = $1, password_hash = $2, full_name = $3, avatar_url = $4, timezone = $5, language = $6, email_verified_at = $7, updated_at = $8
+		WHERE id = $9
 	`
 
 	model := FromDomain(user)
@@ -120,6 +130,8 @@ func (r *postgresRepository) Update(ctx context.Context, user *domain.User) erro
 		model.FullName,
 		model.AvatarURL,
 		model.Timezone,
+		model.Language,
+		model.EmailVerifiedAt,
 		time.Now(),
 		model.ID,
 	)
@@ -132,3 +144,82 @@ func (r *postgresRepository) Delete(ctx context.Context, id int) error {
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+type roleRepository struct{ db *sqlx.DB }
+
+func NewRoleRepository(db *sqlx.DB) RoleRepository { return &roleRepository{db: db} }
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *roleRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *roleRepository) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	query := `
+		SELECT r.name, r.description,
+			COALESCE(array_agg(p.permission ORDER BY p.permission) FILTER (WHERE p.permission IS NOT NULL), '{}') AS permissions
+		FROM roles r
+		LEFT JOIN role_permissions p ON p.role = r.name
+		GROUP BY r.name, r.description
+		ORDER BY r.name
+	`
+
+	var models []RoleModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query); err != nil {
+		return nil, err
+	}
+
+	roles := make([]*domain.Role, len(models))
+	for i := range models {
+		roles[i] = models[i].ToDomain()
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetAccess(ctx context.Context, userID int) (*domain.Access, error) {
+	query := `
+		SELECT
+			COALESCE((SELECT array_agg(role ORDER BY role) FROM user_roles WHERE user_id = $1), '{}') AS roles,
+			COALESCE((
+				SELECT array_agg(DISTINCT p.permission ORDER BY p.permission)
+				FROM user_roles ur
+				JOIN role_permissions p ON p.role = ur.role
+				WHERE ur.user_id = $1
+			), '{}') AS permissions
+	`
+
+	var model AccessModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, userID); err != nil {
+		return nil, err
+	}
+	return &domain.Access{Roles: []string(model.Roles), Permissions: []string(model.Permissions)}, nil
+}
+
+func (r *roleRepository) SetUserRoles(ctx context.Context, userID int, roles []string) error {
+	if _, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO user_roles (user_id, role)
+		SELECT $1, unnest($2::text[])`,
+		userID, pq.StringArray(roles))
+	return err
+}
+
+func (r *roleRepository) CountUsersWithRole(ctx context.Context, role string) (int, error) {
+	var count int
+	err := r.conn(ctx).GetContext(ctx, &count, `SELECT COUNT(*) FROM user_roles WHERE role = $1`, role)
+	return count, err
+}
+
+func (r *roleRepository) GrantByEmails(ctx context.Context, emails []string, role string) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO user_roles (user_id, role)
+		SELECT id, $2 FROM users WHERE lower(email) = ANY($1)
+		ON CONFLICT (user_id, role) DO NOTHING`,
+		pq.StringArray(emails), role)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -9,8 +9,21 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) (*domain.User, error)
 	GetByID(ctx context.Context, id int) (*domain.User, error)
+	// GetByEmail matches the email case-insensitively
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetAll(ctx context.Context) ([]*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
 	Delete(ctx context.Context, id int) error
 }
+
+type RoleRepository interface {
+	// ListRoles returns every role with its permissions
+	ListRoles(ctx context.Context) ([]*domain.Role, error)
+	// GetAccess returns the user's roles and the union of their permissions
+	GetAccess(ctx context.Context, userID int) (*domain.Access, error)
+	// SetUserRoles replaces the user's roles
+	SetUserRoles(ctx context.Context, userID int, roles []string) error
+	CountUsersWithRole(ctx context.Context, role string) (int, error)
+	// GrantByEmails gives the role to the existing users with the given emails and returns how many gained it
+	GrantByEmails(ctx context.Context, emails []string, role string) (int64, error)
+}
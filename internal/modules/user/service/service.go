@@ -2,10 +2,27 @@ package service
 
 import (
 	"context"
+	"errors"
+	"os"
+	"strings"
 
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
 )
 
+// AdminEmailsFromEnv reads ADMIN_EMAILS, a comma separated list of accounts that get the admin role at startup
+func AdminEmailsFromEnv() []string {
+	var emails []string
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// ErrLastAdmin is returned when a change would leave no user with the admin role
+var ErrLastAdmin = errors.New("cannot remove the last admin")
+
 type UserService interface {
 	CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*dto.UserResponse, error)
 	GetUser(ctx context.Context, id int) (*dto.UserResponse, error)
@@ -13,4 +30,13 @@ type UserService interface {
 	GetAllUsers(ctx context.Context) ([]*dto.UserResponse, error)
 	UpdateUser(ctx context.Context, id int, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
 	DeleteUser(ctx context.Context, id int) error
+
+	// GetMe returns the caller's profile with their roles and permissions
+	GetMe(ctx context.Context, userID int) (*dto.MeResponse, error)
+	ListRoles(ctx context.Context) ([]*dto.RoleResponse, error)
+	// SetRoles replaces a user's roles on behalf of actorID; the user's current access tokens stop
+	// working so the change applies at once
+	SetRoles(ctx context.Context, actorID, id int, req *dto.SetRolesRequest) (*dto.UserAccessResponse, error)
+	// BootstrapAdmins gives the admin role to the existing users with the given emails
+	BootstrapAdmins(ctx context.Context, emails []string) (int64, error)
 }
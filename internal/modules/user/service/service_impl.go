@@ -3,8 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
 	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
 	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
@@ -12,15 +17,28 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// AccessRevoker invalidates a user's access tokens so their next request needs a fresh one
+type AccessRevoker interface {
+	RevokeAccessTokens(ctx context.Context, userID int) error
+}
+
 type userService struct {
-	repo   repository.UserRepository
-	logger *logger.ZapLogger
+	repo    repository.UserRepository
+	roles   repository.RoleRepository
+	uow     *database.UnitOfWork
+	revoker AccessRevoker
+	logger  *logger.ZapLogger
+	bus     *eventbus.Bus
 }
 
-func NewUserService(repo repository.UserRepository, logger *logger.ZapLogger) UserService {
+func NewUserService(repo repository.UserRepository, roles repository.RoleRepository, uow *database.UnitOfWork, revoker AccessRevoker, logger *logger.ZapLogger, bus *eventbus.Bus) UserService {
 	return &userService{
-		repo:   repo,
-		logger: logger,
+		repo:    repo,
+		roles:   roles,
+		uow:     uow,
+		revoker: revoker,
+		logger:  logger,
+		bus:     bus,
 	}
 }
 
@@ -48,17 +66,37 @@ func (s *userService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 		timezone = "Europe/Istanbul"
 	}
 
+	language := req.Language
+	if language == "" {
+		language = "tr"
+	}
+
 	now := time.Now()
 	user := &domain.User{
 		Email:        req.Email,
 		PasswordHash: string(hashedPassword),
 		FullName:     req.FullName,
 		Timezone:     timezone,
+		Language:     language,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
 
-	created, err := s.repo.Create(ctx, user)
+	var (
+		created  *domain.User
+		response *dto.UserResponse
+	)
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, user); err != nil {
+			return err
+		}
+		response = dto.ToUserResponse(created)
+		return s.publish(ctx, created.ID, events.UserCreated{
+			UserID: created.ID,
+			User:   response,
+		})
+	})
 	if err != nil {
 		s.logger.Error("failed to create user", err, map[string]interface{}{
 			"email":  req.Email,
@@ -73,7 +111,7 @@ func (s *userService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 		"action":  "CREATE_USER",
 	})
 
-	return dto.ToUserResponse(created), nil
+	return response, nil
 }
 
 func (s *userService) GetUser(ctx context.Context, id int) (*dto.UserResponse, error) {
@@ -115,36 +153,56 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *dto.UpdateUse
 		"action":  "UPDATE_USER",
 	})
 
-	user, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if user == nil {
-		return nil, errors.New("user not found")
-	}
-
-	if req.Email != nil {
-		existing, err := s.repo.GetByEmail(ctx, *req.Email)
+	var response *dto.UserResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		user, err := s.repo.GetByID(ctx, id)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		if existing != nil && existing.ID != id {
-			return nil, errors.New("email already exists")
+		if user == nil {
+			return errors.New("user not found")
+		}
+		before := dto.ToUserResponse(user)
+
+		if req.Email != nil {
+			existing, err := s.repo.GetByEmail(ctx, *req.Email)
+			if err != nil {
+				return err
+			}
+			if existing != nil && existing.ID != id {
+				return errors.New("email already exists")
+			}
+			// A new address has to be verified again
+			if *req.Email != user.Email {
+				user.EmailVerifiedAt = nil
+			}
+			user.Email = *req.Email
 		}
-		user.Email = *req.Email
-	}
 
-	if req.FullName != nil {
-		user.FullName = *req.FullName
-	}
+		if req.FullName != nil {
+			user.FullName = *req.FullName
+		}
 
-	if req.Timezone != nil {
-		user.Timezone = *req.Timezone
-	}
+		if req.Timezone != nil {
+			user.Timezone = *req.Timezone
+		}
+
+		if req.Language != nil {
+			user.Language = *req.Language
+		}
 
-	user.UpdatedAt = time.Now()
+		user.UpdatedAt = time.Now()
 
-	if err := s.repo.Update(ctx, user); err != nil {
+		response = dto.ToUserResponse(user)
+		if err := s.repo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), id, events.UserUpdated{
+			UserID: id,
+			User:   response,
+		})
+	})
+	if err != nil {
 		s.logger.Error("failed to update user", err, map[string]interface{}{
 			"user_id": id,
 			"action":  "UPDATE_USER_FAILED",
@@ -157,7 +215,7 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *dto.UpdateUse
 		"action":  "UPDATE_USER",
 	})
 
-	return dto.ToUserResponse(user), nil
+	return response, nil
 }
 
 func (s *userService) DeleteUser(ctx context.Context, id int) error {
@@ -166,15 +224,35 @@ func (s *userService) DeleteUser(ctx context.Context, id int) error {
 		"action":  "DELETE_USER",
 	})
 
-	user, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if user == nil {
-		return errors.New("user not found")
-	}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		user, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return errors.New("user not found")
+		}
+		before := dto.ToUserResponse(user)
+
+		access, err := s.roles.GetAccess(ctx, id)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(access.Roles, authz.RoleAdmin) {
+			if err := s.ensureAnotherAdmin(ctx); err != nil {
+				return err
+			}
+		}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), id, events.UserDeleted{
+			UserID: id,
+			Email:  user.Email,
+		})
+	})
+	if err != nil {
 		s.logger.Error("failed to delete user", err, map[string]interface{}{
 			"user_id": id,
 			"action":  "DELETE_USER_FAILED",
@@ -189,3 +267,168 @@ func (s *userService) DeleteUser(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func (s *userService) GetMe(ctx context.Context, userID int) (*dto.MeResponse, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	access, err := s.roles.GetAccess(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToMeResponse(user, access), nil
+}
+
+func (s *userService) ListRoles(ctx context.Context) ([]*dto.RoleResponse, error) {
+	roles, err := s.roles.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToRoleResponseList(roles), nil
+}
+
+func (s *userService) SetRoles(ctx context.Context, actorID, id int, req *dto.SetRolesRequest) (*dto.UserAccessResponse, error) {
+	s.logger.Info("Setting user roles", map[string]interface{}{
+		"user_id":  id,
+		"actor_id": actorID,
+		"roles":    req.Roles,
+		"action":   "UPDATE_USER_ROLES",
+	})
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	known, err := s.roles.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]string, 0, len(req.Roles))
+	for _, role := range req.Roles {
+		if !slices.ContainsFunc(known, func(r *domain.Role) bool { return r.Name == role }) {
+			return nil, errors.New("invalid role")
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	var access *domain.Access
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		current, err := s.roles.GetAccess(ctx, id)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(current.Roles, authz.RoleAdmin) && !slices.Contains(roles, authz.RoleAdmin) {
+			if err := s.ensureAnotherAdmin(ctx); err != nil {
+				return err
+			}
+		}
+
+		if err := s.roles.SetUserRoles(ctx, id, roles); err != nil {
+			return err
+		}
+		if access, err = s.roles.GetAccess(ctx, id); err != nil {
+			return err
+		}
+
+		if sameRoles(current.Roles, access.Roles) {
+			return nil
+		}
+		return s.publish(ctx, id, events.RolesChanged{
+			UserID:        id,
+			ActorID:       actorID,
+			PreviousRoles: current.Roles,
+			Roles:         access.Roles,
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, ErrLastAdmin) {
+			s.logger.Error("failed to set user roles", err, map[string]interface{}{
+				"user_id":  id,
+				"actor_id": actorID,
+				"action":   "UPDATE_USER_ROLES_FAILED",
+			})
+		}
+		return nil, err
+	}
+
+	// Roles travel inside access tokens; make the user fetch a new one
+	if s.revoker != nil {
+		if err := s.revoker.RevokeAccessTokens(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info("user roles updated", map[string]interface{}{
+		"user_id":  id,
+		"actor_id": actorID,
+		"roles":    access.Roles,
+		"action":   "UPDATE_USER_ROLES",
+	})
+
+	return dto.ToUserAccessResponse(id, access), nil
+}
+
+func (s *userService) BootstrapAdmins(ctx context.Context, emails []string) (int64, error) {
+	if len(emails) == 0 {
+		return 0, nil
+	}
+
+	granted, err := s.roles.GrantByEmails(ctx, emails, authz.RoleAdmin)
+	if err != nil {
+		return 0, err
+	}
+
+	if granted > 0 {
+		s.logger.Info("admin role granted from ADMIN_EMAILS", map[string]interface{}{
+			"granted": granted,
+			"action":  "BOOTSTRAP_ADMINS",
+		})
+	}
+	return granted, nil
+}
+
+// ensureAnotherAdmin fails when taking the admin role away would leave nobody able to manage users
+func (s *userService) ensureAnotherAdmin(ctx context.Context) error {
+	admins, err := s.roles.CountUsersWithRole(ctx, authz.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// sameRoles reports whether two role lists hold the same roles, in any order
+func sameRoles(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, role := range a {
+		if !slices.Contains(b, role) {
+			return false
+		}
+	}
+	return true
+}
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *userService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
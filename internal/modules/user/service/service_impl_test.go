@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/authz"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
+)
+
+type fakeUsers struct {
+	repository.UserRepository
+}
+
+func (fakeUsers) GetByID(ctx context.Context, id int) (*domain.User, error) {
+	return &domain.User{ID: id}, nil
+}
+
+// fakeRoles keeps each user's roles in memory
+type fakeRoles struct {
+	repository.RoleRepository
+	roles map[int][]string
+}
+
+func (r *fakeRoles) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	return []*domain.Role{{Name: authz.RoleAdmin}, {Name: authz.RoleUser}}, nil
+}
+
+func (r *fakeRoles) GetAccess(ctx context.Context, userID int) (*domain.Access, error) {
+	return &domain.Access{Roles: r.roles[userID]}, nil
+}
+
+func (r *fakeRoles) SetUserRoles(ctx context.Context, userID int, roles []string) error {
+	r.roles[userID] = roles
+	return nil
+}
+
+func (r *fakeRoles) CountUsersWithRole(ctx context.Context, role string) (int, error) {
+	count := 0
+	for _, roles := range r.roles {
+		for _, r := range roles {
+			if r == role {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func TestSetRoles(t *testing.T) {
+	admin, user := []string{authz.RoleAdmin, authz.RoleUser}, []string{authz.RoleUser}
+
+	tests := []struct {
+		name    string
+		roles   map[int][]string
+		target  int
+		set     []string
+		wantErr error
+		want    []events.RolesChanged
+	}{
+		{
+			name:   "granting admin",
+			roles:  map[int][]string{1: admin, 2: user},
+			target: 2,
+			set:    admin,
+			want:   []events.RolesChanged{{UserID: 2, ActorID: 1, PreviousRoles: user, Roles: admin}},
+		},
+		{
+			name:   "removing admin",
+			roles:  map[int][]string{1: admin, 2: admin},
+			target: 2,
+			set:    user,
+			want:   []events.RolesChanged{{UserID: 2, ActorID: 1, PreviousRoles: admin, Roles: user}},
+		},
+		{
+			name:   "same roles in another order",
+			roles:  map[int][]string{1: admin, 2: user},
+			target: 1,
+			set:    []string{authz.RoleUser, authz.RoleAdmin},
+		},
+		{
+			name:    "last admin",
+			roles:   map[int][]string{1: admin, 2: user},
+			target:  1,
+			set:     user,
+			wantErr: ErrLastAdmin,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := eventbus.New(nil, logger.NewLogger(nil))
+			var published []events.RolesChanged
+			eventbus.Subscribe(bus, "test", func(ctx context.Context, userID int, event events.RolesChanged) error {
+				published = append(published, event)
+				return nil
+			})
+			svc := NewUserService(fakeUsers{}, &fakeRoles{roles: tt.roles}, nil, nil, logger.NewLogger(nil), bus)
+
+			_, err := svc.SetRoles(context.Background(), 1, tt.target, &dto.SetRolesRequest{Roles: tt.set})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(published, tt.want) {
+				t.Errorf("published %+v, want %+v", published, tt.want)
+			}
+		})
+	}
+}
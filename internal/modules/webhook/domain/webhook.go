@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AllEvents subscribes an endpoint to every event type
+const AllEvents = "*"
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Endpoint is a user-registered URL that receives signed event deliveries
+type Endpoint struct {
+	ID          int
+	UserID      int
+	URL         string
+	Description string
+	Secret      string
+	EventTypes  []string
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Subscribes reports whether the endpoint wants the given event type
+func (e *Endpoint) Subscribes(eventType string) bool {
+	for _, t := range e.EventTypes {
+		if t == AllEvents || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one event sent (or to be sent) to an endpoint, with the outcome of its last attempt
+type Delivery struct {
+	ID             int
+	EndpointID     int
+	UserID         int
+	EventType      string
+	MessageID      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	MaxAttempts    int
+	NextAttemptAt  time.Time
+	ResponseStatus *int
+	LastError      string
+	DurationMs     *int
+	DeliveredAt    *time.Time
+	RedeliveryOf   *int
+	CreatedAt      time.Time
+}
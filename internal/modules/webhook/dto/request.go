@@ -0,0 +1,21 @@
+package dto
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url"`
+	Description string   `json:"description,omitempty" validate:"max=255"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,dive,required"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url,omitempty" validate:"omitempty,url"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+	EventTypes  []string `json:"event_types,omitempty" validate:"omitempty,min=1,dive,required"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// ListDeliveriesRequest is built from the query string of GET /webhooks/{id}/deliveries
+type ListDeliveriesRequest struct {
+	Page  int `json:"page" validate:"min=1"`
+	Limit int `json:"limit" validate:"min=1,max=100"`
+}
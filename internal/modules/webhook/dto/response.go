@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/domain"
+)
+
+type WebhookResponse struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	EventTypes  []string  `json:"event_types"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookSecretResponse is returned only on create and secret rotation
+type WebhookSecretResponse struct {
+	*WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type DeliveryResponse struct {
+	ID             int             `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	MessageID      string          `json:"message_id,omitempty"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	MaxAttempts    int             `json:"max_attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DurationMs     *int            `json:"duration_ms,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	RedeliveryOf   *int            `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type DeliveryListResponse struct {
+	Items []*DeliveryResponse `json:"items"`
+	Total int                 `json:"total"`
+	Page  int                 `json:"page"`
+	Limit int                 `json:"limit"`
+}
+
+func ToWebhookResponse(e *domain.Endpoint) *WebhookResponse {
+	if e == nil {
+		return nil
+	}
+	return &WebhookResponse{ID: e.ID, URL: e.URL, Description: e.Description, EventTypes: e.EventTypes, Active: e.Active, CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt}
+}
+
+func ToWebhookResponseList(endpoints []*domain.Endpoint) []*WebhookResponse {
+	result := make([]*WebhookResponse, len(endpoints))
+	for i, e := range endpoints {
+		result[i] = ToWebhookResponse(e)
+	}
+	return result
+}
+
+func ToWebhookSecretResponse(e *domain.Endpoint) *WebhookSecretResponse {
+	return &WebhookSecretResponse{WebhookResponse: ToWebhookResponse(e), Secret: e.Secret}
+}
+
+// ToDeliveryResponse includes the payload; lists leave it out to stay small
+func ToDeliveryResponse(d *domain.Delivery, withPayload bool) *DeliveryResponse {
+	if d == nil {
+		return nil
+	}
+	resp := &DeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.EndpointID,
+		EventType:      d.EventType,
+		MessageID:      d.MessageID,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		MaxAttempts:    d.MaxAttempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DurationMs:     d.DurationMs,
+		DeliveredAt:    d.DeliveredAt,
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt,
+	}
+	if withPayload {
+		resp.Payload = d.Payload
+	}
+	if d.Status == domain.DeliveryPending {
+		next := d.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+	return resp
+}
+
+func ToDeliveryResponseList(deliveries []*domain.Delivery) []*DeliveryResponse {
+	result := make([]*DeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = ToDeliveryResponse(d, false)
+	}
+	return result
+}
@@ -0,0 +1,236 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/service"
+	"github.com/gorilla/mux"
+)
+
+const defaultPageSize = 20
+
+type Handler struct{ service service.WebhookService }
+
+func NewHandler(service service.WebhookService) *Handler { return &Handler{service: service} }
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/webhooks", h.GetAll).Methods("GET")
+	router.HandleFunc("/webhooks", h.Create).Methods("POST")
+	router.HandleFunc("/webhooks/{id}", h.GetByID).Methods("GET")
+	router.HandleFunc("/webhooks/{id}", h.Update).Methods("PUT", "PATCH")
+	router.HandleFunc("/webhooks/{id}", h.Delete).Methods("DELETE")
+	router.HandleFunc("/webhooks/{id}/rotate-secret", h.RotateSecret).Methods("POST")
+	router.HandleFunc("/webhooks/{id}/deliveries", h.ListDeliveries).Methods("GET")
+	router.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}", h.GetDelivery).Methods("GET")
+	router.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}/redeliver", h.Redeliver).Methods("POST")
+}
+
+func (h *Handler) getUserID(r *http.Request) int {
+	return utils.GetUserIDFromContext(r.Context())
+}
+
+// Create registers an endpoint; the signing secret is only returned here and on rotation
+// POST /api/webhooks
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+	webhook, err := h.service.Create(r.Context(), &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Webhook oluşturulamadı")
+		return
+	}
+	utils.WriteJson(w, webhook, http.StatusCreated, "Webhook oluşturuldu")
+}
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+	webhook, err := h.service.GetByID(r.Context(), id, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Webhook getirilemedi")
+		return
+	}
+	utils.WriteJson(w, webhook, http.StatusOK, "Webhook getirildi")
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.GetAll(r.Context(), h.getUserID(r))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Webhook'lar getirilemedi", err.Error())
+		return
+	}
+	utils.WriteJson(w, webhooks, http.StatusOK, "Webhook'lar getirildi")
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+	var req dto.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+	webhook, err := h.service.Update(r.Context(), id, &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Webhook güncellenemedi")
+		return
+	}
+	utils.WriteJson(w, webhook, http.StatusOK, "Webhook güncellendi")
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+	if err := h.service.Delete(r.Context(), id, h.getUserID(r)); err != nil {
+		h.handleError(w, err, "Webhook silinemedi")
+		return
+	}
+	utils.WriteJson(w, nil, http.StatusOK, "Webhook silindi")
+}
+
+// RotateSecret replaces the signing secret; deliveries from now on use the new one
+// POST /api/webhooks/{id}/rotate-secret
+func (h *Handler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+	webhook, err := h.service.RotateSecret(r.Context(), id, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Webhook anahtarı yenilenemedi")
+		return
+	}
+	utils.WriteJson(w, webhook, http.StatusOK, "Webhook anahtarı yenilendi")
+}
+
+// ListDeliveries returns the delivery log, newest first
+// GET /api/webhooks/{id}/deliveries?page=1&limit=20
+func (h *Handler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	req := dto.ListDeliveriesRequest{Page: 1, Limit: defaultPageSize}
+	if pageStr := query.Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz sayfa", err.Error())
+			return
+		}
+		req.Page = page
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			utils.ReturnError(w, "BAD_REQUEST", "Geçersiz limit", err.Error())
+			return
+		}
+		req.Limit = limit
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(r.Context(), id, &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Gönderimler getirilemedi")
+		return
+	}
+	utils.WriteJson(w, deliveries, http.StatusOK, "Gönderimler getirildi")
+}
+
+// GetDelivery returns one delivery including its payload
+// GET /api/webhooks/{id}/deliveries/{deliveryId}
+func (h *Handler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID, ok := h.deliveryIDs(w, r)
+	if !ok {
+		return
+	}
+	delivery, err := h.service.GetDelivery(r.Context(), id, deliveryID, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Gönderim getirilemedi")
+		return
+	}
+	utils.WriteJson(w, delivery, http.StatusOK, "Gönderim getirildi")
+}
+
+// Redeliver sends the payload of an earlier delivery again as a new delivery
+// POST /api/webhooks/{id}/deliveries/{deliveryId}/redeliver
+func (h *Handler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID, ok := h.deliveryIDs(w, r)
+	if !ok {
+		return
+	}
+	delivery, err := h.service.Redeliver(r.Context(), id, deliveryID, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Yeniden gönderim başlatılamadı")
+		return
+	}
+	utils.WriteJson(w, delivery, http.StatusAccepted, "Yeniden gönderim kuyruğa alındı")
+}
+
+func (h *Handler) deliveryIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return 0, 0, false
+	}
+	deliveryID, err := strconv.Atoi(vars["deliveryId"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz gönderim ID", err.Error())
+		return 0, 0, false
+	}
+	return id, deliveryID, true
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string) {
+	switch err.Error() {
+	case "webhook not found":
+		utils.ReturnError(w, "NOT_FOUND", "Webhook bulunamadı", err.Error())
+	case "delivery not found":
+		utils.ReturnError(w, "NOT_FOUND", "Gönderim bulunamadı", err.Error())
+	case "unknown event type":
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz olay tipi", err.Error())
+	case "invalid webhook url":
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz webhook adresi", err.Error())
+	case "webhook url must use https":
+		utils.ReturnError(w, "BAD_REQUEST", "Webhook adresi https kullanmalı", err.Error())
+	case "webhook url points to a private address":
+		utils.ReturnError(w, "BAD_REQUEST", "Webhook adresi iç ağa veya bu sunucuya yönlendirilemez", err.Error())
+	case "unauthorized":
+		utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
+	default:
+		utils.ReturnError(w, "INTERNAL_ERROR", message, err.Error())
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/domain"
+	"github.com/lib/pq"
+)
+
+type EndpointModel struct {
+	ID          int            `db:"id"`
+	UserID      int            `db:"user_id"`
+	URL         string         `db:"url"`
+	Description *string        `db:"description"`
+	Secret      string         `db:"secret"`
+	EventTypes  pq.StringArray `db:"event_types"`
+	Active      bool           `db:"active"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+func (m *EndpointModel) ToDomain() *domain.Endpoint {
+	description := ""
+	if m.Description != nil {
+		description = *m.Description
+	}
+	return &domain.Endpoint{ID: m.ID, UserID: m.UserID, URL: m.URL, Description: description, Secret: m.Secret, EventTypes: []string(m.EventTypes), Active: m.Active, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}
+}
+
+func EndpointFromDomain(e *domain.Endpoint) *EndpointModel {
+	var description *string
+	if e.Description != "" {
+		description = &e.Description
+	}
+	return &EndpointModel{ID: e.ID, UserID: e.UserID, URL: e.URL, Description: description, Secret: e.Secret, EventTypes: pq.StringArray(e.EventTypes), Active: e.Active, CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt}
+}
+
+type DeliveryModel struct {
+	ID             int        `db:"id"`
+	EndpointID     int        `db:"endpoint_id"`
+	UserID         int        `db:"user_id"`
+	EventType      string     `db:"event_type"`
+	MessageID      *string    `db:"message_id"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	MaxAttempts    int        `db:"max_attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	ResponseStatus *int       `db:"response_status"`
+	LastError      *string    `db:"last_error"`
+	DurationMs     *int       `db:"duration_ms"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	RedeliveryOf   *int       `db:"redelivery_of"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+func (m *DeliveryModel) ToDomain() *domain.Delivery {
+	d := &domain.Delivery{
+		ID:             m.ID,
+		EndpointID:     m.EndpointID,
+		UserID:         m.UserID,
+		EventType:      m.EventType,
+		Payload:        m.Payload,
+		Status:         m.Status,
+		Attempts:       m.Attempts,
+		MaxAttempts:    m.MaxAttempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		ResponseStatus: m.ResponseStatus,
+		DurationMs:     m.DurationMs,
+		DeliveredAt:    m.DeliveredAt,
+		RedeliveryOf:   m.RedeliveryOf,
+		CreatedAt:      m.CreatedAt,
+	}
+	if m.MessageID != nil {
+		d.MessageID = *m.MessageID
+	}
+	if m.LastError != nil {
+		d.LastError = *m.LastError
+	}
+	return d
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const endpointColumns = `id, user_id, url, description, secret, event_types, active, created_at, updated_at`
+
+const deliveryColumns = `id, endpoint_id, user_id, event_type, message_id, payload, status, attempts, max_attempts,
+	next_attempt_at, response_status, last_error, duration_ms, delivered_at, redelivery_of, created_at`
+
+type endpointRepository struct{ db *sqlx.DB }
+
+func NewEndpointRepository(db *sqlx.DB) EndpointRepository { return &endpointRepository{db: db} }
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *endpointRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *endpointRepository) Create(ctx context.Context, e *domain.Endpoint) (*domain.Endpoint, error) {
+	query := `INSERT INTO webhook_endpoints (user_id, url, description, secret, event_types, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
+	now := time.Now()
+	model := EndpointFromDomain(e)
+	err := r.conn(ctx).QueryRowxContext(ctx, query, model.UserID, model.URL, model.Description, model.Secret, model.EventTypes, model.Active, now, now).Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *endpointRepository) GetByID(ctx context.Context, id int) (*domain.Endpoint, error) {
+	query := `SELECT ` + endpointColumns + ` FROM webhook_endpoints WHERE id = $1`
+	var model EndpointModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *endpointRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Endpoint, error) {
+	query := `SELECT ` + endpointColumns + ` FROM webhook_endpoints WHERE user_id = $1 ORDER BY created_at`
+	return r.selectEndpoints(ctx, query, userID)
+}
+
+func (r *endpointRepository) GetSubscribed(ctx context.Context, userID int, eventType string) ([]*domain.Endpoint, error) {
+	query := `SELECT ` + endpointColumns + ` FROM webhook_endpoints
+		WHERE user_id = $1 AND active AND ($2 = ANY(event_types) OR $3 = ANY(event_types))`
+	return r.selectEndpoints(ctx, query, userID, eventType, domain.AllEvents)
+}
+
+func (r *endpointRepository) selectEndpoints(ctx context.Context, query string, args ...interface{}) ([]*domain.Endpoint, error) {
+	var models []EndpointModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, err
+	}
+	endpoints := make([]*domain.Endpoint, len(models))
+	for i, m := range models {
+		endpoints[i] = m.ToDomain()
+	}
+	return endpoints, nil
+}
+
+func (r *endpointRepository) Update(ctx context.Context, e *domain.Endpoint) error {
+	query := `UPDATE webhook_endpoints SET url = $1, description = $2, secret = $3, event_types = $4, active = $5, updated_at = $6 WHERE id = $7`
+	model := EndpointFromDomain(e)
+	_, err := r.conn(ctx).ExecContext(ctx, query, model.URL, model.Description, model.Secret, model.EventTypes, model.Active, time.Now(), model.ID)
+	return err
+}
+
+func (r *endpointRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	return err
+}
+
+type deliveryRepository struct{ db *sqlx.DB }
+
+func NewDeliveryRepository(db *sqlx.DB) DeliveryRepository { return &deliveryRepository{db: db} }
+
+func (r *deliveryRepository) Create(ctx context.Context, d *domain.Delivery) (*domain.Delivery, error) {
+	query := `INSERT INTO webhook_deliveries (endpoint_id, user_id, event_type, message_id, payload, max_attempts, redelivery_of, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + deliveryColumns
+	var messageID *string
+	if d.MessageID != "" {
+		messageID = &d.MessageID
+	}
+	var model DeliveryModel
+	err := r.db.QueryRowxContext(ctx, query, d.EndpointID, d.UserID, d.EventType, messageID, []byte(d.Payload), d.MaxAttempts, d.RedeliveryOf, time.Now()).StructScan(&model)
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *deliveryRepository) GetByID(ctx context.Context, id int) (*domain.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+	var model DeliveryModel
+	if err := r.db.GetContext(ctx, &model, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *deliveryRepository) ListByEndpoint(ctx context.Context, endpointID, limit, offset int) ([]*domain.Delivery, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM webhook_deliveries WHERE endpoint_id = $1`, endpointID); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE endpoint_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
+	var models []DeliveryModel
+	if err := r.db.SelectContext(ctx, &models, query, endpointID, limit, offset); err != nil {
+		return nil, 0, err
+	}
+	deliveries := make([]*domain.Delivery, len(models))
+	for i, m := range models {
+		deliveries[i] = m.ToDomain()
+	}
+	return deliveries, total, nil
+}
+
+func (r *deliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.Delivery, error) {
+	query := `UPDATE webhook_deliveries
+		SET attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $1)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $2 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+	var models []DeliveryModel
+	if err := r.db.SelectContext(ctx, &models, query, lease.Seconds(), domain.DeliveryPending, limit); err != nil {
+		return nil, err
+	}
+	deliveries := make([]*domain.Delivery, len(models))
+	for i, m := range models {
+		deliveries[i] = m.ToDomain()
+	}
+	return deliveries, nil
+}
+
+func (r *deliveryRepository) RecordAttempt(ctx context.Context, d *domain.Delivery) error {
+	query := `UPDATE webhook_deliveries
+		SET status = $1, next_attempt_at = $2, response_status = $3, last_error = $4,
+			duration_ms = $5, delivered_at = $6
+		WHERE id = $7`
+	var lastError *string
+	if d.LastError != "" {
+		lastError = &d.LastError
+	}
+	_, err := r.db.ExecContext(ctx, query, d.Status, d.NextAttemptAt, d.ResponseStatus, lastError, d.DurationMs, d.DeliveredAt, d.ID)
+	return err
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/domain"
+)
+
+type EndpointRepository interface {
+	Create(ctx context.Context, e *domain.Endpoint) (*domain.Endpoint, error)
+	GetByID(ctx context.Context, id int) (*domain.Endpoint, error)
+	GetByUserID(ctx context.Context, userID int) ([]*domain.Endpoint, error)
+	// GetSubscribed returns the user's active endpoints subscribed to eventType (directly or via "*")
+	GetSubscribed(ctx context.Context, userID int, eventType string) ([]*domain.Endpoint, error)
+	Update(ctx context.Context, e *domain.Endpoint) error
+	Delete(ctx context.Context, id int) error
+}
+
+type DeliveryRepository interface {
+	Create(ctx context.Context, d *domain.Delivery) (*domain.Delivery, error)
+	GetByID(ctx context.Context, id int) (*domain.Delivery, error)
+	ListByEndpoint(ctx context.Context, endpointID, limit, offset int) ([]*domain.Delivery, int, error)
+	// ClaimDue counts an attempt on up to limit pending deliveries that are due and hides them
+	// from other workers for lease, so each attempt is made by exactly one worker
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.Delivery, error)
+	// RecordAttempt stores the outcome of the latest attempt
+	RecordAttempt(ctx context.Context, d *domain.Delivery) error
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/metrics"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/websocket"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/repository"
+)
+
+const (
+	// maxAttempts per delivery; with exponential backoff the last retry is about an hour after the first try
+	maxAttempts     = 8
+	retryBaseDelay  = 30 * time.Second
+	retryMaxDelay   = time.Hour
+	deliveryBatch   = 20
+	deliveryLease   = 2 * time.Minute
+	deliveryTimeout = 10 * time.Second
+	userAgent       = "Webhook-Dispatcher/1.0"
+)
+
+// Dispatcher turns published events into webhook deliveries and sends them with retries
+type Dispatcher struct {
+	endpoints  repository.EndpointRepository
+	deliveries repository.DeliveryRepository
+	client     *http.Client
+	logger     *logger.ZapLogger
+}
+
+func NewDispatcher(endpoints repository.EndpointRepository, deliveries repository.DeliveryRepository, cfg Config, logger *logger.ZapLogger) *Dispatcher {
+	return &Dispatcher{
+		endpoints:  endpoints,
+		deliveries: deliveries,
+		client:     newClient(cfg),
+		logger:     logger,
+	}
+}
+
+// Dispatch queues a delivery of the message to each of the user's endpoints subscribed to its type.
+// Sending happens in DeliverDue so the publisher never waits on a receiver.
+func (d *Dispatcher) Dispatch(ctx context.Context, userID int, message *websocket.Message) error {
+	endpoints, err := d.endpoints.GetSubscribed(ctx, userID, message.Type)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		delivery, err := d.deliveries.Create(ctx, &domain.Delivery{
+			EndpointID:  endpoint.ID,
+			UserID:      userID,
+			EventType:   message.Type,
+			MessageID:   message.MessageID,
+			Payload:     body,
+			MaxAttempts: maxAttempts,
+		})
+		if err != nil {
+			return err
+		}
+
+		d.logger.Info("Webhook delivery queued", map[string]interface{}{
+			"user_id":     userID,
+			"webhook_id":  endpoint.ID,
+			"delivery_id": delivery.ID,
+			"type":        message.Type,
+			"action":      "WEBHOOK_DELIVERY_QUEUED",
+		})
+	}
+	return nil
+}
+
+// DeliverDue attempts every pending delivery that is due, concurrently. Failed attempts are
+// rescheduled with exponential backoff until maxAttempts. Returns the number delivered.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	due, err := d.deliveries.ClaimDue(ctx, deliveryBatch, deliveryLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		delivered int
+	)
+	for _, delivery := range due {
+		wg.Add(1)
+		go func(delivery *domain.Delivery) {
+			defer wg.Done()
+			if d.attempt(ctx, delivery) {
+				mu.Lock()
+				delivered++
+				mu.Unlock()
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return delivered, nil
+}
+
+// attempt sends one delivery and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, delivery *domain.Delivery) bool {
+	endpoint, err := d.endpoints.GetByID(ctx, delivery.EndpointID)
+	if err != nil {
+		// Leave the row claimed; it becomes due again when the lease expires
+		d.logger.Error("Failed to load webhook", err, map[string]interface{}{
+			"delivery_id": delivery.ID,
+			"action":      "WEBHOOK_DELIVERY_FAILED",
+		})
+		return false
+	}
+	if endpoint == nil || !endpoint.Active {
+		delivery.Status = domain.DeliveryFailed
+		delivery.LastError = "webhook disabled"
+		d.record(ctx, delivery, "failed")
+		return false
+	}
+
+	started := time.Now()
+	status, sendErr := d.send(ctx, endpoint, delivery)
+	duration := int(time.Since(started).Milliseconds())
+
+	delivery.DurationMs = &duration
+	delivery.ResponseStatus = nil
+	delivery.LastError = ""
+	if status > 0 {
+		delivery.ResponseStatus = &status
+	}
+
+	if sendErr == nil && status >= 200 && status < 300 {
+		now := time.Now()
+		delivery.Status = domain.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		d.record(ctx, delivery, "succeeded")
+
+		d.logger.Info("Webhook delivered", map[string]interface{}{
+			"webhook_id":  endpoint.ID,
+			"delivery_id": delivery.ID,
+			"status":      status,
+			"attempt":     delivery.Attempts,
+			"action":      "WEBHOOK_DELIVERED",
+		})
+		return true
+	}
+
+	if sendErr != nil {
+		delivery.LastError = sendErr.Error()
+	} else {
+		delivery.LastError = fmt.Sprintf("unexpected status %d", status)
+	}
+
+	if delivery.Attempts >= delivery.MaxAttempts {
+		delivery.Status = domain.DeliveryFailed
+		d.record(ctx, delivery, "failed")
+	} else {
+		delivery.Status = domain.DeliveryPending
+		delivery.NextAttemptAt = time.Now().Add(backoff(delivery.Attempts))
+		d.record(ctx, delivery, "retry")
+	}
+
+	d.logger.Error("Webhook delivery attempt failed", nil, map[string]interface{}{
+		"webhook_id":  endpoint.ID,
+		"delivery_id": delivery.ID,
+		"attempt":     delivery.Attempts,
+		"error":       delivery.LastError,
+		"status":      delivery.Status,
+		"action":      "WEBHOOK_DELIVERY_FAILED",
+	})
+	return false
+}
+
+// send posts the delivery and returns the response status. The response body is never read:
+// the delivery log is shown to the user, and a receiver's answer must not become a way to read
+// pages the server can reach.
+func (d *Dispatcher) send(ctx context.Context, endpoint *domain.Endpoint, delivery *domain.Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(delivery.ID))
+	if delivery.MessageID != "" {
+		req.Header.Set(HeaderMessageID, delivery.MessageID)
+	}
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, time.Now().Unix(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) record(ctx context.Context, delivery *domain.Delivery, result string) {
+	metrics.WebhookDeliveriesTotal.WithLabelValues(result).Inc()
+	if err := d.deliveries.RecordAttempt(ctx, delivery); err != nil {
+		d.logger.Error("Failed to record webhook delivery", err, map[string]interface{}{
+			"delivery_id": delivery.ID,
+			"action":      "WEBHOOK_DELIVERY_RECORD_FAILED",
+		})
+	}
+}
+
+// backoff returns the wait before the next attempt: 30s, 1m, 2m, 4m ... capped at retryMaxDelay
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	errInsecureURL          = errors.New("webhook url must use https")
+	errForbiddenDestination = errors.New("webhook url points to a private address")
+	errInvalidWebhookURL    = errors.New("invalid webhook url")
+)
+
+// Ranges reserved for local use that netip has no predicate for
+var (
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+	thisNetwork        = netip.MustParsePrefix("0.0.0.0/8")
+	benchmarkNetwork   = netip.MustParsePrefix("198.18.0.0/15")
+)
+
+// Config controls where webhooks may be delivered
+type Config struct {
+	// Development allows plain http and receivers on loopback or private networks, e.g. a
+	// test server on the developer's machine
+	Development bool
+}
+
+// ConfigFromEnv relaxes the destination rules when APP_ENV is "development"
+func ConfigFromEnv() Config {
+	return Config{Development: os.Getenv("APP_ENV") == "development"}
+}
+
+// validateURL checks an endpoint URL when it is registered. Host names are resolved only
+// when a delivery connects, so the dialer checks the address again (see newClient).
+func validateURL(raw string, cfg Config) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return errInvalidWebhookURL
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !cfg.Development {
+			return errInsecureURL
+		}
+	default:
+		return errInvalidWebhookURL
+	}
+	if cfg.Development {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errForbiddenDestination
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !publicAddress(addr) {
+		return errForbiddenDestination
+	}
+	return nil
+}
+
+// publicAddress reports whether webhooks may connect to addr: not loopback, private,
+// link-local, multicast, unspecified or otherwise reserved for local use
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+	return !sharedAddressSpace.Contains(addr) && !thisNetwork.Contains(addr) && !benchmarkNetwork.Contains(addr)
+}
+
+// newClient returns the HTTP client deliveries are sent with. Outside development its dialer
+// refuses every connection to a non-public address; the check runs on the address actually
+// dialed, after DNS resolution, so a name that resolves (or later rebinds) to an internal
+// address cannot be reached either. Proxies from the environment are not used for the same reason.
+func newClient(cfg Config) *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout}
+	if !cfg.Development {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddress(addrPort.Addr()) {
+				return errForbiddenDestination
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: deliveryTimeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   deliveryTimeout,
+			ExpectContinueTimeout: time.Second,
+		},
+		// Receivers must answer directly; following redirects would send the signed body elsewhere
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
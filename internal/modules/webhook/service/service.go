@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/dto"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, req *dto.CreateWebhookRequest, userID int) (*dto.WebhookSecretResponse, error)
+	GetByID(ctx context.Context, id, userID int) (*dto.WebhookResponse, error)
+	GetAll(ctx context.Context, userID int) ([]*dto.WebhookResponse, error)
+	Update(ctx context.Context, id int, req *dto.UpdateWebhookRequest, userID int) (*dto.WebhookResponse, error)
+	Delete(ctx context.Context, id, userID int) error
+	RotateSecret(ctx context.Context, id, userID int) (*dto.WebhookSecretResponse, error)
+	ListDeliveries(ctx context.Context, id int, req *dto.ListDeliveriesRequest, userID int) (*dto.DeliveryListResponse, error)
+	GetDelivery(ctx context.Context, id, deliveryID, userID int) (*dto.DeliveryResponse, error)
+	// Redeliver queues a new delivery with the same payload as an earlier one
+	Redeliver(ctx context.Context, id, deliveryID, userID int) (*dto.DeliveryResponse, error)
+}
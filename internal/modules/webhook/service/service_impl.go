@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/webhook/repository"
+)
+
+type webhookService struct {
+	endpoints  repository.EndpointRepository
+	deliveries repository.DeliveryRepository
+	uow        *database.UnitOfWork
+	config     Config
+	logger     *logger.ZapLogger
+	bus        *eventbus.Bus
+}
+
+func NewWebhookService(endpoints repository.EndpointRepository, deliveries repository.DeliveryRepository, uow *database.UnitOfWork, config Config, logger *logger.ZapLogger, bus *eventbus.Bus) WebhookService {
+	return &webhookService{endpoints: endpoints, deliveries: deliveries, uow: uow, config: config, logger: logger, bus: bus}
+}
+
+func (s *webhookService) Create(ctx context.Context, req *dto.CreateWebhookRequest, userID int) (*dto.WebhookSecretResponse, error) {
+	if err := validateURL(req.URL, s.config); err != nil {
+		return nil, err
+	}
+	if err := validateEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	endpoint := &domain.Endpoint{UserID: userID, URL: req.URL, Description: req.Description, Secret: secret, EventTypes: req.EventTypes, Active: active}
+	var created *domain.Endpoint
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.endpoints.Create(ctx, endpoint); err != nil {
+			return err
+		}
+		return s.publish(ctx, userID, events.WebhookCreated{WebhookID: created.ID, Webhook: dto.ToWebhookResponse(created)})
+	})
+	if err != nil {
+		s.logger.Error("Failed to create webhook", err, map[string]interface{}{"user_id": userID, "action": "CREATE_WEBHOOK_FAILED"})
+		return nil, err
+	}
+
+	s.logger.Info("Webhook created", map[string]interface{}{"user_id": userID, "webhook_id": created.ID, "event_types": created.EventTypes, "action": "CREATE_WEBHOOK_SUCCESS"})
+	return dto.ToWebhookSecretResponse(created), nil
+}
+
+func (s *webhookService) GetByID(ctx context.Context, id, userID int) (*dto.WebhookResponse, error) {
+	endpoint, err := s.getOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToWebhookResponse(endpoint), nil
+}
+
+func (s *webhookService) GetAll(ctx context.Context, userID int) ([]*dto.WebhookResponse, error) {
+	endpoints, err := s.endpoints.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToWebhookResponseList(endpoints), nil
+}
+
+func (s *webhookService) Update(ctx context.Context, id int, req *dto.UpdateWebhookRequest, userID int) (*dto.WebhookResponse, error) {
+	if req.URL != nil {
+		if err := validateURL(*req.URL, s.config); err != nil {
+			return nil, err
+		}
+	}
+	if req.EventTypes != nil {
+		if err := validateEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	var response *dto.WebhookResponse
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		endpoint, err := s.getOwned(ctx, id, userID)
+		if err != nil {
+			return err
+		}
+		before := dto.ToWebhookResponse(endpoint)
+
+		if req.URL != nil {
+			endpoint.URL = *req.URL
+		}
+		if req.Description != nil {
+			endpoint.Description = *req.Description
+		}
+		if req.EventTypes != nil {
+			endpoint.EventTypes = req.EventTypes
+		}
+		if req.Active != nil {
+			endpoint.Active = *req.Active
+		}
+
+		if err := s.endpoints.Update(ctx, endpoint); err != nil {
+			return err
+		}
+		updated, err := s.endpoints.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		response = dto.ToWebhookResponse(updated)
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.WebhookUpdated{WebhookID: id, Webhook: response})
+	})
+	if err != nil {
+		s.logger.Error("Failed to update webhook", err, map[string]interface{}{"user_id": userID, "webhook_id": id, "action": "UPDATE_WEBHOOK_FAILED"})
+		return nil, err
+	}
+
+	s.logger.Info("Webhook updated", map[string]interface{}{"user_id": userID, "webhook_id": id, "action": "UPDATE_WEBHOOK_SUCCESS"})
+	return response, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, id, userID int) error {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		endpoint, err := s.getOwned(ctx, id, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.endpoints.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, dto.ToWebhookResponse(endpoint)), userID, events.WebhookDeleted{WebhookID: id, URL: endpoint.URL})
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete webhook", err, map[string]interface{}{"user_id": userID, "webhook_id": id, "action": "DELETE_WEBHOOK_FAILED"})
+		return err
+	}
+	s.logger.Info("Webhook deleted", map[string]interface{}{"user_id": userID, "webhook_id": id, "action": "DELETE_WEBHOOK_SUCCESS"})
+	return nil
+}
+
+func (s *webhookService) RotateSecret(ctx context.Context, id, userID int) (*dto.WebhookSecretResponse, error) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoint *domain.Endpoint
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if endpoint, err = s.getOwned(ctx, id, userID); err != nil {
+			return err
+		}
+		endpoint.Secret = secret
+		if err := s.endpoints.Update(ctx, endpoint); err != nil {
+			return err
+		}
+		return s.publish(ctx, userID, events.WebhookSecretRotated{WebhookID: id})
+	})
+	if err != nil {
+		s.logger.Error("Failed to rotate webhook secret", err, map[string]interface{}{"user_id": userID, "webhook_id": id, "action": "ROTATE_WEBHOOK_SECRET_FAILED"})
+		return nil, err
+	}
+
+	s.logger.Info("Webhook secret rotated", map[string]interface{}{"user_id": userID, "webhook_id": id, "action": "ROTATE_WEBHOOK_SECRET_SUCCESS"})
+	return dto.ToWebhookSecretResponse(endpoint), nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, id int, req *dto.ListDeliveriesRequest, userID int) (*dto.DeliveryListResponse, error) {
+	if _, err := s.getOwned(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := s.deliveries.ListByEndpoint(ctx, id, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.DeliveryListResponse{
+		Items: dto.ToDeliveryResponseList(deliveries),
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+	}, nil
+}
+
+func (s *webhookService) GetDelivery(ctx context.Context, id, deliveryID, userID int) (*dto.DeliveryResponse, error) {
+	delivery, err := s.getDelivery(ctx, id, deliveryID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToDeliveryResponse(delivery, true), nil
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, id, deliveryID, userID int) (*dto.DeliveryResponse, error) {
+	original, err := s.getDelivery(ctx, id, deliveryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.deliveries.Create(ctx, &domain.Delivery{
+		EndpointID:   original.EndpointID,
+		UserID:       original.UserID,
+		EventType:    original.EventType,
+		MessageID:    original.MessageID,
+		Payload:      original.Payload,
+		MaxAttempts:  maxAttempts,
+		RedeliveryOf: &original.ID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to queue webhook redelivery", err, map[string]interface{}{"user_id": userID, "webhook_id": id, "delivery_id": deliveryID, "action": "WEBHOOK_REDELIVER_FAILED"})
+		return nil, err
+	}
+
+	s.logger.Info("Webhook redelivery queued", map[string]interface{}{"user_id": userID, "webhook_id": id, "delivery_id": created.ID, "redelivery_of": deliveryID, "action": "WEBHOOK_REDELIVER_QUEUED"})
+	return dto.ToDeliveryResponse(created, true), nil
+}
+
+func (s *webhookService) getOwned(ctx context.Context, id, userID int) (*domain.Endpoint, error) {
+	endpoint, err := s.endpoints.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, errors.New("webhook not found")
+	}
+	if endpoint.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+	return endpoint, nil
+}
+
+func (s *webhookService) getDelivery(ctx context.Context, id, deliveryID, userID int) (*domain.Delivery, error) {
+	if _, err := s.getOwned(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.deliveries.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil || delivery.EndpointID != id {
+		return nil, errors.New("delivery not found")
+	}
+	return delivery, nil
+}
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *webhookService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
+
+// validateEventTypes accepts "*" and catalog types that are stored as notifications
+func validateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if eventType == domain.AllEvents {
+			continue
+		}
+		if _, ok := events.Lookup(eventType); !ok || events.IsTransient(eventType) {
+			return errors.New("unknown event type")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/repository"
+)
+
+// Roles other modules check entities against
+const (
+	RoleViewer = domain.RoleViewer
+	RoleEditor = domain.RoleEditor
+	RoleOwner  = domain.RoleOwner
+)
+
+// Access answers who may see and change a shareable entity. An entity is personal when its
+// workspace ID is nil, and then only its owner has access, with every role; otherwise access
+// follows the user's role in the workspace, whoever created the entity.
+type Access struct {
+	members repository.MemberRepository
+	bus     *eventbus.Bus
+}
+
+func NewAccess(members repository.MemberRepository, bus *eventbus.Bus) *Access {
+	return &Access{members: members, bus: bus}
+}
+
+// Role returns the user's role in the workspace, or "" if they are not a member
+func (a *Access) Role(ctx context.Context, workspaceID, userID int) (string, error) {
+	member, err := a.members.Get(ctx, workspaceID, userID)
+	if err != nil || member == nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// Check returns "unauthorized" unless the user holds at least the required role on the entity
+func (a *Access) Check(ctx context.Context, ownerID int, workspaceID *int, userID int, required string) error {
+	if workspaceID == nil {
+		if ownerID != userID {
+			return errors.New("unauthorized")
+		}
+		return nil
+	}
+	return a.CheckWorkspace(ctx, *workspaceID, userID, required)
+}
+
+// CheckWorkspace returns "unauthorized" unless the user holds at least the required role in
+// the workspace, e.g. before creating or moving an entity into it
+func (a *Access) CheckWorkspace(ctx context.Context, workspaceID, userID int, required string) error {
+	role, err := a.Role(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !domain.RoleAllows(role, required) {
+		return errors.New("unauthorized")
+	}
+	return nil
+}
+
+// CheckMove resolves where an entity the user can already edit is moved to. Target 0 makes it
+// personal to its owner again, which only the owner may do; any other workspace needs editor access.
+func (a *Access) CheckMove(ctx context.Context, ownerID, target, userID int) (*int, error) {
+	if target == 0 {
+		if ownerID != userID {
+			return nil, errors.New("unauthorized")
+		}
+		return nil, nil
+	}
+	if err := a.CheckWorkspace(ctx, target, userID, RoleEditor); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// Audience returns the users who see the entity: its owner, or every workspace member
+func (a *Access) Audience(ctx context.Context, ownerID int, workspaceID *int) ([]int, error) {
+	if workspaceID == nil {
+		return []int{ownerID}, nil
+	}
+	return a.members.UserIDs(ctx, *workspaceID)
+}
+
+// Publish raises the event once, for the entity's owner, with the entity's audience attached
+// so notifications and WebSocket updates reach every member of a shared workspace. Inside a
+// unit of work the event commits with it.
+func (a *Access) Publish(ctx context.Context, ownerID int, workspaceID *int, event events.Event) error {
+	if a.bus == nil {
+		return nil
+	}
+	if workspaceID == nil {
+		return a.bus.Publish(ctx, ownerID, event)
+	}
+	audience, err := a.Audience(ctx, ownerID, workspaceID)
+	if err != nil {
+		return err
+	}
+	return a.bus.PublishTo(ctx, ownerID, audience, event)
+}
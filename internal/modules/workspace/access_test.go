@@ -0,0 +1,102 @@
+package workspace
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/repository"
+)
+
+// fakeMembers holds the roles of workspace 1
+type fakeMembers struct {
+	repository.MemberRepository
+	roles map[int]string
+}
+
+func (f fakeMembers) Get(ctx context.Context, workspaceID, userID int) (*domain.Member, error) {
+	role, ok := f.roles[userID]
+	if workspaceID != 1 || !ok {
+		return nil, nil
+	}
+	return &domain.Member{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil
+}
+
+func (f fakeMembers) UserIDs(ctx context.Context, workspaceID int) ([]int, error) {
+	var ids []int
+	for id := range f.roles {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func TestAccessCheck(t *testing.T) {
+	workspaceID := 1
+	access := NewAccess(fakeMembers{roles: map[int]string{1: RoleOwner, 2: RoleEditor, 3: RoleViewer}}, nil)
+
+	tests := []struct {
+		name        string
+		ownerID     int
+		workspaceID *int
+		userID      int
+		required    string
+		wantErr     bool
+	}{
+		{"owner of a personal record", 1, nil, 1, RoleOwner, false},
+		{"someone else's personal record", 1, nil, 2, RoleViewer, true},
+		{"viewer reads", 1, &workspaceID, 3, RoleViewer, false},
+		{"viewer cannot edit", 1, &workspaceID, 3, RoleEditor, true},
+		{"editor edits another member's record", 1, &workspaceID, 2, RoleEditor, false},
+		{"editor cannot manage", 1, &workspaceID, 2, RoleOwner, true},
+		{"non-member", 1, &workspaceID, 9, RoleViewer, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := access.Check(context.Background(), tt.ownerID, tt.workspaceID, tt.userID, tt.required)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessPublishOncePerChange(t *testing.T) {
+	workspaceID := 1
+	tests := []struct {
+		name           string
+		workspaceID    *int
+		wantRecipients []int
+	}{
+		{"personal record", nil, []int{4}},
+		{"shared record", &workspaceID, []int{1, 2, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := eventbus.New(nil, logger.NewLogger(nil))
+			var received []eventbus.Envelope
+			bus.SubscribeAll("test", func(ctx context.Context, env eventbus.Envelope) error {
+				received = append(received, env)
+				return nil
+			})
+			access := NewAccess(fakeMembers{roles: map[int]string{1: RoleOwner, 2: RoleViewer, 4: RoleEditor}}, bus)
+
+			if err := access.Publish(context.Background(), 4, tt.workspaceID, events.TaskUpdated{TaskID: 8}); err != nil {
+				t.Fatal(err)
+			}
+			if len(received) != 1 {
+				t.Fatalf("subscriber got %d events, want 1", len(received))
+			}
+			if received[0].UserID != 4 {
+				t.Errorf("event user = %d, want the owner", received[0].UserID)
+			}
+			if got := received[0].Recipients(); !slices.Equal(got, tt.wantRecipients) {
+				t.Errorf("recipients = %v, want %v", got, tt.wantRecipients)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Member roles, from least to most access
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleOwner  = "owner"
+)
+
+var roleRank = map[string]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+// RoleAllows reports whether role grants at least the access of required. Unknown roles,
+// including "" for non-members, allow nothing.
+func RoleAllows(role, required string) bool {
+	rank := roleRank[role]
+	return rank > 0 && rank >= roleRank[required]
+}
+
+// Workspace shares tasks, courses and goals between its members
+type Workspace struct {
+	ID        int
+	Name      string
+	CreatedBy *int
+	// Role is the requesting user's role when the workspace is loaded through a membership
+	Role        string
+	MemberCount int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Member is a user's role in a workspace, with the user's name and email for display
+type Member struct {
+	WorkspaceID int
+	UserID      int
+	Role        string
+	Email       string
+	FullName    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Invitation lets whoever owns Email join the workspace with Role until it expires
+type Invitation struct {
+	ID          int
+	WorkspaceID int
+	Email       string
+	Role        string
+	TokenHash   string
+	InvitedBy   *int
+	ExpiresAt   time.Time
+	AcceptedAt  *time.Time
+	CreatedAt   time.Time
+}
+
+// Pending reports whether the invitation can still be accepted
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
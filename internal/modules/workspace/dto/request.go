@@ -0,0 +1,22 @@
+package dto
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type UpdateWorkspaceRequest struct {
+	Name *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+type InviteRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	Role  string `json:"role,omitempty" validate:"omitempty,oneof=viewer editor owner"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required,max=128"`
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/domain"
+)
+
+type WorkspaceResponse struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Role        string    `json:"role,omitempty"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WorkspaceDetailResponse is a workspace with its members
+type WorkspaceDetailResponse struct {
+	*WorkspaceResponse
+	Members []*MemberResponse `json:"members"`
+}
+
+type MemberResponse struct {
+	UserID   int       `json:"user_id"`
+	Email    string    `json:"email"`
+	FullName string    `json:"full_name,omitempty"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type InvitationResponse struct {
+	ID        int       `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy *int      `json:"invited_by,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToWorkspaceResponse(w *domain.Workspace) *WorkspaceResponse {
+	return &WorkspaceResponse{
+		ID:          w.ID,
+		Name:        w.Name,
+		Role:        w.Role,
+		MemberCount: w.MemberCount,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+func ToWorkspaceResponseList(workspaces []*domain.Workspace) []*WorkspaceResponse {
+	result := make([]*WorkspaceResponse, len(workspaces))
+	for i, w := range workspaces {
+		result[i] = ToWorkspaceResponse(w)
+	}
+	return result
+}
+
+func ToMemberResponse(m *domain.Member) *MemberResponse {
+	return &MemberResponse{
+		UserID:   m.UserID,
+		Email:    m.Email,
+		FullName: m.FullName,
+		Role:     m.Role,
+		JoinedAt: m.CreatedAt,
+	}
+}
+
+func ToMemberResponseList(members []*domain.Member) []*MemberResponse {
+	result := make([]*MemberResponse, len(members))
+	for i, m := range members {
+		result[i] = ToMemberResponse(m)
+	}
+	return result
+}
+
+func ToInvitationResponse(i *domain.Invitation) *InvitationResponse {
+	return &InvitationResponse{
+		ID:        i.ID,
+		Email:     i.Email,
+		Role:      i.Role,
+		InvitedBy: i.InvitedBy,
+		ExpiresAt: i.ExpiresAt,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func ToInvitationResponseList(invitations []*domain.Invitation) []*InvitationResponse {
+	result := make([]*InvitationResponse, len(invitations))
+	for i, inv := range invitations {
+		result[i] = ToInvitationResponse(inv)
+	}
+	return result
+}
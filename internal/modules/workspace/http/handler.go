@@ -0,0 +1,271 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/utils"
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/validation"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/service"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct{ service service.WorkspaceService }
+
+func NewHandler(service service.WorkspaceService) *Handler { return &Handler{service: service} }
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/workspaces", h.GetAll).Methods("GET")
+	router.HandleFunc("/workspaces", h.Create).Methods("POST")
+	router.HandleFunc("/workspaces/{id}", h.GetByID).Methods("GET")
+	router.HandleFunc("/workspaces/{id}", h.Update).Methods("PUT", "PATCH")
+	router.HandleFunc("/workspaces/{id}", h.Delete).Methods("DELETE")
+	router.HandleFunc("/workspaces/{id}/members/{userId}", h.UpdateMember).Methods("PUT", "PATCH")
+	router.HandleFunc("/workspaces/{id}/members/{userId}", h.RemoveMember).Methods("DELETE")
+	router.HandleFunc("/workspaces/{id}/invitations", h.GetInvitations).Methods("GET")
+	router.HandleFunc("/workspaces/{id}/invitations", h.Invite).Methods("POST")
+	router.HandleFunc("/workspaces/{id}/invitations/{invitationId}", h.RevokeInvitation).Methods("DELETE")
+	router.HandleFunc("/workspace-invitations/accept", h.AcceptInvitation).Methods("POST")
+}
+
+func (h *Handler) getUserID(r *http.Request) int {
+	return utils.GetUserIDFromContext(r.Context())
+}
+
+// Create makes a workspace with the caller as its owner
+// POST /api/workspaces
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+	workspace, err := h.service.Create(r.Context(), &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Çalışma alanı oluşturulamadı")
+		return
+	}
+	utils.WriteJson(w, workspace, http.StatusCreated, "Çalışma alanı oluşturuldu")
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	workspaces, err := h.service.GetAll(r.Context(), h.getUserID(r))
+	if err != nil {
+		utils.ReturnError(w, "INTERNAL_ERROR", "Çalışma alanları getirilemedi", err.Error())
+		return
+	}
+	utils.WriteJson(w, workspaces, http.StatusOK, "Çalışma alanları getirildi")
+}
+
+// GetByID returns the workspace with its members
+// GET /api/workspaces/{id}
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.workspaceID(w, r)
+	if !ok {
+		return
+	}
+	workspace, err := h.service.GetByID(r.Context(), id, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Çalışma alanı getirilemedi")
+		return
+	}
+	utils.WriteJson(w, workspace, http.StatusOK, "Çalışma alanı getirildi")
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.workspaceID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.UpdateWorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+	workspace, err := h.service.Update(r.Context(), id, &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Çalışma alanı güncellenemedi")
+		return
+	}
+	utils.WriteJson(w, workspace, http.StatusOK, "Çalışma alanı güncellendi")
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.workspaceID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(r.Context(), id, h.getUserID(r)); err != nil {
+		h.handleError(w, err, "Çalışma alanı silinemedi")
+		return
+	}
+	utils.WriteJson(w, nil, http.StatusOK, "Çalışma alanı silindi")
+}
+
+// UpdateMember changes a member's role
+// PUT /api/workspaces/{id}/members/{userId}
+func (h *Handler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	id, memberID, ok := h.subresourceIDs(w, r, "userId")
+	if !ok {
+		return
+	}
+	var req dto.UpdateMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+	member, err := h.service.UpdateMember(r.Context(), id, memberID, &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Üye rolü güncellenemedi")
+		return
+	}
+	utils.WriteJson(w, member, http.StatusOK, "Üye rolü güncellendi")
+}
+
+// RemoveMember removes a member; members may remove themselves to leave the workspace
+// DELETE /api/workspaces/{id}/members/{userId}
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, memberID, ok := h.subresourceIDs(w, r, "userId")
+	if !ok {
+		return
+	}
+	if err := h.service.RemoveMember(r.Context(), id, memberID, h.getUserID(r)); err != nil {
+		h.handleError(w, err, "Üye çıkarılamadı")
+		return
+	}
+	utils.WriteJson(w, nil, http.StatusOK, "Üye çıkarıldı")
+}
+
+// GetInvitations lists invitations that have not been accepted or expired
+// GET /api/workspaces/{id}/invitations
+func (h *Handler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.workspaceID(w, r)
+	if !ok {
+		return
+	}
+	invitations, err := h.service.GetInvitations(r.Context(), id, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Davetler getirilemedi")
+		return
+	}
+	utils.WriteJson(w, invitations, http.StatusOK, "Davetler getirildi")
+}
+
+// Invite emails an invitation link to the address
+// POST /api/workspaces/{id}/invitations
+func (h *Handler) Invite(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.workspaceID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.InviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+	invitation, err := h.service.Invite(r.Context(), id, &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Davet gönderilemedi")
+		return
+	}
+	utils.WriteJson(w, invitation, http.StatusCreated, "Davet gönderildi")
+}
+
+// RevokeInvitation cancels an open invitation
+// DELETE /api/workspaces/{id}/invitations/{invitationId}
+func (h *Handler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	id, invitationID, ok := h.subresourceIDs(w, r, "invitationId")
+	if !ok {
+		return
+	}
+	if err := h.service.RevokeInvitation(r.Context(), id, invitationID, h.getUserID(r)); err != nil {
+		h.handleError(w, err, "Davet iptal edilemedi")
+		return
+	}
+	utils.WriteJson(w, nil, http.StatusOK, "Davet iptal edildi")
+}
+
+// AcceptInvitation joins the workspace with the token from an invitation email
+// POST /api/workspace-invitations/accept
+func (h *Handler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var req dto.AcceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz istek", err.Error())
+		return
+	}
+	if err := validation.Get().Struct(req); err != nil {
+		utils.ReturnError(w, "VALIDATION_ERROR", "Doğrulama hatası", validation.FormatErr(err))
+		return
+	}
+	workspace, err := h.service.AcceptInvitation(r.Context(), &req, h.getUserID(r))
+	if err != nil {
+		h.handleError(w, err, "Davet kabul edilemedi")
+		return
+	}
+	utils.WriteJson(w, workspace, http.StatusOK, "Çalışma alanına katıldınız")
+}
+
+func (h *Handler) workspaceID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) subresourceIDs(w http.ResponseWriter, r *http.Request, name string) (int, int, bool) {
+	id, ok := h.workspaceID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	subID, err := strconv.Atoi(mux.Vars(r)[name])
+	if err != nil {
+		utils.ReturnError(w, "BAD_REQUEST", "Geçersiz ID", err.Error())
+		return 0, 0, false
+	}
+	return id, subID, true
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string) {
+	switch err.Error() {
+	case "workspace not found":
+		utils.ReturnError(w, "NOT_FOUND", "Çalışma alanı bulunamadı", err.Error())
+	case "member not found":
+		utils.ReturnError(w, "NOT_FOUND", "Üye bulunamadı", err.Error())
+	case "invitation not found":
+		utils.ReturnError(w, "NOT_FOUND", "Davet bulunamadı", err.Error())
+	case "invalid or expired invitation":
+		utils.ReturnError(w, "BAD_REQUEST", "Davet geçersiz veya süresi dolmuş", err.Error())
+	case "invitation was sent to a different email":
+		utils.ReturnError(w, "FORBIDDEN", "Davet başka bir e-posta adresine gönderilmiş", err.Error())
+	case "user is already a member":
+		utils.ReturnError(w, "BAD_REQUEST", "Kullanıcı zaten üye", err.Error())
+	case "too many pending invitations":
+		utils.ReturnError(w, "BAD_REQUEST", "Bekleyen davet sayısı sınırına ulaşıldı", err.Error())
+	case "workspace must keep an owner":
+		utils.ReturnError(w, "BAD_REQUEST", "Çalışma alanında en az bir sahip kalmalı", err.Error())
+	case "unauthorized":
+		utils.ReturnError(w, "FORBIDDEN", "Bu işlem için yetkiniz yok", err.Error())
+	default:
+		utils.ReturnError(w, "INTERNAL_ERROR", message, err.Error())
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/domain"
+)
+
+type WorkspaceModel struct {
+	ID          int            `db:"id"`
+	Name        string         `db:"name"`
+	CreatedBy   *int           `db:"created_by"`
+	Role        sql.NullString `db:"role"`
+	MemberCount int            `db:"member_count"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+func (m *WorkspaceModel) ToDomain() *domain.Workspace {
+	return &domain.Workspace{
+		ID:          m.ID,
+		Name:        m.Name,
+		CreatedBy:   m.CreatedBy,
+		Role:        m.Role.String,
+		MemberCount: m.MemberCount,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+type MemberModel struct {
+	WorkspaceID int            `db:"workspace_id"`
+	UserID      int            `db:"user_id"`
+	Role        string         `db:"role"`
+	Email       string         `db:"email"`
+	FullName    sql.NullString `db:"full_name"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+func (m *MemberModel) ToDomain() *domain.Member {
+	return &domain.Member{
+		WorkspaceID: m.WorkspaceID,
+		UserID:      m.UserID,
+		Role:        m.Role,
+		Email:       m.Email,
+		FullName:    m.FullName.String,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+type InvitationModel struct {
+	ID          int        `db:"id"`
+	WorkspaceID int        `db:"workspace_id"`
+	Email       string     `db:"email"`
+	Role        string     `db:"role"`
+	TokenHash   string     `db:"token_hash"`
+	InvitedBy   *int       `db:"invited_by"`
+	ExpiresAt   time.Time  `db:"expires_at"`
+	AcceptedAt  *time.Time `db:"accepted_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+func (m *InvitationModel) ToDomain() *domain.Invitation {
+	return &domain.Invitation{
+		ID:          m.ID,
+		WorkspaceID: m.WorkspaceID,
+		Email:       m.Email,
+		Role:        m.Role,
+		TokenHash:   m.TokenHash,
+		InvitedBy:   m.InvitedBy,
+		ExpiresAt:   m.ExpiresAt,
+		AcceptedAt:  m.AcceptedAt,
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const workspaceColumns = `w.id, w.name, w.created_by, w.created_at, w.updated_at,
+	(SELECT COUNT(*) FROM workspace_members c WHERE c.workspace_id = w.id) AS member_count`
+
+const invitationColumns = `id, workspace_id, email, role, token_hash, invited_by, expires_at, accepted_at, created_at`
+
+type workspaceRepository struct{ db *sqlx.DB }
+
+func NewWorkspaceRepository(db *sqlx.DB) WorkspaceRepository { return &workspaceRepository{db: db} }
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *workspaceRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *workspaceRepository) Create(ctx context.Context, w *domain.Workspace) (*domain.Workspace, error) {
+	query := `INSERT INTO workspaces (name, created_by, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`
+	now := time.Now()
+	created := *w
+	err := r.conn(ctx).QueryRowxContext(ctx, query, w.Name, w.CreatedBy, now, now).Scan(&created.ID, &created.CreatedAt, &created.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *workspaceRepository) GetByID(ctx context.Context, id int) (*domain.Workspace, error) {
+	query := `SELECT ` + workspaceColumns + `, NULL AS role FROM workspaces w WHERE w.id = $1`
+	var model WorkspaceModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *workspaceRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Workspace, error) {
+	query := `SELECT ` + workspaceColumns + `, m.role
+		FROM workspaces w JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1 ORDER BY w.name`
+	var models []WorkspaceModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, userID); err != nil {
+		return nil, err
+	}
+	workspaces := make([]*domain.Workspace, len(models))
+	for i := range models {
+		workspaces[i] = models[i].ToDomain()
+	}
+	return workspaces, nil
+}
+
+func (r *workspaceRepository) Update(ctx context.Context, w *domain.Workspace) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE workspaces SET name = $1, updated_at = $2 WHERE id = $3`, w.Name, time.Now(), w.ID)
+	return err
+}
+
+func (r *workspaceRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM workspaces WHERE id = $1`, id)
+	return err
+}
+
+func (r *workspaceRepository) Lock(ctx context.Context, id int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `SELECT id FROM workspaces WHERE id = $1 FOR UPDATE`, id)
+	return err
+}
+
+type memberRepository struct{ db *sqlx.DB }
+
+func NewMemberRepository(db *sqlx.DB) MemberRepository { return &memberRepository{db: db} }
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *memberRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *memberRepository) Add(ctx context.Context, workspaceID, userID int, role string) error {
+	query := `INSERT INTO workspace_members (workspace_id, user_id, role, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at`
+	_, err := r.conn(ctx).ExecContext(ctx, query, workspaceID, userID, role, time.Now())
+	return err
+}
+
+func (r *memberRepository) Get(ctx context.Context, workspaceID, userID int) (*domain.Member, error) {
+	query := `SELECT m.workspace_id, m.user_id, m.role, u.email, u.full_name, m.created_at, m.updated_at
+		FROM workspace_members m JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1 AND m.user_id = $2`
+	var model MemberModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, workspaceID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *memberRepository) List(ctx context.Context, workspaceID int) ([]*domain.Member, error) {
+	query := `SELECT m.workspace_id, m.user_id, m.role, u.email, u.full_name, m.created_at, m.updated_at
+		FROM workspace_members m JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1 ORDER BY m.created_at`
+	var models []MemberModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, workspaceID); err != nil {
+		return nil, err
+	}
+	members := make([]*domain.Member, len(models))
+	for i := range models {
+		members[i] = models[i].ToDomain()
+	}
+	return members, nil
+}
+
+func (r *memberRepository) UserIDs(ctx context.Context, workspaceID int) ([]int, error) {
+	var ids []int
+	err := r.conn(ctx).SelectContext(ctx, &ids, `SELECT user_id FROM workspace_members WHERE workspace_id = $1 ORDER BY user_id`, workspaceID)
+	return ids, err
+}
+
+func (r *memberRepository) Remove(ctx context.Context, workspaceID, userID int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID)
+	return err
+}
+
+func (r *memberRepository) CountOwners(ctx context.Context, workspaceID int) (int, error) {
+	var count int
+	err := r.conn(ctx).QueryRowxContext(ctx, `SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1 AND role = $2`, workspaceID, domain.RoleOwner).Scan(&count)
+	return count, err
+}
+
+type invitationRepository struct{ db *sqlx.DB }
+
+func NewInvitationRepository(db *sqlx.DB) InvitationRepository { return &invitationRepository{db: db} }
+
+// conn runs queries in the caller's unit of work when there is one
+func (r *invitationRepository) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *invitationRepository) Create(ctx context.Context, inv *domain.Invitation) (*domain.Invitation, error) {
+	query := `INSERT INTO workspace_invitations (workspace_id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	created := *inv
+	err := r.conn(ctx).QueryRowxContext(ctx, query, inv.WorkspaceID, inv.Email, inv.Role, inv.TokenHash, inv.InvitedBy, inv.ExpiresAt, time.Now()).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *invitationRepository) GetByID(ctx context.Context, id int) (*domain.Invitation, error) {
+	return r.getOne(ctx, `SELECT `+invitationColumns+` FROM workspace_invitations WHERE id = $1`, id)
+}
+
+func (r *invitationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	return r.getOne(ctx, `SELECT `+invitationColumns+` FROM workspace_invitations WHERE token_hash = $1`, tokenHash)
+}
+
+func (r *invitationRepository) getOne(ctx context.Context, query string, arg interface{}) (*domain.Invitation, error) {
+	var model InvitationModel
+	if err := r.conn(ctx).GetContext(ctx, &model, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *invitationRepository) ListPending(ctx context.Context, workspaceID int, now time.Time) ([]*domain.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM workspace_invitations
+		WHERE workspace_id = $1 AND accepted_at IS NULL AND expires_at > $2 ORDER BY created_at DESC`
+	var models []InvitationModel
+	if err := r.conn(ctx).SelectContext(ctx, &models, query, workspaceID, now); err != nil {
+		return nil, err
+	}
+	invitations := make([]*domain.Invitation, len(models))
+	for i := range models {
+		invitations[i] = models[i].ToDomain()
+	}
+	return invitations, nil
+}
+
+func (r *invitationRepository) CountPending(ctx context.Context, workspaceID int, now time.Time) (int, error) {
+	var count int
+	err := r.conn(ctx).QueryRowxContext(ctx, `SELECT COUNT(*) FROM workspace_invitations WHERE workspace_id = $1 AND accepted_at IS NULL AND expires_at > $2`, workspaceID, now).Scan(&count)
+	return count, err
+}
+
+func (r *invitationRepository) DeletePending(ctx context.Context, workspaceID int, email string) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM workspace_invitations WHERE workspace_id = $1 AND LOWER(email) = $2 AND accepted_at IS NULL`, workspaceID, strings.ToLower(email))
+	return err
+}
+
+func (r *invitationRepository) MarkAccepted(ctx context.Context, id int, at time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE workspace_invitations SET accepted_at = $1 WHERE id = $2 AND accepted_at IS NULL`, at, id)
+	return err
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM workspace_invitations WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/domain"
+)
+
+type WorkspaceRepository interface {
+	Create(ctx context.Context, w *domain.Workspace) (*domain.Workspace, error)
+	GetByID(ctx context.Context, id int) (*domain.Workspace, error)
+	// GetByUserID returns the workspaces the user is a member of, with the user's role
+	GetByUserID(ctx context.Context, userID int) ([]*domain.Workspace, error)
+	Update(ctx context.Context, w *domain.Workspace) error
+	Delete(ctx context.Context, id int) error
+	// Lock holds the workspace row until the unit of work ends, serializing membership changes
+	Lock(ctx context.Context, id int) error
+}
+
+type MemberRepository interface {
+	// Add inserts the membership, or changes the role if the user is already a member
+	Add(ctx context.Context, workspaceID, userID int, role string) error
+	Get(ctx context.Context, workspaceID, userID int) (*domain.Member, error)
+	List(ctx context.Context, workspaceID int) ([]*domain.Member, error)
+	UserIDs(ctx context.Context, workspaceID int) ([]int, error)
+	Remove(ctx context.Context, workspaceID, userID int) error
+	CountOwners(ctx context.Context, workspaceID int) (int, error)
+}
+
+type InvitationRepository interface {
+	Create(ctx context.Context, inv *domain.Invitation) (*domain.Invitation, error)
+	GetByID(ctx context.Context, id int) (*domain.Invitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error)
+	ListPending(ctx context.Context, workspaceID int, now time.Time) ([]*domain.Invitation, error)
+	CountPending(ctx context.Context, workspaceID int, now time.Time) (int, error)
+	// DeletePending drops unaccepted invitations to email, so a new one replaces them
+	DeletePending(ctx context.Context, workspaceID int, email string) error
+	MarkAccepted(ctx context.Context, id int, at time.Time) error
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/dto"
+)
+
+// InvitationConfig controls workspace invitation emails
+type InvitationConfig struct {
+	// TTL is how long an invitation can be accepted
+	TTL time.Duration
+	// Link is the frontend page that accepts an invitation; the token is appended to it
+	Link string
+}
+
+// InvitationConfigFromEnv reads WORKSPACE_INVITATION_TTL and APP_BASE_URL
+func InvitationConfigFromEnv() InvitationConfig {
+	cfg := InvitationConfig{TTL: 7 * 24 * time.Hour}
+
+	if v, err := time.ParseDuration(os.Getenv("WORKSPACE_INVITATION_TTL")); err == nil && v > 0 {
+		cfg.TTL = v
+	}
+
+	baseURL := strings.TrimRight(os.Getenv("APP_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = "http://localhost:3000"
+	}
+	cfg.Link = baseURL + "/workspace-invitations?token="
+
+	return cfg
+}
+
+type WorkspaceService interface {
+	// Create makes a workspace with the user as its first owner
+	Create(ctx context.Context, req *dto.CreateWorkspaceRequest, userID int) (*dto.WorkspaceResponse, error)
+	GetAll(ctx context.Context, userID int) ([]*dto.WorkspaceResponse, error)
+	GetByID(ctx context.Context, id, userID int) (*dto.WorkspaceDetailResponse, error)
+	Update(ctx context.Context, id int, req *dto.UpdateWorkspaceRequest, userID int) (*dto.WorkspaceResponse, error)
+	// Delete removes the workspace; its tasks, courses and goals become personal to their creators again
+	Delete(ctx context.Context, id, userID int) error
+
+	// UpdateMember changes a member's role. A workspace always keeps at least one owner.
+	UpdateMember(ctx context.Context, id, memberID int, req *dto.UpdateMemberRequest, userID int) (*dto.MemberResponse, error)
+	// RemoveMember lets owners remove anyone and other members leave
+	RemoveMember(ctx context.Context, id, memberID, userID int) error
+
+	// Invite emails a link that adds whoever signs in with that address to the workspace
+	Invite(ctx context.Context, id int, req *dto.InviteRequest, userID int) (*dto.InvitationResponse, error)
+	GetInvitations(ctx context.Context, id, userID int) ([]*dto.InvitationResponse, error)
+	RevokeInvitation(ctx context.Context, id, invitationID, userID int) error
+	// AcceptInvitation adds the user to the workspace if the invitation was sent to their email
+	AcceptInvitation(ctx context.Context, req *dto.AcceptInvitationRequest, userID int) (*dto.WorkspaceResponse, error)
+}
@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/M1ralai/go-modular-monolith-template/internal/common/events"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/database"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/eventbus"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/logger"
+	"github.com/M1ralai/go-modular-monolith-template/internal/infrastructure/mailer"
+	userDomain "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/domain"
+	userRepo "github.com/M1ralai/go-modular-monolith-template/internal/modules/user/repository"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/domain"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/dto"
+	"github.com/M1ralai/go-modular-monolith-template/internal/modules/workspace/repository"
+)
+
+const (
+	invitationTemplate = "workspace_invitation"
+	// maxPendingInvitations caps open invitations per workspace
+	maxPendingInvitations = 50
+)
+
+var (
+	errLastOwner         = errors.New("workspace must keep an owner")
+	errInvalidInvitation = errors.New("invalid or expired invitation")
+)
+
+type workspaceService struct {
+	workspaces  repository.WorkspaceRepository
+	members     repository.MemberRepository
+	invitations repository.InvitationRepository
+	userRepo    userRepo.UserRepository
+	uow         *database.UnitOfWork
+	outbox      *mailer.Outbox
+	renderer    *mailer.Renderer
+	config      InvitationConfig
+	logger      *logger.ZapLogger
+	bus         *eventbus.Bus
+}
+
+func NewWorkspaceService(workspaces repository.WorkspaceRepository, members repository.MemberRepository, invitations repository.InvitationRepository, userRepo userRepo.UserRepository, uow *database.UnitOfWork, outbox *mailer.Outbox, renderer *mailer.Renderer, config InvitationConfig, logger *logger.ZapLogger, bus *eventbus.Bus) WorkspaceService {
+	return &workspaceService{
+		workspaces:  workspaces,
+		members:     members,
+		invitations: invitations,
+		userRepo:    userRepo,
+		uow:         uow,
+		outbox:      outbox,
+		renderer:    renderer,
+		config:      config,
+		logger:      logger,
+		bus:         bus,
+	}
+}
+
+// invitationEmail is the data of the workspace_invitation template
+type invitationEmail struct {
+	Inviter   string
+	Workspace string
+	Role      string
+	Link      string
+	Days      int
+}
+
+func (s *workspaceService) Create(ctx context.Context, req *dto.CreateWorkspaceRequest, userID int) (*dto.WorkspaceResponse, error) {
+	var created *domain.Workspace
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		ws, err := s.workspaces.Create(ctx, &domain.Workspace{Name: strings.TrimSpace(req.Name), CreatedBy: &userID})
+		if err != nil {
+			return err
+		}
+		created = ws
+		if err := s.members.Add(ctx, ws.ID, userID, domain.RoleOwner); err != nil {
+			return err
+		}
+		ws.MemberCount = 1
+		if err := s.publish(ctx, userID, events.WorkspaceCreated{WorkspaceID: ws.ID, Workspace: snapshot(ws)}); err != nil {
+			return err
+		}
+		return s.publish(ctx, userID, events.WorkspaceMemberAdded{WorkspaceID: ws.ID, MemberID: userID, Role: domain.RoleOwner})
+	})
+	if err != nil {
+		s.logger.Error("Failed to create workspace", err, map[string]interface{}{"user_id": userID, "action": "CREATE_WORKSPACE_FAILED"})
+		return nil, err
+	}
+
+	created.Role = domain.RoleOwner
+	s.logger.Info("Workspace created", map[string]interface{}{"user_id": userID, "workspace_id": created.ID, "action": "CREATE_WORKSPACE_SUCCESS"})
+	return dto.ToWorkspaceResponse(created), nil
+}
+
+func (s *workspaceService) GetAll(ctx context.Context, userID int) ([]*dto.WorkspaceResponse, error) {
+	workspaces, err := s.workspaces.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToWorkspaceResponseList(workspaces), nil
+}
+
+func (s *workspaceService) GetByID(ctx context.Context, id, userID int) (*dto.WorkspaceDetailResponse, error) {
+	ws, err := s.authorize(ctx, id, userID, domain.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.members.List(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.WorkspaceDetailResponse{
+		WorkspaceResponse: dto.ToWorkspaceResponse(ws),
+		Members:           dto.ToMemberResponseList(members),
+	}, nil
+}
+
+func (s *workspaceService) Update(ctx context.Context, id int, req *dto.UpdateWorkspaceRequest, userID int) (*dto.WorkspaceResponse, error) {
+	var ws *domain.Workspace
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if ws, err = s.authorize(ctx, id, userID, domain.RoleOwner); err != nil {
+			return err
+		}
+		before := snapshot(ws)
+		if req.Name != nil {
+			ws.Name = strings.TrimSpace(*req.Name)
+		}
+		ws.UpdatedAt = time.Now()
+		if err := s.workspaces.Update(ctx, ws); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.WorkspaceUpdated{WorkspaceID: id, Workspace: snapshot(ws)})
+	})
+	if err != nil {
+		s.logger.Error("Failed to update workspace", err, map[string]interface{}{"user_id": userID, "workspace_id": id, "action": "UPDATE_WORKSPACE_FAILED"})
+		return nil, err
+	}
+	s.logger.Info("Workspace updated", map[string]interface{}{"user_id": userID, "workspace_id": id, "action": "UPDATE_WORKSPACE_SUCCESS"})
+	return dto.ToWorkspaceResponse(ws), nil
+}
+
+func (s *workspaceService) Delete(ctx context.Context, id, userID int) error {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		ws, err := s.authorize(ctx, id, userID, domain.RoleOwner)
+		if err != nil {
+			return err
+		}
+		before := snapshot(ws)
+		if err := s.workspaces.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.publish(eventbus.WithBefore(ctx, before), userID, events.WorkspaceDeleted{WorkspaceID: id, Name: ws.Name})
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete workspace", err, map[string]interface{}{"user_id": userID, "workspace_id": id, "action": "DELETE_WORKSPACE_FAILED"})
+		return err
+	}
+	s.logger.Info("Workspace deleted", map[string]interface{}{"user_id": userID, "workspace_id": id, "action": "DELETE_WORKSPACE_SUCCESS"})
+	return nil
+}
+
+func (s *workspaceService) UpdateMember(ctx context.Context, id, memberID int, req *dto.UpdateMemberRequest, userID int) (*dto.MemberResponse, error) {
+	if _, err := s.authorize(ctx, id, userID, domain.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	var updated *domain.Member
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		// Serializes owner changes so two owners cannot demote each other at once
+		if err := s.workspaces.Lock(ctx, id); err != nil {
+			return err
+		}
+		member, err := s.members.Get(ctx, id, memberID)
+		if err != nil {
+			return err
+		}
+		if member == nil {
+			return errors.New("member not found")
+		}
+		if member.Role == domain.RoleOwner && req.Role != domain.RoleOwner {
+			if err := s.ensureAnotherOwner(ctx, id); err != nil {
+				return err
+			}
+		}
+		if err := s.members.Add(ctx, id, memberID, req.Role); err != nil {
+			return err
+		}
+		previous := member.Role
+		member.Role = req.Role
+		updated = member
+		if previous == req.Role {
+			return nil
+		}
+		return s.publish(ctx, userID, events.WorkspaceMemberUpdated{WorkspaceID: id, MemberID: memberID, PreviousRole: previous, Role: req.Role})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Workspace member role changed", map[string]interface{}{"user_id": userID, "workspace_id": id, "member_id": memberID, "role": req.Role, "action": "UPDATE_WORKSPACE_MEMBER_SUCCESS"})
+	return dto.ToMemberResponse(updated), nil
+}
+
+func (s *workspaceService) RemoveMember(ctx context.Context, id, memberID, userID int) error {
+	required := domain.RoleOwner
+	if memberID == userID {
+		required = domain.RoleViewer
+	}
+	if _, err := s.authorize(ctx, id, userID, required); err != nil {
+		return err
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.workspaces.Lock(ctx, id); err != nil {
+			return err
+		}
+		member, err := s.members.Get(ctx, id, memberID)
+		if err != nil {
+			return err
+		}
+		if member == nil {
+			return errors.New("member not found")
+		}
+		if member.Role == domain.RoleOwner {
+			if err := s.ensureAnotherOwner(ctx, id); err != nil {
+				return err
+			}
+		}
+		if err := s.members.Remove(ctx, id, memberID); err != nil {
+			return err
+		}
+		return s.publish(ctx, userID, events.WorkspaceMemberRemoved{WorkspaceID: id, MemberID: memberID, PreviousRole: member.Role})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Workspace member removed", map[string]interface{}{"user_id": userID, "workspace_id": id, "member_id": memberID, "action": "REMOVE_WORKSPACE_MEMBER_SUCCESS"})
+	return nil
+}
+
+func (s *workspaceService) Invite(ctx context.Context, id int, req *dto.InviteRequest, userID int) (*dto.InvitationResponse, error) {
+	ws, err := s.authorize(ctx, id, userID, domain.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	role := req.Role
+	if role == "" {
+		role = domain.RoleViewer
+	}
+
+	invitee, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if invitee != nil {
+		member, err := s.members.Get(ctx, id, invitee.ID)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil {
+			return nil, errors.New("user is already a member")
+		}
+	}
+
+	pending, err := s.invitations.CountPending(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if pending >= maxPendingInvitations {
+		return nil, errors.New("too many pending invitations")
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *domain.Invitation
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// A new invitation replaces any still open for the same address
+		if err := s.invitations.DeletePending(ctx, id, email); err != nil {
+			return err
+		}
+		created, err = s.invitations.Create(ctx, &domain.Invitation{
+			WorkspaceID: id,
+			Email:       email,
+			Role:        role,
+			TokenHash:   hashToken(token),
+			InvitedBy:   &userID,
+			ExpiresAt:   time.Now().Add(s.config.TTL),
+		})
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to create workspace invitation", err, map[string]interface{}{"user_id": userID, "workspace_id": id, "action": "INVITE_WORKSPACE_MEMBER_FAILED"})
+		return nil, err
+	}
+
+	if err := s.sendInvitation(ctx, ws, created, token, invitee, userID); err != nil {
+		s.logger.Error("Failed to send workspace invitation", err, map[string]interface{}{"user_id": userID, "workspace_id": id, "invitation_id": created.ID, "action": "INVITE_WORKSPACE_MEMBER_FAILED"})
+		return nil, err
+	}
+
+	s.logger.Info("Workspace invitation sent", map[string]interface{}{"user_id": userID, "workspace_id": id, "invitation_id": created.ID, "role": role, "action": "INVITE_WORKSPACE_MEMBER_SUCCESS"})
+	return dto.ToInvitationResponse(created), nil
+}
+
+// sendInvitation mails the link in the invitee's language when they already have an
+// account, otherwise in the inviter's
+func (s *workspaceService) sendInvitation(ctx context.Context, ws *domain.Workspace, inv *domain.Invitation, token string, invitee *userDomain.User, inviterID int) error {
+	inviter, err := s.userRepo.GetByID(ctx, inviterID)
+	if err != nil {
+		return err
+	}
+
+	data := invitationEmail{
+		Workspace: ws.Name,
+		Role:      inv.Role,
+		Link:      s.config.Link + token,
+		Days:      max(int(s.config.TTL/(24*time.Hour)), 1),
+	}
+	lang := ""
+	if inviter != nil {
+		data.Inviter = inviter.FullName
+		if data.Inviter == "" {
+			data.Inviter = inviter.Email
+		}
+		lang = inviter.Language
+	}
+	recipientID := 0
+	if invitee != nil {
+		lang = invitee.Language
+		recipientID = invitee.ID
+	}
+
+	msg, err := s.renderer.Render(invitationTemplate, lang, data)
+	if err != nil {
+		return err
+	}
+	msg.To = inv.Email
+	_, err = s.outbox.Enqueue(ctx, recipientID, msg)
+	return err
+}
+
+func (s *workspaceService) GetInvitations(ctx context.Context, id, userID int) ([]*dto.InvitationResponse, error) {
+	if _, err := s.authorize(ctx, id, userID, domain.RoleOwner); err != nil {
+		return nil, err
+	}
+	invitations, err := s.invitations.ListPending(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToInvitationResponseList(invitations), nil
+}
+
+func (s *workspaceService) RevokeInvitation(ctx context.Context, id, invitationID, userID int) error {
+	if _, err := s.authorize(ctx, id, userID, domain.RoleOwner); err != nil {
+		return err
+	}
+	inv, err := s.invitations.GetByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if inv == nil || inv.WorkspaceID != id || inv.AcceptedAt != nil {
+		return errors.New("invitation not found")
+	}
+	if err := s.invitations.Delete(ctx, invitationID); err != nil {
+		return err
+	}
+	s.logger.Info("Workspace invitation revoked", map[string]interface{}{"user_id": userID, "workspace_id": id, "invitation_id": invitationID, "action": "REVOKE_WORKSPACE_INVITATION_SUCCESS"})
+	return nil
+}
+
+func (s *workspaceService) AcceptInvitation(ctx context.Context, req *dto.AcceptInvitationRequest, userID int) (*dto.WorkspaceResponse, error) {
+	now := time.Now()
+	inv, err := s.invitations.GetByTokenHash(ctx, hashToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if inv == nil || !inv.Pending(now) {
+		return nil, errInvalidInvitation
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// The token proves access to the mailbox; the account must be the one it was sent to
+	if user == nil || !strings.EqualFold(user.Email, inv.Email) {
+		return nil, errors.New("invitation was sent to a different email")
+	}
+
+	role := inv.Role
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.workspaces.Lock(ctx, inv.WorkspaceID); err != nil {
+			return err
+		}
+		member, err := s.members.Get(ctx, inv.WorkspaceID, userID)
+		if err != nil {
+			return err
+		}
+		// Accepting never lowers the role of someone who is already a member
+		var joined events.Event
+		switch {
+		case member != nil && domain.RoleAllows(member.Role, inv.Role):
+			role = member.Role
+		case member != nil:
+			joined = events.WorkspaceMemberUpdated{WorkspaceID: inv.WorkspaceID, MemberID: userID, PreviousRole: member.Role, Role: inv.Role}
+		default:
+			joined = events.WorkspaceMemberAdded{WorkspaceID: inv.WorkspaceID, MemberID: userID, Role: inv.Role}
+		}
+		if joined != nil {
+			if err := s.members.Add(ctx, inv.WorkspaceID, userID, inv.Role); err != nil {
+				return err
+			}
+			if err := s.publish(ctx, userID, joined); err != nil {
+				return err
+			}
+		}
+		return s.invitations.MarkAccepted(ctx, inv.ID, now)
+	})
+	if err != nil {
+		s.logger.Error("Failed to accept workspace invitation", err, map[string]interface{}{"user_id": userID, "workspace_id": inv.WorkspaceID, "action": "ACCEPT_WORKSPACE_INVITATION_FAILED"})
+		return nil, err
+	}
+
+	ws, err := s.workspaces.GetByID(ctx, inv.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if ws == nil {
+		return nil, errors.New("workspace not found")
+	}
+	ws.Role = role
+
+	s.logger.Info("Workspace invitation accepted", map[string]interface{}{"user_id": userID, "workspace_id": inv.WorkspaceID, "invitation_id": inv.ID, "role": role, "action": "ACCEPT_WORKSPACE_INVITATION_SUCCESS"})
+	return dto.ToWorkspaceResponse(ws), nil
+}
+
+// authorize loads the workspace and checks that the user holds at least the required role,
+// returning it with that role filled in
+func (s *workspaceService) authorize(ctx context.Context, id, userID int, required string) (*domain.Workspace, error) {
+	ws, err := s.workspaces.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ws == nil {
+		return nil, errors.New("workspace not found")
+	}
+	member, err := s.members.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || !domain.RoleAllows(member.Role, required) {
+		return nil, errors.New("unauthorized")
+	}
+	ws.Role = member.Role
+	return ws, nil
+}
+
+// ensureAnotherOwner fails if the workspace has a single owner, who therefore cannot leave
+// or step down. It expects the workspace row to be locked.
+func (s *workspaceService) ensureAnotherOwner(ctx context.Context, id int) error {
+	owners, err := s.members.CountOwners(ctx, id)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return errLastOwner
+	}
+	return nil
+}
+
+// snapshot is the workspace as the audit log records it, without the viewer's role
+func snapshot(ws *domain.Workspace) *dto.WorkspaceResponse {
+	response := dto.ToWorkspaceResponse(ws)
+	response.Role = ""
+	return response
+}
+
+// publish records the event in the unit of work running in ctx, so it is sent only if the
+// change commits
+func (s *workspaceService) publish(ctx context.Context, userID int, event events.Event) error {
+	if s.bus == nil {
+		return nil
+	}
+	return s.bus.Publish(ctx, userID, event)
+}
+
+// randomToken returns n random bytes, hex encoded
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken is how invitation tokens are stored: they are high-entropy, so a plain SHA-256 suffices
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}